@@ -1,5 +1,16 @@
 package xlsx
 
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
 type adjustDirection bool
 
 const (
@@ -8,15 +19,16 @@ const (
 )
 
 // adjustHelper provides a function to adjust rows and columns dimensions,
-// hyperlinks, merged cells and auto filter when inserting or deleting rows or
-// columns.
+// hyperlinks, merged cells, auto filter, formulas, tables, data validations,
+// conditional formats, comments, drawings, sparklines and pivot tables when
+// inserting or deleting rows or columns.
 //
 // sheet: Worksheet name that we're editing
 // column: Index number of the column we're inserting/deleting before
 // row: Index number of the row we're inserting/deleting before
 // offset: Number of rows/column to insert/delete negative values indicate deletion
 //
-// TODO: adjustPageBreaks, adjustComments, adjustDataValidations, adjustProtectedCells
+// TODO: adjustPageBreaks, adjustProtectedCells
 //
 func (f *File) adjustHelper(sheet string, dir adjustDirection, num, offset int) error {
 	ws, err := f.workSheetReader(sheet)
@@ -39,8 +51,20 @@ func (f *File) adjustHelper(sheet string, dir adjustDirection, num, offset int)
 	if err = f.adjustCalcChain(dir, num, offset, sheetID); err != nil {
 		return err
 	}
+	if err = f.adjustFormulas(sheet, dir, num, offset); err != nil {
+		return err
+	}
+	f.adjustDataValidations(ws, dir, num, offset)
+	f.adjustConditionalFormats(ws, dir, num, offset)
+	f.adjustExtLst(ws, dir, num, offset)
+	f.adjustComments(ws, sheet, dir, num, offset)
+	f.adjustDrawings(ws, sheet, dir, num, offset)
+	f.adjustPivotTables(sheet, dir, num, offset)
 	checkSheet(ws)
 	_ = checkRow(ws)
+	if err = f.adjustTables(ws, sheet, dir, num, offset); err != nil {
+		return err
+	}
 
 	if ws.MergeCells != nil && len(ws.MergeCells.Cells) == 0 {
 		ws.MergeCells = nil
@@ -268,3 +292,942 @@ func (f *File) adjustCalcChain(dir adjustDirection, num, offset, sheetID int) er
 	}
 	return nil
 }
+
+// adjustFormulas provides a function to update the cell references in the
+// formulas of all worksheets and the defined names when inserting or deleting
+// rows or columns in the given worksheet.
+func (f *File) adjustFormulas(sheet string, dir adjustDirection, num, offset int) error {
+	sheet = trimSheetName(sheet)
+	for _, name := range f.GetSheetList() {
+		ws, err := f.workSheetReader(name)
+		if err != nil {
+			if err.Error() == fmt.Sprintf("sheet %s is not a worksheet", trimSheetName(name)) {
+				continue
+			}
+			return err
+		}
+		for rowIdx := range ws.SheetData.Row {
+			for colIdx := range ws.SheetData.Row[rowIdx].C {
+				formula := ws.SheetData.Row[rowIdx].C[colIdx].F
+				if formula == nil {
+					continue
+				}
+				if formula.Content != "" {
+					formula.Content = adjustFormulaRef(formula.Content, name, sheet, dir, num, offset)
+				}
+				// The shared formula and array formula range on the worksheet.
+				if formula.Ref != "" && strings.EqualFold(name, sheet) {
+					if ref, ok := adjustRangeRef(formula.Ref, dir, num, offset); ok && ref != formulaErrorREF {
+						formula.Ref = ref
+					}
+				}
+			}
+		}
+		if ws.DataValidations != nil {
+			for _, dv := range ws.DataValidations.DataValidation {
+				dv.Formula1 = adjustXMLFormulas(dv.Formula1, name, sheet, dir, num, offset)
+				dv.Formula2 = adjustXMLFormulas(dv.Formula2, name, sheet, dir, num, offset)
+			}
+		}
+		for _, cf := range ws.ConditionalFormatting {
+			for _, rule := range cf.CfRule {
+				for idx := range rule.Formula {
+					rule.Formula[idx] = adjustFormulaRef(rule.Formula[idx], name, sheet, dir, num, offset)
+				}
+			}
+		}
+		if ws.ExtLst != nil {
+			ws.ExtLst.Ext = adjustXMLFormulas(ws.ExtLst.Ext, name, sheet, dir, num, offset)
+		}
+	}
+	wb := f.workbookReader()
+	if wb.DefinedNames == nil {
+		return nil
+	}
+	for idx := range wb.DefinedNames.DefinedName {
+		definedName := &wb.DefinedNames.DefinedName[idx]
+		definedName.Data = adjustFormulaRef(definedName.Data, "", sheet, dir, num, offset)
+	}
+	return nil
+}
+
+// adjustFormulaRef provides a function to update the cell references in the
+// formula by given worksheet name which the formula belongs to, worksheet
+// name which inserting or deleting rows or columns, adjust direction,
+// operation axis and offset. The references without worksheet name are
+// relative to the worksheet of the formula, and the formulas of the defined
+// names have no worksheet. The references to the deleted cells will be
+// replaced with #REF! as Excel does.
+func adjustFormulaRef(formula, formulaSheet, sheet string, dir adjustDirection, num, offset int) string {
+	return replaceFormulaRefs(formula, func(refSheet, ref string) (string, bool) {
+		if refSheet == "" && (formulaSheet == "" || !strings.EqualFold(formulaSheet, sheet)) {
+			return ref, false
+		}
+		if refSheet != "" && !strings.EqualFold(unquoteSheetName(refSheet), sheet) {
+			return ref, false
+		}
+		newRef, ok := adjustRangeRef(ref, dir, num, offset)
+		if !ok {
+			return ref, false
+		}
+		if refSheet != "" {
+			newRef = refSheet + "!" + newRef
+		}
+		return newRef, true
+	})
+}
+
+// replaceFormulaRefs provides a function to walk through the formula and
+// replace the operands which may be references by the given function. The
+// function receives the worksheet name (quoted as it is in the formula,
+// empty if the operand has no worksheet name) and the reference of the
+// operand, and returns the replacement of the whole operand and whether it
+// should be replaced. The reference of the structured reference is the table
+// name followed by the brackets, such as Table1[Column], so it will not be
+// taken as a cell reference. Function names, string literals and external
+// workbook references will be kept as is.
+func replaceFormulaRefs(formula string, fn func(sheet, ref string) (string, bool)) string {
+	var b strings.Builder
+	for i, n := 0, len(formula); i < n; {
+		ch := formula[i]
+		switch {
+		case ch == '"':
+			j := scanFormulaQuoted(formula, i)
+			b.WriteString(formula[i:j])
+			i = j
+		case ch == '[':
+			j := scanFormulaBracket(formula, i)
+			if i == 0 || !isFormulaRefChar(formula[i-1]) {
+				// The reference to an external workbook, such as [1]Sheet1!A1
+				j = scanFormulaRefToken(formula, j)
+			}
+			b.WriteString(formula[i:j])
+			i = j
+		case ch == '\'':
+			j := scanFormulaQuoted(formula, i)
+			if j < n && formula[j] == '!' {
+				k := scanFormulaStructuredRef(formula, scanFormulaRefToken(formula, j+1))
+				if s, ok := fn(formula[i:j], formula[j+1:k]); ok {
+					b.WriteString(s)
+				} else {
+					b.WriteString(formula[i:k])
+				}
+				i = k
+				continue
+			}
+			b.WriteString(formula[i:j])
+			i = j
+		case isFormulaRefChar(ch):
+			j := scanFormulaRefToken(formula, i)
+			k := j
+			for k < n && formula[k] == ' ' {
+				k++
+			}
+			if k < n && formula[k] == '(' {
+				b.WriteString(formula[i:j])
+				i = j
+				continue
+			}
+			end := scanFormulaStructuredRef(formula, j)
+			token, refSheet, ref := formula[i:end], "", formula[i:j]
+			if idx := strings.LastIndexByte(ref, '!'); idx != -1 {
+				refSheet, ref = ref[:idx], ref[idx+1:]
+			}
+			ref += formula[j:end]
+			i = end
+			if s, ok := fn(refSheet, ref); ok {
+				b.WriteString(s)
+				continue
+			}
+			b.WriteString(token)
+		default:
+			b.WriteByte(ch)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// scanFormulaStructuredRef provides a function to get the end index of the
+// structured reference in the formula if the table name which ends at the
+// given index is followed by it, such as Table1[Column].
+func scanFormulaStructuredRef(formula string, i int) int {
+	if i < len(formula) && formula[i] == '[' {
+		return scanFormulaBracket(formula, i)
+	}
+	return i
+}
+
+// isFormulaRefChar provides a function to check if the given byte can be a
+// part of the worksheet name, cell reference, number or defined name in the
+// formula.
+func isFormulaRefChar(ch byte) bool {
+	return ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' ||
+		ch == '_' || ch == '.' || ch == '$' || ch == '\\' || ch >= 0x80
+}
+
+// scanFormulaRefToken provides a function to get the end index of the
+// operand in the formula which starts at the given index.
+func scanFormulaRefToken(formula string, i int) int {
+	for i < len(formula) && (isFormulaRefChar(formula[i]) || formula[i] == '!' || formula[i] == ':') {
+		i++
+	}
+	return i
+}
+
+// scanFormulaQuoted provides a function to get the end index of the quoted
+// string or worksheet name in the formula which starts at the given index,
+// the doubled quotes are the escaped quote characters.
+func scanFormulaQuoted(formula string, i int) int {
+	quote := formula[i]
+	for i++; i < len(formula); i++ {
+		if formula[i] != quote {
+			continue
+		}
+		if i+1 < len(formula) && formula[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return i
+}
+
+// scanFormulaBracket provides a function to get the end index of the nested
+// brackets in the formula which starts at the given index.
+func scanFormulaBracket(formula string, i int) int {
+	var depth int
+	for ; i < len(formula); i++ {
+		switch formula[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+var (
+	// sheetNameUnquotedPattern defined the pattern of the worksheet name
+	// which can be used in the formula without quotes.
+	sheetNameUnquotedPattern = regexp.MustCompile(`^[A-Za-z_\x{80}-\x{10FFFF}][0-9A-Za-z_.\x{80}-\x{10FFFF}]*$`)
+	// sheetNameR1C1Pattern defined the pattern of the worksheet name which
+	// looks like a R1C1 style reference.
+	sheetNameR1C1Pattern = regexp.MustCompile(`^(?i)(R[0-9]*C?[0-9]*|C[0-9]*)$`)
+)
+
+// quoteSheetName provides a function to quote the worksheet name for using
+// in the formula if it contains spaces or special characters, or looks like
+// a cell reference.
+func quoteSheetName(name string) string {
+	if sheetNameUnquotedPattern.MatchString(name) && !sheetNameR1C1Pattern.MatchString(name) {
+		if part, ok := parseCellRefPart(name); !ok || part.col == 0 || part.row == 0 {
+			return name
+		}
+	}
+	return "'" + strings.Replace(name, "'", "''", -1) + "'"
+}
+
+// unquoteSheetName provides a function to get the worksheet name from the
+// worksheet name in the formula which may be quoted.
+func unquoteSheetName(name string) string {
+	if len(name) > 1 && name[0] == '\'' && name[len(name)-1] == '\'' {
+		return strings.Replace(name[1:len(name)-1], "''", "'", -1)
+	}
+	return name
+}
+
+// cellRefPart directly maps the part of the cell reference, which may be a
+// cell, a whole column or a whole row.
+type cellRefPart struct {
+	col, row       int
+	absCol, absRow bool
+}
+
+// parseCellRefPart provides a function to parse the part of the cell
+// reference, such as A1, $A$1, $A or 1.
+func parseCellRefPart(s string) (part cellRefPart, ok bool) {
+	var i int
+	if i < len(s) && s[i] == '$' {
+		part.absCol = true
+		i++
+	}
+	start := i
+	for i < len(s) && (s[i] >= 'A' && s[i] <= 'Z' || s[i] >= 'a' && s[i] <= 'z') {
+		i++
+	}
+	letters := s[start:i]
+	if i < len(s) && s[i] == '$' {
+		if letters == "" {
+			return part, false
+		}
+		part.absRow = true
+		i++
+	}
+	start = i
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	digits := s[start:i]
+	if i != len(s) || len(letters) > 3 || letters == "" && digits == "" {
+		return part, false
+	}
+	if letters != "" {
+		col, err := ColumnNameToNumber(letters)
+		if err != nil {
+			return part, false
+		}
+		part.col = col
+	} else {
+		part.absRow, part.absCol = part.absCol, false
+	}
+	if digits != "" {
+		row, err := strconv.Atoi(digits)
+		if err != nil || row < 1 || row > TotalRows {
+			return part, false
+		}
+		part.row = row
+	} else if part.absRow {
+		return part, false
+	}
+	return part, true
+}
+
+// String provides a function to format the part of the cell reference.
+func (part cellRefPart) String() string {
+	var s string
+	if part.col > 0 {
+		if part.absCol {
+			s += "$"
+		}
+		name, _ := ColumnNumberToName(part.col)
+		s += name
+	}
+	if part.row > 0 {
+		if part.absRow {
+			s += "$"
+		}
+		s += strconv.Itoa(part.row)
+	}
+	return s
+}
+
+// parseRangeRef provides a function to parse the cell reference or range
+// reference without worksheet name, such as A1, A1:B2, A:B or 1:2.
+func parseRangeRef(ref string) ([]cellRefPart, bool) {
+	var parts []cellRefPart
+	for _, s := range strings.Split(ref, ":") {
+		part, ok := parseCellRefPart(s)
+		if !ok {
+			return parts, false
+		}
+		parts = append(parts, part)
+	}
+	switch len(parts) {
+	case 1:
+		return parts, parts[0].col > 0 && parts[0].row > 0
+	case 2:
+		return parts, (parts[0].col > 0) == (parts[1].col > 0) && (parts[0].row > 0) == (parts[1].row > 0)
+	}
+	return parts, false
+}
+
+// adjustRangeRef provides a function to update the cell reference or range
+// reference without worksheet name by given adjust direction, operation axis
+// and offset. It returns #REF! if all referenced cells were deleted, and
+// returns false if the given reference is not a valid reference.
+func adjustRangeRef(ref string, dir adjustDirection, num, offset int) (string, bool) {
+	parts, ok := parseRangeRef(ref)
+	if !ok {
+		return ref, false
+	}
+	first, last := &parts[0], &parts[len(parts)-1]
+	start, end, max := &first.col, &last.col, TotalColumns
+	if dir == rows {
+		start, end, max = &first.row, &last.row, TotalRows
+	}
+	if *start == 0 {
+		// The whole row reference for columns, or whole column reference
+		// for rows are not affected.
+		return ref, true
+	}
+	if len(parts) == 1 {
+		end = new(int)
+		*end = *start
+	}
+	if !adjustRefAxis(start, end, max, num, offset) {
+		return formulaErrorREF, true
+	}
+	if len(parts) == 1 {
+		return first.String(), true
+	}
+	return first.String() + ":" + last.String(), true
+}
+
+// adjustRefAxis provides a function to update the start and end axis of the
+// reference by given maximum axis, operation axis and offset. It returns
+// false if all cells between the start and end axis were deleted or moved out
+// of the worksheet.
+func adjustRefAxis(start, end *int, max, num, offset int) bool {
+	if offset > 0 {
+		if *start >= num {
+			*start += offset
+		}
+		if *end >= num {
+			*end += offset
+		}
+		if *start > max || (*start == *end && *end > max) {
+			return false
+		}
+		if *end > max {
+			*end = max
+		}
+		return true
+	}
+	last := num - offset - 1
+	if *start >= num && *end <= last {
+		return false
+	}
+	if *start > last {
+		*start += offset
+	} else if *start >= num {
+		*start = num
+	}
+	if *end > last {
+		*end += offset
+	} else if *end >= num {
+		*end = num - 1
+	}
+	return true
+}
+
+// xmlFormulaPattern defined the pattern of the formula elements in the raw
+// XML content, such as the formula of data validations, the xm:f element of
+// sparklines and conditional formats in the worksheet extension list, and
+// the c:f element of the chart series.
+var xmlFormulaPattern = regexp.MustCompile(`(<(?:\w+:)?(?:f|formula[12]?)>)([^<]*)(</)`)
+
+// replaceXMLFormulas provides a function to replace the formula of the
+// formula elements in the raw XML content by given function.
+func replaceXMLFormulas(content string, fn func(formula string) string) string {
+	return xmlFormulaPattern.ReplaceAllStringFunc(content, func(s string) string {
+		matches := xmlFormulaPattern.FindStringSubmatch(s)
+		var formula string
+		if err := xml.Unmarshal([]byte("<f>"+matches[2]+"</f>"), &formula); err != nil {
+			return s
+		}
+		replaced := fn(formula)
+		if replaced == formula {
+			return s
+		}
+		var buf bytes.Buffer
+		_ = xml.EscapeText(&buf, []byte(replaced))
+		return matches[1] + buf.String() + matches[3]
+	})
+}
+
+// adjustXMLFormulas provides a function to update the cell references in the
+// formula elements of the raw XML content.
+func adjustXMLFormulas(content, formulaSheet, sheet string, dir adjustDirection, num, offset int) string {
+	return replaceXMLFormulas(content, func(formula string) string {
+		return adjustFormulaRef(formula, formulaSheet, sheet, dir, num, offset)
+	})
+}
+
+// adjustSqref provides a function to update the space separated references
+// by given adjust direction, operation axis and offset, the deleted
+// references will be removed.
+func adjustSqref(sqref string, dir adjustDirection, num, offset int) string {
+	var refs []string
+	for _, ref := range strings.Fields(sqref) {
+		if newRef, ok := adjustRangeRef(ref, dir, num, offset); ok {
+			ref = newRef
+		}
+		if ref != formulaErrorREF {
+			refs = append(refs, ref)
+		}
+	}
+	return strings.Join(refs, " ")
+}
+
+// adjustTables provides a function to update the range of the tables in the
+// worksheet when inserting or deleting rows or columns. The table columns
+// will be added or removed when inserting or deleting columns inside the
+// table, and the table will be removed if all cells of the table were
+// deleted.
+func (f *File) adjustTables(ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset int) error {
+	if ws.TableParts == nil {
+		return nil
+	}
+	for idx := 0; idx < len(ws.TableParts.TableParts); idx++ {
+		tablePart := ws.TableParts.TableParts[idx]
+		tableXML := strings.Replace(f.getSheetRelationshipsTargetByID(sheet, tablePart.RID), "..", "xl", -1)
+		content, ok := f.Pkg.Load(tableXML)
+		if !ok {
+			continue
+		}
+		t := xlsxTable{}
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte)))).
+			Decode(&t); err != nil && err != io.EOF {
+			return err
+		}
+		coordinates, err := areaRefToCoordinates(t.Ref)
+		if err != nil {
+			return err
+		}
+		ref, _ := adjustRangeRef(t.Ref, dir, num, offset)
+		if ref == formulaErrorREF {
+			ws.TableParts.TableParts = append(ws.TableParts.TableParts[:idx], ws.TableParts.TableParts[idx+1:]...)
+			ws.TableParts.Count = len(ws.TableParts.TableParts)
+			idx--
+			f.deleteSheetRelationships(sheet, tablePart.RID)
+			f.deleteSheetFromContentTypes("/" + tableXML)
+			f.Pkg.Delete(tableXML)
+			continue
+		}
+		t.Ref = ref
+		if t.AutoFilter != nil {
+			if ref, _ = adjustRangeRef(t.AutoFilter.Ref, dir, num, offset); ref != formulaErrorREF {
+				t.AutoFilter.Ref = ref
+			}
+		}
+		if dir == columns && t.TableColumns != nil {
+			if err = f.adjustTableColumns(sheet, &t, coordinates, num, offset); err != nil {
+				return err
+			}
+		}
+		table, _ := xml.Marshal(t)
+		f.saveFileList(tableXML, table)
+	}
+	if len(ws.TableParts.TableParts) == 0 {
+		ws.TableParts = nil
+	}
+	return nil
+}
+
+// adjustTableColumns provides a function to add or remove the table columns
+// by given table, the coordinates of the table before adjusting, operation
+// column and offset. The name of the added column will be written into the
+// header row cell.
+func (f *File) adjustTableColumns(sheet string, t *xlsxTable, coordinates []int, num, offset int) error {
+	x1, y1, x2 := coordinates[0], coordinates[1], coordinates[2]
+	tableColumns := t.TableColumns.TableColumn
+	if offset > 0 {
+		if num <= x1 || num > x2 || num-x1 > len(tableColumns) {
+			return nil
+		}
+		var columnID int
+		names := make(map[string]bool, len(tableColumns))
+		for _, column := range tableColumns {
+			if column.ID > columnID {
+				columnID = column.ID
+			}
+			names[column.Name] = true
+		}
+		columns := make([]*xlsxTableColumn, offset)
+		for i, idx := 0, 1; i < offset; i++ {
+			for ; names["Column"+strconv.Itoa(idx)]; idx++ {
+			}
+			columnID++
+			columns[i] = &xlsxTableColumn{ID: columnID, Name: "Column" + strconv.Itoa(idx)}
+			names[columns[i].Name] = true
+			if t.HeaderRowCount == nil || *t.HeaderRowCount > 0 {
+				cell, err := CoordinatesToCellName(num+i, y1)
+				if err != nil {
+					return err
+				}
+				if err = f.SetCellStr(sheet, cell, columns[i].Name); err != nil {
+					return err
+				}
+			}
+		}
+		pos := num - x1
+		tableColumns = append(tableColumns[:pos], append(columns, tableColumns[pos:]...)...)
+	} else {
+		for col := num - offset - 1; col >= num; col-- {
+			if pos := col - x1; col <= x2 && pos >= 0 && pos < len(tableColumns) {
+				tableColumns = append(tableColumns[:pos], tableColumns[pos+1:]...)
+			}
+		}
+	}
+	t.TableColumns.TableColumn = tableColumns
+	t.TableColumns.Count = len(tableColumns)
+	return nil
+}
+
+// adjustDataValidations provides a function to update the range of the data
+// validations in the worksheet when inserting or deleting rows or columns,
+// the data validation will be removed if all cells of it were deleted.
+func (f *File) adjustDataValidations(ws *xlsxWorksheet, dir adjustDirection, num, offset int) {
+	if ws.DataValidations == nil {
+		return
+	}
+	dvs := ws.DataValidations.DataValidation[:0]
+	for _, dv := range ws.DataValidations.DataValidation {
+		if dv.Sqref = adjustSqref(dv.Sqref, dir, num, offset); dv.Sqref != "" {
+			dvs = append(dvs, dv)
+		}
+	}
+	ws.DataValidations.DataValidation = dvs
+	ws.DataValidations.Count = len(dvs)
+	if len(dvs) == 0 {
+		ws.DataValidations = nil
+	}
+}
+
+// adjustConditionalFormats provides a function to update the range of the
+// conditional formats in the worksheet when inserting or deleting rows or
+// columns, the conditional format will be removed if all cells of it were
+// deleted.
+func (f *File) adjustConditionalFormats(ws *xlsxWorksheet, dir adjustDirection, num, offset int) {
+	cfs := ws.ConditionalFormatting[:0]
+	for _, cf := range ws.ConditionalFormatting {
+		if cf.SQRef = adjustSqref(cf.SQRef, dir, num, offset); cf.SQRef != "" {
+			cfs = append(cfs, cf)
+		}
+	}
+	ws.ConditionalFormatting = cfs
+}
+
+var (
+	// extLstElementPattern defined the pattern of the sparklines, conditional
+	// formats and data validations in the worksheet extension list.
+	extLstElementPattern = regexp.MustCompile(`(?s)<x14:(?:sparkline|conditionalFormatting|dataValidation)\b[^>]*>.*?</x14:(?:sparkline|conditionalFormatting|dataValidation)>`)
+	// extLstSqrefPattern defined the pattern of the xm:sqref element.
+	extLstSqrefPattern = regexp.MustCompile(`(<xm:sqref>)([^<]*)(</xm:sqref>)`)
+	// extLstSparklineGroupPattern defined the pattern of the sparkline group
+	// in the worksheet extension list.
+	extLstSparklineGroupPattern = regexp.MustCompile(`(?s)<x14:sparklineGroup\b[^>]*>.*?</x14:sparklineGroup>`)
+	// extLstEmptyPattern defined the pattern of the empty elements in the
+	// worksheet extension list.
+	extLstEmptyPattern = regexp.MustCompile(`<x14:(?:sparklineGroups|conditionalFormattings|dataValidations)\b[^>]*>\s*</x14:(?:sparklineGroups|conditionalFormattings|dataValidations)>`)
+	// extLstEmptyExtPattern defined the pattern of the empty ext element.
+	extLstEmptyExtPattern = regexp.MustCompile(`<(?:\w+:)?ext\b[^>]*>\s*</(?:\w+:)?ext>`)
+)
+
+// adjustExtLst provides a function to update the range of the sparklines,
+// conditional formats and data validations in the worksheet extension list
+// when inserting or deleting rows or columns, the element will be removed if
+// all cells of it were deleted.
+func (f *File) adjustExtLst(ws *xlsxWorksheet, dir adjustDirection, num, offset int) {
+	if ws.ExtLst == nil {
+		return
+	}
+	ws.ExtLst.Ext = extLstElementPattern.ReplaceAllStringFunc(ws.ExtLst.Ext, func(s string) string {
+		var deleted bool
+		s = extLstSqrefPattern.ReplaceAllStringFunc(s, func(sqref string) string {
+			matches := extLstSqrefPattern.FindStringSubmatch(sqref)
+			ref := adjustSqref(matches[2], dir, num, offset)
+			deleted = deleted || ref == ""
+			return matches[1] + ref + matches[3]
+		})
+		if deleted {
+			return ""
+		}
+		return s
+	})
+	ws.ExtLst.Ext = extLstSparklineGroupPattern.ReplaceAllStringFunc(ws.ExtLst.Ext, func(s string) string {
+		if strings.Contains(s, "<x14:sparkline>") {
+			return s
+		}
+		return ""
+	})
+	ws.ExtLst.Ext = extLstEmptyExtPattern.ReplaceAllString(extLstEmptyPattern.ReplaceAllString(ws.ExtLst.Ext, ""), "")
+	if strings.TrimSpace(ws.ExtLst.Ext) == "" {
+		ws.ExtLst = nil
+	}
+}
+
+// adjustComments provides a function to update the cell of the comments and
+// the anchor of the comment shapes in the worksheet when inserting or
+// deleting rows or columns, the comments of the deleted cells will be
+// removed.
+func (f *File) adjustComments(ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset int) {
+	if target := f.getSheetComments(filepath.Base(f.sheetMap[trimSheetName(sheet)])); target != "" {
+		if !strings.HasPrefix(target, "/") {
+			target = "xl" + strings.TrimPrefix(target, "..")
+		}
+		if comments := f.commentsReader(strings.TrimPrefix(target, "/")); comments != nil {
+			list := comments.CommentList.Comment[:0]
+			for _, comment := range comments.CommentList.Comment {
+				if ref, ok := adjustRangeRef(comment.Ref, dir, num, offset); ok {
+					if comment.Ref = ref; ref == formulaErrorREF {
+						continue
+					}
+				}
+				list = append(list, comment)
+			}
+			comments.CommentList.Comment = list
+		}
+	}
+	f.replaceVMLShapes(ws, sheet, func(shape string) (string, bool) {
+		return adjustVMLShape(shape, dir, num, offset)
+	})
+}
+
+// replaceVMLShapes provides a function to replace the shapes in the VML
+// drawing of the worksheet by given replace function, the shape will be
+// removed if the function returns false.
+func (f *File) replaceVMLShapes(ws *xlsxWorksheet, sheet string, fn func(shape string) (string, bool)) {
+	if ws.LegacyDrawing == nil {
+		return
+	}
+	drawingVML := strings.Replace(f.getSheetRelationshipsTargetByID(sheet, ws.LegacyDrawing.RID), "..", "xl", -1)
+	if vml := f.VMLDrawing[drawingVML]; vml != nil {
+		shapes := vml.Shape[:0]
+		for _, shape := range vml.Shape {
+			var ok bool
+			if shape.Val, ok = fn(shape.Val); ok {
+				shapes = append(shapes, shape)
+			}
+		}
+		vml.Shape = shapes
+	}
+	if d := f.DecodeVMLDrawing[drawingVML]; d != nil {
+		shapes := d.Shape[:0]
+		for _, shape := range d.Shape {
+			var ok bool
+			if shape.Val, ok = fn(shape.Val); ok {
+				shapes = append(shapes, shape)
+			}
+		}
+		d.Shape = shapes
+	}
+	if content, ok := f.Pkg.Load(drawingVML); ok && content != nil {
+		f.Pkg.Store(drawingVML, []byte(vmlShapePattern.ReplaceAllStringFunc(string(content.([]byte)), func(s string) string {
+			if shape, ok := fn(s); ok {
+				return shape
+			}
+			return ""
+		})))
+	}
+}
+
+var (
+	// vmlShapePattern defined the pattern of the shape in the VML drawing.
+	vmlShapePattern = regexp.MustCompile(`(?s)<v:shape\b.*?</v:shape>`)
+	// vmlClientDataPattern defined the pattern of the row and column of the
+	// cell which the VML shape attached to.
+	vmlClientDataPattern = regexp.MustCompile(`(<x:(Row|Column)>)(\d+)(</x:(?:Row|Column)>)`)
+	// vmlAnchorPattern defined the pattern of the anchor of the VML shape.
+	vmlAnchorPattern = regexp.MustCompile(`(<x:Anchor>)([^<]*)(</x:Anchor>)`)
+)
+
+// adjustVMLShape provides a function to update the attached cell and anchor
+// of the VML shape by given adjust direction, operation axis and offset. It
+// returns false if the attached cell of the shape was deleted.
+func adjustVMLShape(shape string, dir adjustDirection, num, offset int) (string, bool) {
+	axis, anchorIdx := "Column", []int{0, 4}
+	if dir == rows {
+		axis, anchorIdx = "Row", []int{2, 6}
+	}
+	var deleted bool
+	shape = vmlClientDataPattern.ReplaceAllStringFunc(shape, func(s string) string {
+		matches := vmlClientDataPattern.FindStringSubmatch(s)
+		if matches[2] != axis {
+			return s
+		}
+		idx, _ := strconv.Atoi(matches[3])
+		if offset < 0 && idx+1 >= num && idx+1 < num-offset {
+			deleted = true
+			return s
+		}
+		return matches[1] + strconv.Itoa(adjustAnchorIdx(idx, num, offset)) + matches[4]
+	})
+	if deleted {
+		return shape, false
+	}
+	shape = vmlAnchorPattern.ReplaceAllStringFunc(shape, func(s string) string {
+		matches := vmlAnchorPattern.FindStringSubmatch(s)
+		values := strings.Split(matches[2], ",")
+		if len(values) != 8 {
+			return s
+		}
+		for _, i := range anchorIdx {
+			idx, err := strconv.Atoi(strings.TrimSpace(values[i]))
+			if err != nil {
+				return s
+			}
+			values[i] = strconv.Itoa(adjustAnchorIdx(idx, num, offset))
+		}
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		return matches[1] + strings.Join(values, ", ") + matches[3]
+	})
+	return shape, true
+}
+
+// adjustAnchorIdx provides a function to update the zero-based row or column
+// index of the drawing object anchor by given operation axis and offset. The
+// anchor inside the deleted rows or columns will be moved to the next row or
+// column after the deleted area.
+func adjustAnchorIdx(idx, num, offset int) int {
+	if idx+1 < num {
+		return idx
+	}
+	if offset > 0 || idx+1 >= num-offset {
+		return idx + offset
+	}
+	return num - 1
+}
+
+var (
+	// drawingAnchorPattern defined the pattern of the start and end anchor of
+	// the drawing object.
+	drawingAnchorPattern = regexp.MustCompile(`(?s)<(?:\w+:)?(from|to)>.*?</(?:\w+:)?(?:from|to)>`)
+	// drawingAnchorAxisPattern defined the pattern of the row and column of
+	// the drawing object anchor.
+	drawingAnchorAxisPattern = regexp.MustCompile(`(<(?:\w+:)?(col|row)>)(\d+)(<)`)
+)
+
+// adjustDrawings provides a function to update the anchor of the pictures,
+// charts and shapes in the worksheet when inserting or deleting rows or
+// columns.
+func (f *File) adjustDrawings(ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset int) {
+	if ws.Drawing == nil {
+		return
+	}
+	drawingXML := strings.Replace(f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID), "..", "xl", -1)
+	if _, ok := f.Pkg.Load(drawingXML); !ok {
+		if _, ok = f.Drawings.Load(drawingXML); !ok {
+			return
+		}
+	}
+	wsDr, _ := f.drawingParser(drawingXML)
+	wsDr.Lock()
+	for _, anchor := range append(wsDr.OneCellAnchor, wsDr.TwoCellAnchor...) {
+		adjustDrawingAnchor(anchor, dir, num, offset)
+	}
+	wsDr.Unlock()
+	f.Drawings.Store(drawingXML, wsDr)
+	if _, ok := f.Pkg.Load(drawingXML); ok {
+		content, _ := xml.Marshal(wsDr)
+		f.saveFileList(drawingXML, content)
+	}
+}
+
+// adjustDrawingAnchor provides a function to update the start and end anchor
+// of the drawing object by given adjust direction, operation axis and offset.
+// The object which positioning as one cell anchor keeps its size.
+func adjustDrawingAnchor(anchor *xdrCellAnchor, dir adjustDirection, num, offset int) {
+	if anchor.EditAs == "absolute" {
+		return
+	}
+	adjust := func(from, to *int) {
+		origin := *from
+		*from = adjustAnchorIdx(*from, num, offset)
+		if to == nil {
+			return
+		}
+		if anchor.EditAs == "oneCell" {
+			*to += *from - origin
+			return
+		}
+		*to = adjustAnchorIdx(*to, num, offset)
+	}
+	if anchor.From != nil {
+		from, to := &anchor.From.Col, (*int)(nil)
+		if dir == rows {
+			from = &anchor.From.Row
+		}
+		if anchor.To != nil {
+			if to = &anchor.To.Col; dir == rows {
+				to = &anchor.To.Row
+			}
+		}
+		adjust(from, to)
+		return
+	}
+	// The anchor of the existing drawing object is in the raw XML content.
+	axis, points := "col", map[string]*int{}
+	if dir == rows {
+		axis = "row"
+	}
+	for _, matches := range drawingAnchorPattern.FindAllStringSubmatch(anchor.GraphicFrame, -1) {
+		for _, axisMatches := range drawingAnchorAxisPattern.FindAllStringSubmatch(matches[0], -1) {
+			if idx, err := strconv.Atoi(axisMatches[3]); err == nil && axisMatches[2] == axis {
+				points[matches[1]] = &idx
+			}
+		}
+	}
+	if points["from"] == nil {
+		return
+	}
+	adjust(points["from"], points["to"])
+	anchor.GraphicFrame = drawingAnchorPattern.ReplaceAllStringFunc(anchor.GraphicFrame, func(s string) string {
+		point := points[drawingAnchorPattern.FindStringSubmatch(s)[1]]
+		return drawingAnchorAxisPattern.ReplaceAllStringFunc(s, func(v string) string {
+			matches := drawingAnchorAxisPattern.FindStringSubmatch(v)
+			if point == nil || matches[2] != axis {
+				return v
+			}
+			return matches[1] + strconv.Itoa(*point) + matches[4]
+		})
+	})
+}
+
+var (
+	// pivotSourcePattern defined the pattern of the source range of the pivot
+	// cache definition.
+	pivotSourcePattern = regexp.MustCompile(`<(?:\w+:)?worksheetSource\b[^>]*>`)
+	// pivotLocationPattern defined the pattern of the location of the pivot
+	// table.
+	pivotLocationPattern = regexp.MustCompile(`<(?:\w+:)?location\b[^>]*>`)
+	// xmlRefAttrPattern defined the pattern of the ref attribute.
+	xmlRefAttrPattern = regexp.MustCompile(`(\sref=")([^"]*)(")`)
+	// xmlSheetAttrPattern defined the pattern of the sheet attribute.
+	xmlSheetAttrPattern = regexp.MustCompile(`\ssheet="([^"]*)"`)
+)
+
+// adjustPivotTables provides a function to update the source range of the
+// pivot caches which based on the worksheet, and the location of the pivot
+// tables in the worksheet when inserting or deleting rows or columns.
+func (f *File) adjustPivotTables(sheet string, dir adjustDirection, num, offset int) {
+	adjustRefAttr := func(tag string) string {
+		return xmlRefAttrPattern.ReplaceAllStringFunc(tag, func(s string) string {
+			matches := xmlRefAttrPattern.FindStringSubmatch(s)
+			if ref, ok := adjustRangeRef(matches[2], dir, num, offset); ok && ref != formulaErrorREF {
+				return matches[1] + ref + matches[3]
+			}
+			return s
+		})
+	}
+	f.Pkg.Range(func(k, v interface{}) bool {
+		path := k.(string)
+		if !strings.HasPrefix(path, "xl/pivotCache/pivotCacheDefinition") {
+			return true
+		}
+		f.Pkg.Store(path, pivotSourcePattern.ReplaceAllFunc(v.([]byte), func(tag []byte) []byte {
+			matches := xmlSheetAttrPattern.FindSubmatch(tag)
+			var name string
+			if matches == nil || xml.Unmarshal([]byte("<s>"+string(matches[1])+"</s>"), &name) != nil ||
+				!strings.EqualFold(name, trimSheetName(sheet)) {
+				return tag
+			}
+			return []byte(adjustRefAttr(string(tag)))
+		}))
+		return true
+	})
+	rels := "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
+	sheetRels := f.relsReader(rels)
+	if sheetRels == nil {
+		return
+	}
+	sheetRels.Lock()
+	defer sheetRels.Unlock()
+	for _, rel := range sheetRels.Relationships {
+		if rel.Type != SourceRelationshipPivotTable {
+			continue
+		}
+		pivotTableXML := strings.Replace(rel.Target, "..", "xl", -1)
+		if content, ok := f.Pkg.Load(pivotTableXML); ok {
+			f.Pkg.Store(pivotTableXML, pivotLocationPattern.ReplaceAllFunc(content.([]byte), func(tag []byte) []byte {
+				return []byte(adjustRefAttr(string(tag)))
+			}))
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package xlsx
 
 import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -103,3 +109,300 @@ func TestAdjustCalcChain(t *testing.T) {
 	f.CalcChain = nil
 	assert.NoError(t, f.InsertCol("Sheet1", "A"))
 }
+
+func TestAdjustFormulaRef(t *testing.T) {
+	for _, c := range []struct {
+		formula    string
+		dir        adjustDirection
+		num        int
+		offset     int
+		expected   string
+		formulaRef string
+	}{
+		{formula: "A1+B2", dir: rows, num: 2, offset: 1, expected: "A1+B3"},
+		{formula: "SUM($A$1:$B$5)", dir: rows, num: 3, offset: 2, expected: "SUM($A$1:$B$7)"},
+		{formula: "SUM(A:A,1:2)", dir: rows, num: 1, offset: 1, expected: "SUM(A:A,2:3)"},
+		{formula: "SUM(A:B,3:3)", dir: columns, num: 1, offset: 1, expected: "SUM(B:C,3:3)"},
+		{formula: "Sheet2!A2+'Sheet 1'!A2+'Sheet1'!A2", dir: rows, num: 1, offset: 1, expected: "Sheet2!A2+'Sheet 1'!A2+'Sheet1'!A3"},
+		{formula: "sheet1!C3*Sheet1!A1", dir: columns, num: 2, offset: -1, expected: "sheet1!B3*Sheet1!A1"},
+		{formula: "B2+Sheet1!B2+SUM(A1:C3)", dir: columns, num: 2, offset: -1, expected: "#REF!+Sheet1!#REF!+SUM(A1:B3)"},
+		{formula: "SUM(B2:C5)+SUM(A3:A4)", dir: rows, num: 3, offset: -2, expected: "SUM(B2:C3)+SUM(#REF!)"},
+		{formula: "SUM(A1:A1048576)+A1048576", dir: rows, num: 1, offset: 1, expected: "SUM(A2:A1048576)+#REF!"},
+		{formula: "\"A1\"&LOG10(A1)&Table1[[#This Row],[A1]]&[1]Sheet1!A1&TRUE", dir: rows, num: 1, offset: 1, expected: "\"A1\"&LOG10(A2)&Table1[[#This Row],[A1]]&[1]Sheet1!A1&TRUE"},
+		{formula: "SUM(Tbl5[Col])+Tbl5[[#Headers],[Col]]+Sheet1!Tbl5[Col]+'Sheet 1'!Tbl5[Col]", dir: rows, num: 1, offset: 1, expected: "SUM(Tbl5[Col])+Tbl5[[#Headers],[Col]]+Sheet1!Tbl5[Col]+'Sheet 1'!Tbl5[Col]"},
+		{formula: "SUM(Tbl5[Col])+Tbl5[[#Headers],[Col]]+Sheet1!Tbl5[Col]+A1", dir: columns, num: 1, offset: 1, expected: "SUM(Tbl5[Col])+Tbl5[[#Headers],[Col]]+Sheet1!Tbl5[Col]+B1"},
+		{formula: "#REF!+ XFE1+A0+ SUM (A1)+1E3+Sheet2:Sheet3!A1", dir: rows, num: 1, offset: 1, expected: "#REF!+ XFE1+A0+ SUM (A2)+1E3+Sheet2:Sheet3!A1"},
+	} {
+		assert.Equal(t, c.expected, adjustFormulaRef(c.formula, "Sheet1", "Sheet1", c.dir, c.num, c.offset), c.formula)
+	}
+	// Test adjust formula references in the formula of the other worksheet.
+	assert.Equal(t, "A1+Sheet1!A2", adjustFormulaRef("A1+Sheet1!A1", "Sheet2", "Sheet1", rows, 1, 1))
+	// Test adjust formula references in the formula of the defined name.
+	assert.Equal(t, "A1+Sheet1!$A$2", adjustFormulaRef("A1+Sheet1!$A$1", "", "Sheet1", rows, 1, 1))
+}
+
+func TestAdjustFormulas(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "SUM(B2:B4)+Sheet2!C3"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "Sheet1!B3*2+C3"))
+	formulaType, ref := STCellFormulaTypeShared, "C3:C5"
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C3", "B3*2", FormulaOpts{Type: &formulaType, Ref: &ref}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$B$2:$B$4"}))
+
+	assert.NoError(t, f.InsertRow("Sheet1", 3))
+	formula, err := f.GetCellFormula("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(B2:B5)+Sheet2!C3", formula)
+	formula, err = f.GetCellFormula("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet1!B4*2+C3", formula)
+	formula, err = f.GetCellFormula("Sheet1", "C4")
+	assert.NoError(t, err)
+	assert.Equal(t, "B4*2", formula)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "C4:C6", ws.SheetData.Row[3].C[2].F.Ref)
+	assert.Equal(t, "Sheet1!$B$2:$B$5", f.GetDefinedName()[0].RefersTo)
+
+	assert.NoError(t, f.RemoveCol("Sheet1", "B"))
+	formula, err = f.GetCellFormula("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(#REF!)+Sheet2!C3", formula)
+	formula, err = f.GetCellFormula("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet1!#REF!*2+C3", formula)
+	assert.Equal(t, "Sheet1!#REF!", f.GetDefinedName()[0].RefersTo)
+
+	assert.NoError(t, f.RemoveRow("Sheet2", 1))
+	assert.NoError(t, f.InsertCol("Sheet2", "A"))
+	formula, err = f.GetCellFormula("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(#REF!)+Sheet2!D2", formula)
+
+	// Test adjust formulas with the chart sheet.
+	assert.NoError(t, f.AddChartSheet("Chart1", `{"type":"col","series":[{"name":"Sheet1!$A$1","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`))
+	assert.NoError(t, f.InsertRow("Sheet1", 1))
+	formula, err = f.GetCellFormula("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(#REF!)+Sheet2!D2", formula)
+}
+
+func TestAdjustTables(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddTable("Sheet1", "B2", "E6", `{"table_name":"Table1"}`))
+	getTable := func() *xlsxTable {
+		content, ok := f.Pkg.Load("xl/tables/table1.xml")
+		if !ok {
+			return nil
+		}
+		table := new(xlsxTable)
+		assert.NoError(t, xml.Unmarshal(content.([]byte), table))
+		return table
+	}
+	assert.NoError(t, f.InsertRow("Sheet1", 1))
+	assert.Equal(t, "B3:E7", getTable().Ref)
+	// Test insert columns inside the table.
+	assert.NoError(t, f.InsertCol("Sheet1", "C"))
+	table := getTable()
+	assert.Equal(t, "B3:F7", table.Ref)
+	assert.Equal(t, "B3:F7", table.AutoFilter.Ref)
+	assert.Equal(t, 5, table.TableColumns.Count)
+	assert.Equal(t, &xlsxTableColumn{ID: 5, Name: "Column5"}, table.TableColumns.TableColumn[1])
+	cellValue, err := f.GetCellValue("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "Column5", cellValue)
+	// Test remove columns and rows inside the table.
+	assert.NoError(t, f.RemoveCol("Sheet1", "B"))
+	assert.NoError(t, f.RemoveRow("Sheet1", 4))
+	table = getTable()
+	assert.Equal(t, "B3:E6", table.Ref)
+	assert.Equal(t, []string{"Column5", "Column2", "Column3", "Column4"}, []string{
+		table.TableColumns.TableColumn[0].Name, table.TableColumns.TableColumn[1].Name,
+		table.TableColumns.TableColumn[2].Name, table.TableColumns.TableColumn[3].Name,
+	})
+	// Test remove all columns of the table.
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, f.RemoveCol("Sheet1", "B"))
+	}
+	assert.Nil(t, getTable())
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.TableParts)
+	assert.Empty(t, f.getSheetRelationshipsTargetByID("Sheet1", "rId1"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAdjustTables.xlsx")))
+
+	// Test adjust table with invalid table range and content.
+	f = NewFile()
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "B2", ""))
+	f.Pkg.Store("xl/tables/table1.xml", []byte(`<table ref="A:B1"></table>`))
+	assert.EqualError(t, f.InsertRow("Sheet1", 1), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	f.Pkg.Store("xl/tables/table1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.InsertRow("Sheet1", 1), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestAdjustDataValidations(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	dv := NewDataValidation(true)
+	dv.Sqref = "A2:A5 C3"
+	assert.NoError(t, dv.SetSqrefDropList("$E$1:$E$3", true))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	dv = NewDataValidation(true)
+	dv.Sqref = "B2"
+	assert.NoError(t, dv.SetSqrefDropList("Sheet1!$E$1:$E$3", true))
+	assert.NoError(t, f.AddDataValidation("Sheet2", dv))
+
+	getDataValidations := func(sheet string) []*DataValidation {
+		ws, err := f.workSheetReader(sheet)
+		assert.NoError(t, err)
+		if ws.DataValidations == nil {
+			return nil
+		}
+		return ws.DataValidations.DataValidation
+	}
+	assert.NoError(t, f.InsertRow("Sheet1", 2))
+	dvs := getDataValidations("Sheet1")
+	assert.Equal(t, "A3:A6 C4", dvs[0].Sqref)
+	assert.Equal(t, "<formula1>$E$1:$E$4</formula1>", dvs[0].Formula1)
+	assert.NoError(t, f.RemoveCol("Sheet1", "C"))
+	assert.NoError(t, f.InsertCol("Sheet1", "A"))
+	dvs = getDataValidations("Sheet1")
+	assert.Equal(t, "B3:B6", dvs[0].Sqref)
+	assert.Equal(t, "<formula1>$E$1:$E$4</formula1>", dvs[0].Formula1)
+	dvs = getDataValidations("Sheet2")
+	assert.Equal(t, "B2", dvs[0].Sqref)
+	assert.Equal(t, "<formula1>Sheet1!$E$1:$E$4</formula1>", dvs[0].Formula1)
+	// Test remove data validation when all cells of it were deleted.
+	assert.NoError(t, f.RemoveCol("Sheet1", "B"))
+	dvs = getDataValidations("Sheet1")
+	assert.Empty(t, dvs)
+}
+
+func TestAdjustConditionalFormats(t *testing.T) {
+	f := NewFile()
+	format, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511"}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10 C2", fmt.Sprintf(`[{"type":"formula","criteria":"=$B1>10","format":%d}]`, format)))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "D1:D5", `[{"type":"data_bar","bar_color":"#638EC6","bar_solid":true}]`))
+
+	assert.NoError(t, f.InsertCol("Sheet1", "B"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:A10 D2", ws.ConditionalFormatting[0].SQRef)
+	assert.Equal(t, []string{"=$C1>10"}, ws.ConditionalFormatting[0].CfRule[0].Formula)
+	assert.Equal(t, "E1:E5", ws.ConditionalFormatting[1].SQRef)
+	assert.Contains(t, ws.ExtLst.Ext, "<xm:sqref>E1:E5</xm:sqref>")
+	// Test remove conditional formats when all cells of it were deleted.
+	assert.NoError(t, f.RemoveCol("Sheet1", "E"))
+	assert.Len(t, ws.ConditionalFormatting, 1)
+	assert.Nil(t, ws.ExtLst)
+	assert.NoError(t, f.RemoveCol("Sheet1", "A"))
+	assert.Equal(t, "C2", ws.ConditionalFormatting[0].SQRef)
+	assert.Equal(t, []string{"=$B1>10"}, ws.ConditionalFormatting[0].CfRule[0].Formula)
+}
+
+func TestAdjustSparklines(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOption{
+		Location: []string{"A2", "A3"},
+		Range:    []string{"Sheet1!B2:J2", "Sheet1!B3:J3"},
+	}))
+	assert.NoError(t, f.InsertRow("Sheet1", 3))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Contains(t, ws.ExtLst.Ext, "<xm:f>Sheet1!B2:J2</xm:f><xm:sqref>A2</xm:sqref>")
+	assert.Contains(t, ws.ExtLst.Ext, "<xm:f>Sheet1!B4:J4</xm:f><xm:sqref>A4</xm:sqref>")
+	assert.NoError(t, f.RemoveRow("Sheet1", 2))
+	assert.NotContains(t, ws.ExtLst.Ext, "<xm:sqref>A2</xm:sqref>")
+	assert.Contains(t, ws.ExtLst.Ext, "<xm:f>Sheet1!B3:J3</xm:f><xm:sqref>A3</xm:sqref>")
+}
+
+func TestAdjustComments(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddComment("Sheet1", "B2", "Excelize", "Comment B2"))
+	assert.NoError(t, f.AddComment("Sheet1", "C3", "Excelize", "Comment C3"))
+	assert.NoError(t, f.InsertRow("Sheet1", 1))
+	assert.NoError(t, f.InsertCol("Sheet1", "C"))
+	comments := f.GetComments()["Sheet1"]
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "B3", comments[0].Ref)
+	assert.Equal(t, "D4", comments[1].Ref)
+	vml := f.VMLDrawing["xl/drawings/vmlDrawing1.vml"]
+	assert.Contains(t, vml.Shape[0].Val, "<x:Anchor>3, 23, 3, 0, 5, 19, 5, 5</x:Anchor>")
+	assert.Contains(t, vml.Shape[1].Val, "<x:Row>3</x:Row><x:Column>3</x:Column>")
+	// Test remove comments of the deleted cells.
+	assert.NoError(t, f.RemoveCol("Sheet1", "B"))
+	comments = f.GetComments()["Sheet1"]
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "C4", comments[0].Ref)
+	assert.Len(t, vml.Shape, 1)
+	assert.Contains(t, vml.Shape[0].Val, "<x:Row>3</x:Row><x:Column>2</x:Column>")
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAdjustComments.xlsx")))
+
+	// Test adjust comments of the opened workbook.
+	f, err := OpenFile(filepath.Join("test", "TestAdjustComments.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.RemoveRow("Sheet1", 4))
+	assert.Empty(t, f.GetComments()["Sheet1"])
+	content, ok := f.Pkg.Load("xl/drawings/vmlDrawing1.vml")
+	assert.True(t, ok)
+	assert.NotContains(t, string(content.([]byte)), "<v:shape ")
+	assert.NoError(t, f.Close())
+}
+
+func TestAdjustDrawings(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddPicture("Sheet1", "B3", filepath.Join("test", "images", "excel.png"), `{"positioning":"oneCell"}`))
+	assert.NoError(t, f.AddChart("Sheet1", "E5", `{"type":"col","series":[{"name":"Sheet1!$A$1","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`))
+	wsDr, _ := f.drawingParser("xl/drawings/drawing1.xml")
+	from, to := *wsDr.TwoCellAnchor[0].From, *wsDr.TwoCellAnchor[0].To
+	assert.NoError(t, f.InsertRow("Sheet1", 2))
+	assert.NoError(t, f.InsertCol("Sheet1", "A"))
+	assert.Equal(t, from.Row+1, wsDr.TwoCellAnchor[0].From.Row)
+	assert.Equal(t, from.Col+1, wsDr.TwoCellAnchor[0].From.Col)
+	assert.Equal(t, to.Row+1, wsDr.TwoCellAnchor[0].To.Row)
+	assert.Equal(t, to.Col+1, wsDr.TwoCellAnchor[0].To.Col)
+	// Test remove rows which the chart anchored, the chart will be moved to
+	// the next row after the deleted rows.
+	from = *wsDr.TwoCellAnchor[1].From
+	assert.NoError(t, f.RemoveRow("Sheet1", from.Row+1))
+	assert.Equal(t, from.Row, wsDr.TwoCellAnchor[1].From.Row)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAdjustDrawings.xlsx")))
+
+	// Test adjust drawings of the opened workbook.
+	f, err := OpenFile(filepath.Join("test", "TestAdjustDrawings.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.InsertRow("Sheet1", 1))
+	file, raw, err := f.GetPicture("Sheet1", "C5")
+	assert.NoError(t, err)
+	assert.Equal(t, "image1.png", file)
+	assert.NotEmpty(t, raw)
+	assert.NoError(t, f.Close())
+
+	// Test adjust drawing anchor with absolute positioning.
+	anchor := &xdrCellAnchor{EditAs: "absolute", From: &xlsxFrom{Row: 5}}
+	adjustDrawingAnchor(anchor, rows, 1, 1)
+	assert.Equal(t, 5, anchor.From.Row)
+}
+
+func TestAdjustPivotTables(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 5; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(row), &[]interface{}{"Month", "Sales"}))
+	}
+	assert.NoError(t, f.AddPivotTable(&PivotTableOption{
+		DataRange:       "Sheet1!$A$1:$B$5",
+		PivotTableRange: "Sheet1!$E$2:$G$10",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Data:            []PivotTableField{{Data: "Sales", Subtotal: "Sum"}},
+	}))
+	assert.NoError(t, f.InsertRow("Sheet1", 1))
+	assert.NoError(t, f.RemoveCol("Sheet1", "C"))
+	content, ok := f.Pkg.Load("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.True(t, ok)
+	assert.True(t, bytes.Contains(content.([]byte), []byte(`<worksheetSource ref="A2:B6" sheet="Sheet1">`)))
+	content, ok = f.Pkg.Load("xl/pivotTables/pivotTable1.xml")
+	assert.True(t, ok)
+	assert.True(t, strings.Contains(string(content.([]byte)), `<location ref="D3:F11"`))
+}
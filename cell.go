@@ -709,15 +709,20 @@ func (f *File) GetCellRichText(sheet, cell string) (runs []RichTextRun, err erro
 	if err != nil {
 		return
 	}
-	siIdx, err := strconv.Atoi(cellData.V)
-	if nil != err {
-		return
-	}
-	sst := f.sharedStringsReader()
-	if len(sst.SI) <= siIdx || siIdx < 0 {
-		return
+	var si xlsxSI
+	if cellData.T == "inlineStr" && cellData.IS != nil {
+		si = *cellData.IS
+	} else {
+		siIdx, err := strconv.Atoi(cellData.V)
+		if nil != err {
+			return runs, err
+		}
+		sst := f.sharedStringsReader()
+		if len(sst.SI) <= siIdx || siIdx < 0 {
+			return runs, err
+		}
+		si = sst.SI[siIdx]
 	}
-	si := sst.SI[siIdx]
 	for _, v := range si.R {
 		run := RichTextRun{
 			Text: v.T.Val,
@@ -863,14 +868,34 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 		return err
 	}
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
-	si := xlsxSI{}
+	si, err := newRichTextSI(runs)
+	if err != nil {
+		return err
+	}
 	sst := f.sharedStringsReader()
+	for idx, strItem := range sst.SI {
+		if reflect.DeepEqual(strItem, si) {
+			cellData.T, cellData.V = "s", strconv.Itoa(idx)
+			return err
+		}
+	}
+	sst.SI = append(sst.SI, si)
+	sst.Count++
+	sst.UniqueCount++
+	cellData.T, cellData.V = "s", strconv.Itoa(len(sst.SI)-1)
+	return err
+}
+
+// newRichTextSI provides a function to create the string item by given rich
+// text runs.
+func newRichTextSI(runs []RichTextRun) (xlsxSI, error) {
+	si := xlsxSI{}
 	textRuns := []xlsxR{}
 	totalCellChars := 0
 	for _, textRun := range runs {
 		totalCellChars += len(textRun.Text)
 		if totalCellChars > TotalCellChars {
-			return ErrCellCharsLength
+			return si, ErrCellCharsLength
 		}
 		run := xlsxR{T: &xlsxT{}}
 		_, run.T.Val, run.T.Space = setCellStr(textRun.Text)
@@ -904,17 +929,7 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 		textRuns = append(textRuns, run)
 	}
 	si.R = textRuns
-	for idx, strItem := range sst.SI {
-		if reflect.DeepEqual(strItem, si) {
-			cellData.T, cellData.V = "s", strconv.Itoa(idx)
-			return err
-		}
-	}
-	sst.SI = append(sst.SI, si)
-	sst.Count++
-	sst.UniqueCount++
-	cellData.T, cellData.V = "s", strconv.Itoa(len(sst.SI)-1)
-	return err
+	return si, nil
 }
 
 // SetSheetRow writes an array to row by given worksheet name, starting
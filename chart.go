@@ -1,7 +1,6 @@
 package xlsx
 
 import (
-	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"strconv"
@@ -248,6 +247,7 @@ var (
 		"top":       "t",
 		"top_right": "tr",
 	}
+	chartMarkerSymbols         = []string{"auto", "circle", "dash", "diamond", "dot", "none", "picture", "plus", "square", "star", "triangle", "x"}
 	chartValAxNumFmtFormatCode = map[string]string{
 		Area:                        "General",
 		AreaStacked:                 "General",
@@ -458,34 +458,65 @@ var (
 	}
 )
 
-// parseFormatChartSet provides a function to parse the format settings of the
-// chart with default value.
-func parseFormatChartSet(formatSet string) (*formatChart, error) {
-	format := formatChart{
-		Dimension: formatChartDimension{
-			Width:  480,
-			Height: 290,
-		},
-		Format: formatPicture{
-			FPrintsWithSheet: true,
-			FLocksWithSheet:  false,
-			NoChangeAspect:   false,
-			OffsetX:          0,
-			OffsetY:          0,
-			XScale:           1.0,
-			YScale:           1.0,
-		},
-		Legend: formatChartLegend{
-			Position:      "bottom",
-			ShowLegendKey: false,
-		},
-		Title: formatChartTitle{
-			Name: " ",
-		},
-		VaryColors:   true,
-		ShowBlanksAs: "gap",
+// parseChartOptions provides a function to validate the format settings of
+// the chart and fill the unset settings with default value.
+func parseChartOptions(opts *ChartOptions) (*ChartOptions, error) {
+	if opts == nil {
+		return nil, ErrParameterRequired
+	}
+	format := *opts
+	if _, ok := chartValAxNumFmtFormatCode[format.Type]; !ok {
+		return &format, newUnsupportChartType(format.Type)
+	}
+	pic, err := parsePictureOptions(&format.Format)
+	if err != nil {
+		return &format, err
+	}
+	format.Format = *pic
+	if format.Dimension.Width < 0 {
+		return &format, newNegativeOptionError("chart width", float64(format.Dimension.Width))
+	}
+	if format.Dimension.Height < 0 {
+		return &format, newNegativeOptionError("chart height", float64(format.Dimension.Height))
+	}
+	if format.Dimension.Width == 0 {
+		format.Dimension.Width = 480
+	}
+	if format.Dimension.Height == 0 {
+		format.Dimension.Height = 290
+	}
+	if format.Legend.Position == "" {
+		format.Legend.Position = "bottom"
+	}
+	if _, ok := chartLegendPosition[format.Legend.Position]; !ok {
+		return &format, newUnsupportedOptionError("legend position", format.Legend.Position)
+	}
+	if format.Title.Name == "" {
+		format.Title.Name = " "
+	}
+	if format.VaryColors == nil {
+		format.VaryColors = boolPtr(true)
+	}
+	if format.ShowBlanksAs == "" {
+		format.ShowBlanksAs = "gap"
+	}
+	if inStrSlice([]string{"gap", "span", "zero"}, format.ShowBlanksAs) == -1 {
+		return &format, newUnsupportedOptionError("show_blanks_as", format.ShowBlanksAs)
+	}
+	if logBase := format.YAxis.LogBase; logBase != 0 && (logBase < 2 || logBase > 1000) {
+		return &format, newOptionRangeError("y_axis logbase", logBase, 2, 1000)
+	}
+	for _, series := range format.Series {
+		if series.Line.Width < 0 {
+			return &format, newNegativeOptionError("series line width", series.Line.Width)
+		}
+		if symbol := series.Marker.Symbol; symbol != "" && inStrSlice(chartMarkerSymbols, symbol) == -1 {
+			return &format, newUnsupportedOptionError("series marker symbol", symbol)
+		}
+		if size := series.Marker.Size; size != 0 && (size < 2 || size > 72) {
+			return &format, newOptionRangeError("series marker size", float64(size), 2, 72)
+		}
 	}
-	err := json.Unmarshal([]byte(formatSet), &format)
 	return &format, err
 }
 
@@ -877,12 +908,39 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //    }
 //
 func (f *File) AddChart(sheet, cell, format string, combo ...string) error {
+	formatSet, comboCharts, err := parseFormatChartSets(format, combo)
+	if err != nil {
+		return err
+	}
+	return f.AddChartWithOptions(sheet, cell, formatSet, comboCharts...)
+}
+
+// AddChartWithOptions provides the method to add chart in a sheet by given
+// worksheet name, cell reference, chart options and the optional combo chart
+// options. The settings are the same as the AddChart function, the zero
+// value of the options will be filled with default value. For example, create
+// a clustered column chart with data Sheet1!$A$1:$D$4:
+//
+//    err := f.AddChartWithOptions("Sheet1", "E1", &xlsx.ChartOptions{
+//        Type: xlsx.Col,
+//        Series: []xlsx.ChartSeries{
+//            {
+//                Name:       "Sheet1!$A$2",
+//                Categories: "Sheet1!$B$1:$D$1",
+//                Values:     "Sheet1!$B$2:$D$2",
+//            },
+//        },
+//        Title:  xlsx.ChartTitle{Name: "Fruit Column Chart"},
+//        Legend: xlsx.ChartLegend{Position: "right"},
+//    })
+//
+func (f *File) AddChartWithOptions(sheet, cell string, opts *ChartOptions, combo ...*ChartOptions) error {
 	// Read sheet data.
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
-	formatSet, comboCharts, err := f.getFormatChart(format, combo)
+	formatSet, comboCharts, err := f.getFormatChart(opts, combo)
 	if err != nil {
 		return err
 	}
@@ -909,11 +967,22 @@ func (f *File) AddChart(sheet, cell, format string, combo ...string) error {
 // and properties set. In Excel a chartsheet is a worksheet that only contains
 // a chart.
 func (f *File) AddChartSheet(sheet, format string, combo ...string) error {
+	formatSet, comboCharts, err := parseFormatChartSets(format, combo)
+	if err != nil {
+		return err
+	}
+	return f.AddChartSheetWithOptions(sheet, formatSet, comboCharts...)
+}
+
+// AddChartSheetWithOptions provides the method to create a chartsheet by
+// given worksheet name, chart options and the optional combo chart options.
+// The settings are the same as the AddChartSheet function.
+func (f *File) AddChartSheetWithOptions(sheet string, opts *ChartOptions, combo ...*ChartOptions) error {
 	// Check if the worksheet already exists
 	if f.GetSheetIndex(sheet) != -1 {
 		return ErrExistsWorksheet
 	}
-	formatSet, comboCharts, err := f.getFormatChart(format, combo)
+	formatSet, comboCharts, err := f.getFormatChart(opts, combo)
 	if err != nil {
 		return err
 	}
@@ -955,27 +1024,39 @@ func (f *File) AddChartSheet(sheet, format string, combo ...string) error {
 	return err
 }
 
+// parseFormatChartSets provides a function to decode the JSON format
+// settings of the chart and the combo charts.
+func parseFormatChartSets(format string, combo []string) (*ChartOptions, []*ChartOptions, error) {
+	var formatSet ChartOptions
+	comboCharts := []*ChartOptions{}
+	if err := unmarshalFormatSet([]byte(format), &formatSet); err != nil {
+		return &formatSet, comboCharts, err
+	}
+	for _, comboFormat := range combo {
+		var comboChart ChartOptions
+		if err := unmarshalFormatSet([]byte(comboFormat), &comboChart); err != nil {
+			return &formatSet, comboCharts, err
+		}
+		comboCharts = append(comboCharts, &comboChart)
+	}
+	return &formatSet, comboCharts, nil
+}
+
 // getFormatChart provides a function to check format set of the chart and
 // create chart format.
-func (f *File) getFormatChart(format string, combo []string) (*formatChart, []*formatChart, error) {
-	comboCharts := []*formatChart{}
-	formatSet, err := parseFormatChartSet(format)
+func (f *File) getFormatChart(opts *ChartOptions, combo []*ChartOptions) (*ChartOptions, []*ChartOptions, error) {
+	comboCharts := []*ChartOptions{}
+	formatSet, err := parseChartOptions(opts)
 	if err != nil {
 		return formatSet, comboCharts, err
 	}
 	for _, comboFormat := range combo {
-		comboChart, err := parseFormatChartSet(comboFormat)
+		comboChart, err := parseChartOptions(comboFormat)
 		if err != nil {
 			return formatSet, comboCharts, err
 		}
-		if _, ok := chartValAxNumFmtFormatCode[comboChart.Type]; !ok {
-			return formatSet, comboCharts, newUnsupportChartType(comboChart.Type)
-		}
 		comboCharts = append(comboCharts, comboChart)
 	}
-	if _, ok := chartValAxNumFmtFormatCode[formatSet.Type]; !ok {
-		return formatSet, comboCharts, newUnsupportChartType(formatSet.Type)
-	}
 	return formatSet, comboCharts, err
 }
 
@@ -242,6 +242,60 @@ func TestAddChartSheet(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartSheet.xlsx")))
 }
 
+func TestAddChartWithOptions(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{nil, "Apple", "Orange", "Pear"}, {"Small", 2, 3, 3}, {"Normal", 5, 2, 4}} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	series := []ChartSeries{
+		{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2"},
+		{Name: "Sheet1!$A$3", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$3:$D$3", Marker: ChartMarker{Symbol: "square", Size: 8}},
+	}
+	opts := &ChartOptions{Type: Col, Series: series, Title: ChartTitle{Name: "Fruit"}}
+	assert.NoError(t, f.AddChartWithOptions("Sheet1", "E1", opts, &ChartOptions{Type: Line, Series: series[1:]}))
+	assert.NoError(t, f.AddChartSheetWithOptions("Chart1", opts))
+	// Test the options of the caller not be changed by the default value.
+	assert.Nil(t, opts.VaryColors)
+	assert.Equal(t, ChartDimension{}, opts.Dimension)
+	formatSet, err := parseChartOptions(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, ChartDimension{Width: 480, Height: 290}, formatSet.Dimension)
+	assert.Equal(t, "bottom", formatSet.Legend.Position)
+	assert.Equal(t, "gap", formatSet.ShowBlanksAs)
+	assert.True(t, *formatSet.VaryColors)
+	assert.True(t, *formatSet.Format.FPrintsWithSheet)
+	assert.Equal(t, 1.0, formatSet.Format.XScale)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartWithOptions.xlsx")))
+
+	// Test add chart with invalid options.
+	for _, c := range []struct {
+		opts *ChartOptions
+		err  string
+	}{
+		{nil, ErrParameterRequired.Error()},
+		{&ChartOptions{Type: "unknown"}, "unsupported chart type unknown"},
+		{&ChartOptions{Type: Col, Dimension: ChartDimension{Width: -1}}, "invalid chart width -1, negative values are not supported"},
+		{&ChartOptions{Type: Col, Dimension: ChartDimension{Height: -1}}, "invalid chart height -1, negative values are not supported"},
+		{&ChartOptions{Type: Col, Legend: ChartLegend{Position: "middle"}}, `unsupported legend position "middle"`},
+		{&ChartOptions{Type: Col, ShowBlanksAs: "blank"}, `unsupported show_blanks_as "blank"`},
+		{&ChartOptions{Type: Col, Format: PictureOptions{XScale: -1}}, "invalid x_scale -1, negative values are not supported"},
+		{&ChartOptions{Type: Col, Series: []ChartSeries{{Line: ChartLine{Width: -1}}}}, "invalid series line width -1, negative values are not supported"},
+		{&ChartOptions{Type: Col, Series: []ChartSeries{{Marker: ChartMarker{Symbol: "cross"}}}}, `unsupported series marker symbol "cross"`},
+		{&ChartOptions{Type: Col, Series: []ChartSeries{{Marker: ChartMarker{Size: 73}}}}, "series marker size 73 is out of range, must be between 2 and 72"},
+	} {
+		assert.EqualError(t, f.AddChartWithOptions("Sheet1", "E20", c.opts), c.err)
+	}
+	assert.EqualError(t, f.AddChartWithOptions("Sheet1", "E20", opts, &ChartOptions{Type: "unknown"}), "unsupported chart type unknown")
+	assert.EqualError(t, f.AddChartWithOptions("SheetN", "E20", opts), "sheet SheetN is not exist")
+	assert.EqualError(t, f.AddChartSheetWithOptions("Chart2", &ChartOptions{}), "unsupported chart type ")
+	// Test add chart with unknown field and invalid combo chart in JSON.
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", `{"type":"col","titel":{"name":"Fruit"}}`), `json: unknown field "titel"`)
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", `{"type":"col"}`, `{`), "unexpected end of JSON input")
+	assert.EqualError(t, f.AddChartSheet("Chart2", ""), "unexpected end of JSON input")
+}
+
 func TestDeleteChart(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	assert.NoError(t, err)
@@ -298,21 +352,21 @@ func TestChartWithLogarithmicBase(t *testing.T) {
 			`"series":[{"name":"value","categories":"Sheet1!$A$1:$A$19","values":"Sheet1!$B$1:$B$10"}],`+
 			`"y_axis":{"logbase":10.5},`+
 			`"title":{"name":"Line chart with log 10 scaling"}}`))
-	assert.NoError(t, f.AddChart(sheet1, "A25",
+	assert.EqualError(t, f.AddChart(sheet1, "A25",
 		`{"type":"line","dimension":{"width":320, "height":240},`+
 			`"series":[{"name":"value","categories":"Sheet1!$A$1:$A$19","values":"Sheet1!$B$1:$B$10"}],`+
 			`"y_axis":{"logbase":1.9},`+
-			`"title":{"name":"Line chart with log 1.9 scaling"}}`))
+			`"title":{"name":"Line chart with log 1.9 scaling"}}`), "y_axis logbase 1.9 is out of range, must be between 2 and 1000")
 	assert.NoError(t, f.AddChart(sheet1, "F25",
 		`{"type":"line","dimension":{"width":320, "height":240},`+
 			`"series":[{"name":"value","categories":"Sheet1!$A$1:$A$19","values":"Sheet1!$B$1:$B$10"}],`+
 			`"y_axis":{"logbase":2},`+
 			`"title":{"name":"Line chart with log 2 scaling"}}`))
-	assert.NoError(t, f.AddChart(sheet1, "K25",
+	assert.EqualError(t, f.AddChart(sheet1, "K25",
 		`{"type":"line","dimension":{"width":320, "height":240},`+
 			`"series":[{"name":"value","categories":"Sheet1!$A$1:$A$19","values":"Sheet1!$B$1:$B$10"}],`+
 			`"y_axis":{"logbase":1000.1},`+
-			`"title":{"name":"Line chart with log 1000.1 scaling"}}`))
+			`"title":{"name":"Line chart with log 1000.1 scaling"}}`), "y_axis logbase 1000.1 is out of range, must be between 2 and 1000")
 	assert.NoError(t, f.AddChart(sheet1, "P25",
 		`{"type":"line","dimension":{"width":320, "height":240},`+
 			`"series":[{"name":"value","categories":"Sheet1!$A$1:$A$19","values":"Sheet1!$B$1:$B$10"}],`+
@@ -331,7 +385,7 @@ func TestChartWithLogarithmicBase(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Check the number of charts
-	expectedChartsCount := 6
+	expectedChartsCount := 4
 	chartsNum := newFile.countCharts()
 	if !assert.Equal(t, expectedChartsCount, chartsNum,
 		"Expected %d charts, actual %d", expectedChartsCount, chartsNum) {
@@ -341,7 +395,7 @@ func TestChartWithLogarithmicBase(t *testing.T) {
 	chartSpaces := make([]xlsxChartSpace, expectedChartsCount)
 	type xmlChartContent []byte
 	xmlCharts := make([]xmlChartContent, expectedChartsCount)
-	expectedChartsLogBase := []float64{0, 10.5, 0, 2, 0, 1000}
+	expectedChartsLogBase := []float64{0, 10.5, 2, 1000}
 	var (
 		drawingML interface{}
 		ok        bool
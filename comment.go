@@ -17,14 +17,11 @@ import (
 func (f *File) GetComments() (comments map[string][]Comment) {
 	comments = map[string][]Comment{}
 	for n, path := range f.sheetMap {
-		target := f.getSheetComments(filepath.Base(path))
-		if target == "" {
+		commentsXML := f.getSheetCommentsXML(path)
+		if commentsXML == "" {
 			continue
 		}
-		if !strings.HasPrefix(target, "/") {
-			target = "xl" + strings.TrimPrefix(target, "..")
-		}
-		if d := f.commentsReader(strings.TrimPrefix(target, "/")); d != nil {
+		if d := f.commentsReader(commentsXML); d != nil {
 			sheetComments := []Comment{}
 			for _, comment := range d.CommentList.Comment {
 				sheetComment := Comment{}
@@ -65,6 +62,20 @@ func (f *File) getSheetComments(sheetFile string) string {
 	return ""
 }
 
+// getSheetCommentsXML provides a function to get the comments part path in
+// the package by given worksheet file path, it returns an empty string if the
+// worksheet has no comments.
+func (f *File) getSheetCommentsXML(sheetXML string) string {
+	target := f.getSheetComments(filepath.Base(sheetXML))
+	if target == "" {
+		return target
+	}
+	if !strings.HasPrefix(target, "/") {
+		target = "xl" + strings.TrimPrefix(target, "..")
+	}
+	return strings.TrimPrefix(target, "/")
+}
+
 // AddComment provides the method to add comment in a sheet by given worksheet
 // index, cell and format set (such as author and text). Note that the max
 // author length is 255 and the max text length is 32512. For example, add a
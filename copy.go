@@ -0,0 +1,755 @@
+package xlsx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mohae/deepcopy"
+)
+
+var (
+	// partNamePattern defined the pattern of the part name in the package
+	// with the sequence number, such as xl/drawings/drawing1.xml.
+	partNamePattern = regexp.MustCompile(`^(.+?)(\d*)(\.[^./]+)$`)
+	// vmlIDMapPattern defined the pattern of the shape ID block in the VML
+	// drawing part.
+	vmlIDMapPattern = regexp.MustCompile(`(<o:idmap\b[^>]*\bdata=")[^"]*(")`)
+	// vmlShapeIDPattern defined the pattern of the shape ID in the VML
+	// drawing part.
+	vmlShapeIDPattern = regexp.MustCompile(`(\bid="_x0000_s)(\d+)(")`)
+)
+
+// sheetCopier directly maps the state of copying a worksheet with the parts
+// related to the worksheet from the source workbook to the destination
+// workbook, the source and the destination workbook can be the same.
+type sheetCopier struct {
+	src, dst           *File
+	srcSheet, dstSheet string
+	names              map[string]string
+	parts              map[string]string
+	tables             map[string]string
+	tableNames         map[string]bool
+	tableID            int
+	sst, xfs, dxfs     map[int]int
+	styleXfs           map[int]int
+}
+
+// newSheetCopier returns a worksheet copier by given source and destination
+// workbook.
+func newSheetCopier(src, dst *File) *sheetCopier {
+	return &sheetCopier{
+		src: src, dst: dst,
+		names: map[string]string{}, parts: map[string]string{}, tables: map[string]string{},
+		sst: map[int]int{}, xfs: map[int]int{}, dxfs: map[int]int{}, styleXfs: map[int]int{},
+	}
+}
+
+// CopySheetFrom provides a function to copy the worksheet from another
+// workbook by given source workbook, source worksheet name and the name of
+// the new worksheet which will be created in the workbook. The cell values,
+// shared strings, merged cells, column and row formats, drawings with
+// pictures and charts, comments, tables, data validations and conditional
+// formats of the source worksheet will be copied, and the styles will be
+// remapped into the stylesheet of the workbook. The copied table will be
+// renamed with a numeric suffix if the table name already exists in the
+// workbook. Note that the pivot tables in the source worksheet will not be
+// copied. For example, copy the worksheet named Sheet1 in the workbook src
+// into the new worksheet named Sheet2:
+//
+//    src, err := xlsx.OpenFile("Book1.xlsx")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    err = f.CopySheetFrom(src, "Sheet1", "Sheet2")
+//
+func (f *File) CopySheetFrom(src *File, srcSheet, dstSheet string) error {
+	if src == nil {
+		return ErrParameterRequired
+	}
+	ws, err := src.workSheetReader(srcSheet)
+	if err != nil {
+		return err
+	}
+	if f.GetSheetIndex(dstSheet) != -1 {
+		return ErrExistsWorksheet
+	}
+	f.NewSheet(dstSheet)
+	return newSheetCopier(src, f).copySheet(srcSheet, dstSheet, ws)
+}
+
+// copySheet provides a function to copy the given source worksheet with the
+// related parts into the destination worksheet.
+func (c *sheetCopier) copySheet(srcSheet, dstSheet string, sheet *xlsxWorksheet) error {
+	c.srcSheet, c.dstSheet = srcSheet, dstSheet
+	srcPath := c.src.sheetMap[trimSheetName(srcSheet)]
+	dstPath, ok := c.dst.sheetMap[trimSheetName(dstSheet)]
+	if !ok {
+		return fmt.Errorf("sheet %s is not exist", dstSheet)
+	}
+	ws := deepcopy.Copy(sheet).(*xlsxWorksheet)
+	if ws.SheetViews != nil && len(ws.SheetViews.SheetView) > 0 {
+		ws.SheetViews.SheetView[0].TabSelected = false
+	}
+	c.copyStyles(ws)
+	c.pairComments(srcPath)
+	c.copyRels(srcPath, dstPath)
+	if !strings.EqualFold(srcSheet, dstSheet) {
+		replaceWorksheetFormulas(ws, func(formula string) string {
+			return renameSheetInFormula(formula, srcSheet, dstSheet)
+		})
+	}
+	if len(c.tables) > 0 {
+		replaceWorksheetFormulas(ws, func(formula string) string {
+			return renameTableInFormula(formula, c.tables)
+		})
+	}
+	c.dst.Sheet.Store(dstPath, ws)
+	c.dst.xmlAttr[dstPath] = append([]xml.Attr{}, c.src.xmlAttr[srcPath]...)
+	c.copyDefinedNames()
+	return nil
+}
+
+// copyDefinedNames provides a function to copy the defined names which
+// scoped in the source worksheet into the destination worksheet, the defined
+// names with the same name in the destination worksheet will be replaced.
+func (c *sheetCopier) copyDefinedNames() {
+	srcWb, dstWb := c.src.workbookReader(), c.dst.workbookReader()
+	srcIdx, dstIdx := c.src.GetSheetIndex(c.srcSheet), c.dst.GetSheetIndex(c.dstSheet)
+	if srcWb.DefinedNames == nil || srcIdx == -1 || dstIdx == -1 {
+		return
+	}
+	var definedNames []xlsxDefinedName
+	for _, dn := range srcWb.DefinedNames.DefinedName {
+		if dn.LocalSheetID == nil || *dn.LocalSheetID != srcIdx {
+			continue
+		}
+		dn.LocalSheetID = intPtr(dstIdx)
+		if !strings.EqualFold(c.srcSheet, c.dstSheet) {
+			dn.Data = renameSheetInFormula(dn.Data, c.srcSheet, c.dstSheet)
+		}
+		definedNames = append(definedNames, dn)
+	}
+	if len(definedNames) == 0 {
+		return
+	}
+	if dstWb.DefinedNames == nil {
+		dstWb.DefinedNames = &xlsxDefinedNames{}
+	}
+	for idx := 0; idx < len(dstWb.DefinedNames.DefinedName); idx++ {
+		dn := dstWb.DefinedNames.DefinedName[idx]
+		if dn.LocalSheetID == nil || *dn.LocalSheetID != dstIdx {
+			continue
+		}
+		for _, v := range definedNames {
+			if strings.EqualFold(v.Name, dn.Name) {
+				dstWb.DefinedNames.DefinedName = append(dstWb.DefinedNames.DefinedName[:idx], dstWb.DefinedNames.DefinedName[idx+1:]...)
+				idx--
+				break
+			}
+		}
+	}
+	dstWb.DefinedNames.DefinedName = append(dstWb.DefinedNames.DefinedName, definedNames...)
+}
+
+// pairComments provides a function to assign the same sequence number for
+// the comments and VML drawing parts of the worksheet, the comments of the
+// worksheet was located by the number of the VML drawing part.
+func (c *sheetCopier) pairComments(srcPath string) {
+	srcRels := c.src.relsReader(getPartRelsPath(srcPath))
+	if srcRels == nil {
+		return
+	}
+	var comments, vml string
+	srcRels.Lock()
+	for _, rel := range srcRels.Relationships {
+		switch rel.Type {
+		case SourceRelationshipComments:
+			comments = resolvePartTarget(srcPath, rel.Target)
+		case SourceRelationshipDrawingVML:
+			vml = resolvePartTarget(srcPath, rel.Target)
+		}
+	}
+	srcRels.Unlock()
+	if comments == "" || vml == "" {
+		return
+	}
+	num := c.dst.nextPartNumber("xl/comments", ".xml")
+	if n := c.dst.nextPartNumber("xl/drawings/vmlDrawing", ".vml"); n > num {
+		num = n
+	}
+	c.names[comments] = "xl/comments" + strconv.Itoa(num) + ".xml"
+	c.names[vml] = "xl/drawings/vmlDrawing" + strconv.Itoa(num) + ".vml"
+}
+
+// copyRels provides a function to copy the relationships and the target
+// parts of the given source part for the destination part. The IDs of the
+// relationships will be kept, and the relationships of the pivot tables
+// will be skipped. The existing relationships of the destination part will
+// be replaced.
+func (c *sheetCopier) copyRels(srcPath, dstPath string) {
+	srcRels := c.src.relsReader(getPartRelsPath(srcPath))
+	if srcRels == nil {
+		c.dst.Relationships.Delete(getPartRelsPath(dstPath))
+		c.dst.Pkg.Delete(getPartRelsPath(dstPath))
+		return
+	}
+	srcRels.Lock()
+	relationships := append([]xlsxRelationship{}, srcRels.Relationships...)
+	srcRels.Unlock()
+	rels := &xlsxRelationships{}
+	for _, rel := range relationships {
+		if rel.Type == SourceRelationshipPivotTable {
+			continue
+		}
+		if rel.TargetMode != "External" {
+			if target := c.copyPart(resolvePartTarget(srcPath, rel.Target)); target != "" {
+				rel.Target = getPartTarget(rel.Target, target)
+			}
+		}
+		rels.Relationships = append(rels.Relationships, rel)
+	}
+	c.dst.Relationships.Store(getPartRelsPath(dstPath), rels)
+}
+
+// copyPart provides a function to copy the part in the package of the source
+// workbook into the destination workbook with a new sequence number by given
+// part name, and returns the name of the copied part. The relationships of
+// the part will be copied recursively, and the images will only be stored
+// once in the destination workbook.
+func (c *sheetCopier) copyPart(srcPath string) string {
+	if dstPath, ok := c.parts[srcPath]; ok {
+		return dstPath
+	}
+	content := c.src.readPartBytes(srcPath)
+	if len(content) == 0 {
+		return ""
+	}
+	dstPath, ok := c.names[srcPath]
+	if !ok && strings.HasPrefix(srcPath, "xl/media/") {
+		dstPath = c.dst.addMedia(content, path.Ext(srcPath))
+	}
+	if dstPath == "" {
+		prefix, ext := srcPath, ""
+		if matches := partNamePattern.FindStringSubmatch(srcPath); matches != nil {
+			prefix, ext = matches[1], matches[3]
+		}
+		dstPath = prefix + strconv.Itoa(c.dst.nextPartNumber(prefix, ext)) + ext
+	}
+	c.parts[srcPath] = dstPath
+	switch {
+	case strings.HasPrefix(dstPath, "xl/charts/chart") && !strings.EqualFold(c.srcSheet, c.dstSheet):
+		content = []byte(replaceXMLFormulas(string(content), func(formula string) string {
+			return renameSheetInFormula(formula, c.srcSheet, c.dstSheet)
+		}))
+	case strings.HasPrefix(dstPath, "xl/drawings/vmlDrawing"):
+		content = renumberVMLDrawing(content, dstPath)
+	case strings.HasPrefix(dstPath, "xl/tables/table"):
+		content = c.copyTable(content)
+	}
+	if !strings.HasPrefix(dstPath, "xl/media/") {
+		c.dst.Pkg.Store(dstPath, content)
+	}
+	c.copyContentType(srcPath, dstPath)
+	c.copyRels(srcPath, dstPath)
+	return dstPath
+}
+
+// copyContentType provides a function to set the content type of the
+// destination part by the content type of the source part.
+func (c *sheetCopier) copyContentType(srcPath, dstPath string) {
+	srcTypes, dstTypes := c.src.contentTypesReader(), c.dst.contentTypesReader()
+	for _, override := range srcTypes.Overrides {
+		if override.PartName != "/"+srcPath {
+			continue
+		}
+		for _, v := range dstTypes.Overrides {
+			if v.PartName == "/"+dstPath {
+				return
+			}
+		}
+		c.dst.setContentTypes("/"+dstPath, override.ContentType)
+		return
+	}
+	ext := strings.TrimPrefix(path.Ext(srcPath), ".")
+	for _, d := range srcTypes.Defaults {
+		if !strings.EqualFold(d.Extension, ext) {
+			continue
+		}
+		dstTypes.Lock()
+		defer dstTypes.Unlock()
+		for _, v := range dstTypes.Defaults {
+			if strings.EqualFold(v.Extension, ext) {
+				return
+			}
+		}
+		dstTypes.Defaults = append(dstTypes.Defaults, d)
+		return
+	}
+}
+
+// copyTable provides a function to update the ID, the name and the
+// differential formatting records of the copied table, the table will be
+// renamed with a numeric suffix if the name already exists in the
+// destination workbook.
+func (c *sheetCopier) copyTable(content []byte) []byte {
+	t := xlsxTable{}
+	if err := c.dst.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
+		Decode(&t); err != nil {
+		return content
+	}
+	if c.tableNames == nil {
+		c.tableNames = map[string]bool{}
+		c.tableID = c.dst.countTables()
+		c.dst.Pkg.Range(func(k, v interface{}) bool {
+			if !strings.HasPrefix(k.(string), "xl/tables/table") {
+				return true
+			}
+			table := xlsxTable{}
+			if err := c.dst.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(v.([]byte)))).
+				Decode(&table); err == nil {
+				c.tableNames[strings.ToLower(table.Name)] = true
+				if table.ID > c.tableID {
+					c.tableID = table.ID
+				}
+			}
+			return true
+		})
+		if wb := c.dst.workbookReader(); wb.DefinedNames != nil {
+			for _, dn := range wb.DefinedNames.DefinedName {
+				c.tableNames[strings.ToLower(dn.Name)] = true
+			}
+		}
+	}
+	name := t.Name
+	for idx := 1; c.tableNames[strings.ToLower(name)]; idx++ {
+		name = t.Name + "_" + strconv.Itoa(idx)
+	}
+	if name != t.Name {
+		c.tables[t.Name] = name
+	}
+	c.tableNames[strings.ToLower(name)] = true
+	c.tableID++
+	t.ID, t.Name, t.DisplayName = c.tableID, name, name
+	t.DataDxfID, t.HeaderRowDxfID = c.copyTableDxf(t.DataDxfID), c.copyTableDxf(t.HeaderRowDxfID)
+	t.HeaderRowBorderDxfID, t.TotalsRowDxfID = c.copyTableDxf(t.HeaderRowBorderDxfID), c.copyTableDxf(t.TotalsRowDxfID)
+	if t.TableColumns != nil {
+		for _, col := range t.TableColumns.TableColumn {
+			col.DataDxfID, col.HeaderRowDxfID = c.copyTableDxf(col.DataDxfID), c.copyTableDxf(col.HeaderRowDxfID)
+			col.TotalsRowDxfID = c.copyTableDxf(col.TotalsRowDxfID)
+		}
+	}
+	table, _ := xml.Marshal(t)
+	return append([]byte(XMLHeader), table...)
+}
+
+// copyTableDxf provides a function to copy the differential formatting
+// record of the table, the zero value means the table doesn't have the
+// formatting.
+func (c *sheetCopier) copyTableDxf(id int) int {
+	if id == 0 {
+		return id
+	}
+	return c.copyDxf(id)
+}
+
+// copyStyles provides a function to remap the shared strings, the style
+// index of the cells, rows and columns, and the differential formatting
+// records of the conditional formats in the copied worksheet into the
+// destination workbook.
+func (c *sheetCopier) copyStyles(ws *xlsxWorksheet) {
+	if c.src == c.dst {
+		return
+	}
+	for rowIdx := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[rowIdx]
+		row.S = c.copyCellXf(row.S)
+		for colIdx := range row.C {
+			cell := &row.C[colIdx]
+			cell.S = c.copyCellXf(cell.S)
+			if cell.T != "s" {
+				continue
+			}
+			if idx, err := strconv.Atoi(cell.V); err == nil {
+				cell.V = strconv.Itoa(c.copySharedString(idx))
+			}
+		}
+	}
+	if ws.Cols != nil {
+		for idx := range ws.Cols.Col {
+			ws.Cols.Col[idx].Style = c.copyCellXf(ws.Cols.Col[idx].Style)
+		}
+	}
+	for _, cf := range ws.ConditionalFormatting {
+		for _, rule := range cf.CfRule {
+			if rule.DxfID != nil {
+				rule.DxfID = intPtr(c.copyDxf(*rule.DxfID))
+			}
+		}
+	}
+}
+
+// copySharedString provides a function to copy the shared string item into
+// the shared strings table of the destination workbook by given index, and
+// returns the index in the destination workbook.
+func (c *sheetCopier) copySharedString(idx int) int {
+	if n, ok := c.sst[idx]; ok {
+		return n
+	}
+	srcSST := c.src.sharedStringsReader()
+	if idx < 0 || idx >= len(srcSST.SI) {
+		return idx
+	}
+	si := srcSST.SI[idx]
+	if si.T != nil && len(si.R) == 0 && len(si.RPh) == 0 && si.PhoneticPr == nil {
+		c.sst[idx] = c.dst.setSharedString(si.T.Val)
+		return c.sst[idx]
+	}
+	sst := c.dst.sharedStringsReader()
+	for n, strItem := range sst.SI {
+		if reflect.DeepEqual(strItem, si) {
+			c.sst[idx] = n
+			return n
+		}
+	}
+	sst.SI = append(sst.SI, deepcopy.Copy(si).(xlsxSI))
+	sst.Count++
+	sst.UniqueCount++
+	c.sst[idx] = len(sst.SI) - 1
+	return c.sst[idx]
+}
+
+// copyCellXf provides a function to copy the cell formatting record with
+// the fonts, fills, borders, number format and named cell style into the
+// stylesheet of the destination workbook by given style index, and returns
+// the style index in the destination workbook. The same formatting records
+// will only be stored once.
+func (c *sheetCopier) copyCellXf(id int) int {
+	if id == 0 {
+		return id
+	}
+	if n, ok := c.xfs[id]; ok {
+		return n
+	}
+	srcStyles, s := c.src.stylesReader(), c.dst.stylesReader()
+	if srcStyles.CellXfs == nil || id < 0 || id >= len(srcStyles.CellXfs.Xf) {
+		return 0
+	}
+	xf := c.copyXf(srcStyles.CellXfs.Xf[id])
+	if xf.XfID != nil {
+		xf.XfID = intPtr(c.copyCellStyleXf(*xf.XfID))
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.CellXfs == nil {
+		s.CellXfs = &xlsxCellXfs{}
+	}
+	c.xfs[id] = appendXf(&s.CellXfs.Xf, xf)
+	s.CellXfs.Count = len(s.CellXfs.Xf)
+	return c.xfs[id]
+}
+
+// copyCellStyleXf provides a function to get the named cell style formatting
+// record in the destination workbook by given formatting record index of
+// the named cell style in the source workbook. The named cell style will be
+// created if it doesn't exist in the destination workbook.
+func (c *sheetCopier) copyCellStyleXf(id int) int {
+	if n, ok := c.styleXfs[id]; ok {
+		return n
+	}
+	srcStyles, s := c.src.stylesReader(), c.dst.stylesReader()
+	var cellStyle *xlsxCellStyle
+	if srcStyles.CellStyles != nil {
+		for _, v := range srcStyles.CellStyles.CellStyle {
+			if v.XfID == id {
+				cellStyle = v
+				break
+			}
+		}
+	}
+	if cellStyle == nil || srcStyles.CellStyleXfs == nil || id >= len(srcStyles.CellStyleXfs.Xf) {
+		c.styleXfs[id] = 0
+		return 0
+	}
+	if v := getCellStyle(s, cellStyle.Name); v != nil {
+		c.styleXfs[id] = v.XfID
+		return v.XfID
+	}
+	xf := c.copyXf(srcStyles.CellStyleXfs.Xf[id])
+	xf.XfID = nil
+	s.Lock()
+	defer s.Unlock()
+	if s.CellStyleXfs == nil {
+		s.CellStyleXfs = &xlsxCellStyleXfs{}
+	}
+	s.CellStyleXfs.Xf = append(s.CellStyleXfs.Xf, xf)
+	s.CellStyleXfs.Count = len(s.CellStyleXfs.Xf)
+	if s.CellStyles == nil {
+		s.CellStyles = &xlsxCellStyles{}
+	}
+	newCellStyle := deepcopy.Copy(cellStyle).(*xlsxCellStyle)
+	newCellStyle.XfID = len(s.CellStyleXfs.Xf) - 1
+	s.CellStyles.CellStyle = append(s.CellStyles.CellStyle, newCellStyle)
+	s.CellStyles.Count = len(s.CellStyles.CellStyle)
+	c.styleXfs[id] = newCellStyle.XfID
+	return newCellStyle.XfID
+}
+
+// copyXf provides a function to copy the fonts, fills, borders and number
+// format referenced by the given formatting record into the stylesheet of
+// the destination workbook, and returns the formatting record with the
+// remapped index.
+func (c *sheetCopier) copyXf(xf xlsxXf) xlsxXf {
+	xf = deepcopy.Copy(xf).(xlsxXf)
+	srcStyles, s := c.src.stylesReader(), c.dst.stylesReader()
+	s.Lock()
+	defer s.Unlock()
+	if xf.NumFmtID != nil {
+		xf.NumFmtID = intPtr(copyNumFmt(srcStyles, s, *xf.NumFmtID))
+	}
+	if id := xf.FontID; id != nil && srcStyles.Fonts != nil && *id < len(srcStyles.Fonts.Font) {
+		if s.Fonts == nil {
+			s.Fonts = &xlsxFonts{}
+		}
+		xf.FontID = intPtr(len(s.Fonts.Font))
+		for idx, font := range s.Fonts.Font {
+			if reflect.DeepEqual(font, srcStyles.Fonts.Font[*id]) {
+				xf.FontID = intPtr(idx)
+				break
+			}
+		}
+		if *xf.FontID == len(s.Fonts.Font) {
+			s.Fonts.Font = append(s.Fonts.Font, deepcopy.Copy(srcStyles.Fonts.Font[*id]).(*xlsxFont))
+			s.Fonts.Count = len(s.Fonts.Font)
+		}
+	}
+	if id := xf.FillID; id != nil && srcStyles.Fills != nil && *id < len(srcStyles.Fills.Fill) {
+		if s.Fills == nil {
+			s.Fills = &xlsxFills{}
+		}
+		xf.FillID = intPtr(len(s.Fills.Fill))
+		for idx, fill := range s.Fills.Fill {
+			if reflect.DeepEqual(fill, srcStyles.Fills.Fill[*id]) {
+				xf.FillID = intPtr(idx)
+				break
+			}
+		}
+		if *xf.FillID == len(s.Fills.Fill) {
+			s.Fills.Fill = append(s.Fills.Fill, deepcopy.Copy(srcStyles.Fills.Fill[*id]).(*xlsxFill))
+			s.Fills.Count = len(s.Fills.Fill)
+		}
+	}
+	if id := xf.BorderID; id != nil && srcStyles.Borders != nil && *id < len(srcStyles.Borders.Border) {
+		if s.Borders == nil {
+			s.Borders = &xlsxBorders{}
+		}
+		xf.BorderID = intPtr(len(s.Borders.Border))
+		for idx, border := range s.Borders.Border {
+			if reflect.DeepEqual(border, srcStyles.Borders.Border[*id]) {
+				xf.BorderID = intPtr(idx)
+				break
+			}
+		}
+		if *xf.BorderID == len(s.Borders.Border) {
+			s.Borders.Border = append(s.Borders.Border, deepcopy.Copy(srcStyles.Borders.Border[*id]).(*xlsxBorder))
+			s.Borders.Count = len(s.Borders.Border)
+		}
+	}
+	return xf
+}
+
+// copyDxf provides a function to copy the differential formatting record
+// into the stylesheet of the destination workbook by given index, and
+// returns the index in the destination workbook.
+func (c *sheetCopier) copyDxf(id int) int {
+	if c.src == c.dst {
+		return id
+	}
+	if n, ok := c.dxfs[id]; ok {
+		return n
+	}
+	srcStyles, s := c.src.stylesReader(), c.dst.stylesReader()
+	if srcStyles.Dxfs == nil || id < 0 || id >= len(srcStyles.Dxfs.Dxfs) {
+		return id
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.Dxfs == nil {
+		s.Dxfs = &xlsxDxfs{}
+	}
+	c.dxfs[id] = len(s.Dxfs.Dxfs)
+	for idx, d := range s.Dxfs.Dxfs {
+		if d.Dxf == srcStyles.Dxfs.Dxfs[id].Dxf {
+			c.dxfs[id] = idx
+			return idx
+		}
+	}
+	s.Dxfs.Dxfs = append(s.Dxfs.Dxfs, &xlsxDxf{Dxf: srcStyles.Dxfs.Dxfs[id].Dxf})
+	s.Dxfs.Count = len(s.Dxfs.Dxfs)
+	return c.dxfs[id]
+}
+
+// copyNumFmt provides a function to copy the custom number format from the
+// source stylesheet into the destination stylesheet by given number format
+// ID, and returns the number format ID in the destination stylesheet. The
+// built-in number format ID will be returned directly.
+func copyNumFmt(src, dst *xlsxStyleSheet, id int) int {
+	if id < 164 || src.NumFmts == nil {
+		return id
+	}
+	var formatCode string
+	for _, numFmt := range src.NumFmts.NumFmt {
+		if numFmt.NumFmtID == id {
+			formatCode = numFmt.FormatCode
+			break
+		}
+	}
+	if formatCode == "" {
+		return 0
+	}
+	if dst.NumFmts == nil {
+		dst.NumFmts = &xlsxNumFmts{}
+	}
+	numFmtID := 163
+	for _, numFmt := range dst.NumFmts.NumFmt {
+		if numFmt.FormatCode == formatCode {
+			return numFmt.NumFmtID
+		}
+		if numFmt.NumFmtID > numFmtID {
+			numFmtID = numFmt.NumFmtID
+		}
+	}
+	dst.NumFmts.NumFmt = append(dst.NumFmts.NumFmt, &xlsxNumFmt{NumFmtID: numFmtID + 1, FormatCode: formatCode})
+	dst.NumFmts.Count = len(dst.NumFmts.NumFmt)
+	return numFmtID + 1
+}
+
+// appendXf provides a function to append the formatting record if it doesn't
+// exist in the given formatting records, and returns the index of the
+// formatting record.
+func appendXf(xfs *[]xlsxXf, xf xlsxXf) int {
+	for idx := range *xfs {
+		if reflect.DeepEqual((*xfs)[idx], xf) {
+			return idx
+		}
+	}
+	*xfs = append(*xfs, xf)
+	return len(*xfs) - 1
+}
+
+// renumberVMLDrawing provides a function to update the shape ID block and
+// the shape IDs in the VML drawing by the sequence number of the given part
+// name, the shape IDs in each VML drawing part should be unique.
+func renumberVMLDrawing(content []byte, name string) []byte {
+	matches := partNamePattern.FindStringSubmatch(name)
+	if matches == nil || matches[2] == "" {
+		return content
+	}
+	num, _ := strconv.Atoi(matches[2])
+	content = vmlIDMapPattern.ReplaceAll(content, []byte("${1}"+matches[2]+"${2}"))
+	return vmlShapeIDPattern.ReplaceAllFunc(content, func(id []byte) []byte {
+		parts := vmlShapeIDPattern.FindSubmatch(id)
+		shapeID, _ := strconv.Atoi(string(parts[2]))
+		return []byte(string(parts[1]) + strconv.Itoa(num*1024+shapeID%1024) + string(parts[3]))
+	})
+}
+
+// renameTableInFormula provides a function to update the table names in the
+// structured references of the formula by given map of the old and new
+// table names.
+func renameTableInFormula(formula string, tables map[string]string) string {
+	return replaceFormulaRefs(formula, func(sheet, ref string) (string, bool) {
+		name, specifier := ref, ""
+		if idx := strings.IndexByte(ref, '['); idx != -1 {
+			name, specifier = ref[:idx], ref[idx:]
+		} else if sheet != "" {
+			return ref, false
+		}
+		if sheet != "" {
+			sheet += "!"
+		}
+		for oldName, newName := range tables {
+			if strings.EqualFold(name, oldName) {
+				return sheet + newName + specifier, true
+			}
+		}
+		return ref, false
+	})
+}
+
+// readPartBytes provides a function to get the latest content of the part in
+// the package by given part name, the deserialized drawings, comments and
+// VML drawings will be serialized.
+func (f *File) readPartBytes(name string) []byte {
+	if d, ok := f.Drawings.Load(name); ok && d != nil {
+		content, _ := xml.Marshal(d.(*xlsxWsDr))
+		return append([]byte(XMLHeader), content...)
+	}
+	if comments := f.Comments[name]; comments != nil {
+		content, _ := xml.Marshal(comments)
+		return append([]byte(XMLHeader), content...)
+	}
+	if vml := f.VMLDrawing[name]; vml != nil {
+		content, _ := xml.Marshal(vml)
+		return content
+	}
+	return f.readBytes(name)
+}
+
+// nextPartNumber provides a function to get the next available sequence
+// number of the part in the package by given prefix and extension of the part
+// name, such as xl/drawings/drawing and .xml.
+func (f *File) nextPartNumber(prefix, ext string) int {
+	var num int
+	check := func(name interface{}) bool {
+		s := name.(string)
+		if len(s) > len(prefix)+len(ext) && strings.HasPrefix(s, prefix) && strings.HasSuffix(s, ext) {
+			if n, err := strconv.Atoi(s[len(prefix) : len(s)-len(ext)]); err == nil && n > num {
+				num = n
+			}
+		}
+		return true
+	}
+	f.Pkg.Range(func(k, v interface{}) bool { return check(k) })
+	f.Sheet.Range(func(k, v interface{}) bool { return check(k) })
+	f.Drawings.Range(func(k, v interface{}) bool { return check(k) })
+	f.tempFiles.Range(func(k, v interface{}) bool { return check(k) })
+	for k := range f.Comments {
+		check(k)
+	}
+	for k := range f.VMLDrawing {
+		check(k)
+	}
+	return num + 1
+}
+
+// getPartRelsPath provides a function to get the relationships part name of
+// the given part, such as xl/worksheets/_rels/sheet1.xml.rels.
+func getPartRelsPath(name string) string {
+	return path.Join(path.Dir(name), "_rels", path.Base(name)+".rels")
+}
+
+// resolvePartTarget provides a function to get the part name in the package
+// by given source part name and the target of the relationship.
+func resolvePartTarget(name, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(name), target)
+}
+
+// getPartTarget provides a function to get the target of the relationship
+// which refers to the given part name in the same style as the original
+// target.
+func getPartTarget(target, name string) string {
+	if strings.HasPrefix(target, "/") {
+		return "/" + name
+	}
+	return path.Join(path.Dir(target), path.Base(name))
+}
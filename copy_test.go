@@ -0,0 +1,123 @@
+package xlsx
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopySheetFrom(t *testing.T) {
+	src := NewFile()
+	for idx, row := range [][]interface{}{{"Name", "Q1", "Q2"}, {"Apple", 2, 3}, {"Orange", 5, 7}} {
+		assert.NoError(t, src.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+1), &row))
+	}
+	assert.NoError(t, src.SetCellRichText("Sheet1", "A5", []RichTextRun{{Text: "bold", Font: &Font{Bold: true}}, {Text: " text"}}))
+	assert.NoError(t, src.SetCellFormula("Sheet1", "D2", "SUM(Sheet1!B2:C2)"))
+	assert.NoError(t, src.SetCellFormula("Sheet1", "D3", "SUM(Table1[Q1])"))
+	style, err := src.NewStyle(&Style{Font: &Font{Bold: true, Color: "#FF0000"}, CustomNumFmt: stringPtr("0.000")})
+	assert.NoError(t, err)
+	assert.NoError(t, src.SetCellStyle("Sheet1", "B2", "C3", style))
+	assert.NoError(t, src.SetColWidth("Sheet1", "A", "A", 20))
+	assert.NoError(t, src.SetRowHeight("Sheet1", 2, 30))
+	assert.NoError(t, src.MergeCell("Sheet1", "A6", "C6"))
+	assert.NoError(t, src.AddComment("Sheet1", "A1", "Author", "Comment"))
+	assert.NoError(t, src.AddPicture("Sheet1", "F1", filepath.Join("test", "images", "excel.png"), ""))
+	assert.NoError(t, src.AddChart("Sheet1", "F20", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$C$1","values":"Sheet1!$B$2:$C$2"}]}`))
+	assert.NoError(t, src.AddTable("Sheet1", "A1", "C3", `{"table_name":"Table1"}`))
+	dv := NewDataValidation(true)
+	dv.SetSqref("B2:C3")
+	assert.NoError(t, dv.SetRange(0, 10, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, src.AddDataValidation("Sheet1", dv))
+	dxf, err := src.NewConditionalStyle(`{"font":{"color":"#9A0511"}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, src.SetConditionalFormat("Sheet1", "B2:C3", fmt.Sprintf(`[{"type":"cell","criteria":">","format":%d,"value":"4"}]`, dxf)))
+	assert.NoError(t, src.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "Sheet1!$D$2:$D$3", Scope: "Sheet1"}))
+
+	f := NewFile()
+	_, err = f.NewStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"#E0EBF5"}, Pattern: 1}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStr("Sheet1", "A1", "A"))
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "B2", `{"table_name":"Table1"}`))
+	assert.NoError(t, f.CopySheetFrom(src, "Sheet1", "Sheet2"))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+
+	// Test get the copied cell values and styles.
+	for cell, expected := range map[string]string{"A1": "Name", "A2": "Apple", "C3": "7", "A5": "bold text"} {
+		val, err := f.GetCellValue("Sheet2", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+	runs, err := f.GetCellRichText("Sheet2", "A5")
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	styleID, err := f.GetCellStyle("Sheet2", "B2")
+	assert.NoError(t, err)
+	s, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.True(t, s.Font.Bold)
+	assert.Contains(t, strings.ToUpper(s.Font.Color), "FF0000")
+	assert.Equal(t, "0.000", *s.CustomNumFmt)
+	width, err := f.GetColWidth("Sheet2", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	height, err := f.GetRowHeight("Sheet2", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	mergeCells, err := f.GetMergeCells("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	// Test get the copied formulas with renamed worksheet and table.
+	formula, err := f.GetCellFormula("Sheet2", "D2")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(Sheet2!B2:C2)", formula)
+	formula, err = f.GetCellFormula("Sheet2", "D3")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(Table1_1[Q1])", formula)
+	// Test get the copied comments, pictures, charts and tables.
+	comments := f.GetComments()
+	assert.Len(t, comments["Sheet2"], 1)
+	assert.Equal(t, "A1", comments["Sheet2"][0].Ref)
+	name, raw, err := f.GetPicture("Sheet2", "F1")
+	assert.NoError(t, err)
+	assert.Equal(t, "image1.png", name)
+	assert.NotEmpty(t, raw)
+	chart, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(chart.([]byte)), "Sheet2!$B$2:$C$2")
+	table, ok := f.Pkg.Load("xl/tables/table2.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(table.([]byte)), `id="2"`)
+	assert.Contains(t, string(table.([]byte)), `name="Table1_1"`)
+	// Test get the copied data validations, conditional formats and defined names.
+	ws, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, ws.DataValidations.DataValidation, 1)
+	assert.Len(t, ws.ConditionalFormatting, 1)
+	dxfID := *ws.ConditionalFormatting[0].CfRule[0].DxfID
+	assert.Contains(t, f.stylesReader().Dxfs.Dxfs[dxfID].Dxf, "FF9A0511")
+	assert.Contains(t, f.GetDefinedName(), DefinedName{Name: "Total", RefersTo: "Sheet2!$D$2:$D$3", Scope: "Sheet2"})
+
+	// Test copy worksheet with invalid parameters.
+	assert.EqualError(t, f.CopySheetFrom(nil, "Sheet1", "Sheet3"), ErrParameterRequired.Error())
+	assert.EqualError(t, f.CopySheetFrom(src, "SheetN", "Sheet3"), "sheet SheetN is not exist")
+	assert.EqualError(t, f.CopySheetFrom(src, "Sheet1", "Sheet2"), ErrExistsWorksheet.Error())
+}
+
+func TestRenumberVMLDrawing(t *testing.T) {
+	content := []byte(`<o:idmap v:ext="edit" data="1"/><v:shape id="_x0000_s1025" type="#_x0000_t202">`)
+	assert.Equal(t, `<o:idmap v:ext="edit" data="3"/><v:shape id="_x0000_s3073" type="#_x0000_t202">`,
+		string(renumberVMLDrawing(content, "xl/drawings/vmlDrawing3.vml")))
+	assert.Equal(t, content, renumberVMLDrawing(content, "xl/drawings/vmlDrawing.vml"))
+}
+
+func TestRenameTableInFormula(t *testing.T) {
+	tables := map[string]string{"Table1": "Table1_1"}
+	assert.Equal(t, "SUM(Table1_1[Q1])+ROWS(Table1_1)+Sheet1!Table1_1[[#Headers],[Q1]]+Table10[Q1]+Sheet1!Table1",
+		renameTableInFormula("SUM(table1[Q1])+ROWS(Table1)+Sheet1!Table1[[#Headers],[Q1]]+Table10[Q1]+Sheet1!Table1", tables))
+}
@@ -7,15 +7,19 @@ import (
 	"crypto/hmac"
 	"crypto/md5"
 	"crypto/rand"
+	"crypto/rc4"
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/xml"
+	"fmt"
 	"hash"
 	"reflect"
+	"sort"
 	"strings"
+	"unicode/utf16"
 
 	"github.com/richardlehane/mscfb"
 	"golang.org/x/crypto/md4"
@@ -23,6 +27,17 @@ import (
 	"golang.org/x/text/encoding/unicode"
 )
 
+// hashAlgorithms defined the hash functions by the hash algorithm names.
+var hashAlgorithms = map[string]func() hash.Hash{
+	"md4":        md4.New,
+	"md5":        md5.New,
+	"ripemd-160": ripemd160.New,
+	"sha1":       sha1.New,
+	"sha256":     sha256.New,
+	"sha384":     sha512.New384,
+	"sha512":     sha512.New,
+}
+
 var (
 	blockKey                   = []byte{0x14, 0x6e, 0x0b, 0xe7, 0xab, 0xac, 0xd0, 0xd6} // Block keys used for encryption
 	blockKeyHmacKey            = []byte{0x5f, 0xb2, 0xad, 0x01, 0x0c, 0xb9, 0xe1, 0xf6}
@@ -31,6 +46,7 @@ var (
 	blockKeyVerifierHashValue  = []byte{0xd7, 0xaa, 0x0f, 0x6d, 0x30, 0x61, 0x34, 0x4e}
 	packageOffset              = 8 // First 8 bytes are the size of the stream
 	packageEncryptionChunkSize = 4096
+	binaryRC4BlockSize         = 1024 // Bytes enciphered by each derived RC4 key
 	iterCount                  = 50000
 	oleIdentifier              = []byte{
 		0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1,
@@ -118,7 +134,11 @@ type StandardEncryptionVerifier struct {
 
 // Decrypt API decrypt the CFB file format with ECMA-376 agile encryption and
 // standard encryption. Support cryptographic algorithm: MD4, MD5, RIPEMD-160,
-// SHA1, SHA256, SHA384 and SHA512 currently.
+// SHA1, SHA256, SHA384 and SHA512 currently. The password and the data
+// integrity of the agile encrypted package will be verified, and the
+// encryption settings of the document will be stored in the options if the
+// encryption mechanism of the options is empty, so that the document could be
+// encrypted by the same scheme on save.
 func Decrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	doc, err := mscfb.New(bytes.NewReader(raw))
 	if err != nil {
@@ -140,123 +160,100 @@ func Decrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	return
 }
 
-// Encrypt API encrypt data with the password.
+// Encrypt API encrypt data with the password. The ECMA-376 agile encryption
+// with AES-256 cipher algorithm, SHA512 hash algorithm and 100000 spin count
+// is used by default, the cipher algorithm, hash algorithm and spin count
+// could be specified by the options. The ECMA-376 standard encryption with
+// AES-128 cipher algorithm and SHA1 hash algorithm will be used if the
+// encryption mechanism is "standard", which is compatible with the readers
+// that not support the agile encryption.
 func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
-	// Generate a random key to use to encrypt the document. Excel uses 32 bytes. We'll use the password to encrypt this key.
-	packageKey, _ := randomBytes(32)
-	keyDataSaltValue, _ := randomBytes(16)
-	keyEncryptors, _ := randomBytes(16)
-	encryptionInfo := Encryption{
-		KeyData: KeyData{
-			BlockSize:       16,
-			KeyBits:         len(packageKey) * 8,
-			HashSize:        64,
-			CipherAlgorithm: "AES",
-			CipherChaining:  "ChainingModeCBC",
-			HashAlgorithm:   "SHA512",
-			SaltValue:       base64.StdEncoding.EncodeToString(keyDataSaltValue),
-		},
-		KeyEncryptors: KeyEncryptors{KeyEncryptor: []KeyEncryptor{{
-			EncryptedKey: EncryptedKey{SpinCount: 100000, KeyData: KeyData{
-				CipherAlgorithm: "AES",
-				CipherChaining:  "ChainingModeCBC",
-				HashAlgorithm:   "SHA512",
-				HashSize:        64,
-				BlockSize:       16,
-				KeyBits:         256,
-				SaltValue:       base64.StdEncoding.EncodeToString(keyEncryptors)},
-			}}},
-		},
-	}
-
-	// Package Encryption
-
-	// Encrypt package using the package key.
-	encryptedPackage, err := cryptPackage(true, packageKey, raw, encryptionInfo)
+	mechanism, keyBits, hashAlgorithm, spinCount, err := encryptionOptions(opt)
 	if err != nil {
 		return
 	}
-
-	// Data Integrity
-
-	// Create the data integrity fields used by clients for integrity checks.
-	// Generate a random array of bytes to use in HMAC. The docs say to use the same length as the key salt, but Excel seems to use 64.
-	hmacKey, _ := randomBytes(64)
-	if err != nil {
-		return
-	}
-	// Create an initialization vector using the package encryption info and the appropriate block key.
-	hmacKeyIV, err := createIV(blockKeyHmacKey, encryptionInfo)
-	if err != nil {
-		return
-	}
-	// Use the package key and the IV to encrypt the HMAC key.
-	encryptedHmacKey, _ := crypt(true, encryptionInfo.KeyData.CipherAlgorithm, encryptionInfo.KeyData.CipherChaining, packageKey, hmacKeyIV, hmacKey)
-	// Create the HMAC.
-	h := hmac.New(sha512.New, append(hmacKey, encryptedPackage...))
-	for _, buf := range [][]byte{hmacKey, encryptedPackage} {
-		_, _ = h.Write(buf)
+	var encryptionInfoBuf, encryptedPackageBuf []byte
+	if mechanism == "standard" {
+		encryptionInfoBuf, encryptedPackageBuf, err = standardEncrypt(raw, opt.Password, keyBits)
+	} else {
+		encryptionInfoBuf, encryptedPackageBuf, err = agileEncrypt(raw, opt.Password, keyBits, hashAlgorithm, spinCount)
 	}
-	hmacValue := h.Sum(nil)
-	// Generate an initialization vector for encrypting the resulting HMAC value.
-	hmacValueIV, err := createIV(blockKeyHmacValue, encryptionInfo)
 	if err != nil {
 		return
 	}
-	// Encrypt the value.
-	encryptedHmacValue, _ := crypt(true, encryptionInfo.KeyData.CipherAlgorithm, encryptionInfo.KeyData.CipherChaining, packageKey, hmacValueIV, hmacValue)
-	// Put the encrypted key and value on the encryption info.
-	encryptionInfo.DataIntegrity.EncryptedHmacKey = base64.StdEncoding.EncodeToString(encryptedHmacKey)
-	encryptionInfo.DataIntegrity.EncryptedHmacValue = base64.StdEncoding.EncodeToString(encryptedHmacValue)
-
-	// Key Encryption
+	streams := encryptionDataSpaces()
+	streams["EncryptionInfo"], streams["EncryptedPackage"] = encryptionInfoBuf, encryptedPackageBuf
+	return writeCFB(streams), err
+}
 
-	// Convert the password to an encryption key.
-	key, err := convertPasswdToKey(opt.Password, blockKey, encryptionInfo)
-	if err != nil {
+// encryptionOptions parse and validate the encryption settings of the
+// options, returns the encryption mechanism, key bits of the cipher
+// algorithm, hash algorithm and spin count.
+func encryptionOptions(opt *Options) (mechanism string, keyBits int, hashAlgorithm string, spinCount int, err error) {
+	mechanism, keyBits, hashAlgorithm, spinCount = strings.ToLower(opt.EncryptionMechanism), 256, "SHA512", 100000
+	switch mechanism {
+	case "", "agile":
+		mechanism = "agile"
+	case "standard":
+		keyBits, hashAlgorithm = 128, "SHA1"
+	default:
+		err = ErrEncrypt
 		return
 	}
-	// Encrypt the package key with the encryption key.
-	encryptedKeyValue, _ := crypt(true, encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.CipherAlgorithm, encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.CipherChaining, key, keyEncryptors, packageKey)
-	encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.EncryptedKeyValue = base64.StdEncoding.EncodeToString(encryptedKeyValue)
-
-	// Verifier hash
-
-	// Create a random byte array for hashing.
-	verifierHashInput, _ := randomBytes(16)
-	// Create an encryption key from the password for the input.
-	verifierHashInputKey, err := convertPasswdToKey(opt.Password, blockKeyVerifierHashInput, encryptionInfo)
-	if err != nil {
-		return
+	if opt.CipherAlgorithm != "" {
+		var ok bool
+		if keyBits, ok = map[string]int{"AES-128": 128, "AES-192": 192, "AES-256": 256}[strings.ToUpper(opt.CipherAlgorithm)]; !ok {
+			err = ErrCipherAlgorithm
+			return
+		}
 	}
-	// Use the key to encrypt the verifier input.
-	encryptedVerifierHashInput, err := crypt(true, encryptionInfo.KeyData.CipherAlgorithm, encryptionInfo.KeyData.CipherChaining, verifierHashInputKey, keyEncryptors, verifierHashInput)
-	if err != nil {
-		return
+	if opt.HashAlgorithm != "" {
+		hashAlgorithm = strings.ToUpper(opt.HashAlgorithm)
+		if hashAlgorithm != "SHA1" && (mechanism == "standard" || inStrSlice([]string{"SHA256", "SHA384", "SHA512"}, hashAlgorithm) == -1) {
+			err = ErrHashAlgorithm
+			return
+		}
 	}
-	encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.EncryptedVerifierHashInput = base64.StdEncoding.EncodeToString(encryptedVerifierHashInput)
-	// Create a hash of the input.
-	verifierHashValue := hashing(encryptionInfo.KeyData.HashAlgorithm, verifierHashInput)
-	// Create an encryption key from the password for the hash.
-	verifierHashValueKey, err := convertPasswdToKey(opt.Password, blockKeyVerifierHashValue, encryptionInfo)
-	if err != nil {
-		return
+	if opt.SpinCount != 0 {
+		if opt.SpinCount < 0 || opt.SpinCount > MaxSpinCount {
+			err = ErrSpinCount
+			return
+		}
+		spinCount = opt.SpinCount
 	}
-	// Use the key to encrypt the hash value.
-	encryptedVerifierHashValue, err := crypt(true, encryptionInfo.KeyData.CipherAlgorithm, encryptionInfo.KeyData.CipherChaining, verifierHashValueKey, keyEncryptors, verifierHashValue)
-	if err != nil {
-		return
+	return
+}
+
+// encryptionDataSpaces create the data spaces storage streams, which
+// specifies the encrypted package stream is transformed by the encryption
+// transform.
+func encryptionDataSpaces() map[string][]byte {
+	lp := func(s string) []byte {
+		u := utf16.Encode([]rune(s))
+		buf := make([]byte, 4+(len(u)*2+3)/4*4)
+		binary.LittleEndian.PutUint32(buf, uint32(len(u)*2))
+		for i, c := range u {
+			binary.LittleEndian.PutUint16(buf[4+i*2:], c)
+		}
+		return buf
 	}
-	encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.EncryptedVerifierHashValue = base64.StdEncoding.EncodeToString(encryptedVerifierHashValue)
-	// Marshal the encryption info buffer.
-	encryptionInfoBuffer, err := xml.Marshal(encryptionInfo)
-	if err != nil {
-		return
+	versions := []byte{1, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0}
+	mapEntry := bytes.Join([][]byte{createUInt32LEBuffer(1, 4), createUInt32LEBuffer(0, 4), lp("EncryptedPackage"), lp("StrongEncryptionDataSpace")}, nil)
+	transformID := lp("{FF9A3F03-56EF-4613-BDD5-5A41C1D07246}")
+	return map[string][]byte{
+		"\x06DataSpaces/Version": append(lp("Microsoft.Container.DataSpaces"), versions...),
+		"\x06DataSpaces/DataSpaceMap": bytes.Join([][]byte{
+			createUInt32LEBuffer(8, 4), createUInt32LEBuffer(1, 4), createUInt32LEBuffer(4+len(mapEntry), 4), mapEntry,
+		}, nil),
+		"\x06DataSpaces/DataSpaceInfo/StrongEncryptionDataSpace": bytes.Join([][]byte{
+			createUInt32LEBuffer(8, 4), createUInt32LEBuffer(1, 4), lp("StrongEncryptionTransform"),
+		}, nil),
+		"\x06DataSpaces/TransformInfo/StrongEncryptionTransform/\x06Primary": bytes.Join([][]byte{
+			createUInt32LEBuffer(8+len(transformID), 4), createUInt32LEBuffer(1, 4), transformID,
+			lp("Microsoft.Container.EncryptionTransform"), versions,
+			createUInt32LEBuffer(0, 4), createUInt32LEBuffer(0, 4), createUInt32LEBuffer(0, 4), createUInt32LEBuffer(4, 4),
+		}, nil),
 	}
-	// TODO: Create a new CFB.
-	_, _ = encryptedPackage, encryptionInfoBuffer
-	err = ErrEncrypt
-	return
 }
 
 // extractPart extract data from storage by specified part name.
@@ -324,7 +321,7 @@ func standardDecrypt(encryptionInfoBuf, encryptedPackageBuf []byte, opt *Options
 		0x00006610: "AES-256",
 	}
 	algorithm := "AES"
-	_, ok := algIDMap[header.AlgID]
+	cipherAlgorithm, ok := algIDMap[header.AlgID]
 	if !ok {
 		algorithm = "RC4"
 	}
@@ -333,20 +330,100 @@ func standardDecrypt(encryptionInfoBuf, encryptedPackageBuf []byte, opt *Options
 	if err != nil {
 		return nil, err
 	}
+	// verify the password
+	decryptedVerifier, err := standardCrypt(false, secretKey, verifier.EncryptedVerifier)
+	if err != nil {
+		return nil, err
+	}
+	decryptedVerifierHash, err := standardCrypt(false, secretKey, verifier.EncryptedVerifierHash)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(hashing("sha1", decryptedVerifier), decryptedVerifierHash[:sha1.Size]) {
+		return nil, ErrWorkbookPassword
+	}
 	// decrypted data
-	x := encryptedPackageBuf[8:]
-	blob, err := aes.NewCipher(secretKey)
+	decrypted, err := standardCrypt(false, secretKey, encryptedPackageBuf[packageOffset:])
 	if err != nil {
 		return nil, err
 	}
-	decrypted := make([]byte, len(x))
-	size := 16
-	for bs, be := 0, size; bs < len(x); bs, be = bs+size, be+size {
-		blob.Decrypt(decrypted[bs:be], x[bs:be])
+	if size := int(binary.LittleEndian.Uint64(encryptedPackageBuf[:packageOffset])); size < len(decrypted) {
+		decrypted = decrypted[:size]
+	}
+	if opt.EncryptionMechanism == "" {
+		opt.EncryptionMechanism, opt.CipherAlgorithm, opt.HashAlgorithm, opt.SpinCount = "standard", cipherAlgorithm, "SHA1", iterCount
 	}
 	return decrypted, err
 }
 
+// standardEncrypt encrypt the package with ECMA-376 standard encryption by
+// given password and key bits of the AES cipher algorithm, returns the
+// encryption info stream and the encrypted package stream.
+func standardEncrypt(raw []byte, passwd string, keyBits int) (encryptionInfoBuf, encryptedPackageBuf []byte, err error) {
+	header := StandardEncryptionHeader{
+		Flags:        0x24, // fCryptoAPI and fAES
+		AlgID:        map[int]uint32{128: 0x0000660E, 192: 0x0000660F, 256: 0x00006610}[keyBits],
+		AlgIDHash:    0x00008004, // SHA1
+		KeySize:      uint32(keyBits),
+		ProviderType: 0x00000018, // PROV_RSA_AES
+		CspName:      "Microsoft Enhanced RSA and AES Cryptographic Provider",
+	}
+	salt, _ := randomBytes(16)
+	verifier := StandardEncryptionVerifier{SaltSize: 16, Salt: salt, VerifierHashSize: sha1.Size}
+	secretKey, err := standardConvertPasswdToKey(header, verifier, &Options{Password: passwd})
+	if err != nil {
+		return
+	}
+	// Create the verifier by a random array of bytes and the hash of it.
+	verifierInput, _ := randomBytes(16)
+	if verifier.EncryptedVerifier, err = standardCrypt(true, secretKey, verifierInput); err != nil {
+		return
+	}
+	if verifier.EncryptedVerifierHash, err = standardCrypt(true, secretKey, hashing("sha1", verifierInput)); err != nil {
+		return
+	}
+	encryptedPackage, err := standardCrypt(true, secretKey, raw)
+	if err != nil {
+		return
+	}
+	encryptedPackageBuf = append(createUInt32LEBuffer(len(raw), packageOffset), encryptedPackage...)
+	// Marshal the encryption header and encryption verifier.
+	cspName, _ := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder().Bytes([]byte(header.CspName))
+	headerBuf := new(bytes.Buffer)
+	for _, v := range []uint32{header.Flags, header.SizeExtra, header.AlgID, header.AlgIDHash, header.KeySize, header.ProviderType, header.Reserved1, header.Reserved2} {
+		_ = binary.Write(headerBuf, binary.LittleEndian, v)
+	}
+	headerBuf.Write(append(cspName, 0, 0))
+	encryptionInfoBuf = bytes.Join([][]byte{
+		{0x04, 0x00, 0x02, 0x00}, createUInt32LEBuffer(int(header.Flags), 4),
+		createUInt32LEBuffer(headerBuf.Len(), 4), headerBuf.Bytes(),
+		createUInt32LEBuffer(int(verifier.SaltSize), 4), verifier.Salt, verifier.EncryptedVerifier,
+		createUInt32LEBuffer(int(verifier.VerifierHashSize), 4), verifier.EncryptedVerifierHash,
+	}, nil)
+	return
+}
+
+// standardCrypt encrypt / decrypt input by given key with the AES cipher
+// algorithm in ECB mode, the input will be padded to the integral multiple of
+// the block size.
+func standardCrypt(encrypt bool, key, input []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	size := block.BlockSize()
+	output := make([]byte, (len(input)+size-1)/size*size)
+	copy(output, input)
+	for bs := 0; bs < len(output); bs += size {
+		if encrypt {
+			block.Encrypt(output[bs:bs+size], output[bs:bs+size])
+			continue
+		}
+		block.Decrypt(output[bs:bs+size], output[bs:bs+size])
+	}
+	return output, nil
+}
+
 // standardEncryptionVerifier extract ECMA-376 standard encryption verifier.
 func standardEncryptionVerifier(algorithm string, blob []byte) StandardEncryptionVerifier {
 	verifier := StandardEncryptionVerifier{
@@ -403,6 +480,110 @@ func standardXORBytes(a, b []byte) []byte {
 	return buf
 }
 
+// Office Binary Document RC4 Encryption
+
+// binaryRC4 specifies the key derivation state of the Office binary document
+// RC4 encryption and RC4 CryptoAPI encryption, used by the legacy BIFF8
+// workbooks.
+type binaryRC4 struct {
+	cryptoAPI bool
+	keySize   int
+	hash      []byte
+}
+
+// newBinaryRC4 parse the RC4 encryption info stored in the FILEPASS record of
+// a BIFF8 workbook and verify the given password against it.
+func newBinaryRC4(encryptionInfo []byte, passwd string) (*binaryRC4, error) {
+	if len(encryptionInfo) < 4 {
+		return nil, ErrUnknownEncryptMechanism
+	}
+	encoder := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder()
+	passwordBuffer, err := encoder.Bytes([]byte(passwd))
+	if err != nil {
+		return nil, err
+	}
+	var (
+		rc                     = &binaryRC4{}
+		verifier, verifierHash []byte
+		versionMajor           = binary.LittleEndian.Uint16(encryptionInfo[:2])
+		versionMinor           = binary.LittleEndian.Uint16(encryptionInfo[2:4])
+	)
+	switch {
+	case versionMajor == 1 && versionMinor == 1:
+		if len(encryptionInfo) < 52 {
+			return nil, ErrUnknownEncryptMechanism
+		}
+		salt := encryptionInfo[4:20]
+		verifier, verifierHash = encryptionInfo[20:36], encryptionInfo[36:52]
+		truncatedHash := hashing("md5", passwordBuffer)[:5]
+		var buf []byte
+		for i := 0; i < 16; i++ {
+			buf = append(append(buf, truncatedHash...), salt...)
+		}
+		rc.keySize, rc.hash = 16, hashing("md5", buf)[:5]
+	case 2 <= versionMajor && versionMajor <= 4 && versionMinor == 2:
+		if len(encryptionInfo) < 12 {
+			return nil, ErrUnknownEncryptMechanism
+		}
+		encryptionHeaderSize := int(binary.LittleEndian.Uint32(encryptionInfo[8:12]))
+		if len(encryptionInfo) < 12+encryptionHeaderSize+60 || encryptionHeaderSize < 32 {
+			return nil, ErrUnknownEncryptMechanism
+		}
+		keySize := int(binary.LittleEndian.Uint32(encryptionInfo[28:32])) / 8
+		if keySize == 0 {
+			keySize = 5
+		}
+		v := standardEncryptionVerifier("RC4", encryptionInfo[12+encryptionHeaderSize:])
+		verifier, verifierHash = v.EncryptedVerifier, v.EncryptedVerifierHash
+		rc.cryptoAPI, rc.keySize, rc.hash = true, keySize, hashing("sha1", v.Salt, passwordBuffer)
+	default:
+		return nil, ErrUnsupportEncryptMechanism
+	}
+	c, err := rc4.NewCipher(rc.blockKey(0))
+	if err != nil {
+		return nil, err
+	}
+	buf := append(append([]byte{}, verifier...), verifierHash...)
+	c.XORKeyStream(buf, buf)
+	hashAlgorithm := "md5"
+	if rc.cryptoAPI {
+		hashAlgorithm = "sha1"
+	}
+	if !bytes.Equal(hashing(hashAlgorithm, buf[:len(verifier)]), buf[len(verifier):]) {
+		return nil, ErrWorkbookPassword
+	}
+	return rc, nil
+}
+
+// blockKey derive the RC4 key for the given block number.
+func (rc *binaryRC4) blockKey(block int) []byte {
+	if !rc.cryptoAPI {
+		return hashing("md5", rc.hash, createUInt32LEBuffer(block, 4))
+	}
+	key := hashing("sha1", rc.hash, createUInt32LEBuffer(block, 4))[:rc.keySize]
+	if rc.keySize == 5 {
+		key = append(key, make([]byte, 11)...)
+	}
+	return key
+}
+
+// crypt encrypt / decrypt the stream in place, the key stream is re-keyed at
+// every 1024 bytes boundary of the stream.
+func (rc *binaryRC4) crypt(stream []byte) error {
+	for block := 0; block*binaryRC4BlockSize < len(stream); block++ {
+		start, end := block*binaryRC4BlockSize, (block+1)*binaryRC4BlockSize
+		if end > len(stream) {
+			end = len(stream)
+		}
+		c, err := rc4.NewCipher(rc.blockKey(block))
+		if err != nil {
+			return err
+		}
+		c.XORKeyStream(stream[start:end], stream[start:end])
+	}
+	return nil
+}
+
 // ECMA-376 Agile Encryption
 
 // agileDecrypt decrypt the CFB file format with ECMA-376 agile encryption.
@@ -412,13 +593,21 @@ func agileDecrypt(encryptionInfoBuf, encryptedPackageBuf []byte, opt *Options) (
 	if encryptionInfo, err = parseEncryptionInfo(encryptionInfoBuf[8:]); err != nil {
 		return
 	}
+	if len(encryptionInfo.KeyEncryptors.KeyEncryptor) == 0 {
+		err = ErrUnknownEncryptMechanism
+		return
+	}
+	// Verify the password by the verifier hash.
+	encryptedKey := encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey
+	if err = agileVerifyPasswd(opt.Password, encryptionInfo); err != nil {
+		return
+	}
 	// Convert the password into an encryption key.
 	key, err := convertPasswdToKey(opt.Password, blockKey, encryptionInfo)
 	if err != nil {
 		return
 	}
 	// Use the key to decrypt the package key.
-	encryptedKey := encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey
 	saltValue, err := base64.StdEncoding.DecodeString(encryptedKey.SaltValue)
 	if err != nil {
 		return
@@ -427,9 +616,202 @@ func agileDecrypt(encryptionInfoBuf, encryptedPackageBuf []byte, opt *Options) (
 	if err != nil {
 		return
 	}
-	packageKey, _ := crypt(false, encryptedKey.CipherAlgorithm, encryptedKey.CipherChaining, key, saltValue, encryptedKeyValue)
+	packageKey, err := crypt(false, encryptedKey.CipherAlgorithm, encryptedKey.CipherChaining, key, saltValue, encryptedKeyValue)
+	if err != nil {
+		return
+	}
+	if keyBytes := encryptionInfo.KeyData.KeyBits / 8; keyBytes > 0 && keyBytes < len(packageKey) {
+		packageKey = packageKey[:keyBytes]
+	}
+	// Verify the data integrity of the encrypted package.
+	if err = agileVerifyDataIntegrity(packageKey, encryptedPackageBuf, encryptionInfo); err != nil {
+		return
+	}
 	// Use the package key to decrypt the package.
-	return cryptPackage(false, packageKey, encryptedPackageBuf, encryptionInfo)
+	if packageBuf, err = cryptPackage(false, packageKey, encryptedPackageBuf, encryptionInfo); err != nil {
+		return
+	}
+	if opt.EncryptionMechanism == "" {
+		opt.EncryptionMechanism, opt.CipherAlgorithm = "agile", fmt.Sprintf("AES-%d", encryptionInfo.KeyData.KeyBits)
+		opt.HashAlgorithm, opt.SpinCount = strings.ToUpper(encryptionInfo.KeyData.HashAlgorithm), encryptedKey.SpinCount
+	}
+	return
+}
+
+// agileVerifyPasswd verify the password by the encrypted verifier hash input
+// and the encrypted verifier hash value of the password key encryptor.
+func agileVerifyPasswd(passwd string, encryption Encryption) error {
+	encryptedKey := encryption.KeyEncryptors.KeyEncryptor[0].EncryptedKey
+	if encryptedKey.EncryptedVerifierHashInput == "" || encryptedKey.EncryptedVerifierHashValue == "" {
+		return nil
+	}
+	saltValue, err := base64.StdEncoding.DecodeString(encryptedKey.SaltValue)
+	if err != nil {
+		return err
+	}
+	var decrypted [2][]byte
+	for i, item := range []struct {
+		blockKey []byte
+		value    string
+	}{
+		{blockKeyVerifierHashInput, encryptedKey.EncryptedVerifierHashInput},
+		{blockKeyVerifierHashValue, encryptedKey.EncryptedVerifierHashValue},
+	} {
+		key, err := convertPasswdToKey(passwd, item.blockKey, encryption)
+		if err != nil {
+			return err
+		}
+		value, err := base64.StdEncoding.DecodeString(item.value)
+		if err != nil {
+			return err
+		}
+		if decrypted[i], err = crypt(false, encryptedKey.CipherAlgorithm, encryptedKey.CipherChaining, key, saltValue, value); err != nil {
+			return err
+		}
+	}
+	verifierHashInput := decrypted[0]
+	if encryptedKey.SaltSize > 0 && encryptedKey.SaltSize < len(verifierHashInput) {
+		verifierHashInput = verifierHashInput[:encryptedKey.SaltSize]
+	}
+	verifierHashValue := hashing(encryption.KeyData.HashAlgorithm, verifierHashInput)
+	if len(decrypted[1]) < len(verifierHashValue) || !bytes.Equal(verifierHashValue, decrypted[1][:len(verifierHashValue)]) {
+		return ErrWorkbookPassword
+	}
+	return nil
+}
+
+// agileVerifyDataIntegrity verify the HMAC of the encrypted package stream
+// with the data integrity of the encryption info.
+func agileVerifyDataIntegrity(packageKey, encryptedPackage []byte, encryption Encryption) error {
+	if encryption.DataIntegrity.EncryptedHmacKey == "" || encryption.DataIntegrity.EncryptedHmacValue == "" {
+		return nil
+	}
+	var decrypted [2][]byte
+	for i, item := range []struct {
+		blockKey []byte
+		value    string
+	}{
+		{blockKeyHmacKey, encryption.DataIntegrity.EncryptedHmacKey},
+		{blockKeyHmacValue, encryption.DataIntegrity.EncryptedHmacValue},
+	} {
+		iv, err := createIV(item.blockKey, encryption)
+		if err != nil {
+			return err
+		}
+		value, err := base64.StdEncoding.DecodeString(item.value)
+		if err != nil {
+			return err
+		}
+		if decrypted[i], err = crypt(false, encryption.KeyData.CipherAlgorithm, encryption.KeyData.CipherChaining, packageKey, iv, value); err != nil {
+			return err
+		}
+	}
+	hashSize := len(hashing(encryption.KeyData.HashAlgorithm))
+	if len(decrypted[0]) < hashSize || len(decrypted[1]) < hashSize {
+		return ErrWorkbookDataIntegrity
+	}
+	if !hmac.Equal(hmacHashing(encryption.KeyData.HashAlgorithm, decrypted[0][:hashSize], encryptedPackage), decrypted[1][:hashSize]) {
+		return ErrWorkbookDataIntegrity
+	}
+	return nil
+}
+
+// agileEncrypt encrypt the package with ECMA-376 agile encryption by given
+// password, key bits of the AES cipher algorithm, hash algorithm and spin
+// count, returns the encryption info stream and the encrypted package stream.
+func agileEncrypt(raw []byte, passwd string, keyBits int, hashAlgorithm string, spinCount int) (encryptionInfoBuf, encryptedPackageBuf []byte, err error) {
+	// Generate a random key to use to encrypt the document. We'll use the password to encrypt this key.
+	packageKey, _ := randomBytes(keyBits / 8)
+	keyDataSaltValue, _ := randomBytes(16)
+	keyEncryptorSaltValue, _ := randomBytes(16)
+	hashSize := len(hashing(hashAlgorithm))
+	keyData := KeyData{
+		SaltSize:        16,
+		BlockSize:       16,
+		KeyBits:         keyBits,
+		HashSize:        hashSize,
+		CipherAlgorithm: "AES",
+		CipherChaining:  "ChainingModeCBC",
+		HashAlgorithm:   hashAlgorithm,
+		SaltValue:       base64.StdEncoding.EncodeToString(keyDataSaltValue),
+	}
+	encryptionInfo := Encryption{KeyData: keyData, KeyEncryptors: KeyEncryptors{KeyEncryptor: []KeyEncryptor{{
+		URI:          "http://schemas.microsoft.com/office/2006/keyEncryptor/password",
+		EncryptedKey: EncryptedKey{SpinCount: spinCount, KeyData: keyData},
+	}}}}
+	encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.SaltValue = base64.StdEncoding.EncodeToString(keyEncryptorSaltValue)
+	encryptedKey := &encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey
+
+	// Package Encryption
+
+	// Encrypt package using the package key.
+	if encryptedPackageBuf, err = cryptPackage(true, packageKey, raw, encryptionInfo); err != nil {
+		return
+	}
+
+	// Data Integrity
+
+	// Create the data integrity fields used by clients for integrity checks.
+	// Generate a random array of bytes with the same length as the hash to use in HMAC.
+	hmacKey, _ := randomBytes(hashSize)
+	for _, item := range []struct {
+		blockKey []byte
+		value    []byte
+		field    *string
+	}{
+		{blockKeyHmacKey, hmacKey, &encryptionInfo.DataIntegrity.EncryptedHmacKey},
+		{blockKeyHmacValue, hmacHashing(hashAlgorithm, hmacKey, encryptedPackageBuf), &encryptionInfo.DataIntegrity.EncryptedHmacValue},
+	} {
+		// Create an initialization vector using the package encryption info and the appropriate block key.
+		iv, err := createIV(item.blockKey, encryptionInfo)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Use the package key and the IV to encrypt the HMAC key and the HMAC value.
+		encrypted, err := crypt(true, keyData.CipherAlgorithm, keyData.CipherChaining, packageKey, iv, padding(item.value, keyData.BlockSize))
+		if err != nil {
+			return nil, nil, err
+		}
+		*item.field = base64.StdEncoding.EncodeToString(encrypted)
+	}
+
+	// Key Encryption and Verifier Hash
+
+	// Create a random byte array for hashing, and encrypt the package key,
+	// the verifier input and the hash of it by the keys converted from the
+	// password.
+	verifierHashInput, _ := randomBytes(16)
+	for _, item := range []struct {
+		blockKey []byte
+		value    []byte
+		field    *string
+	}{
+		{blockKey, packageKey, &encryptedKey.EncryptedKeyValue},
+		{blockKeyVerifierHashInput, verifierHashInput, &encryptedKey.EncryptedVerifierHashInput},
+		{blockKeyVerifierHashValue, hashing(hashAlgorithm, verifierHashInput), &encryptedKey.EncryptedVerifierHashValue},
+	} {
+		key, err := convertPasswdToKey(passwd, item.blockKey, encryptionInfo)
+		if err != nil {
+			return nil, nil, err
+		}
+		encrypted, err := crypt(true, encryptedKey.CipherAlgorithm, encryptedKey.CipherChaining, key, keyEncryptorSaltValue, padding(item.value, keyData.BlockSize))
+		if err != nil {
+			return nil, nil, err
+		}
+		*item.field = base64.StdEncoding.EncodeToString(encrypted)
+	}
+	// Marshal the encryption info buffer.
+	buf, err := xml.Marshal(encryptionInfo)
+	if err != nil {
+		return
+	}
+	encryptionInfoBuf = append([]byte{0x04, 0x00, 0x04, 0x00, 0x40, 0x00, 0x00, 0x00}, []byte(xml.Header)...)
+	encryptionInfoBuf = append(encryptionInfoBuf, strings.NewReplacer(
+		"<encryption>", `<encryption xmlns="http://schemas.microsoft.com/office/2006/encryption" xmlns:p="http://schemas.microsoft.com/office/2006/keyEncryptor/password">`,
+		`<encryptedKey xmlns="http://schemas.microsoft.com/office/2006/keyEncryptor/password"`, "<p:encryptedKey",
+		"</encryptedKey>", "</p:encryptedKey>",
+	).Replace(string(buf))...)
+	return
 }
 
 // convertPasswdToKey convert the password into an encryption key.
@@ -456,31 +838,17 @@ func convertPasswdToKey(passwd string, blockKey []byte, encryption Encryption) (
 	// Now generate the final hash.
 	key = hashing(encryption.KeyData.HashAlgorithm, key, blockKey)
 	// Truncate or pad as needed to get to length of keyBits.
-	keyBytes := encryption.KeyEncryptors.KeyEncryptor[0].EncryptedKey.KeyBits / 8
-	if len(key) < keyBytes {
-		tmp := make([]byte, 0x36)
-		key = append(key, tmp...)
-	} else if len(key) > keyBytes {
-		key = key[:keyBytes]
-	}
+	key = truncateOrPad(key, encryption.KeyEncryptors.KeyEncryptor[0].EncryptedKey.KeyBits/8)
 	return
 }
 
 // hashing data by specified hash algorithm.
 func hashing(hashAlgorithm string, buffer ...[]byte) (key []byte) {
-	var hashMap = map[string]hash.Hash{
-		"md4":        md4.New(),
-		"md5":        md5.New(),
-		"ripemd-160": ripemd160.New(),
-		"sha1":       sha1.New(),
-		"sha256":     sha256.New(),
-		"sha384":     sha512.New384(),
-		"sha512":     sha512.New(),
-	}
-	handler, ok := hashMap[strings.ToLower(hashAlgorithm)]
+	newHash, ok := hashAlgorithms[strings.ToLower(hashAlgorithm)]
 	if !ok {
 		return key
 	}
+	handler := newHash()
 	for _, buf := range buffer {
 		_, _ = handler.Write(buf)
 	}
@@ -488,6 +856,38 @@ func hashing(hashAlgorithm string, buffer ...[]byte) (key []byte) {
 	return key
 }
 
+// hmacHashing calculate the HMAC of the data by specified hash algorithm and
+// key.
+func hmacHashing(hashAlgorithm string, key []byte, buffer ...[]byte) []byte {
+	newHash, ok := hashAlgorithms[strings.ToLower(hashAlgorithm)]
+	if !ok {
+		return nil
+	}
+	handler := hmac.New(newHash, key)
+	for _, buf := range buffer {
+		_, _ = handler.Write(buf)
+	}
+	return handler.Sum(nil)
+}
+
+// truncateOrPad truncate the buffer or pad it with 0x36 bytes to the given
+// size.
+func truncateOrPad(buf []byte, size int) []byte {
+	if len(buf) < size {
+		return append(append([]byte{}, buf...), bytes.Repeat([]byte{0x36}, size-len(buf))...)
+	}
+	return buf[:size]
+}
+
+// padding pad the buffer with zero bytes to the integral multiple of the
+// block size.
+func padding(buf []byte, blockSize int) []byte {
+	if remainder := len(buf) % blockSize; remainder != 0 {
+		return append(append([]byte{}, buf...), make([]byte, blockSize-remainder)...)
+	}
+	return buf
+}
+
 // createUInt32LEBuffer create buffer with little endian 32-bit unsigned
 // integer.
 func createUInt32LEBuffer(value int, bufferSize int) []byte {
@@ -509,46 +909,40 @@ func crypt(encrypt bool, cipherAlgorithm, cipherChaining string, key, iv, input
 	if err != nil {
 		return input, err
 	}
+	if len(input)%block.BlockSize() != 0 {
+		return input, ErrUnknownEncryptMechanism
+	}
 	var stream cipher.BlockMode
 	if encrypt {
 		stream = cipher.NewCBCEncrypter(block, iv)
 	} else {
 		stream = cipher.NewCBCDecrypter(block, iv)
 	}
-	stream.CryptBlocks(input, input)
-	return input, nil
+	output := make([]byte, len(input))
+	stream.CryptBlocks(output, input)
+	return output, nil
 }
 
 // cryptPackage encrypt / decrypt package by given packageKey and encryption
 // info.
 func cryptPackage(encrypt bool, packageKey, input []byte, encryption Encryption) (outputChunks []byte, err error) {
 	encryptedKey := encryption.KeyData
-	var offset = packageOffset
-	if encrypt {
-		offset = 0
+	size := len(input)
+	if !encrypt {
+		if len(input) < packageOffset {
+			err = ErrUnknownEncryptMechanism
+			return
+		}
+		size, input = int(binary.LittleEndian.Uint64(input[:packageOffset])), input[packageOffset:]
 	}
-	var i, start, end int
 	var iv, outputChunk []byte
-	for end < len(input) {
-		start = end
-		end = start + packageEncryptionChunkSize
-
+	for i, start := 0, 0; start < len(input); i, start = i+1, start+packageEncryptionChunkSize {
+		end := start + packageEncryptionChunkSize
 		if end > len(input) {
 			end = len(input)
 		}
-		// Grab the next chunk
-		var inputChunk []byte
-		if (end + offset) < len(input) {
-			inputChunk = input[start+offset : end+offset]
-		} else {
-			inputChunk = input[start+offset : end]
-		}
-
-		// Pad the chunk if it is not an integer multiple of the block size
-		remainder := len(inputChunk) % encryptedKey.BlockSize
-		if remainder != 0 {
-			inputChunk = append(inputChunk, make([]byte, encryptedKey.BlockSize-remainder)...)
-		}
+		// Grab the next chunk and pad it if it is not an integer multiple of the block size
+		inputChunk := padding(input[start:end], encryptedKey.BlockSize)
 		// Create the initialization vector
 		iv, err = createIV(i, encryption)
 		if err != nil {
@@ -560,10 +954,11 @@ func cryptPackage(encrypt bool, packageKey, input []byte, encryption Encryption)
 			return
 		}
 		outputChunks = append(outputChunks, outputChunk...)
-		i++
 	}
 	if encrypt {
-		outputChunks = append(createUInt32LEBuffer(len(input), 8), outputChunks...)
+		outputChunks = append(createUInt32LEBuffer(size, packageOffset), outputChunks...)
+	} else if size < len(outputChunks) {
+		outputChunks = outputChunks[:size]
 	}
 	return
 }
@@ -585,13 +980,7 @@ func createIV(blockKey interface{}, encryption Encryption) ([]byte, error) {
 	// Create the initialization vector by hashing the salt with the block key.
 	// Truncate or pad as needed to meet the block size.
 	iv := hashing(encryptedKey.HashAlgorithm, append(saltValue, blockKeyBuf...))
-	if len(iv) < encryptedKey.BlockSize {
-		tmp := make([]byte, 0x36)
-		iv = append(iv, tmp...)
-	} else if len(iv) > encryptedKey.BlockSize {
-		iv = iv[:encryptedKey.BlockSize]
-	}
-	return iv, nil
+	return truncateOrPad(iv, encryptedKey.BlockSize), nil
 }
 
 // randomBytes returns securely generated random bytes. It will return an error if the system's
@@ -602,3 +991,212 @@ func randomBytes(n int) ([]byte, error) {
 	_, err := rand.Read(b)
 	return b, err
 }
+
+// Compound File Binary
+
+// cfbEntry specifies the storage or stream directory entry of the compound
+// file.
+type cfbEntry struct {
+	name                      string
+	data                      []byte
+	storage                   bool
+	children                  []*cfbEntry
+	id                        int
+	left, right, child, start uint32
+}
+
+// cfbLess compare the directory entry names by the length and the upper-case
+// names, the directory entries in a storage are stored as a binary search
+// tree by this order.
+func cfbLess(a, b string) bool {
+	la, lb := len(utf16.Encode([]rune(a))), len(utf16.Encode([]rune(b)))
+	if la != lb {
+		return la < lb
+	}
+	return strings.ToUpper(a) < strings.ToUpper(b)
+}
+
+// writeCFB create a compound file binary (CFB) version 3 file with 512 bytes
+// sectors by given streams, the paths of the streams are separated by slash,
+// and the storages in the paths will be created automatically.
+func writeCFB(streams map[string][]byte) []byte {
+	const endOfChain, freeSect, fatSect, difSect, noStream = 0xFFFFFFFE, 0xFFFFFFFF, 0xFFFFFFFD, 0xFFFFFFFC, 0xFFFFFFFF
+	root := &cfbEntry{name: "Root Entry", storage: true, left: noStream, right: noStream}
+	var paths []string
+	for path := range streams {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		parent, names := root, strings.Split(path, "/")
+		for i, name := range names {
+			var entry *cfbEntry
+			for _, child := range parent.children {
+				if child.name == name {
+					entry = child
+					break
+				}
+			}
+			if entry == nil {
+				entry = &cfbEntry{name: name, storage: i < len(names)-1}
+				parent.children = append(parent.children, entry)
+			}
+			if i == len(names)-1 {
+				entry.data = streams[path]
+			}
+			parent = entry
+		}
+	}
+	// Flatten the directory entries, and build the children of each storage
+	// as a balanced binary search tree.
+	entries := []*cfbEntry{root}
+	var tree func(children []*cfbEntry) uint32
+	tree = func(children []*cfbEntry) uint32 {
+		if len(children) == 0 {
+			return noStream
+		}
+		mid := len(children) / 2
+		children[mid].left, children[mid].right = tree(children[:mid]), tree(children[mid+1:])
+		return uint32(children[mid].id)
+	}
+	for i := 0; i < len(entries); i++ {
+		children := entries[i].children
+		sort.Slice(children, func(a, b int) bool { return cfbLess(children[a].name, children[b].name) })
+		for _, child := range children {
+			child.id = len(entries)
+			entries = append(entries, child)
+		}
+		entries[i].child = tree(children)
+	}
+	// Allocate the sectors for the directory, mini stream and the streams.
+	sectors := func(size, sectorSize int) int { return (size + sectorSize - 1) / sectorSize }
+	var fat, miniFAT []uint32
+	var body, mini []byte
+	alloc := func(table *[]uint32, buf *[]byte, data []byte, sectorSize int) uint32 {
+		n := sectors(len(data), sectorSize)
+		if n == 0 {
+			return endOfChain
+		}
+		first := len(*table)
+		for i := 0; i < n; i++ {
+			*table = append(*table, uint32(len(*table)+1))
+		}
+		(*table)[len(*table)-1] = endOfChain
+		*buf = append(*buf, data...)
+		*buf = append(*buf, make([]byte, n*sectorSize-len(data))...)
+		return uint32(first)
+	}
+	dirStart := alloc(&fat, &body, make([]byte, len(entries)*128), 512)
+	for _, entry := range entries[1:] {
+		if !entry.storage && len(entry.data) < 4096 {
+			entry.start = alloc(&miniFAT, &mini, entry.data, 64)
+		}
+	}
+	miniFATBuf := make([]byte, len(miniFAT)*4)
+	for i, v := range miniFAT {
+		binary.LittleEndian.PutUint32(miniFATBuf[i*4:], v)
+	}
+	miniFATStart := alloc(&fat, &body, miniFATBuf, 512)
+	root.start, root.data = alloc(&fat, &body, mini, 512), mini
+	for _, entry := range entries[1:] {
+		if !entry.storage && len(entry.data) >= 4096 {
+			entry.start = alloc(&fat, &body, entry.data, 512)
+		}
+	}
+	// Write the directory entries.
+	dir := body[:sectors(len(entries)*128, 512)*512]
+	for i := 0; i < len(dir)/128; i++ {
+		for _, offset := range []int{68, 72, 76} {
+			binary.LittleEndian.PutUint32(dir[i*128+offset:], noStream)
+		}
+	}
+	for _, entry := range entries {
+		buf := dir[entry.id*128 : (entry.id+1)*128]
+		u := utf16.Encode([]rune(entry.name))
+		if len(u) > 31 {
+			u = u[:31]
+		}
+		for i, c := range u {
+			binary.LittleEndian.PutUint16(buf[i*2:], c)
+		}
+		binary.LittleEndian.PutUint16(buf[64:], uint16(len(u)*2+2))
+		buf[66], buf[67] = 2, 1
+		if entry.storage {
+			buf[66] = 1
+		}
+		if entry == root {
+			buf[66] = 5
+		}
+		binary.LittleEndian.PutUint32(buf[68:], entry.left)
+		binary.LittleEndian.PutUint32(buf[72:], entry.right)
+		if !entry.storage {
+			entry.child = noStream
+		}
+		binary.LittleEndian.PutUint32(buf[76:], entry.child)
+		if entry == root || !entry.storage {
+			binary.LittleEndian.PutUint32(buf[116:], entry.start)
+			binary.LittleEndian.PutUint32(buf[120:], uint32(len(entry.data)))
+		}
+	}
+	// Allocate the sectors for the FAT and the DIFAT.
+	bodySectors, fatSectors, difatSectors := len(fat), 0, 0
+	for {
+		n := sectors(bodySectors+fatSectors+difatSectors, 128)
+		d := 0
+		if n > 109 {
+			d = sectors(n-109, 127)
+		}
+		if n == fatSectors && d == difatSectors {
+			break
+		}
+		fatSectors, difatSectors = n, d
+	}
+	var difat []uint32
+	for i := 0; i < fatSectors; i++ {
+		difat = append(difat, uint32(len(fat)))
+		fat = append(fat, fatSect)
+	}
+	for i := 0; i < difatSectors; i++ {
+		fat = append(fat, difSect)
+	}
+	header := make([]byte, 512)
+	copy(header, oleIdentifier)
+	binary.LittleEndian.PutUint16(header[24:], 0x003E)
+	binary.LittleEndian.PutUint16(header[26:], 3)
+	binary.LittleEndian.PutUint16(header[28:], 0xFFFE)
+	binary.LittleEndian.PutUint16(header[30:], 9)
+	binary.LittleEndian.PutUint16(header[32:], 6)
+	binary.LittleEndian.PutUint32(header[44:], uint32(fatSectors))
+	binary.LittleEndian.PutUint32(header[48:], dirStart)
+	binary.LittleEndian.PutUint32(header[56:], 4096)
+	binary.LittleEndian.PutUint32(header[60:], miniFATStart)
+	binary.LittleEndian.PutUint32(header[64:], uint32(sectors(len(miniFATBuf), 512)))
+	binary.LittleEndian.PutUint32(header[68:], endOfChain)
+	if difatSectors > 0 {
+		binary.LittleEndian.PutUint32(header[68:], uint32(bodySectors+fatSectors))
+	}
+	binary.LittleEndian.PutUint32(header[72:], uint32(difatSectors))
+	for i := 0; i < 109; i++ {
+		v := uint32(freeSect)
+		if i < len(difat) {
+			v = difat[i]
+		}
+		binary.LittleEndian.PutUint32(header[76+i*4:], v)
+	}
+	fatBuf := bytes.Repeat([]byte{0xFF}, fatSectors*512)
+	for i, v := range fat {
+		binary.LittleEndian.PutUint32(fatBuf[i*4:], v)
+	}
+	difatBuf := bytes.Repeat([]byte{0xFF}, difatSectors*512)
+	for i := 0; i < difatSectors; i++ {
+		for j := 0; j < 127 && 109+i*127+j < len(difat); j++ {
+			binary.LittleEndian.PutUint32(difatBuf[i*512+j*4:], difat[109+i*127+j])
+		}
+		next := uint32(endOfChain)
+		if i+1 < difatSectors {
+			next = uint32(bodySectors + fatSectors + i + 1)
+		}
+		binary.LittleEndian.PutUint32(difatBuf[i*512+508:], next)
+	}
+	return bytes.Join([][]byte{header, body, fatBuf, difatBuf}, nil)
+}
@@ -1,17 +1,141 @@
 package xlsx
 
 import (
+	"bytes"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/richardlehane/mscfb"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestEncrypt(t *testing.T) {
+	// Test re-encrypt the spreadsheet with the same encryption settings.
 	f, err := OpenFile(filepath.Join("test", "encryptSHA1.xlsx"), Options{Password: "password"})
 	assert.NoError(t, err)
-	assert.EqualError(t, f.SaveAs(filepath.Join("test", "BadEncrypt.xlsx"), Options{Password: "password"}), "not support encryption currently")
+	assert.Equal(t, "agile", f.options.EncryptionMechanism)
+	assert.Equal(t, "AES-128", f.options.CipherAlgorithm)
+	assert.Equal(t, "SHA1", f.options.HashAlgorithm)
+	assert.Equal(t, 100000, f.options.SpinCount)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestEncrypt.xlsx"), Options{Password: "passwd"}))
 	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestEncrypt.xlsx"), Options{Password: "passwd"})
+	assert.NoError(t, err)
+	assert.Equal(t, Options{Password: "passwd", UnzipSizeLimit: UnzipSizeLimit, WorksheetUnzipMemLimit: StreamChunkSize,
+		EncryptionMechanism: "agile", CipherAlgorithm: "AES-128", HashAlgorithm: "SHA1", SpinCount: 100000}, *f.options)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SECRET", val)
+	// Test save the opened spreadsheet with the password.
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", "Saved"))
+	assert.NoError(t, f.Save())
+	assert.NoError(t, f.Close())
+	_, err = OpenFile(filepath.Join("test", "TestEncrypt.xlsx"))
+	assert.EqualError(t, err, ErrWorkbookPassword.Error())
+	_, err = OpenFile(filepath.Join("test", "TestEncrypt.xlsx"), Options{Password: "password"})
+	assert.EqualError(t, err, ErrWorkbookPassword.Error())
+	f, err = OpenFile(filepath.Join("test", "TestEncrypt.xlsx"), Options{Password: "passwd"})
+	assert.NoError(t, err)
+	val, err = f.GetCellValue("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Saved", val)
+	assert.NoError(t, f.Close())
+
+	// Test encrypt the spreadsheet with the specified encryption settings.
+	for _, opts := range []Options{
+		{},
+		{CipherAlgorithm: "AES-128", HashAlgorithm: "SHA1", SpinCount: 1000},
+		{CipherAlgorithm: "aes-192", HashAlgorithm: "sha256", SpinCount: 1000},
+		{EncryptionMechanism: "agile", HashAlgorithm: "SHA384", SpinCount: 1000},
+		{EncryptionMechanism: "standard"},
+		{EncryptionMechanism: "Standard", CipherAlgorithm: "AES-256", HashAlgorithm: "SHA1"},
+	} {
+		f = NewFile()
+		assert.NoError(t, f.SetCellValue("Sheet1", "A1", "SECRET"))
+		opts.Password = "password"
+		buf, err := f.WriteToBuffer()
+		assert.NoError(t, err)
+		raw, err := Encrypt(buf.Bytes(), &opts)
+		assert.NoError(t, err)
+		opened := Options{Password: "password"}
+		decrypted, err := Decrypt(raw, &opened)
+		assert.NoError(t, err)
+		assert.Equal(t, buf.Bytes(), decrypted)
+		mechanism, keyBits, hashAlgorithm, spinCount, err := encryptionOptions(&opts)
+		assert.NoError(t, err)
+		if mechanism == "standard" {
+			spinCount = iterCount
+		}
+		assert.Equal(t, Options{Password: "password", EncryptionMechanism: mechanism, CipherAlgorithm: "AES-" + strconv.Itoa(keyBits),
+			HashAlgorithm: hashAlgorithm, SpinCount: spinCount}, opened)
+		f, err = OpenReader(bytes.NewReader(raw), Options{Password: "password"})
+		assert.NoError(t, err)
+		val, err := f.GetCellValue("Sheet1", "A1")
+		assert.NoError(t, err)
+		assert.Equal(t, "SECRET", val)
+		_, err = OpenReader(bytes.NewReader(raw), Options{Password: "passwd"})
+		assert.EqualError(t, err, ErrWorkbookPassword.Error())
+	}
+
+	// Test encrypt the spreadsheet with invalid encryption settings.
+	f = NewFile()
+	for _, c := range []struct {
+		opts Options
+		err  error
+	}{
+		{Options{Password: "password", EncryptionMechanism: "extensible"}, ErrEncrypt},
+		{Options{Password: "password", CipherAlgorithm: "RC4"}, ErrCipherAlgorithm},
+		{Options{Password: "password", HashAlgorithm: "MD5"}, ErrHashAlgorithm},
+		{Options{Password: "password", EncryptionMechanism: "standard", HashAlgorithm: "SHA512"}, ErrHashAlgorithm},
+		{Options{Password: "password", SpinCount: -1}, ErrSpinCount},
+		{Options{Password: "password", SpinCount: MaxSpinCount + 1}, ErrSpinCount},
+	} {
+		assert.EqualError(t, f.SaveAs(filepath.Join("test", "TestEncrypt.xlsx"), c.opts), c.err.Error())
+	}
+}
+
+func TestDecryptDataIntegrity(t *testing.T) {
+	raw := bytes.Repeat([]byte("xlsx"), packageEncryptionChunkSize)
+	encryptionInfo, encryptedPackage, err := agileEncrypt(raw, "password", 256, "SHA512", 1000)
+	assert.NoError(t, err)
+	decrypted, err := Decrypt(writeCFB(map[string][]byte{"EncryptionInfo": encryptionInfo, "EncryptedPackage": encryptedPackage}), &Options{Password: "password"})
+	assert.NoError(t, err)
+	assert.Equal(t, raw, decrypted)
+	// Test decrypt the tampered encrypted package.
+	encryptedPackage[len(encryptedPackage)-1] ^= 0xFF
+	_, err = Decrypt(writeCFB(map[string][]byte{"EncryptionInfo": encryptionInfo, "EncryptedPackage": encryptedPackage}), &Options{Password: "password"})
+	assert.EqualError(t, err, ErrWorkbookDataIntegrity.Error())
+	_, err = OpenReader(bytes.NewReader(writeCFB(map[string][]byte{"EncryptionInfo": encryptionInfo, "EncryptedPackage": encryptedPackage})), Options{Password: "password"})
+	assert.EqualError(t, err, ErrWorkbookDataIntegrity.Error())
+}
+
+func TestWriteCFB(t *testing.T) {
+	// Test write the compound file with storages and the large stream which
+	// require the DIFAT sectors.
+	large := bytes.Repeat([]byte{1, 2, 3, 4}, 2<<20)
+	streams := encryptionDataSpaces()
+	streams["EncryptedPackage"], streams["EncryptionInfo"] = large, []byte("info")
+	doc, err := mscfb.New(bytes.NewReader(writeCFB(streams)))
+	assert.NoError(t, err)
+	read := map[string][]byte{}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		buf := make([]byte, entry.Size)
+		_, _ = doc.Read(buf)
+		path := entry.Name
+		for i := len(entry.Path) - 1; i >= 0; i-- {
+			path = entry.Path[i] + "/" + path
+		}
+		read[path] = buf
+	}
+	assert.Len(t, read, len(streams))
+	for path, data := range streams {
+		assert.True(t, bytes.Equal(data, read[strings.ReplaceAll(path, "\x06", "")]), path)
+	}
 }
 
 func TestEncryptionMechanism(t *testing.T) {
@@ -24,4 +148,5 @@ func TestEncryptionMechanism(t *testing.T) {
 
 func TestHashing(t *testing.T) {
 	assert.Equal(t, hashing("unsupportHashAlgorithm", []byte{}), []uint8([]byte(nil)))
+	assert.Nil(t, hmacHashing("unsupportHashAlgorithm", []byte{}))
 }
@@ -44,7 +44,7 @@ func (f *File) prepareChartSheetDrawing(cs *xlsxChartsheet, drawingID int, sheet
 
 // addChart provides a function to create chart as xl/charts/chart%d.xml by
 // given format sets.
-func (f *File) addChart(formatSet *formatChart, comboCharts []*formatChart) {
+func (f *File) addChart(formatSet *ChartOptions, comboCharts []*ChartOptions) {
 	count := f.countCharts()
 	xlsxChartSpace := xlsxChartSpace{
 		XMLNSa:         NameSpaceDrawingML.Value,
@@ -168,7 +168,7 @@ func (f *File) addChart(formatSet *formatChart, comboCharts []*formatChart) {
 			},
 		},
 	}
-	plotAreaFunc := map[string]func(*formatChart) *cPlotArea{
+	plotAreaFunc := map[string]func(*ChartOptions) *cPlotArea{
 		Area:                        f.drawBaseChart,
 		AreaStacked:                 f.drawBaseChart,
 		AreaPercentStacked:          f.drawBaseChart,
@@ -251,7 +251,7 @@ func (f *File) addChart(formatSet *formatChart, comboCharts []*formatChart) {
 
 // drawBaseChart provides a function to draw the c:plotArea element for bar,
 // and column series charts by given format sets.
-func (f *File) drawBaseChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawBaseChart(formatSet *ChartOptions) *cPlotArea {
 	c := cCharts{
 		BarDir: &attrValString{
 			Val: stringPtr("col"),
@@ -260,7 +260,7 @@ func (f *File) drawBaseChart(formatSet *formatChart) *cPlotArea {
 			Val: stringPtr("clustered"),
 		},
 		VaryColors: &attrValBool{
-			Val: boolPtr(formatSet.VaryColors),
+			Val: formatSet.VaryColors,
 		},
 		Ser:   f.drawChartSeries(formatSet),
 		Shape: f.drawChartShape(formatSet),
@@ -500,11 +500,11 @@ func (f *File) drawBaseChart(formatSet *formatChart) *cPlotArea {
 
 // drawDoughnutChart provides a function to draw the c:plotArea element for
 // doughnut chart by given format sets.
-func (f *File) drawDoughnutChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawDoughnutChart(formatSet *ChartOptions) *cPlotArea {
 	return &cPlotArea{
 		DoughnutChart: &cCharts{
 			VaryColors: &attrValBool{
-				Val: boolPtr(formatSet.VaryColors),
+				Val: formatSet.VaryColors,
 			},
 			Ser:      f.drawChartSeries(formatSet),
 			HoleSize: &attrValInt{Val: intPtr(75)},
@@ -514,7 +514,7 @@ func (f *File) drawDoughnutChart(formatSet *formatChart) *cPlotArea {
 
 // drawLineChart provides a function to draw the c:plotArea element for line
 // chart by given format sets.
-func (f *File) drawLineChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawLineChart(formatSet *ChartOptions) *cPlotArea {
 	return &cPlotArea{
 		LineChart: &cCharts{
 			Grouping: &attrValString{
@@ -540,11 +540,11 @@ func (f *File) drawLineChart(formatSet *formatChart) *cPlotArea {
 
 // drawPieChart provides a function to draw the c:plotArea element for pie
 // chart by given format sets.
-func (f *File) drawPieChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawPieChart(formatSet *ChartOptions) *cPlotArea {
 	return &cPlotArea{
 		PieChart: &cCharts{
 			VaryColors: &attrValBool{
-				Val: boolPtr(formatSet.VaryColors),
+				Val: formatSet.VaryColors,
 			},
 			Ser: f.drawChartSeries(formatSet),
 		},
@@ -553,11 +553,11 @@ func (f *File) drawPieChart(formatSet *formatChart) *cPlotArea {
 
 // drawPie3DChart provides a function to draw the c:plotArea element for 3D
 // pie chart by given format sets.
-func (f *File) drawPie3DChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawPie3DChart(formatSet *ChartOptions) *cPlotArea {
 	return &cPlotArea{
 		Pie3DChart: &cCharts{
 			VaryColors: &attrValBool{
-				Val: boolPtr(formatSet.VaryColors),
+				Val: formatSet.VaryColors,
 			},
 			Ser: f.drawChartSeries(formatSet),
 		},
@@ -566,14 +566,14 @@ func (f *File) drawPie3DChart(formatSet *formatChart) *cPlotArea {
 
 // drawPieOfPieChart provides a function to draw the c:plotArea element for
 // pie chart by given format sets.
-func (f *File) drawPieOfPieChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawPieOfPieChart(formatSet *ChartOptions) *cPlotArea {
 	return &cPlotArea{
 		OfPieChart: &cCharts{
 			OfPieType: &attrValString{
 				Val: stringPtr("pie"),
 			},
 			VaryColors: &attrValBool{
-				Val: boolPtr(formatSet.VaryColors),
+				Val: formatSet.VaryColors,
 			},
 			Ser:      f.drawChartSeries(formatSet),
 			SerLines: &attrValString{},
@@ -583,14 +583,14 @@ func (f *File) drawPieOfPieChart(formatSet *formatChart) *cPlotArea {
 
 // drawBarOfPieChart provides a function to draw the c:plotArea element for
 // pie chart by given format sets.
-func (f *File) drawBarOfPieChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawBarOfPieChart(formatSet *ChartOptions) *cPlotArea {
 	return &cPlotArea{
 		OfPieChart: &cCharts{
 			OfPieType: &attrValString{
 				Val: stringPtr("bar"),
 			},
 			VaryColors: &attrValBool{
-				Val: boolPtr(formatSet.VaryColors),
+				Val: formatSet.VaryColors,
 			},
 			Ser:      f.drawChartSeries(formatSet),
 			SerLines: &attrValString{},
@@ -600,7 +600,7 @@ func (f *File) drawBarOfPieChart(formatSet *formatChart) *cPlotArea {
 
 // drawRadarChart provides a function to draw the c:plotArea element for radar
 // chart by given format sets.
-func (f *File) drawRadarChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawRadarChart(formatSet *ChartOptions) *cPlotArea {
 	return &cPlotArea{
 		RadarChart: &cCharts{
 			RadarStyle: &attrValString{
@@ -623,7 +623,7 @@ func (f *File) drawRadarChart(formatSet *formatChart) *cPlotArea {
 
 // drawScatterChart provides a function to draw the c:plotArea element for
 // scatter chart by given format sets.
-func (f *File) drawScatterChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawScatterChart(formatSet *ChartOptions) *cPlotArea {
 	return &cPlotArea{
 		ScatterChart: &cCharts{
 			ScatterStyle: &attrValString{
@@ -646,7 +646,7 @@ func (f *File) drawScatterChart(formatSet *formatChart) *cPlotArea {
 
 // drawSurface3DChart provides a function to draw the c:surface3DChart element by
 // given format sets.
-func (f *File) drawSurface3DChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawSurface3DChart(formatSet *ChartOptions) *cPlotArea {
 	plotArea := &cPlotArea{
 		Surface3DChart: &cCharts{
 			Ser: f.drawChartSeries(formatSet),
@@ -668,7 +668,7 @@ func (f *File) drawSurface3DChart(formatSet *formatChart) *cPlotArea {
 
 // drawSurfaceChart provides a function to draw the c:surfaceChart element by
 // given format sets.
-func (f *File) drawSurfaceChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawSurfaceChart(formatSet *ChartOptions) *cPlotArea {
 	plotArea := &cPlotArea{
 		SurfaceChart: &cCharts{
 			Ser: f.drawChartSeries(formatSet),
@@ -690,7 +690,7 @@ func (f *File) drawSurfaceChart(formatSet *formatChart) *cPlotArea {
 
 // drawChartShape provides a function to draw the c:shape element by given
 // format sets.
-func (f *File) drawChartShape(formatSet *formatChart) *attrValString {
+func (f *File) drawChartShape(formatSet *ChartOptions) *attrValString {
 	shapes := map[string]string{
 		Bar3DConeClustered:          "cone",
 		Bar3DConeStacked:            "cone",
@@ -722,7 +722,7 @@ func (f *File) drawChartShape(formatSet *formatChart) *attrValString {
 
 // drawChartSeries provides a function to draw the c:ser element by given
 // format sets.
-func (f *File) drawChartSeries(formatSet *formatChart) *[]cSer {
+func (f *File) drawChartSeries(formatSet *ChartOptions) *[]cSer {
 	ser := []cSer{}
 	for k := range formatSet.Series {
 		ser = append(ser, cSer{
@@ -751,7 +751,7 @@ func (f *File) drawChartSeries(formatSet *formatChart) *[]cSer {
 
 // drawChartSeriesSpPr provides a function to draw the c:spPr element by given
 // format sets.
-func (f *File) drawChartSeriesSpPr(i int, formatSet *formatChart) *cSpPr {
+func (f *File) drawChartSeriesSpPr(i int, formatSet *ChartOptions) *cSpPr {
 	spPrScatter := &cSpPr{
 		Ln: &aLn{
 			W:      25400,
@@ -773,7 +773,7 @@ func (f *File) drawChartSeriesSpPr(i int, formatSet *formatChart) *cSpPr {
 
 // drawChartSeriesDPt provides a function to draw the c:dPt element by given
 // data index and format sets.
-func (f *File) drawChartSeriesDPt(i int, formatSet *formatChart) []*cDPt {
+func (f *File) drawChartSeriesDPt(i int, formatSet *ChartOptions) []*cDPt {
 	dpt := []*cDPt{{
 		IDx:      &attrValInt{Val: intPtr(i)},
 		Bubble3D: &attrValBool{Val: boolPtr(false)},
@@ -802,7 +802,7 @@ func (f *File) drawChartSeriesDPt(i int, formatSet *formatChart) []*cDPt {
 
 // drawChartSeriesCat provides a function to draw the c:cat element by given
 // chart series and format sets.
-func (f *File) drawChartSeriesCat(v formatChartSeries, formatSet *formatChart) *cCat {
+func (f *File) drawChartSeriesCat(v ChartSeries, formatSet *ChartOptions) *cCat {
 	cat := &cCat{
 		StrRef: &cStrRef{
 			F: v.Categories,
@@ -817,7 +817,7 @@ func (f *File) drawChartSeriesCat(v formatChartSeries, formatSet *formatChart) *
 
 // drawChartSeriesVal provides a function to draw the c:val element by given
 // chart series and format sets.
-func (f *File) drawChartSeriesVal(v formatChartSeries, formatSet *formatChart) *cVal {
+func (f *File) drawChartSeriesVal(v ChartSeries, formatSet *ChartOptions) *cVal {
 	val := &cVal{
 		NumRef: &cNumRef{
 			F: v.Values,
@@ -832,7 +832,7 @@ func (f *File) drawChartSeriesVal(v formatChartSeries, formatSet *formatChart) *
 
 // drawChartSeriesMarker provides a function to draw the c:marker element by
 // given data index and format sets.
-func (f *File) drawChartSeriesMarker(i int, formatSet *formatChart) *cMarker {
+func (f *File) drawChartSeriesMarker(i int, formatSet *ChartOptions) *cMarker {
 	defaultSymbol := map[string]*attrValString{Scatter: {Val: stringPtr("circle")}}
 	marker := &cMarker{
 		Symbol: defaultSymbol[formatSet.Type],
@@ -867,7 +867,7 @@ func (f *File) drawChartSeriesMarker(i int, formatSet *formatChart) *cMarker {
 
 // drawChartSeriesXVal provides a function to draw the c:xVal element by given
 // chart series and format sets.
-func (f *File) drawChartSeriesXVal(v formatChartSeries, formatSet *formatChart) *cCat {
+func (f *File) drawChartSeriesXVal(v ChartSeries, formatSet *ChartOptions) *cCat {
 	cat := &cCat{
 		StrRef: &cStrRef{
 			F: v.Categories,
@@ -879,7 +879,7 @@ func (f *File) drawChartSeriesXVal(v formatChartSeries, formatSet *formatChart)
 
 // drawChartSeriesYVal provides a function to draw the c:yVal element by given
 // chart series and format sets.
-func (f *File) drawChartSeriesYVal(v formatChartSeries, formatSet *formatChart) *cVal {
+func (f *File) drawChartSeriesYVal(v ChartSeries, formatSet *ChartOptions) *cVal {
 	val := &cVal{
 		NumRef: &cNumRef{
 			F: v.Values,
@@ -891,7 +891,7 @@ func (f *File) drawChartSeriesYVal(v formatChartSeries, formatSet *formatChart)
 
 // drawCharSeriesBubbleSize provides a function to draw the c:bubbleSize
 // element by given chart series and format sets.
-func (f *File) drawCharSeriesBubbleSize(v formatChartSeries, formatSet *formatChart) *cVal {
+func (f *File) drawCharSeriesBubbleSize(v ChartSeries, formatSet *ChartOptions) *cVal {
 	if _, ok := map[string]bool{Bubble: true, Bubble3D: true}[formatSet.Type]; !ok {
 		return nil
 	}
@@ -904,7 +904,7 @@ func (f *File) drawCharSeriesBubbleSize(v formatChartSeries, formatSet *formatCh
 
 // drawCharSeriesBubble3D provides a function to draw the c:bubble3D element
 // by given format sets.
-func (f *File) drawCharSeriesBubble3D(formatSet *formatChart) *attrValBool {
+func (f *File) drawCharSeriesBubble3D(formatSet *ChartOptions) *attrValBool {
 	if _, ok := map[string]bool{Bubble3D: true}[formatSet.Type]; !ok {
 		return nil
 	}
@@ -913,7 +913,7 @@ func (f *File) drawCharSeriesBubble3D(formatSet *formatChart) *attrValBool {
 
 // drawChartDLbls provides a function to draw the c:dLbls element by given
 // format sets.
-func (f *File) drawChartDLbls(formatSet *formatChart) *cDLbls {
+func (f *File) drawChartDLbls(formatSet *ChartOptions) *cDLbls {
 	return &cDLbls{
 		ShowLegendKey:   &attrValBool{Val: boolPtr(formatSet.Legend.ShowLegendKey)},
 		ShowVal:         &attrValBool{Val: boolPtr(formatSet.Plotarea.ShowVal)},
@@ -927,7 +927,7 @@ func (f *File) drawChartDLbls(formatSet *formatChart) *cDLbls {
 
 // drawChartSeriesDLbls provides a function to draw the c:dLbls element by
 // given format sets.
-func (f *File) drawChartSeriesDLbls(formatSet *formatChart) *cDLbls {
+func (f *File) drawChartSeriesDLbls(formatSet *ChartOptions) *cDLbls {
 	dLbls := f.drawChartDLbls(formatSet)
 	chartSeriesDLbls := map[string]*cDLbls{
 		Scatter: nil, Surface3D: nil, WireframeSurface3D: nil, Contour: nil, WireframeContour: nil, Bubble: nil, Bubble3D: nil}
@@ -938,7 +938,7 @@ func (f *File) drawChartSeriesDLbls(formatSet *formatChart) *cDLbls {
 }
 
 // drawPlotAreaCatAx provides a function to draw the c:catAx element.
-func (f *File) drawPlotAreaCatAx(formatSet *formatChart) []*cAxs {
+func (f *File) drawPlotAreaCatAx(formatSet *ChartOptions) []*cAxs {
 	min := &attrValFloat{Val: float64Ptr(formatSet.XAxis.Minimum)}
 	max := &attrValFloat{Val: float64Ptr(formatSet.XAxis.Maximum)}
 	if formatSet.XAxis.Minimum == 0 {
@@ -987,7 +987,7 @@ func (f *File) drawPlotAreaCatAx(formatSet *formatChart) []*cAxs {
 }
 
 // drawPlotAreaValAx provides a function to draw the c:valAx element.
-func (f *File) drawPlotAreaValAx(formatSet *formatChart) []*cAxs {
+func (f *File) drawPlotAreaValAx(formatSet *ChartOptions) []*cAxs {
 	min := &attrValFloat{Val: float64Ptr(formatSet.YAxis.Minimum)}
 	max := &attrValFloat{Val: float64Ptr(formatSet.YAxis.Maximum)}
 	if formatSet.YAxis.Minimum == 0 {
@@ -1041,7 +1041,7 @@ func (f *File) drawPlotAreaValAx(formatSet *formatChart) []*cAxs {
 }
 
 // drawPlotAreaSerAx provides a function to draw the c:serAx element.
-func (f *File) drawPlotAreaSerAx(formatSet *formatChart) []*cAxs {
+func (f *File) drawPlotAreaSerAx(formatSet *ChartOptions) []*cAxs {
 	min := &attrValFloat{Val: float64Ptr(formatSet.YAxis.Minimum)}
 	max := &attrValFloat{Val: float64Ptr(formatSet.YAxis.Maximum)}
 	if formatSet.YAxis.Minimum == 0 {
@@ -1173,7 +1173,7 @@ func (f *File) drawingParser(path string) (*xlsxWsDr, int) {
 
 // addDrawingChart provides a function to add chart graphic frame by given
 // sheet, drawingXML, cell, width, height, relationship index and format sets.
-func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rID int, formatSet *formatPicture) error {
+func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rID int, formatSet *PictureOptions) error {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
 		return err
@@ -1223,7 +1223,7 @@ func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rI
 	twoCellAnchor.GraphicFrame = string(graphic)
 	twoCellAnchor.ClientData = &xdrClientData{
 		FLocksWithSheet:  formatSet.FLocksWithSheet,
-		FPrintsWithSheet: formatSet.FPrintsWithSheet,
+		FPrintsWithSheet: *formatSet.FPrintsWithSheet,
 	}
 	content.TwoCellAnchor = append(content.TwoCellAnchor, &twoCellAnchor)
 	f.Drawings.Store(drawingXML, content)
@@ -1233,7 +1233,7 @@ func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rI
 // addSheetDrawingChart provides a function to add chart graphic frame for
 // chartsheet by given sheet, drawingXML, width, height, relationship index
 // and format sets.
-func (f *File) addSheetDrawingChart(drawingXML string, rID int, formatSet *formatPicture) {
+func (f *File) addSheetDrawingChart(drawingXML string, rID int, formatSet *PictureOptions) {
 	content, cNvPrID := f.drawingParser(drawingXML)
 	absoluteAnchor := xdrCellAnchor{
 		EditAs: formatSet.Positioning,
@@ -1263,7 +1263,7 @@ func (f *File) addSheetDrawingChart(drawingXML string, rID int, formatSet *forma
 	absoluteAnchor.GraphicFrame = string(graphic)
 	absoluteAnchor.ClientData = &xdrClientData{
 		FLocksWithSheet:  formatSet.FLocksWithSheet,
-		FPrintsWithSheet: formatSet.FPrintsWithSheet,
+		FPrintsWithSheet: *formatSet.FPrintsWithSheet,
 	}
 	content.AbsoluteAnchor = append(content.AbsoluteAnchor, &absoluteAnchor)
 	f.Drawings.Store(drawingXML, content)
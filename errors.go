@@ -40,15 +40,88 @@ func newInvalidStyleID(styleID int) error {
 	return fmt.Errorf("invalid style ID %d, negative values are not supported", styleID)
 }
 
+// newStyleNotExistError defined the error message on receiving the style ID
+// which is not exist.
+func newStyleNotExistError(styleID int) error {
+	return fmt.Errorf("style ID %d is not exist", styleID)
+}
+
+// newNamedStyleNotExistError defined the error message on receiving the named
+// cell style name which is not exist.
+func newNamedStyleNotExistError(name string) error {
+	return fmt.Errorf("named style %s is not exist", name)
+}
+
 // newFieldLengthError defined the error message on receiving the field length overflow.
 func newFieldLengthError(name string) error {
 	return fmt.Errorf("field %s must be less or equal than 255 characters", name)
 }
 
+// newUnsupportedOptionError defined the error message on receiving the
+// unsupported value of the option.
+func newUnsupportedOptionError(option, value string) error {
+	return fmt.Errorf("unsupported %s %q", option, value)
+}
+
+// newNegativeOptionError defined the error message on receiving the negative
+// value of the option which only accepts non-negative values.
+func newNegativeOptionError(option string, value float64) error {
+	return fmt.Errorf("invalid %s %v, negative values are not supported", option, value)
+}
+
+// newXLSBFormulaError defined the error message on writing the formula which
+// could not be encoded and has no cached value in the binary workbook.
+func newXLSBFormulaError(cell, formula string) error {
+	return fmt.Errorf("unsupported formula %q in cell %s without cached value for the binary workbook", formula, cell)
+}
+
+// newOptionRangeError defined the error message on receiving the value of the
+// option which is out of range.
+func newOptionRangeError(option string, value, min, max float64) error {
+	return fmt.Errorf("%s %v is out of range, must be between %v and %v", option, value, min, max)
+}
+
 var (
 	// ErrStreamSetColWidth defined the error message on set column width in
 	// stream writing mode.
 	ErrStreamSetColWidth = errors.New("must call the SetColWidth function before the SetRow function")
+	// ErrStreamSetColStyle defined the error message on set column style in
+	// stream writing mode.
+	ErrStreamSetColStyle = errors.New("must call the SetColStyle function before the SetRow function")
+	// ErrStreamSetColOutlineLevel defined the error message on set column
+	// outline level in stream writing mode.
+	ErrStreamSetColOutlineLevel = errors.New("must call the SetColOutlineLevel function before the SetRow function")
+	// ErrStreamSetColVisible defined the error message on set column visible
+	// in stream writing mode.
+	ErrStreamSetColVisible = errors.New("must call the SetColVisible function before the SetRow function")
+	// ErrCellErrorValue defined the error message on receiving an invalid
+	// error value of the cell.
+	ErrCellErrorValue = errors.New("invalid cell error value")
+	// ErrStreamSetPanes defined the error message on set panes in stream
+	// writing mode.
+	ErrStreamSetPanes = errors.New("must call the SetPanes function before the SetRow function")
+	// ErrStreamSetSheetView defined the error message on set sheet view
+	// options in stream writing mode.
+	ErrStreamSetSheetView = errors.New("must call the SetSheetViewOptions function before the SetRow function")
+	// ErrStreamOutput defined the error message on writing the workbook while
+	// it is being written to the streaming output.
+	ErrStreamOutput = errors.New("the workbook is being written to the streaming output")
+	// ErrStreamNotFlushed defined the error message on create stream writer
+	// or end the streaming output before the previous stream writer flushed.
+	ErrStreamNotFlushed = errors.New("must call the Flush function of the previous stream writer on the streaming output")
+	// ErrStreamTableHeader defined the error message on add table for the
+	// stream writer on the streaming output with the header row which is not
+	// the first row written.
+	ErrStreamTableHeader = errors.New("the table header must be the first row written on the streaming output")
+	// ErrRowsIteration defined the error message on get the worksheet
+	// elements after the sheet data before the rows iteration completes.
+	ErrRowsIteration = errors.New("must iterate all rows before getting the worksheet elements after the sheet data")
+	// ErrNamedStyleName defined the error message on receiving the invalid
+	// named cell style name.
+	ErrNamedStyleName = errors.New("the name of the named style must be 1 to 255 characters")
+	// ErrCompactStylesStream defined the error message on compact the
+	// stylesheet of the workbook with stream writer.
+	ErrCompactStylesStream = errors.New("can't compact the stylesheet of the workbook with stream writer")
 	// ErrColumnNumber defined the error message on receive an invalid column
 	// number.
 	ErrColumnNumber = errors.New("column number exceeds maximum limit")
@@ -84,14 +157,36 @@ var (
 	// ErrMaxFileNameLength defined the error message on receive the file name
 	// length overflow.
 	ErrMaxFileNameLength = errors.New("file name length exceeds maximum limit")
-	// ErrEncrypt defined the error message on encryption spreadsheet.
-	ErrEncrypt = errors.New("not support encryption currently")
+	// ErrEncrypt defined the error message on receiving an unsupported
+	// encryption mechanism on encryption spreadsheet.
+	ErrEncrypt = errors.New("the encryption mechanism should be agile or standard")
+	// ErrCipherAlgorithm defined the error message on receiving an
+	// unsupported cipher algorithm on encryption spreadsheet.
+	ErrCipherAlgorithm = errors.New("the cipher algorithm should be AES-128, AES-192 or AES-256")
+	// ErrHashAlgorithm defined the error message on receiving an unsupported
+	// hash algorithm on encryption spreadsheet.
+	ErrHashAlgorithm = errors.New("the hash algorithm should be SHA1, SHA256, SHA384 or SHA512, and only SHA1 for the standard encryption")
+	// ErrSpinCount defined the error message on receiving an invalid spin
+	// count on encryption spreadsheet.
+	ErrSpinCount = fmt.Errorf("the spin count should be between 0 and %d", MaxSpinCount)
+	// ErrWorkbookDataIntegrity defined the error message on the data
+	// integrity check of the encrypted workbook failed.
+	ErrWorkbookDataIntegrity = errors.New("the data integrity check of the encrypted workbook failed")
 	// ErrUnknownEncryptMechanism defined the error message on unsupport
 	// encryption mechanism.
 	ErrUnknownEncryptMechanism = errors.New("unknown encryption mechanism")
 	// ErrUnsupportEncryptMechanism defined the error message on unsupport
 	// encryption mechanism.
 	ErrUnsupportEncryptMechanism = errors.New("unsupport encryption mechanism")
+	// ErrWorkbookPassword defined the error message on receiving the
+	// incorrect workbook password.
+	ErrWorkbookPassword = errors.New("the supplied open workbook password is not correct")
+	// ErrWorkbookFileFormat defined the error message on receiving an
+	// unsupported legacy workbook file format.
+	ErrWorkbookFileFormat = errors.New("unsupported workbook file format, only BIFF8 legacy workbooks are supported")
+	// ErrWorkbookBinaryPart defined the error message on receiving a
+	// corrupted BIFF12 record in the binary workbook parts.
+	ErrWorkbookBinaryPart = errors.New("invalid record in the binary workbook part")
 	// ErrParameterRequired defined the error message on receive the empty
 	// parameter.
 	ErrParameterRequired = errors.New("parameter is required")
@@ -128,4 +223,16 @@ var (
 	// ErrOptionsUnzipSizeLimit defined the error message for receiving
 	// invalid UnzipSizeLimit and WorksheetUnzipMemLimit.
 	ErrOptionsUnzipSizeLimit = errors.New("the value of UnzipSizeLimit should be greater than or equal to WorksheetUnzipMemLimit")
+	// ErrSortKeyColumn defined the error message on receive the sort key
+	// column outside the sort range.
+	ErrSortKeyColumn = errors.New("the column of the sort key must be in the sort range")
+	// ErrSortMergedCells defined the error message on sort the range which
+	// contains merged cells across multiple rows.
+	ErrSortMergedCells = errors.New("cannot sort the range which contains merged cells across multiple rows")
+	// ErrAutoFilterTop10Value defined the error message on receive the
+	// invalid number of items or percent of the top 10 auto filter.
+	ErrAutoFilterTop10Value = errors.New("the value of the top 10 filter must be 1-500 items or 1-100 percent")
+	// ErrFindValue defined the error message on receive the empty value to
+	// find.
+	ErrFindValue = errors.New("the value to find can not be empty")
 )
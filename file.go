@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 )
 
@@ -44,16 +45,45 @@ func NewFile() *File {
 	return f
 }
 
-// Save provides a function to override the spreadsheet with origin path.
+// Save provides a function to override the spreadsheet with origin path, the
+// spreadsheet opened with password will be encrypted with the same password.
 func (f *File) Save() error {
 	if f.Path == "" {
 		return fmt.Errorf("no path defined for file, consider File.WriteTo or File.Write")
 	}
+	if f.options != nil {
+		return f.SaveAs(f.Path, *f.options)
+	}
 	return f.SaveAs(f.Path)
 }
 
 // SaveAs provides a function to create or update to an spreadsheet at the
-// provided path.
+// provided path. The spreadsheet will be saved as binary (BIFF12) workbook if
+// the path with .xlsb extension, in which the workbook, worksheets, shared
+// strings and styles are written in binary parts. The formulas which only
+// contain the common built-in functions and the references without worksheet
+// name are written in binary parts, the other formulas are written as the
+// cached values, and an error will be returned if such a formula has no
+// cached value. Note that the calculation chain, defined names, conditional
+// formats, data validations and the rich text runs are not written in the
+// binary workbook.
+//
+// The spreadsheet will be saved as OpenDocument spreadsheet if the path with
+// .ods extension, the cell values, formulas in the OpenFormula syntax, merged
+// cells, column widths, row heights, basic cell styles and sheet visibility
+// are written, the other parts of the workbook such as charts, pictures and
+// comments are not written in the OpenDocument spreadsheet.
+//
+// The spreadsheet will be encrypted if the password specified in the
+// options, the encryption settings of the spreadsheet opened with password
+// will be kept if the encryption settings are not specified, for example,
+// save the spreadsheet with the ECMA-376 standard encryption:
+//
+//    err := f.SaveAs("Book1.xlsx", xlsx.Options{
+//        Password:            "password",
+//        EncryptionMechanism: "standard",
+//    })
+//
 func (f *File) SaveAs(name string, opt ...Options) error {
 	if len(name) > MaxFileNameLength {
 		return ErrMaxFileNameLength
@@ -64,10 +94,19 @@ func (f *File) SaveAs(name string, opt ...Options) error {
 		return err
 	}
 	defer file.Close()
+	options := f.options
 	f.options = nil
 	for i := range opt {
 		f.options = &opt[i]
 	}
+	// Keep the encryption settings of the spreadsheet when saving with
+	// password without specified encryption settings.
+	if f.options != nil && options != nil && f.options.Password != "" &&
+		f.options.EncryptionMechanism == "" && f.options.CipherAlgorithm == "" &&
+		f.options.HashAlgorithm == "" && f.options.SpinCount == 0 {
+		f.options.EncryptionMechanism, f.options.CipherAlgorithm = options.EncryptionMechanism, options.CipherAlgorithm
+		f.options.HashAlgorithm, f.options.SpinCount = options.HashAlgorithm, options.SpinCount
+	}
 	return f.Write(file)
 }
 
@@ -108,6 +147,9 @@ func (f *File) WriteTo(w io.Writer) (int64, error) {
 // and it allocates space in memory. Be careful when the file size is large.
 func (f *File) WriteToBuffer() (*bytes.Buffer, error) {
 	buf := new(bytes.Buffer)
+	if f.streamOutput != nil {
+		return buf, ErrStreamOutput
+	}
 	zw := zip.NewWriter(buf)
 
 	if err := f.writeToZip(zw); err != nil {
@@ -139,8 +181,85 @@ func (f *File) writeDirectToWriter(w io.Writer) error {
 	return zw.Close()
 }
 
+// StreamTo provides a function to write a new workbook progressively to the
+// given io.Writer without temporary files. After that, the rows of each
+// worksheet set by the stream writer will be compressed into the output
+// directly when the SetRow function is called, the other parts of the
+// workbook such as the workbook, styles and shared strings will be written
+// when the EndStream function is called. Note that only one stream writer
+// can be used at a time on the streaming output, the Flush function of the
+// stream writer must be called before creating the next one, the table
+// header row must be the first row written by the stream writer, and the
+// workbook can't be saved by the other functions until the streaming output
+// ended. For example, write a workbook to the HTTP response:
+//
+//    func handler(w http.ResponseWriter, r *http.Request) {
+//        f := xlsx.NewFile()
+//        if err := f.StreamTo(w); err != nil {
+//            fmt.Println(err)
+//            return
+//        }
+//        streamWriter, err := f.NewStreamWriter("Sheet1")
+//        if err != nil {
+//            fmt.Println(err)
+//            return
+//        }
+//        for rowID := 1; rowID <= 102400; rowID++ {
+//            cell, _ := xlsx.CoordinatesToCellName(1, rowID)
+//            if err := streamWriter.SetRow(cell, []interface{}{rowID, "Data"}); err != nil {
+//                fmt.Println(err)
+//                return
+//            }
+//        }
+//        if err := streamWriter.Flush(); err != nil {
+//            fmt.Println(err)
+//            return
+//        }
+//        if err := f.EndStream(); err != nil {
+//            fmt.Println(err)
+//        }
+//    }
+//
+func (f *File) StreamTo(w io.Writer) error {
+	if f.streamOutput != nil {
+		return ErrStreamOutput
+	}
+	f.streamOutput = zip.NewWriter(w)
+	return nil
+}
+
+// EndStream provides a function to write the remaining parts of the workbook
+// to the streaming output which began by the StreamTo function, and end the
+// streaming output.
+func (f *File) EndStream() error {
+	if f.streamOutput == nil {
+		return nil
+	}
+	for _, stream := range f.streams {
+		if stream.rawData.w != nil {
+			return ErrStreamNotFlushed
+		}
+	}
+	zw := f.streamOutput
+	err := f.writeToZip(zw)
+	f.streamOutput = nil
+	if err != nil {
+		_ = zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
 // writeToZip provides a function to write to zip.Writer
 func (f *File) writeToZip(zw *zip.Writer) error {
+	if f.streamOutput != nil && f.streamOutput != zw {
+		return ErrStreamOutput
+	}
+	if f.options != nil && f.options.CompactStyles {
+		if err := f.CompactStyles(); err != nil {
+			return err
+		}
+	}
 	f.calcChainWriter()
 	f.commentsWriter()
 	f.contentTypesWriter()
@@ -151,8 +270,17 @@ func (f *File) writeToZip(zw *zip.Writer) error {
 	f.relsWriter()
 	f.sharedStringsWriter()
 	f.styleSheetWriter()
+	if f.streamOutput == nil && strings.EqualFold(filepath.Ext(f.Path), ".xlsb") {
+		return f.writeBinaryToZip(zw)
+	}
+	if f.streamOutput == nil && strings.EqualFold(filepath.Ext(f.Path), ".ods") {
+		return f.writeODSToZip(zw)
+	}
 
 	for path, stream := range f.streams {
+		if stream.rawData.direct {
+			continue
+		}
 		fi, err := zw.Create(path)
 		if err != nil {
 			return err
@@ -0,0 +1,591 @@
+package xlsx
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// filterValue directly maps the value of the cell for evaluating the filter
+// criteria of the auto filter.
+type filterValue struct {
+	blank, number bool
+	num           float64
+	text          string
+	style         int
+}
+
+// autoFilterEvaluator provides the state of evaluating the filter criteria
+// of the auto filter in the worksheet, the dynamic filter criteria which are
+// relative to today will be evaluated by the reference time.
+type autoFilterEvaluator struct {
+	f        *File
+	ws       *xlsxWorksheet
+	sst      *xlsxSST
+	date1904 bool
+	now      time.Time
+	colors   map[int][2]string
+}
+
+// ApplyAutoFilter provides a function to evaluate the filter criteria of the
+// auto filter by given worksheet name. The rows in the auto filter range which
+// don't match the criteria of all filter columns will be hidden, and the
+// other rows will be shown. The AutoFilter function applies the filter
+// automatically, use this function to filter the rows again after the cell
+// values were changed, or the workbook was opened from the file. For example,
+// apply the auto filter in Sheet1:
+//
+//    err := f.ApplyAutoFilter("Sheet1")
+//
+func (f *File) ApplyAutoFilter(sheet string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if ws.AutoFilter == nil {
+		return nil
+	}
+	return f.applyAutoFilter(sheet, ws)
+}
+
+// GetAutoFilter provides a function to get the range and the filter settings
+// of the columns of the auto filter by given worksheet name, it returns nil if
+// there is no auto filter in the worksheet. The filter settings of each column
+// are the same as the options of the AutoFilterWithOptions function, the
+// custom filters will be returned as the expression, and the blank cells in
+// the value list will be returned as empty string. For example, get the auto
+// filter in Sheet1:
+//
+//    state, err := f.GetAutoFilter("Sheet1")
+//
+func (f *File) GetAutoFilter(sheet string) (*AutoFilterState, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.AutoFilter == nil {
+		return nil, err
+	}
+	rect, err := rangeRefToCoordinates(ws.AutoFilter.Ref)
+	if err != nil {
+		return nil, err
+	}
+	state := &AutoFilterState{Ref: strings.Replace(ws.AutoFilter.Ref, "$", "", -1)}
+	for _, column := range ws.AutoFilter.FilterColumn {
+		var opts AutoFilterOptions
+		if opts.Column, err = ColumnNumberToName(rect[0] + column.ColID); err != nil {
+			return state, err
+		}
+		switch {
+		case column.Filters != nil:
+			for _, filter := range column.Filters.Filter {
+				opts.Values = append(opts.Values, filter.Val)
+			}
+			if column.Filters.Blank {
+				opts.Values = append(opts.Values, "")
+			}
+		case column.CustomFilters != nil:
+			opts.Expression = getCustomFiltersExpression(column.CustomFilters)
+		case column.Top10 != nil:
+			opts.Top10 = &AutoFilterTop10Options{
+				Bottom:  !column.Top10.Top,
+				Percent: column.Top10.Percent,
+				Value:   column.Top10.Val,
+			}
+		case column.DynamicFilter != nil:
+			opts.Dynamic = column.DynamicFilter.Type
+		case column.ColorFilter != nil:
+			style, err := f.GetConditionalStyle(column.ColorFilter.DxfID)
+			if err != nil {
+				return state, err
+			}
+			opts.Color = &AutoFilterColorOptions{FontColor: !column.ColorFilter.CellColor}
+			if opts.Color.FontColor && style.Font != nil {
+				opts.Color.Color = style.Font.Color
+			}
+			if !opts.Color.FontColor && len(style.Fill.Color) > 0 {
+				opts.Color.Color = style.Fill.Color[0]
+			}
+		}
+		state.Columns = append(state.Columns, opts)
+	}
+	return state, nil
+}
+
+// getCustomFiltersExpression provides a function to convert the custom
+// filters to the filter expression.
+func getCustomFiltersExpression(customFilters *xlsxCustomFilters) string {
+	operators := map[string]string{
+		"":                   "==",
+		"equal":              "==",
+		"lessThan":           "<",
+		"lessThanOrEqual":    "<=",
+		"greaterThan":        ">",
+		"notEqual":           "!=",
+		"greaterThanOrEqual": ">=",
+	}
+	var expressions []string
+	for _, customFilter := range customFilters.CustomFilter {
+		val := customFilter.Val
+		if val == " " {
+			val = "blanks"
+		} else if val == "" || strings.ContainsAny(val, " \t\"") {
+			val = `"` + strings.Replace(val, `"`, `""`, -1) + `"`
+		}
+		expressions = append(expressions, "x "+operators[customFilter.Operator]+" "+val)
+	}
+	if customFilters.And {
+		return strings.Join(expressions, " and ")
+	}
+	return strings.Join(expressions, " or ")
+}
+
+// applyAutoFilter provides a function to evaluate the filter criteria of the
+// auto filter in the worksheet and set the visibility of the rows. The
+// worksheet written by the stream writer will not be evaluated, because its
+// rows are not kept in memory.
+func (f *File) applyAutoFilter(sheet string, ws *xlsxWorksheet) error {
+	if f.streams[f.sheetMap[trimSheetName(sheet)]] != nil {
+		return nil
+	}
+	rect, err := rangeRefToCoordinates(ws.AutoFilter.Ref)
+	if err != nil {
+		return err
+	}
+	e := &autoFilterEvaluator{f: f, ws: ws, sst: f.sharedStringsReader(), now: time.Now(), colors: make(map[int][2]string)}
+	if wb := f.workbookReader(); wb.WorkbookPr != nil {
+		e.date1904 = wb.WorkbookPr.Date1904
+	}
+	visible := make([]bool, rect[3]-rect[1])
+	for idx := range visible {
+		visible[idx] = true
+	}
+	for _, column := range ws.AutoFilter.FilterColumn {
+		values := make([]filterValue, len(visible))
+		for idx := range values {
+			if values[idx], err = e.value(rect[0]+column.ColID, rect[1]+idx+1); err != nil {
+				return err
+			}
+		}
+		matched, err := e.match(column, values)
+		if err != nil {
+			return err
+		}
+		for idx := range visible {
+			visible[idx] = visible[idx] && matched[idx]
+		}
+	}
+	for idx, rowVisible := range visible {
+		row := rect[1] + idx + 1
+		if row > len(ws.SheetData.Row) {
+			if rowVisible {
+				continue
+			}
+			prepareSheetXML(ws, 0, row)
+		}
+		ws.SheetData.Row[row-1].Hidden = !rowVisible
+	}
+	return nil
+}
+
+// showAutoFilterRows provides a function to show the data rows of the auto
+// filter by given range reference.
+func showAutoFilterRows(ws *xlsxWorksheet, ref string) {
+	rect, err := rangeRefToCoordinates(ref)
+	if err != nil {
+		return
+	}
+	for row := rect[1] + 1; row <= rect[3] && row <= len(ws.SheetData.Row); row++ {
+		ws.SheetData.Row[row-1].Hidden = false
+	}
+}
+
+// value provides a function to get the value of the cell by given
+// coordinates for evaluating the filter criteria.
+func (e *autoFilterEvaluator) value(col, row int) (filterValue, error) {
+	var value filterValue
+	if row > len(e.ws.SheetData.Row) || col > len(e.ws.SheetData.Row[row-1].C) {
+		value.blank = true
+		return value, nil
+	}
+	c := &e.ws.SheetData.Row[row-1].C[col-1]
+	value.style = c.S
+	if c.V == "" && c.IS == nil {
+		value.blank = true
+		return value, nil
+	}
+	text, err := c.getValueFrom(e.f, e.sst, false)
+	if err != nil {
+		return value, err
+	}
+	value.text = text
+	if c.T == "" || c.T == "n" {
+		if num, err := strconv.ParseFloat(c.V, 64); err == nil {
+			value.number, value.num = true, num
+		}
+	}
+	return value, nil
+}
+
+// match provides a function to evaluate the filter criteria of the filter
+// column by given values of the cells in the column, and returns if each
+// value matches the criteria.
+func (e *autoFilterEvaluator) match(column *xlsxFilterColumn, values []filterValue) ([]bool, error) {
+	matched := make([]bool, len(values))
+	var fn func(value filterValue) (bool, error)
+	switch {
+	case column.Filters != nil:
+		fn = e.matchFilters(column.Filters)
+	case column.CustomFilters != nil:
+		fn = func(value filterValue) (bool, error) {
+			for _, customFilter := range column.CustomFilters.CustomFilter {
+				if ok := matchCustomFilter(value, customFilter); ok != column.CustomFilters.And {
+					return ok, nil
+				}
+			}
+			return column.CustomFilters.And, nil
+		}
+	case column.Top10 != nil:
+		fn = matchTop10Filter(column.Top10, values)
+	case column.DynamicFilter != nil:
+		fn = e.matchDynamicFilter(column.DynamicFilter, values)
+	case column.ColorFilter != nil:
+		fn = func(value filterValue) (bool, error) {
+			return e.matchColorFilter(column.ColorFilter, value)
+		}
+	default:
+		fn = func(value filterValue) (bool, error) { return true, nil }
+	}
+	var err error
+	for idx, value := range values {
+		if matched[idx], err = fn(value); err != nil {
+			return matched, err
+		}
+	}
+	return matched, err
+}
+
+// matchFilters returns the function to check if the value is in the value
+// list or the date groups of the filters.
+func (e *autoFilterEvaluator) matchFilters(filters *xlsxFilters) func(value filterValue) (bool, error) {
+	vals := make(map[string]bool, len(filters.Filter))
+	for _, filter := range filters.Filter {
+		vals[strings.ToLower(filter.Val)] = true
+	}
+	return func(value filterValue) (bool, error) {
+		if value.blank {
+			return filters.Blank, nil
+		}
+		if vals[strings.ToLower(value.text)] {
+			return true, nil
+		}
+		if !value.number || len(filters.DateGroupItem) == 0 {
+			return false, nil
+		}
+		t := timeFromExcelTime(value.num, e.date1904)
+		for _, item := range filters.DateGroupItem {
+			if matchDateGroupItem(t, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// matchDateGroupItem provides a function to check if the date and time
+// matches the date group item.
+func matchDateGroupItem(t time.Time, item *xlsxDateGroupItem) bool {
+	parts := []struct {
+		grouping   string
+		val, match int
+	}{
+		{"year", item.Year, t.Year()},
+		{"month", item.Month, int(t.Month())},
+		{"day", item.Day, t.Day()},
+		{"hour", item.Hour, t.Hour()},
+		{"minute", item.Minute, t.Minute()},
+		{"second", item.Second, t.Second()},
+	}
+	for _, part := range parts {
+		if part.val != part.match {
+			return false
+		}
+		if part.grouping == item.DateTimeGrouping {
+			return true
+		}
+	}
+	return true
+}
+
+// matchCustomFilter provides a function to check if the value matches the
+// custom filter criteria.
+func matchCustomFilter(value filterValue, customFilter *xlsxCustomFilter) bool {
+	operator, val := customFilter.Operator, customFilter.Val
+	if operator == "" {
+		operator = "equal"
+	}
+	if operator == "equal" || operator == "notEqual" {
+		var equal bool
+		switch {
+		case val == " " || val == "":
+			equal = value.blank
+		case strings.ContainsAny(val, "*?"):
+			equal = !value.blank && filterWildcardPattern(val).MatchString(value.text)
+		default:
+			num, err := strconv.ParseFloat(val, 64)
+			if value.number && err == nil {
+				equal = value.num == num
+			} else {
+				equal = !value.blank && strings.EqualFold(value.text, val)
+			}
+		}
+		return equal == (operator == "equal")
+	}
+	if value.blank {
+		return false
+	}
+	var result int
+	num, err := strconv.ParseFloat(val, 64)
+	switch {
+	case value.number && err == nil:
+		if value.num < num {
+			result = -1
+		} else if value.num > num {
+			result = 1
+		}
+	case !value.number && err != nil:
+		result = strings.Compare(strings.ToLower(value.text), strings.ToLower(val))
+	default:
+		return false
+	}
+	switch operator {
+	case "lessThan":
+		return result < 0
+	case "lessThanOrEqual":
+		return result <= 0
+	case "greaterThan":
+		return result > 0
+	case "greaterThanOrEqual":
+		return result >= 0
+	}
+	return false
+}
+
+// filterWildcardPattern provides a function to convert the filter criteria
+// with the wildcard characters to the regular expression, the '*' matches any
+// characters, the '?' matches any single character, and the '~' escapes the
+// next character.
+func filterWildcardPattern(val string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	runes := []rune(val)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '~':
+			if i+1 < len(runes) {
+				i++
+			}
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// matchTop10Filter returns the function to check if the value is in the top
+// or bottom N items or percent of the numbers, the filter value of the top
+// 10 filter will be updated with the threshold.
+func matchTop10Filter(top10 *xlsxTop10, values []filterValue) func(value filterValue) (bool, error) {
+	var nums []float64
+	for _, value := range values {
+		if value.number {
+			nums = append(nums, value.num)
+		}
+	}
+	if len(nums) == 0 {
+		return func(value filterValue) (bool, error) { return false, nil }
+	}
+	sort.Float64s(nums)
+	if top10.Top {
+		sort.Sort(sort.Reverse(sort.Float64Slice(nums)))
+	}
+	count := int(top10.Val)
+	if top10.Percent {
+		count = int(float64(len(nums)) * top10.Val / 100)
+	}
+	if count < 1 {
+		count = 1
+	}
+	if count > len(nums) {
+		count = len(nums)
+	}
+	top10.FilterVal = nums[count-1]
+	return func(value filterValue) (bool, error) {
+		if !value.number {
+			return false, nil
+		}
+		if top10.Top {
+			return value.num >= top10.FilterVal, nil
+		}
+		return value.num <= top10.FilterVal, nil
+	}
+}
+
+// matchDynamicFilter returns the function to check if the value matches the
+// dynamic filter, the values of the dynamic filter will be updated with the
+// average or the date range.
+func (e *autoFilterEvaluator) matchDynamicFilter(filter *xlsxDynamicFilter, values []filterValue) func(value filterValue) (bool, error) {
+	filter.Val, filter.ValISO, filter.MaxVal, filter.MaxValISO = 0, "", 0, ""
+	switch filter.Type {
+	case "aboveAverage", "belowAverage":
+		var sum float64
+		var count int
+		for _, value := range values {
+			if value.number {
+				sum += value.num
+				count++
+			}
+		}
+		if count == 0 {
+			return func(value filterValue) (bool, error) { return false, nil }
+		}
+		filter.Val = sum / float64(count)
+		return func(value filterValue) (bool, error) {
+			if filter.Type == "aboveAverage" {
+				return value.number && value.num > filter.Val, nil
+			}
+			return value.number && value.num < filter.Val, nil
+		}
+	}
+	if len(filter.Type) > 1 && (filter.Type[0] == 'Q' || filter.Type[0] == 'M') {
+		if num, err := strconv.Atoi(filter.Type[1:]); err == nil {
+			return func(value filterValue) (bool, error) {
+				if !value.number {
+					return false, nil
+				}
+				month := int(timeFromExcelTime(value.num, e.date1904).Month())
+				if filter.Type[0] == 'Q' {
+					return (month-1)/3+1 == num, nil
+				}
+				return month == num, nil
+			}
+		}
+	}
+	start, end, ok := dynamicFilterPeriod(filter.Type, e.now)
+	if !ok {
+		return func(value filterValue) (bool, error) { return true, nil }
+	}
+	startVal, endVal := e.excelTime(start), e.excelTime(end)
+	filter.Val, filter.MaxVal = startVal, endVal
+	filter.ValISO, filter.MaxValISO = start.Format("2006-01-02T15:04:05"), end.Format("2006-01-02T15:04:05")
+	return func(value filterValue) (bool, error) {
+		return value.number && value.num >= startVal && value.num < endVal, nil
+	}
+}
+
+// excelTime provides a function to convert the date to the serial number of
+// the date system of the workbook.
+func (e *autoFilterEvaluator) excelTime(t time.Time) float64 {
+	val, _ := timeToExcelTime(t)
+	if e.date1904 {
+		val -= 1462
+	}
+	return val
+}
+
+// dynamicFilterPeriod provides a function to get the start date and the end
+// date (exclusive) of the date period of the dynamic filter by given current
+// time.
+func dynamicFilterPeriod(typ string, now time.Time) (time.Time, time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	week := today.AddDate(0, 0, -int(today.Weekday()))
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	quarter := time.Date(today.Year(), time.Month((int(today.Month())-1)/3*3+1), 1, 0, 0, 0, 0, time.UTC)
+	year := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	periods := map[string][2]time.Time{
+		"today":       {today, today.AddDate(0, 0, 1)},
+		"yesterday":   {today.AddDate(0, 0, -1), today},
+		"tomorrow":    {today.AddDate(0, 0, 1), today.AddDate(0, 0, 2)},
+		"thisWeek":    {week, week.AddDate(0, 0, 7)},
+		"lastWeek":    {week.AddDate(0, 0, -7), week},
+		"nextWeek":    {week.AddDate(0, 0, 7), week.AddDate(0, 0, 14)},
+		"thisMonth":   {month, month.AddDate(0, 1, 0)},
+		"lastMonth":   {month.AddDate(0, -1, 0), month},
+		"nextMonth":   {month.AddDate(0, 1, 0), month.AddDate(0, 2, 0)},
+		"thisQuarter": {quarter, quarter.AddDate(0, 3, 0)},
+		"lastQuarter": {quarter.AddDate(0, -3, 0), quarter},
+		"nextQuarter": {quarter.AddDate(0, 3, 0), quarter.AddDate(0, 6, 0)},
+		"thisYear":    {year, year.AddDate(1, 0, 0)},
+		"lastYear":    {year.AddDate(-1, 0, 0), year},
+		"nextYear":    {year.AddDate(1, 0, 0), year.AddDate(2, 0, 0)},
+		"yearToDate":  {year, today.AddDate(0, 0, 1)},
+	}
+	period, ok := periods[typ]
+	return period[0], period[1], ok
+}
+
+// matchColorFilter provides a function to check if the fill color or font
+// color of the cell matches the color of the color filter.
+func (e *autoFilterEvaluator) matchColorFilter(filter *xlsxColorFilter, value filterValue) (bool, error) {
+	expected, err := e.styleColors(filter.DxfID, true)
+	if err != nil {
+		return false, err
+	}
+	actual, err := e.styleColors(value.style, false)
+	if err != nil {
+		return false, err
+	}
+	if filter.CellColor {
+		return expected[0] != "" && expected[0] == actual[0], nil
+	}
+	return expected[1] != "" && expected[1] == actual[1], nil
+}
+
+// styleColors provides a function to get the normalized fill color and font
+// color by given style index of the cell or the differential formatting.
+func (e *autoFilterEvaluator) styleColors(idx int, dxf bool) ([2]string, error) {
+	key := idx
+	if dxf {
+		key = -idx - 1
+	}
+	if colors, ok := e.colors[key]; ok {
+		return colors, nil
+	}
+	var colors [2]string
+	var style *Style
+	var err error
+	if dxf {
+		style, err = e.f.GetConditionalStyle(idx)
+	} else {
+		style, err = e.f.GetStyle(idx)
+	}
+	if err != nil {
+		return colors, err
+	}
+	// The pattern type of the fill may be omitted in the differential
+	// formatting.
+	if style.Fill.Type == "pattern" && (dxf || style.Fill.Pattern > 0) && len(style.Fill.Color) > 0 {
+		colors[0] = normalizeFilterColor(style.Fill.Color[0])
+	}
+	if style.Font != nil {
+		colors[1] = normalizeFilterColor(style.Font.Color)
+	}
+	if !dxf && colors[1] == "" {
+		colors[1] = "000000"
+	}
+	e.colors[key] = colors
+	return colors, nil
+}
+
+// normalizeFilterColor provides a function to convert the color to the
+// uppercase RGB hex color without the hash sign and the alpha channel.
+func normalizeFilterColor(color string) string {
+	color = strings.ToUpper(strings.TrimPrefix(color, "#"))
+	return color[int(math.Max(0, float64(len(color)-6))):]
+}
@@ -0,0 +1,169 @@
+package xlsx
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyAutoFilter(t *testing.T) {
+	f := NewFile()
+	now := time.Date(2022, time.May, 18, 15, 30, 0, 0, time.UTC)
+	for idx, row := range [][]interface{}{
+		{"Region", "Sales", "Date"},
+		{"East", 10, now},
+		{"West", 20, now.AddDate(-1, 0, 0)},
+		{"New York", 30, now.AddDate(-1, 0, 0)},
+		{nil, 40, now.AddDate(-1, 0, 0)},
+		{"East", 50, now.AddDate(-1, 0, 0)},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+1), &row))
+	}
+	fill, err := f.NewStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"#FFFF00"}, Pattern: 1}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A3", "A3", fill))
+	font, err := f.NewStyle(&Style{Font: &Font{Color: "#FF0000"}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A4", "A4", font))
+
+	getVisibleRows := func() []int {
+		var rows []int
+		for row := 2; row <= 6; row++ {
+			visible, err := f.GetRowVisible("Sheet1", row)
+			assert.NoError(t, err)
+			if visible {
+				rows = append(rows, row)
+			}
+		}
+		return rows
+	}
+
+	for _, c := range []struct {
+		opts     *AutoFilterOptions
+		expected []int
+	}{
+		{&AutoFilterOptions{}, []int{2, 3, 4, 5, 6}},
+		{&AutoFilterOptions{Column: "A", Values: []string{"east", ""}}, []int{2, 5, 6}},
+		{&AutoFilterOptions{Column: "A", Expression: "x == blanks"}, []int{5}},
+		{&AutoFilterOptions{Column: "A", Expression: "x != blanks"}, []int{2, 3, 4, 6}},
+		{&AutoFilterOptions{Column: "A", Expression: `x == "New York" or x == West`}, []int{3, 4}},
+		{&AutoFilterOptions{Column: "A", Expression: "x == *st"}, []int{2, 3, 6}},
+		{&AutoFilterOptions{Column: "A", Expression: "x != E*"}, []int{3, 4, 5}},
+		{&AutoFilterOptions{Column: "B", Expression: "x > 15 and x <= 40"}, []int{3, 4, 5}},
+		{&AutoFilterOptions{Column: "B", Expression: "x < 15 or x >= 50"}, []int{2, 6}},
+		{&AutoFilterOptions{Column: "B", Top10: &AutoFilterTop10Options{Value: 2}}, []int{5, 6}},
+		{&AutoFilterOptions{Column: "B", Top10: &AutoFilterTop10Options{Value: 40, Percent: true, Bottom: true}}, []int{2, 3}},
+		{&AutoFilterOptions{Column: "B", Dynamic: "aboveAverage"}, []int{5, 6}},
+		{&AutoFilterOptions{Column: "B", Dynamic: "belowAverage"}, []int{2, 3}},
+		{&AutoFilterOptions{Column: "C", Dynamic: fmt.Sprintf("M%d", now.Month())}, []int{2, 3, 4, 5, 6}},
+		{&AutoFilterOptions{Column: "A", Color: &AutoFilterColorOptions{Color: "#FFFF00"}}, []int{3}},
+		{&AutoFilterOptions{Column: "A", Color: &AutoFilterColorOptions{Color: "#FF0000", FontColor: true}}, []int{4}},
+	} {
+		assert.NoError(t, f.AutoFilterWithOptions("Sheet1", "A1", "A1", nil))
+		assert.NoError(t, f.AutoFilterWithOptions("Sheet1", "A1", "C6", c.opts))
+		assert.Equal(t, c.expected, getVisibleRows(), c.opts)
+	}
+
+	// Test filter by multiple columns and get the auto filter state.
+	assert.NoError(t, f.AutoFilterWithOptions("Sheet1", "A1", "C6", &AutoFilterOptions{Column: "A", Values: []string{"East", ""}}))
+	assert.NoError(t, f.AutoFilter("Sheet1", "A1", "C6", `{"column":"B","expression":"x > 15"}`))
+	assert.Equal(t, []int{5, 6}, getVisibleRows())
+	state, err := f.GetAutoFilter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &AutoFilterState{Ref: "A1:C6", Columns: []AutoFilterOptions{
+		{Column: "A", Values: []string{"East", ""}},
+		{Column: "B", Expression: "x > 15"},
+	}}, state)
+
+	// Test apply the auto filter again after changing the cell values.
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 60))
+	assert.NoError(t, f.ApplyAutoFilter("Sheet1"))
+	assert.Equal(t, []int{2, 5, 6}, getVisibleRows())
+
+	// Test the rows hidden by the previous auto filter are shown after
+	// narrowing the range.
+	assert.NoError(t, f.AutoFilter("Sheet1", "A1", "C3", `{"column":"B","expression":"x > 100"}`))
+	assert.Equal(t, []int{4, 5, 6}, getVisibleRows())
+
+	// Test get the auto filter state of the other filter types.
+	for _, opts := range []AutoFilterOptions{
+		{Column: "A", Expression: `x != "New York" or x == blanks`},
+		{Column: "B", Top10: &AutoFilterTop10Options{Value: 10, Percent: true}},
+		{Column: "C", Dynamic: "thisMonth"},
+		{Column: "A", Color: &AutoFilterColorOptions{Color: "#FFFF00"}},
+	} {
+		assert.NoError(t, f.AutoFilterWithOptions("Sheet1", "A1", "A1", nil))
+		assert.NoError(t, f.AutoFilterWithOptions("Sheet1", "A1", "C6", &opts))
+		state, err := f.GetAutoFilter("Sheet1")
+		assert.NoError(t, err)
+		assert.Equal(t, []AutoFilterOptions{opts}, state.Columns)
+	}
+	_, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test auto filter with invalid options.
+	assert.EqualError(t, f.AutoFilterWithOptions("Sheet1", "A1", "C6", &AutoFilterOptions{Column: "B", Top10: &AutoFilterTop10Options{Value: 0}}), ErrAutoFilterTop10Value.Error())
+	assert.EqualError(t, f.AutoFilterWithOptions("Sheet1", "A1", "C6", &AutoFilterOptions{Column: "B", Dynamic: "unknown"}), "unsupported dynamic filter type 'unknown'")
+	assert.EqualError(t, f.ApplyAutoFilter("SheetN"), "sheet SheetN is not exist")
+	_, err = f.GetAutoFilter("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.ApplyAutoFilter("Sheet2"))
+	state, err = f.GetAutoFilter("Sheet2")
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+}
+
+func TestMatchDynamicFilter(t *testing.T) {
+	f := NewFile()
+	now := time.Date(2022, time.May, 18, 15, 30, 0, 0, time.UTC)
+	for idx, date := range []time.Time{now, now.AddDate(-1, 0, 0), now.AddDate(0, -1, 0)} {
+		assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("A%d", idx+1), date))
+	}
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	e := &autoFilterEvaluator{f: f, ws: ws, sst: f.sharedStringsReader(), now: now, colors: make(map[int][2]string)}
+	values := make([]filterValue, 3)
+	for idx := range values {
+		values[idx], err = e.value(1, idx+1)
+		assert.NoError(t, err)
+	}
+	for typ, expected := range map[string][]bool{
+		"today":     {true, false, false},
+		"lastYear":  {false, true, false},
+		"lastMonth": {false, false, true},
+		"thisYear":  {true, false, true},
+	} {
+		filter := &xlsxDynamicFilter{Type: typ}
+		matched, err := e.match(&xlsxFilterColumn{DynamicFilter: filter}, values)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, matched, typ)
+		assert.NotEmpty(t, filter.ValISO, typ)
+	}
+}
+
+func TestDynamicFilterPeriod(t *testing.T) {
+	now := time.Date(2022, time.May, 18, 15, 30, 0, 0, time.UTC)
+	for typ, expected := range map[string][2]string{
+		"yesterday":   {"2022-05-17", "2022-05-18"},
+		"thisWeek":    {"2022-05-15", "2022-05-22"},
+		"lastMonth":   {"2022-04-01", "2022-05-01"},
+		"nextQuarter": {"2022-07-01", "2022-10-01"},
+		"yearToDate":  {"2022-01-01", "2022-05-19"},
+	} {
+		start, end, ok := dynamicFilterPeriod(typ, now)
+		assert.True(t, ok)
+		assert.Equal(t, expected, [2]string{start.Format("2006-01-02"), end.Format("2006-01-02")}, typ)
+	}
+	_, _, ok := dynamicFilterPeriod("aboveAverage", now)
+	assert.False(t, ok)
+}
+
+func TestMatchDateGroupItem(t *testing.T) {
+	date := time.Date(2022, time.May, 18, 15, 30, 0, 0, time.UTC)
+	assert.True(t, matchDateGroupItem(date, &xlsxDateGroupItem{DateTimeGrouping: "month", Year: 2022, Month: 5}))
+	assert.False(t, matchDateGroupItem(date, &xlsxDateGroupItem{DateTimeGrouping: "day", Year: 2022, Month: 5, Day: 17}))
+	assert.True(t, matchDateGroupItem(date, &xlsxDateGroupItem{DateTimeGrouping: "minute", Year: 2022, Month: 5, Day: 18, Hour: 15, Minute: 30}))
+}
@@ -0,0 +1,379 @@
+package xlsx
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FindLookIn directly maps the type of the content to look in when finding or
+// replacing in the workbook.
+type FindLookIn byte
+
+// This section defines the currently supported content types to look in.
+const (
+	LookInValues FindLookIn = iota
+	LookInFormulas
+	LookInComments
+)
+
+// FindOptions directly maps the settings of finding and replacing in the
+// workbook. Sheets specifies the worksheets to search, each worksheet will be
+// searched once even if its name is repeated in different cases, and all
+// worksheets of the workbook will be searched if it's empty. Range restricts
+// the search to the given range reference of each worksheet, such as
+// "A1:D10".
+type FindOptions struct {
+	Sheets    []string
+	Range     string
+	LookIn    FindLookIn
+	MatchCase bool
+	WholeCell bool
+	RegExp    bool
+}
+
+// FindResult directly maps a cell found by FindAll or changed by ReplaceAll.
+// Value is the content of the cell before replacing, and NewValue is the
+// content after replacing, which is empty for the results of FindAll.
+type FindResult struct {
+	Sheet    string
+	Cell     string
+	Value    string
+	NewValue string
+}
+
+// cellFinder directly maps the compiled settings of finding and replacing.
+type cellFinder struct {
+	re        *regexp.Regexp
+	replace   string
+	regExp    bool
+	replacing bool
+	rect      []int
+}
+
+// FindAll provides a function to find the cells which contain the given value
+// in the workbook. The cell values, formulas or comments can be searched by
+// the LookIn option, and the cell values are matched by the formatted text
+// of the cells. Set RegExp to true to find with the regular expression. For
+// example, find the cells whose formulas contain the SUM function in Sheet1
+// and Sheet2, ignoring case:
+//
+//    results, err := f.FindAll("sum(", &xlsx.FindOptions{
+//        Sheets: []string{"Sheet1", "Sheet2"},
+//        LookIn: xlsx.LookInFormulas,
+//    })
+//
+func (f *File) FindAll(value string, opts *FindOptions) ([]FindResult, error) {
+	return f.findAll(value, "", false, opts)
+}
+
+// ReplaceAll provides a function to replace the given value with the
+// replacement in the cells of the workbook and returns the changed cells with
+// the old and new content. With the RegExp option, the replacement can refer
+// to the capture groups of the regular expression by $1, ${name} and so on.
+// When looking in the cell values, the cells with formulas and the number
+// cells with a number format other than General will be skipped. The number
+// cells are matched by their raw values, and the replaced value will be kept
+// as a number if the new value is still numeric, otherwise it will be set as
+// a string.
+// For example, swap the first and last names in the range A2:A100 of the
+// whole workbook:
+//
+//    results, err := f.ReplaceAll(`^(\w+) (\w+)$`, "$2 $1", &xlsx.FindOptions{
+//        Range:  "A2:A100",
+//        RegExp: true,
+//    })
+//
+func (f *File) ReplaceAll(value, replace string, opts *FindOptions) ([]FindResult, error) {
+	return f.findAll(value, replace, true, opts)
+}
+
+// findAll provides a function to find or replace the given value in the
+// worksheets by given options.
+func (f *File) findAll(value, replace string, replacing bool, opts *FindOptions) ([]FindResult, error) {
+	if opts == nil {
+		opts = &FindOptions{}
+	}
+	cf, err := newCellFinder(value, replace, replacing, opts)
+	if err != nil {
+		return nil, err
+	}
+	var sheets []string
+	visited := make(map[string]bool)
+	for _, sheet := range opts.Sheets {
+		// The worksheet names are case-insensitive.
+		if name := strings.ToLower(trimSheetName(sheet)); !visited[name] {
+			visited[name] = true
+			sheets = append(sheets, sheet)
+		}
+	}
+	if len(sheets) == 0 {
+		for _, sheet := range f.GetSheetList() {
+			if strings.HasPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") {
+				sheets = append(sheets, sheet)
+			}
+		}
+	}
+	var results []FindResult
+	for _, sheet := range sheets {
+		ws, err := f.workSheetReader(sheet)
+		if err != nil {
+			return results, err
+		}
+		var found []FindResult
+		switch opts.LookIn {
+		case LookInFormulas:
+			found, err = f.findFormulas(sheet, ws, cf)
+		case LookInComments:
+			found, err = f.findComments(sheet, cf)
+		default:
+			found, err = f.findValues(sheet, ws, cf)
+		}
+		results = append(results, found...)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// newCellFinder provides a function to compile the value to find with the
+// given options.
+func newCellFinder(value, replace string, replacing bool, opts *FindOptions) (*cellFinder, error) {
+	if value == "" {
+		return nil, ErrFindValue
+	}
+	cf := &cellFinder{replace: replace, regExp: opts.RegExp, replacing: replacing}
+	expr := value
+	if !opts.RegExp {
+		expr = regexp.QuoteMeta(value)
+	}
+	if opts.WholeCell {
+		expr = "^(?:" + expr + ")$"
+	}
+	if !opts.MatchCase {
+		expr = "(?i)" + expr
+	}
+	var err error
+	if cf.re, err = regexp.Compile(expr); err != nil {
+		return nil, err
+	}
+	if opts.Range != "" {
+		if cf.rect, err = rangeRefToCoordinates(opts.Range); err != nil {
+			return nil, err
+		}
+	}
+	return cf, nil
+}
+
+// inRange returns if the given cell is in the range to search.
+func (cf *cellFinder) inRange(cell string) (bool, error) {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil || cf.rect == nil {
+		return err == nil, err
+	}
+	return col >= cf.rect[0] && col <= cf.rect[2] && row >= cf.rect[1] && row <= cf.rect[3], nil
+}
+
+// match returns if the given content contains the value to find.
+func (cf *cellFinder) match(content string) bool {
+	return content != "" && cf.re.MatchString(content)
+}
+
+// replaceString returns the content with all matches of the value to find
+// replaced by the replacement.
+func (cf *cellFinder) replaceString(content string) string {
+	if cf.regExp {
+		return cf.re.ReplaceAllString(content, cf.replace)
+	}
+	return cf.re.ReplaceAllLiteralString(content, cf.replace)
+}
+
+// findValues provides a function to find or replace the value in the
+// formatted values of the cells in the worksheet.
+func (f *File) findValues(sheet string, ws *xlsxWorksheet, cf *cellFinder) ([]FindResult, error) {
+	var results []FindResult
+	sst := f.sharedStringsReader()
+	for rowIdx := range ws.SheetData.Row {
+		for colIdx := range ws.SheetData.Row[rowIdx].C {
+			c := &ws.SheetData.Row[rowIdx].C[colIdx]
+			if ok, err := cf.inRange(c.R); !ok || err != nil {
+				if err != nil {
+					return results, err
+				}
+				continue
+			}
+			if cf.replacing && (c.F != nil || isNumberCell(c) && !f.isGeneralNumFmt(c.S)) {
+				continue
+			}
+			// Replace the raw value of the number cell to keep the precision.
+			val, err := c.getValueFrom(f, sst, cf.replacing && isNumberCell(c))
+			if err != nil {
+				return results, err
+			}
+			if !cf.match(val) {
+				continue
+			}
+			result := FindResult{Sheet: sheet, Cell: c.R, Value: val}
+			if cf.replacing {
+				result.NewValue = cf.replaceString(val)
+				f.setFoundCellValue(c, result.NewValue)
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// isNumberCell returns if the given cell is a number cell.
+func isNumberCell(c *xlsxC) bool {
+	return c.T == "" || c.T == "n"
+}
+
+// isGeneralNumFmt provides a function to check if the number format of the
+// given style is General.
+func (f *File) isGeneralNumFmt(styleID int) bool {
+	styleSheet := f.stylesReader()
+	if styleSheet.CellXfs == nil || styleID >= len(styleSheet.CellXfs.Xf) {
+		return true
+	}
+	numFmtID := styleSheet.CellXfs.Xf[styleID].NumFmtID
+	return numFmtID == nil || *numFmtID == 0
+}
+
+// setFoundCellValue provides a function to set the replaced value of the
+// cell, the number cell will be kept as a number if the new value is numeric.
+func (f *File) setFoundCellValue(c *xlsxC, value string) {
+	if isNumberCell(c) {
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			c.V = value
+			return
+		}
+	}
+	c.IS = nil
+	c.T, c.V = f.setCellString(value)
+}
+
+// findFormulas provides a function to find or replace the value in the
+// formulas of the cells in the worksheet. The shared formula will be
+// converted into normal formulas before replacing.
+func (f *File) findFormulas(sheet string, ws *xlsxWorksheet, cf *cellFinder) ([]FindResult, error) {
+	var results []FindResult
+	for rowIdx := range ws.SheetData.Row {
+		for colIdx := range ws.SheetData.Row[rowIdx].C {
+			c := &ws.SheetData.Row[rowIdx].C[colIdx]
+			if c.F == nil {
+				continue
+			}
+			if ok, err := cf.inRange(c.R); !ok || err != nil {
+				if err != nil {
+					return results, err
+				}
+				continue
+			}
+			formula := c.F.Content
+			if c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+				formula = getSharedForumula(ws, *c.F.Si, c.R)
+			}
+			if !cf.match(formula) {
+				continue
+			}
+			result := FindResult{Sheet: sheet, Cell: c.R, Value: formula}
+			if cf.replacing {
+				if c.F.T == STCellFormulaTypeShared {
+					col, row, _ := CellNameToCoordinates(c.R)
+					unshareFormulas(ws, []int{col, row, col, row})
+				}
+				result.NewValue = cf.replaceString(formula)
+				c.F.Content = result.NewValue
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// findComments provides a function to find or replace the value in the text
+// of the comments in the worksheet. The author name which leads the comment
+// text is not searched.
+func (f *File) findComments(sheet string, cf *cellFinder) ([]FindResult, error) {
+	var results []FindResult
+	commentsXML := f.getSheetCommentsXML(f.sheetMap[trimSheetName(sheet)])
+	if commentsXML == "" {
+		return results, nil
+	}
+	comments := f.commentsReader(commentsXML)
+	if comments == nil {
+		return results, nil
+	}
+	for idx := range comments.CommentList.Comment {
+		cmt := &comments.CommentList.Comment[idx]
+		if ok, err := cf.inRange(cmt.Ref); !ok || err != nil {
+			if err != nil {
+				return results, err
+			}
+			continue
+		}
+		start := 0
+		if len(cmt.Text.R) > 0 && cmt.AuthorID < len(comments.Authors.Author) &&
+			cmt.Text.R[0].T != nil && cmt.Text.R[0].T.Val == comments.Authors.Author[cmt.AuthorID] {
+			start = 1
+		}
+		text := getCommentText(&cmt.Text, start)
+		if !cf.match(text) {
+			continue
+		}
+		result := FindResult{Sheet: sheet, Cell: cmt.Ref, Value: text}
+		if cf.replacing {
+			result.NewValue = cf.replaceString(text)
+			replaceCommentText(&cmt.Text, start, result.NewValue, cf)
+		}
+		results = append(results, result)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		col1, row1, _ := CellNameToCoordinates(results[i].Cell)
+		col2, row2, _ := CellNameToCoordinates(results[j].Cell)
+		return row1 < row2 || (row1 == row2 && col1 < col2)
+	})
+	return results, nil
+}
+
+// getCommentText returns the text of the comment from the given run index.
+func getCommentText(text *xlsxText, start int) string {
+	var content string
+	if text.T != nil {
+		content += *text.T
+	}
+	for _, r := range text.R[start:] {
+		if r.T != nil {
+			content += r.T.Val
+		}
+	}
+	return content
+}
+
+// replaceCommentText provides a function to replace the text of the comment
+// run by run to keep the rich text formatting, the runs will be merged into
+// one if the value to find across the runs.
+func replaceCommentText(text *xlsxText, start int, value string, cf *cellFinder) {
+	if text.T != nil {
+		replaced := cf.replaceString(*text.T)
+		text.T = &replaced
+	}
+	for idx := range text.R[start:] {
+		if r := &text.R[start+idx]; r.T != nil {
+			r.T = &xlsxT{Val: cf.replaceString(r.T.Val)}
+		}
+	}
+	if getCommentText(text, start) == value {
+		return
+	}
+	if start < len(text.R) {
+		text.T = nil
+		text.R[start].T = &xlsxT{Val: value}
+		text.R = text.R[:start+1]
+		return
+	}
+	text.T = &value
+}
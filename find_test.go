@@ -0,0 +1,158 @@
+package xlsx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAll(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Apple", "apple pie", 100}))
+	assert.NoError(t, f.SetSheetRow("Sheet2", "A1", &[]interface{}{"APPLE", "Banana", 1100}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A2", "SUM(C1:C1)"))
+	assert.NoError(t, f.AddComment("Sheet2", "B2", "Author", "Check the apple price"))
+
+	for _, c := range []struct {
+		value    string
+		opts     *FindOptions
+		expected []FindResult
+	}{
+		{"apple", nil, []FindResult{
+			{Sheet: "Sheet1", Cell: "A1", Value: "Apple"},
+			{Sheet: "Sheet1", Cell: "B1", Value: "apple pie"},
+			{Sheet: "Sheet2", Cell: "A1", Value: "APPLE"},
+		}},
+		{"apple", &FindOptions{MatchCase: true}, []FindResult{
+			{Sheet: "Sheet1", Cell: "B1", Value: "apple pie"},
+		}},
+		{"apple", &FindOptions{WholeCell: true, Sheets: []string{"Sheet2"}}, []FindResult{
+			{Sheet: "Sheet2", Cell: "A1", Value: "APPLE"},
+		}},
+		{"^1?100$", &FindOptions{RegExp: true}, []FindResult{
+			{Sheet: "Sheet1", Cell: "C1", Value: "100"},
+			{Sheet: "Sheet2", Cell: "C1", Value: "1100"},
+		}},
+		{"apple", &FindOptions{Range: "$B$1:C2"}, []FindResult{
+			{Sheet: "Sheet1", Cell: "B1", Value: "apple pie"},
+		}},
+		{"c1", &FindOptions{LookIn: LookInFormulas}, []FindResult{
+			{Sheet: "Sheet1", Cell: "A2", Value: "SUM(C1:C1)"},
+		}},
+		{"APPLE", &FindOptions{LookIn: LookInComments}, []FindResult{
+			{Sheet: "Sheet2", Cell: "B2", Value: "Check the apple price"},
+		}},
+		{"Author", &FindOptions{LookIn: LookInComments}, nil},
+	} {
+		results, err := f.FindAll(c.value, c.opts)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, results, c.value)
+	}
+
+	// Test find with invalid options.
+	_, err := f.FindAll("", nil)
+	assert.EqualError(t, err, ErrFindValue.Error())
+	_, err = f.FindAll("(", &FindOptions{RegExp: true})
+	assert.EqualError(t, err, "error parsing regexp: missing closing ): `(?i)(`")
+	_, err = f.FindAll("apple", &FindOptions{Range: "A"})
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, err = f.FindAll("apple", &FindOptions{Sheets: []string{"SheetN"}})
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestReplaceAll(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Smith John", "Doe Jane", 1200, "id-1200"}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A2", "A1&B1"))
+	formulaType, ref := STCellFormulaTypeShared, "C2:C4"
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C2", "SUM(A1:B1)", FormulaOpts{Ref: &ref, Type: &formulaType}))
+	assert.NoError(t, f.AddComment("Sheet1", "A1", "Author", "Old name"))
+
+	// Test replace values with the capture groups of the regular expression.
+	results, err := f.ReplaceAll(`^(\w+) (\w+)$`, "$2 $1", &FindOptions{RegExp: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []FindResult{
+		{Sheet: "Sheet1", Cell: "A1", Value: "Smith John", NewValue: "John Smith"},
+		{Sheet: "Sheet1", Cell: "B1", Value: "Doe Jane", NewValue: "Jane Doe"},
+	}, results)
+	for cell, expected := range map[string]string{"A1": "John Smith", "B1": "Jane Doe"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+
+	// Test replace values and keep the number type.
+	results, err = f.ReplaceAll("12", "13", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []FindResult{
+		{Sheet: "Sheet1", Cell: "C1", Value: "1200", NewValue: "1300"},
+		{Sheet: "Sheet1", Cell: "D1", Value: "id-1200", NewValue: "id-1300"},
+	}, results)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", ws.SheetData.Row[0].C[2].T)
+	assert.Equal(t, "1300", ws.SheetData.Row[0].C[2].V)
+	assert.Equal(t, "s", ws.SheetData.Row[0].C[3].T)
+
+	// Test replace values skip the number cells with number formats.
+	decimal, err := f.NewStyle(&Style{NumFmt: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "E1", 0.3333333))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "E1", "E1", decimal))
+	assert.NoError(t, f.SetCellValue("Sheet1", "F1", time.Date(2023, time.March, 3, 0, 0, 0, 0, time.UTC)))
+	results, err = f.FindAll("0.33", &FindOptions{Range: "E1:F1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []FindResult{{Sheet: "Sheet1", Cell: "E1", Value: "0.33"}}, results)
+	results, err = f.ReplaceAll("3", "4", &FindOptions{Range: "E1:F1"})
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Equal(t, "0.3333333", ws.SheetData.Row[0].C[4].V)
+	assert.Equal(t, "", ws.SheetData.Row[0].C[5].T)
+	assert.Equal(t, "44988", ws.SheetData.Row[0].C[5].V)
+
+	// Test replace in the formulas, the shared formula will be unshared.
+	results, err = f.ReplaceAll("SUM(", "AVERAGE(", &FindOptions{LookIn: LookInFormulas, Range: "C3"})
+	assert.NoError(t, err)
+	assert.Equal(t, []FindResult{
+		{Sheet: "Sheet1", Cell: "C3", Value: "SUM(A2:B2)", NewValue: "AVERAGE(A2:B2)"},
+	}, results)
+	for cell, expected := range map[string]string{"A2": "A1&B1", "C2": "SUM(A1:B1)", "C3": "AVERAGE(A2:B2)", "C4": "SUM(A3:B3)"} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula)
+	}
+
+	// Test replace in the comments, the author will be kept.
+	results, err = f.ReplaceAll("old", "New", &FindOptions{LookIn: LookInComments})
+	assert.NoError(t, err)
+	assert.Equal(t, []FindResult{
+		{Sheet: "Sheet1", Cell: "A1", Value: "Old name", NewValue: "New name"},
+	}, results)
+	assert.Equal(t, "AuthorNew name", f.GetComments()["Sheet1"][0].Text)
+
+	// Test replace the comment text across the rich text runs.
+	comments := f.commentsReader(f.getSheetCommentsXML(f.sheetMap["Sheet1"]))
+	comments.CommentList.Comment[0].Text.R = append(comments.CommentList.Comment[0].Text.R, xlsxR{T: &xlsxT{Val: " here"}})
+	results, err = f.ReplaceAll("name here", "value", &FindOptions{LookIn: LookInComments})
+	assert.NoError(t, err)
+	assert.Equal(t, []FindResult{
+		{Sheet: "Sheet1", Cell: "A1", Value: "New name here", NewValue: "New value"},
+	}, results)
+	assert.Len(t, comments.CommentList.Comment[0].Text.R, 2)
+	assert.Equal(t, "AuthorNew value", f.GetComments()["Sheet1"][0].Text)
+	_, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test replace in the duplicate worksheets only once.
+	results, err = f.ReplaceAll("Smith", "Smith Jr", &FindOptions{Sheets: []string{"Sheet1", "sheet1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []FindResult{
+		{Sheet: "Sheet1", Cell: "A1", Value: "John Smith", NewValue: "John Smith Jr"},
+	}, results)
+
+	// Test replace with invalid options.
+	_, err = f.ReplaceAll("", "", nil)
+	assert.EqualError(t, err, ErrFindValue.Error())
+}
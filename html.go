@@ -0,0 +1,604 @@
+package xlsx
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// HTMLOptions directly maps the settings of exporting the worksheet range as
+// HTML table.
+//
+// FullDocument specifies if the table should be wrapped in a standalone HTML
+// document, only the table element will be written by default.
+//
+// RawCellValue specifies if apply the number format for the cell value or get
+// the raw value.
+type HTMLOptions struct {
+	FullDocument bool
+	RawCellValue bool
+}
+
+// htmlBorderStyles defined the CSS border style by the border style name of
+// the spreadsheet.
+var htmlBorderStyles = map[string]string{
+	"thin":             "1px solid",
+	"medium":           "2px solid",
+	"thick":            "3px solid",
+	"double":           "3px double",
+	"hair":             "1px solid",
+	"dashed":           "1px dashed",
+	"dotted":           "1px dotted",
+	"mediumDashed":     "2px dashed",
+	"dashDot":          "1px dashed",
+	"mediumDashDot":    "2px dashed",
+	"dashDotDot":       "1px dotted",
+	"mediumDashDotDot": "2px dotted",
+	"slantDashDot":     "2px dashed",
+}
+
+// htmlHorizontalAlignment defined the CSS text alignment by the horizontal
+// alignment of the cell.
+var htmlHorizontalAlignment = map[string]string{
+	"left":             "left",
+	"center":           "center",
+	"right":            "right",
+	"fill":             "left",
+	"justify":          "justify",
+	"centerContinuous": "center",
+	"distributed":      "justify",
+}
+
+// htmlVerticalAlignment defined the CSS vertical alignment by the vertical
+// alignment of the cell.
+var htmlVerticalAlignment = map[string]string{
+	"top":         "top",
+	"center":      "middle",
+	"bottom":      "bottom",
+	"justify":     "middle",
+	"distributed": "middle",
+}
+
+// htmlExporter provides the state of exporting the worksheet range as HTML
+// table.
+type htmlExporter struct {
+	f        *File
+	sheet    string
+	ws       *xlsxWorksheet
+	opts     HTMLOptions
+	styles   map[int]string
+	cells    map[[2]int]*xlsxC
+	links    map[[2]int]string
+	pictures map[[2]int][]string
+}
+
+// ExportHTML provides a function to export the worksheet range as HTML table
+// to the io.Writer by given worksheet name and range reference. The merged
+// cells will be rendered as the cells with row span and column span, the
+// hidden rows and columns will be skipped. The column widths, row heights,
+// fonts, fills, borders and alignment of the cells will be rendered as the
+// inline CSS styles, and the pictures will be embedded as data URIs. Only the
+// hyperlinks with the http, https and mailto schemes and the hyperlinks to the
+// locations in the workbook will be rendered as links. The used range of the
+// worksheet will be exported if the range reference is empty.
+// For example, export range A1:D10 on Sheet1 as standalone HTML document:
+//
+//    var buf bytes.Buffer
+//    err := f.ExportHTML("Sheet1", "A1:D10", &buf, xlsx.HTMLOptions{FullDocument: true})
+//
+func (f *File) ExportHTML(sheet, rangeRef string, w io.Writer, opts ...HTMLOptions) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	e := &htmlExporter{
+		f:        f,
+		sheet:    sheet,
+		ws:       ws,
+		styles:   make(map[int]string),
+		cells:    make(map[[2]int]*xlsxC),
+		links:    make(map[[2]int]string),
+		pictures: make(map[[2]int][]string),
+	}
+	for _, opt := range opts {
+		e.opts = opt
+	}
+	rect, err := e.usedRange(rangeRef)
+	if err != nil {
+		return err
+	}
+	if err = e.prepare(rect); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if e.opts.FullDocument {
+		buf.WriteString(`<!DOCTYPE html><html><head><meta charset="utf-8"><title>` + html.EscapeString(sheet) + `</title></head><body>`)
+	}
+	if err = e.writeTable(&buf, rect); err != nil {
+		return err
+	}
+	if e.opts.FullDocument {
+		buf.WriteString(`</body></html>`)
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// usedRange returns the coordinates of the range by given range reference,
+// the used range of the worksheet will be returned if the reference is empty.
+func (e *htmlExporter) usedRange(rangeRef string) ([]int, error) {
+	rangeRef = strings.ReplaceAll(rangeRef, "$", "")
+	if rangeRef != "" {
+		if !strings.Contains(rangeRef, ":") {
+			rangeRef += ":" + rangeRef
+		}
+		rect, err := areaRefToCoordinates(rangeRef)
+		if err != nil {
+			return nil, err
+		}
+		return rect, sortCoordinates(rect)
+	}
+	rect := []int{1, 1, 0, 0}
+	for _, row := range e.ws.SheetData.Row {
+		for _, c := range row.C {
+			col, r, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return nil, err
+			}
+			if c.V != "" || c.F != nil || c.IS != nil || c.S != 0 {
+				rect[2], rect[3] = int(math.Max(float64(rect[2]), float64(col))), int(math.Max(float64(rect[3]), float64(r)))
+			}
+		}
+	}
+	if e.ws.MergeCells != nil {
+		for _, mergeCell := range e.ws.MergeCells.Cells {
+			if mergeCell == nil {
+				continue
+			}
+			coordinates, err := areaRefToCoordinates(mergeCell.Ref)
+			if err != nil {
+				return nil, err
+			}
+			_ = sortCoordinates(coordinates)
+			rect[2], rect[3] = int(math.Max(float64(rect[2]), float64(coordinates[2]))), int(math.Max(float64(rect[3]), float64(coordinates[3])))
+		}
+	}
+	return rect, nil
+}
+
+// prepare collect the cells, hyperlinks and pictures in the range.
+func (e *htmlExporter) prepare(rect []int) error {
+	inRange := func(col, row int) bool {
+		return rect[0] <= col && col <= rect[2] && rect[1] <= row && row <= rect[3]
+	}
+	mergedCells := make(map[[2]int]bool)
+	if e.ws.MergeCells != nil {
+		for _, mergeCell := range e.ws.MergeCells.Cells {
+			if mergeCell == nil {
+				continue
+			}
+			if col, row, err := CellNameToCoordinates(strings.Split(mergeCell.Ref, ":")[0]); err == nil {
+				mergedCells[[2]int{col, row}] = true
+			}
+		}
+	}
+	for i := range e.ws.SheetData.Row {
+		for j := range e.ws.SheetData.Row[i].C {
+			c := &e.ws.SheetData.Row[i].C[j]
+			col, row, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return err
+			}
+			if inRange(col, row) || mergedCells[[2]int{col, row}] {
+				e.cells[[2]int{col, row}] = c
+			}
+		}
+	}
+	if e.ws.Hyperlinks != nil {
+		for _, link := range e.ws.Hyperlinks.Hyperlink {
+			ref := link.Ref
+			if !strings.Contains(ref, ":") {
+				ref += ":" + ref
+			}
+			coordinates, err := areaRefToCoordinates(ref)
+			if err != nil {
+				continue
+			}
+			_ = sortCoordinates(coordinates)
+			// Only walk through the intersection of the hyperlink and the
+			// range, the hyperlink may refer to the whole worksheet.
+			for i := 0; i < 2; i++ {
+				coordinates[i] = int(math.Max(float64(coordinates[i]), float64(rect[i])))
+				coordinates[i+2] = int(math.Min(float64(coordinates[i+2]), float64(rect[i+2])))
+			}
+			target := "#" + link.Location
+			if link.RID != "" {
+				target = e.f.getSheetRelationshipsTargetByID(e.sheet, link.RID)
+			}
+			for col := coordinates[0]; col <= coordinates[2]; col++ {
+				for row := coordinates[1]; row <= coordinates[3]; row++ {
+					e.links[[2]int{col, row}] = target
+				}
+			}
+		}
+	}
+	return e.preparePictures(inRange)
+}
+
+// preparePictures collect the pictures which anchored in the range, the
+// pictures will be rendered as img elements with data URIs.
+func (e *htmlExporter) preparePictures(inRange func(col, row int) bool) error {
+	if e.ws.Drawing == nil {
+		return nil
+	}
+	target := e.f.getSheetRelationshipsTargetByID(e.sheet, e.ws.Drawing.RID)
+	drawingXML := strings.Replace(target, "..", "xl", -1)
+	drawingRels := strings.Replace(strings.Replace(target, "../drawings", "xl/drawings/_rels", -1), ".xml", ".xml.rels", -1)
+	if _, ok := e.f.Pkg.Load(drawingXML); !ok {
+		if _, ok = e.f.Drawings.Load(drawingXML); !ok {
+			return nil
+		}
+	}
+	wsDr, _ := e.f.drawingParser(drawingXML)
+	var anchors []*decodeTwoCellAnchor
+	wsDr.Lock()
+	for _, anchor := range append(append([]*xdrCellAnchor{}, wsDr.OneCellAnchor...), wsDr.TwoCellAnchor...) {
+		if anchor.Pic != nil && anchor.From != nil {
+			anchors = append(anchors, &decodeTwoCellAnchor{
+				From: &decodeFrom{Col: anchor.From.Col, Row: anchor.From.Row},
+				Pic: &decodePic{
+					BlipFill: decodeBlipFill{Blip: decodeBlip{Embed: anchor.Pic.BlipFill.Blip.Embed}},
+					SpPr:     decodeSpPr{Xfrm: decodeXfrm{Ext: decodeExt{Cx: anchor.Pic.SpPr.Xfrm.Ext.Cx, Cy: anchor.Pic.SpPr.Xfrm.Ext.Cy}}},
+				},
+			})
+			continue
+		}
+		if anchor.GraphicFrame == "" {
+			continue
+		}
+		deAnchor := new(decodeTwoCellAnchor)
+		if err := e.f.xmlNewDecoder(strings.NewReader("<decodeTwoCellAnchor>" + anchor.GraphicFrame + "</decodeTwoCellAnchor>")).
+			Decode(deAnchor); err != nil && err != io.EOF {
+			wsDr.Unlock()
+			return fmt.Errorf("xml decode error: %s", err)
+		}
+		if deAnchor.From != nil && deAnchor.Pic != nil {
+			anchors = append(anchors, deAnchor)
+		}
+	}
+	wsDr.Unlock()
+	for _, anchor := range anchors {
+		col, row := anchor.From.Col+1, anchor.From.Row+1
+		if !inRange(col, row) {
+			continue
+		}
+		drawRel := e.f.getDrawingRelationships(drawingRels, anchor.Pic.BlipFill.Blip.Embed)
+		if drawRel == nil {
+			continue
+		}
+		ext, ok := supportImageTypes[strings.ToLower(filepath.Ext(drawRel.Target))]
+		if !ok {
+			continue
+		}
+		content, ok := e.f.Pkg.Load(strings.Replace(drawRel.Target, "..", "xl", -1))
+		if !ok || content == nil {
+			continue
+		}
+		img := `<img src="data:image/` + strings.TrimPrefix(ext, ".") + `;base64,` + base64.StdEncoding.EncodeToString(content.([]byte)) + `"`
+		if ext := anchor.Pic.SpPr.Xfrm.Ext; ext.Cx > 0 && ext.Cy > 0 {
+			img += fmt.Sprintf(` width="%d" height="%d"`, ext.Cx/EMU, ext.Cy/EMU)
+		}
+		e.pictures[[2]int{col, row}] = append(e.pictures[[2]int{col, row}], img+` alt="`+html.EscapeString(filepath.Base(drawRel.Target))+`">`)
+	}
+	return nil
+}
+
+// writeTable write the table element of the range.
+func (e *htmlExporter) writeTable(buf *bytes.Buffer, rect []int) error {
+	var cols, rows []int
+	var tableWidth float64
+	widths := make(map[int]float64)
+	for col := rect[0]; col <= rect[2] && rect[2] > 0; col++ {
+		name, err := ColumnNumberToName(col)
+		if err != nil {
+			return err
+		}
+		if visible, err := e.f.GetColVisible(e.sheet, name); err != nil || !visible {
+			continue
+		}
+		width, err := e.f.GetColWidth(e.sheet, name)
+		if err != nil {
+			return err
+		}
+		cols, widths[col] = append(cols, col), convertColWidthToPixels(width)
+		tableWidth += widths[col]
+	}
+	for row := rect[1]; row <= rect[3] && rect[3] > 0; row++ {
+		if visible, err := e.f.GetRowVisible(e.sheet, row); err == nil && !visible {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	spans, covered, values, err := e.mergedCells(rect, cols, rows)
+	if err != nil {
+		return err
+	}
+	fontCSS := "font-family:" + e.fontFamily(e.f.GetDefaultFont())
+	if font := e.f.readDefaultFont(); font.Sz != nil && font.Sz.Val != nil {
+		fontCSS += ";font-size:" + strconv.FormatFloat(*font.Sz.Val, 'f', -1, 64) + "pt"
+	}
+	fmt.Fprintf(buf, `<table style="border-collapse:collapse;table-layout:fixed;width:%gpx;%s">`, tableWidth, fontCSS)
+	if len(cols) > 0 {
+		buf.WriteString(`<colgroup>`)
+		for _, col := range cols {
+			fmt.Fprintf(buf, `<col style="width:%gpx">`, widths[col])
+		}
+		buf.WriteString(`</colgroup>`)
+	}
+	for _, row := range rows {
+		height, err := e.f.GetRowHeight(e.sheet, row)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, `<tr style="height:%gpx">`, convertRowHeightToPixels(height))
+		for _, col := range cols {
+			cell := [2]int{col, row}
+			if covered[cell] {
+				continue
+			}
+			source := cell
+			if value, ok := values[cell]; ok {
+				source = value
+			}
+			if err = e.writeCell(buf, cell, source, spans[cell]); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(`</tr>`)
+	}
+	buf.WriteString(`</table>`)
+	return nil
+}
+
+// mergedCells returns the spans of the visible anchor cells, the covered
+// cells and the source cells of the anchor cells for the merged cells in the
+// range. The merged cells will be clipped by the range.
+func (e *htmlExporter) mergedCells(rect, cols, rows []int) (map[[2]int][2]int, map[[2]int]bool, map[[2]int][2]int, error) {
+	spans, covered, values := make(map[[2]int][2]int), make(map[[2]int]bool), make(map[[2]int][2]int)
+	if e.ws.MergeCells == nil {
+		return spans, covered, values, nil
+	}
+	for _, mergeCell := range e.ws.MergeCells.Cells {
+		if mergeCell == nil {
+			continue
+		}
+		merged, err := areaRefToCoordinates(mergeCell.Ref)
+		if err != nil {
+			return spans, covered, values, err
+		}
+		_ = sortCoordinates(merged)
+		clip := []int{
+			int(math.Max(float64(merged[0]), float64(rect[0]))), int(math.Max(float64(merged[1]), float64(rect[1]))),
+			int(math.Min(float64(merged[2]), float64(rect[2]))), int(math.Min(float64(merged[3]), float64(rect[3]))),
+		}
+		if clip[0] > clip[2] || clip[1] > clip[3] {
+			continue
+		}
+		var visibleCols, visibleRows []int
+		for _, col := range cols {
+			if clip[0] <= col && col <= clip[2] {
+				visibleCols = append(visibleCols, col)
+			}
+		}
+		for _, row := range rows {
+			if clip[1] <= row && row <= clip[3] {
+				visibleRows = append(visibleRows, row)
+			}
+		}
+		for col := clip[0]; col <= clip[2]; col++ {
+			for row := clip[1]; row <= clip[3]; row++ {
+				covered[[2]int{col, row}] = true
+			}
+		}
+		if len(visibleCols) == 0 || len(visibleRows) == 0 {
+			continue
+		}
+		anchor := [2]int{visibleCols[0], visibleRows[0]}
+		delete(covered, anchor)
+		spans[anchor] = [2]int{len(visibleCols), len(visibleRows)}
+		values[anchor] = [2]int{merged[0], merged[1]}
+		for col := clip[0]; col <= clip[2]; col++ {
+			for row := clip[1]; row <= clip[3]; row++ {
+				if pictures, ok := e.pictures[[2]int{col, row}]; ok && [2]int{col, row} != anchor {
+					e.pictures[anchor] = append(e.pictures[anchor], pictures...)
+				}
+			}
+		}
+	}
+	return spans, covered, values, nil
+}
+
+// writeCell write the td element by given cell coordinates, the coordinates
+// of the cell which provides value and style, and the spans of the cell.
+func (e *htmlExporter) writeCell(buf *bytes.Buffer, cell, source [2]int, span [2]int) error {
+	c := e.cells[source]
+	var styleID int
+	var value, cellType string
+	if c != nil {
+		styleID, cellType = c.S, c.T
+		var err error
+		if value, err = c.getValueFrom(e.f, e.f.sharedStringsReader(), e.opts.RawCellValue); err != nil {
+			return err
+		}
+	}
+	css := e.cellStyle(styleID)
+	if !strings.Contains(css, "text-align:") && value != "" {
+		switch cellType {
+		case "b", "e":
+			css += ";text-align:center"
+		case "", "n":
+			if _, err := strconv.ParseFloat(c.V, 64); err == nil {
+				css += ";text-align:right"
+			}
+		}
+	}
+	buf.WriteString(`<td`)
+	if span[0] > 1 {
+		fmt.Fprintf(buf, ` colspan="%d"`, span[0])
+	}
+	if span[1] > 1 {
+		fmt.Fprintf(buf, ` rowspan="%d"`, span[1])
+	}
+	buf.WriteString(` style="` + html.EscapeString(strings.TrimPrefix(css, ";")) + `">`)
+	content := html.EscapeString(value)
+	if cellType == "b" && !e.opts.RawCellValue {
+		content = map[string]string{"1": "TRUE", "0": "FALSE"}[value]
+	}
+	link, ok := e.links[source]
+	if !ok {
+		link, ok = e.links[cell]
+	}
+	if ok && isSafeHTMLLink(link) {
+		content = `<a href="` + html.EscapeString(link) + `">` + content + `</a>`
+	}
+	buf.WriteString(content)
+	for _, img := range e.pictures[cell] {
+		buf.WriteString(img)
+	}
+	buf.WriteString(`</td>`)
+	return nil
+}
+
+// isSafeHTMLLink returns if the hyperlink target could be rendered as the
+// link, only the http, https, mailto and the location targets are allowed.
+func isSafeHTMLLink(link string) bool {
+	if strings.HasPrefix(link, "#") {
+		return true
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// fontFamily returns the CSS font family by given font name.
+func (e *htmlExporter) fontFamily(name string) string {
+	if strings.ContainsAny(name, " '\"") {
+		name = "'" + strings.ReplaceAll(name, "'", "") + "'"
+	}
+	return name
+}
+
+// color returns the CSS color by given color settings, the theme colors and
+// indexed colors will be resolved.
+func (e *htmlExporter) color(color *xlsxColor) string {
+	if rgb := e.f.getColor(color); rgb != "" {
+		return "#" + rgb
+	}
+	return ""
+}
+
+// cellStyle returns the CSS declarations by given cell style index.
+func (e *htmlExporter) cellStyle(styleID int) string {
+	if css, ok := e.styles[styleID]; ok {
+		return css
+	}
+	var css []string
+	styleSheet := e.f.stylesReader()
+	var xf xlsxXf
+	if styleSheet.CellXfs != nil && styleID >= 0 && styleID < len(styleSheet.CellXfs.Xf) {
+		xf = styleSheet.CellXfs.Xf[styleID]
+	}
+	if xf.FontID != nil && *xf.FontID != 0 && styleSheet.Fonts != nil && *xf.FontID < len(styleSheet.Fonts.Font) {
+		font := styleSheet.Fonts.Font[*xf.FontID]
+		if font.Name != nil && font.Name.Val != nil {
+			css = append(css, "font-family:"+e.fontFamily(*font.Name.Val))
+		}
+		if font.Sz != nil && font.Sz.Val != nil {
+			css = append(css, "font-size:"+strconv.FormatFloat(*font.Sz.Val, 'f', -1, 64)+"pt")
+		}
+		if font.B != nil && (font.B.Val == nil || *font.B.Val) {
+			css = append(css, "font-weight:bold")
+		}
+		if font.I != nil && (font.I.Val == nil || *font.I.Val) {
+			css = append(css, "font-style:italic")
+		}
+		var decorations []string
+		if font.U != nil && (font.U.Val == nil || *font.U.Val != "none") {
+			decorations = append(decorations, "underline")
+		}
+		if font.Strike != nil && (font.Strike.Val == nil || *font.Strike.Val) {
+			decorations = append(decorations, "line-through")
+		}
+		if len(decorations) > 0 {
+			css = append(css, "text-decoration:"+strings.Join(decorations, " "))
+		}
+		if color := e.color(font.Color); color != "" {
+			css = append(css, "color:"+color)
+		}
+	}
+	if xf.FillID != nil && styleSheet.Fills != nil && *xf.FillID < len(styleSheet.Fills.Fill) {
+		fill := styleSheet.Fills.Fill[*xf.FillID]
+		if fill.PatternFill != nil && fill.PatternFill.PatternType != "" && fill.PatternFill.PatternType != "none" {
+			if color := e.color(fill.PatternFill.FgColor); color != "" {
+				css = append(css, "background-color:"+color)
+			}
+		}
+		if fill.GradientFill != nil && len(fill.GradientFill.Stop) > 0 {
+			var stops []string
+			for _, stop := range fill.GradientFill.Stop {
+				if color := e.color(&stop.Color); color != "" {
+					stops = append(stops, fmt.Sprintf("%s %g%%", color, stop.Position*100))
+				}
+			}
+			if len(stops) > 0 {
+				css = append(css, fmt.Sprintf("background:linear-gradient(%gdeg,%s)", fill.GradientFill.Degree+90, strings.Join(stops, ",")))
+			}
+		}
+	}
+	if xf.BorderID != nil && styleSheet.Borders != nil && *xf.BorderID < len(styleSheet.Borders.Border) {
+		border := styleSheet.Borders.Border[*xf.BorderID]
+		for _, side := range []struct {
+			name string
+			line xlsxLine
+		}{{"left", border.Left}, {"right", border.Right}, {"top", border.Top}, {"bottom", border.Bottom}} {
+			if style, ok := htmlBorderStyles[side.line.Style]; ok {
+				color := e.color(side.line.Color)
+				if color == "" {
+					color = "#000000"
+				}
+				css = append(css, "border-"+side.name+":"+style+" "+color)
+			}
+		}
+	}
+	vertical, whiteSpace := "bottom", "pre"
+	if alignment := xf.Alignment; alignment != nil {
+		if align, ok := htmlHorizontalAlignment[alignment.Horizontal]; ok {
+			css = append(css, "text-align:"+align)
+		}
+		if align, ok := htmlVerticalAlignment[alignment.Vertical]; ok {
+			vertical = align
+		}
+		if alignment.WrapText {
+			whiteSpace = "pre-wrap"
+		}
+		if alignment.Indent > 0 {
+			css = append(css, fmt.Sprintf("padding-left:%dpx", alignment.Indent*9))
+		}
+	}
+	css = append(css, "vertical-align:"+vertical, "white-space:"+whiteSpace, "overflow:hidden")
+	e.styles[styleID] = strings.Join(css, ";")
+	return e.styles[styleID]
+}
@@ -0,0 +1,94 @@
+package xlsx
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportHTML(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", 3.14, true, "<b>"}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", "Merged"))
+	assert.NoError(t, f.MergeCell("Sheet1", "A2", "C3"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A4", "Hidden row"))
+	assert.NoError(t, f.SetRowVisible("Sheet1", 4, false))
+	assert.NoError(t, f.SetColVisible("Sheet1", "D", false))
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "A", 20))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 1, 30))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A5", "Link"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A5", "https://github.com/carmel/xlsx", "External"))
+	style, err := f.NewStyle(&Style{
+		Font:      &Font{Bold: true, Italic: true, Underline: "single", Color: "#FF0000", Family: "Times New Roman", Size: 14},
+		Fill:      Fill{Type: "pattern", Pattern: 1, Color: []string{"#E0EBF5"}},
+		Border:    []Border{{Type: "left", Color: "#0000FF", Style: 2}},
+		Alignment: &Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+	assert.NoError(t, f.AddPicture("Sheet1", "B5", filepath.Join("test", "images", "excel.png"), ""))
+
+	// Test export the used range of the worksheet.
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportHTML("Sheet1", "", &buf))
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, `<table style="border-collapse:collapse;table-layout:fixed;`))
+	assert.Contains(t, out, `<col style="width:146px">`)
+	assert.Contains(t, out, `<tr style="height:40px">`)
+	assert.Contains(t, out, `<td style="font-family:&#39;Times New Roman&#39;;font-size:14pt;font-weight:bold;font-style:italic;text-decoration:underline;color:#FF0000;background-color:#E0EBF5;border-left:2px solid #0000FF;text-align:center;vertical-align:middle;white-space:pre-wrap;overflow:hidden">Name</td>`)
+	assert.Contains(t, out, `text-align:right">3.14</td>`)
+	assert.Contains(t, out, `text-align:center">TRUE</td>`)
+	assert.Contains(t, out, `<td colspan="3" rowspan="2" style="vertical-align:bottom;white-space:pre;overflow:hidden">Merged</td>`)
+	assert.Contains(t, out, `<a href="https://github.com/carmel/xlsx">Link</a>`)
+	assert.Contains(t, out, `<img src="data:image/png;base64,`)
+	assert.NotContains(t, out, "Hidden row")
+	assert.NotContains(t, out, "&lt;b&gt;")
+	assert.Equal(t, 4, strings.Count(out, "<tr "))
+	assert.Equal(t, 3, strings.Count(out, "<col "))
+
+	// Test export the range which clipped the merged cell as HTML document.
+	buf.Reset()
+	assert.NoError(t, f.ExportHTML("Sheet1", "B3:C3", &buf, HTMLOptions{FullDocument: true, RawCellValue: true}))
+	assert.Equal(t, `<!DOCTYPE html><html><head><meta charset="utf-8"><title>Sheet1</title></head><body>`+
+		`<table style="border-collapse:collapse;table-layout:fixed;width:140px;font-family:Calibri;font-size:11pt">`+
+		`<colgroup><col style="width:70px"><col style="width:70px"></colgroup><tr style="height:20px">`+
+		`<td colspan="2" style="vertical-align:bottom;white-space:pre;overflow:hidden">Merged</td></tr></table></body></html>`, buf.String())
+
+	// Test export with the theme color and indexed color.
+	themeStyle, err := f.NewStyle(&Style{Font: &Font{Color: "#000000"}, Fill: Fill{Type: "pattern", Pattern: 1, Color: []string{"#000000"}}})
+	assert.NoError(t, err)
+	xf := f.Styles.CellXfs.Xf[themeStyle]
+	f.Styles.Fonts.Font[*xf.FontID].Color = &xlsxColor{Theme: intPtr(4), Tint: 0.5}
+	f.Styles.Fills.Fill[*xf.FillID].PatternFill.FgColor = &xlsxColor{Indexed: 10}
+	assert.NoError(t, f.SetCellValue("Sheet1", "E1", "Theme"))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "E1", "E1", themeStyle))
+	buf.Reset()
+	assert.NoError(t, f.ExportHTML("Sheet1", "E1", &buf))
+	assert.Contains(t, buf.String(), "color:#ADCDEA;background-color:#FF0000")
+
+	// Test export with the hyperlink which refers to the whole worksheet.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.Hyperlinks.Hyperlink = append(ws.Hyperlinks.Hyperlink, xlsxHyperlink{Ref: "A1:XFD1048576", Location: "Sheet1!A1"})
+	buf.Reset()
+	assert.NoError(t, f.ExportHTML("Sheet1", "E1", &buf))
+	assert.Contains(t, buf.String(), `<a href="#Sheet1!A1">Theme</a>`)
+
+	// Test export the hyperlinks with the unsafe schemes as plain text.
+	ws.Hyperlinks.Hyperlink = ws.Hyperlinks.Hyperlink[:len(ws.Hyperlinks.Hyperlink)-1]
+	for _, link := range []string{"javascript:alert(1)", " JavaScript:alert(1)", "data:text/html,<b>", "file.xlsx"} {
+		assert.NoError(t, f.SetCellHyperLink("Sheet1", "E1", link, "External"))
+		buf.Reset()
+		assert.NoError(t, f.ExportHTML("Sheet1", "E1", &buf))
+		assert.NotContains(t, buf.String(), "<a ", link)
+	}
+	assert.True(t, isSafeHTMLLink("mailto:user@example.com"))
+	assert.False(t, isSafeHTMLLink("%zz"))
+
+	// Test export with invalid worksheet name and range reference.
+	assert.EqualError(t, f.ExportHTML("SheetN", "", &buf), "sheet SheetN is not exist")
+	assert.Error(t, f.ExportHTML("Sheet1", "A:B", &buf))
+}
@@ -4,6 +4,8 @@ import (
 	"archive/zip"
 	"bytes"
 	"container/list"
+	"crypto/rand"
+	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -471,6 +473,18 @@ func parseFormatSet(formatSet string) []byte {
 	return []byte("{}")
 }
 
+// unmarshalFormatSet provides a function to decode the JSON format settings
+// into the given options, the unknown fields of the settings will be reported
+// as an error instead of being silently ignored.
+func unmarshalFormatSet(data []byte, v interface{}) error {
+	if !json.Valid(data) {
+		return json.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
 // namespaceStrictToTransitional provides a method to convert Strict and
 // Transitional namespaces.
 func namespaceStrictToTransitional(content []byte) []byte {
@@ -802,3 +816,12 @@ func (stack *Stack) Len() int {
 func (stack *Stack) Empty() bool {
 	return stack.list.Len() == 0
 }
+
+// newGUID provides a function to generate a random GUID (version 4 UUID) in
+// the registry format, such as {8CA1FE1B-2A42-4F7E-9B8E-64D7D0F4A9C1}.
+func newGUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6], b[8] = b[6]&0x0f|0x40, b[8]&0x3f|0x80
+	return fmt.Sprintf("{%X-%X-%X-%X-%X}", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
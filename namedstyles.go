@@ -0,0 +1,407 @@
+package xlsx
+
+import (
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// builtInCellStyles defined the built-in ID of the built-in named cell
+// styles.
+var builtInCellStyles = map[string]int{
+	"Normal": 0, "Comma": 3, "Currency": 4, "Percent": 5, "Comma [0]": 6,
+	"Currency [0]": 7, "Hyperlink": 8, "Followed Hyperlink": 9, "Note": 10,
+	"Warning Text": 11, "Title": 15, "Heading 1": 16, "Heading 2": 17,
+	"Heading 3": 18, "Heading 4": 19, "Input": 20, "Output": 21,
+	"Calculation": 22, "Check Cell": 23, "Linked Cell": 24, "Total": 25,
+	"Good": 26, "Bad": 27, "Neutral": 28, "Accent1": 29,
+	"20% - Accent1": 30, "40% - Accent1": 31, "60% - Accent1": 32,
+	"Accent2": 33, "20% - Accent2": 34, "40% - Accent2": 35,
+	"60% - Accent2": 36, "Accent3": 37, "20% - Accent3": 38,
+	"40% - Accent3": 39, "60% - Accent3": 40, "Accent4": 41,
+	"20% - Accent4": 42, "40% - Accent4": 43, "60% - Accent4": 44,
+	"Accent5": 45, "20% - Accent5": 46, "40% - Accent5": 47,
+	"60% - Accent5": 48, "Accent6": 49, "20% - Accent6": 50,
+	"40% - Accent6": 51, "60% - Accent6": 52, "Explanatory Text": 53,
+}
+
+// NewNamedStyle provides a function to create or redefine the named cell
+// style by given style name and style settings, the parameters of the style
+// settings are the same as function NewStyle. The built-in ID will be set if
+// the name is one of the built-in named cell styles, such as "Good", "Bad",
+// "Heading 1" and "Total". The cells that applied the named cell style will
+// inherit the new formatting after it redefined. For example, create a named
+// cell style "Corporate" and apply it to the cells Sheet1!A1:C3:
+//
+//    err := f.NewNamedStyle("Corporate", &xlsx.Style{
+//        Font: &xlsx.Font{Bold: true, Color: "#1F4E78"},
+//        Fill: xlsx.Fill{Type: "pattern", Color: []string{"#DDEBF7"}, Pattern: 1},
+//    })
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    err = f.SetCellNamedStyle("Sheet1", "A1", "C3", "Corporate")
+//
+func (f *File) NewNamedStyle(name string, style interface{}) error {
+	if name == "" || utf8.RuneCountInString(name) > MaxFieldLength {
+		return ErrNamedStyleName
+	}
+	fs, err := parseFormatStyleSet(style)
+	if err != nil {
+		return err
+	}
+	if fs.DecimalPlaces == 0 {
+		fs.DecimalPlaces = 2
+	}
+	s := f.stylesReader()
+	s.Lock()
+	defer s.Unlock()
+	numFmtID, fontID, borderID, fillID := f.newXfIDs(s, fs)
+	xf := xlsxXf{NumFmtID: intPtr(numFmtID), FontID: intPtr(fontID), FillID: intPtr(fillID), BorderID: intPtr(borderID)}
+	xf.ApplyNumberFormat, xf.ApplyFont = boolPtr(numFmtID != 0), boolPtr(fontID != 0)
+	xf.ApplyFill, xf.ApplyBorder = boolPtr(fillID != 0), boolPtr(borderID != 0)
+	xf.ApplyAlignment, xf.ApplyProtection = boolPtr(fs.Alignment != nil), boolPtr(fs.Protection != nil)
+	if fs.Alignment != nil {
+		xf.Alignment = newAlignment(fs)
+	}
+	if fs.Protection != nil {
+		xf.Protection = newProtection(fs)
+	}
+	if s.CellStyleXfs == nil {
+		s.CellStyleXfs = &xlsxCellStyleXfs{}
+	}
+	if s.CellStyles == nil {
+		s.CellStyles = &xlsxCellStyles{}
+	}
+	if cellStyle := getCellStyle(s, name); cellStyle != nil && cellStyle.XfID < len(s.CellStyleXfs.Xf) {
+		s.CellStyleXfs.Xf[cellStyle.XfID] = xf
+		f.inheritNamedStyle(s, cellStyle.XfID)
+		return err
+	}
+	s.CellStyleXfs.Xf = append(s.CellStyleXfs.Xf, xf)
+	s.CellStyleXfs.Count = len(s.CellStyleXfs.Xf)
+	cellStyle := &xlsxCellStyle{Name: name, XfID: s.CellStyleXfs.Count - 1}
+	if builtInID, ok := builtInCellStyles[name]; ok {
+		cellStyle.BuiltInID, cellStyle.CustomBuiltIn = intPtr(builtInID), boolPtr(true)
+	}
+	s.CellStyles.CellStyle = append(s.CellStyles.CellStyle, cellStyle)
+	s.CellStyles.Count = len(s.CellStyles.CellStyle)
+	return err
+}
+
+// getCellStyle provides a function to get the named cell style by given
+// style name, the name is case insensitive.
+func getCellStyle(s *xlsxStyleSheet, name string) *xlsxCellStyle {
+	if s.CellStyles == nil {
+		return nil
+	}
+	for _, cellStyle := range s.CellStyles.CellStyle {
+		if cellStyle != nil && strings.EqualFold(cellStyle.Name, name) {
+			return cellStyle
+		}
+	}
+	return nil
+}
+
+// inheritNamedStyle provides a function to update the formatting records of
+// cells which inherit the formatting from the redefined named cell style by
+// given index of the named cell style formatting record, the aspects of the
+// formatting which not applied by the cell formatting record will be
+// inherited.
+func (f *File) inheritNamedStyle(s *xlsxStyleSheet, xfID int) {
+	if s.CellXfs == nil {
+		return
+	}
+	styleXf := s.CellStyleXfs.Xf[xfID]
+	for i, xf := range s.CellXfs.Xf {
+		if xf.XfID == nil || *xf.XfID != xfID {
+			continue
+		}
+		if xf.ApplyNumberFormat == nil || !*xf.ApplyNumberFormat {
+			s.CellXfs.Xf[i].NumFmtID = styleXf.NumFmtID
+		}
+		if xf.ApplyFont == nil || !*xf.ApplyFont {
+			s.CellXfs.Xf[i].FontID = styleXf.FontID
+		}
+		if xf.ApplyFill == nil || !*xf.ApplyFill {
+			s.CellXfs.Xf[i].FillID = styleXf.FillID
+		}
+		if xf.ApplyBorder == nil || !*xf.ApplyBorder {
+			s.CellXfs.Xf[i].BorderID = styleXf.BorderID
+		}
+		if xf.ApplyAlignment == nil || !*xf.ApplyAlignment {
+			s.CellXfs.Xf[i].Alignment = styleXf.Alignment
+		}
+		if xf.ApplyProtection == nil || !*xf.ApplyProtection {
+			s.CellXfs.Xf[i].Protection = styleXf.Protection
+		}
+	}
+}
+
+// SetCellNamedStyle provides a function to apply the named cell style to the
+// cells by given worksheet name, coordinate area and style name. The
+// formatting of the cells will be replaced by the named cell style, and the
+// cells will inherit the formatting of the named cell style when it
+// redefined by the NewNamedStyle function. For example, apply the built-in
+// named cell style "Normal" to the cells Sheet1!A1:B2:
+//
+//    err := f.SetCellNamedStyle("Sheet1", "A1", "B2", "Normal")
+//
+func (f *File) SetCellNamedStyle(sheet, hcell, vcell, name string) error {
+	s := f.stylesReader()
+	s.Lock()
+	cellStyle := getCellStyle(s, name)
+	if cellStyle == nil || s.CellStyleXfs == nil || cellStyle.XfID >= len(s.CellStyleXfs.Xf) {
+		s.Unlock()
+		return newNamedStyleNotExistError(name)
+	}
+	xf := s.CellStyleXfs.Xf[cellStyle.XfID]
+	xf.XfID = intPtr(cellStyle.XfID)
+	xf.ApplyNumberFormat, xf.ApplyFont, xf.ApplyFill, xf.ApplyBorder = nil, nil, nil, nil
+	xf.ApplyAlignment, xf.ApplyProtection = nil, nil
+	if s.CellXfs == nil {
+		s.CellXfs = &xlsxCellXfs{}
+	}
+	styleID := -1
+	for i := range s.CellXfs.Xf {
+		if reflect.DeepEqual(s.CellXfs.Xf[i], xf) {
+			styleID = i
+			break
+		}
+	}
+	if styleID == -1 {
+		s.CellXfs.Xf = append(s.CellXfs.Xf, xf)
+		s.CellXfs.Count = len(s.CellXfs.Xf)
+		styleID = s.CellXfs.Count - 1
+	}
+	s.Unlock()
+	return f.SetCellStyle(sheet, hcell, vcell, styleID)
+}
+
+// GetNamedStyles provides a function to get the names of all named cell
+// styles in the workbook.
+func (f *File) GetNamedStyles() []string {
+	var names []string
+	s := f.stylesReader()
+	s.Lock()
+	defer s.Unlock()
+	if s.CellStyles != nil {
+		for _, cellStyle := range s.CellStyles.CellStyle {
+			if cellStyle != nil {
+				names = append(names, cellStyle.Name)
+			}
+		}
+	}
+	return names
+}
+
+// GetNamedStyle provides a function to get the style settings of the named
+// cell style by given style name.
+func (f *File) GetNamedStyle(name string) (*Style, error) {
+	s := f.stylesReader()
+	s.Lock()
+	defer s.Unlock()
+	cellStyle := getCellStyle(s, name)
+	if cellStyle == nil || s.CellStyleXfs == nil || cellStyle.XfID >= len(s.CellStyleXfs.Xf) {
+		return nil, newNamedStyleNotExistError(name)
+	}
+	return f.getXfStyle(s, s.CellStyleXfs.Xf[cellStyle.XfID]), nil
+}
+
+// CompactStyles provides a function to remove the unreferenced formatting
+// records, fonts, fills, borders and custom number formats from the
+// stylesheet, and remap the style index of the cells, rows and columns in
+// all worksheets. The named cell styles will be kept. Note that the style
+// index which were obtained before will be invalid after the stylesheet
+// compacted, and the stylesheet of the workbook with stream writer can't be
+// compacted. The stylesheet will be compacted on save if the CompactStyles
+// of the options is true.
+func (f *File) CompactStyles() error {
+	if len(f.streams) > 0 {
+		return ErrCompactStylesStream
+	}
+	var worksheets []*xlsxWorksheet
+	for _, sheet := range f.GetSheetList() {
+		name, ok := f.sheetMap[trimSheetName(sheet)]
+		if !ok || !strings.HasPrefix(name, "xl/worksheets") {
+			continue
+		}
+		ws, err := f.workSheetReader(sheet)
+		if err != nil {
+			return err
+		}
+		worksheets = append(worksheets, ws)
+	}
+	s := f.stylesReader()
+	s.Lock()
+	defer s.Unlock()
+	if s.CellXfs == nil || len(s.CellXfs.Xf) == 0 {
+		return nil
+	}
+	// Collect the referenced cell formatting records.
+	usedXfs := map[int]bool{0: true}
+	for _, ws := range worksheets {
+		for _, row := range ws.SheetData.Row {
+			usedXfs[row.S] = true
+			for _, c := range row.C {
+				usedXfs[c.S] = true
+			}
+		}
+		if ws.Cols != nil {
+			for _, col := range ws.Cols.Col {
+				usedXfs[col.Style] = true
+			}
+		}
+	}
+	xfMap := compactXfs(&s.CellXfs.Xf, usedXfs)
+	s.CellXfs.Count = len(s.CellXfs.Xf)
+	for _, ws := range worksheets {
+		for r := range ws.SheetData.Row {
+			ws.SheetData.Row[r].S = xfMap[ws.SheetData.Row[r].S]
+			for c := range ws.SheetData.Row[r].C {
+				ws.SheetData.Row[r].C[c].S = xfMap[ws.SheetData.Row[r].C[c].S]
+			}
+		}
+		if ws.Cols != nil {
+			for i := range ws.Cols.Col {
+				ws.Cols.Col[i].Style = xfMap[ws.Cols.Col[i].Style]
+			}
+		}
+	}
+	// Collect the referenced named cell style formatting records.
+	if s.CellStyleXfs != nil {
+		usedStyleXfs := map[int]bool{0: true}
+		if s.CellStyles != nil {
+			for _, cellStyle := range s.CellStyles.CellStyle {
+				usedStyleXfs[cellStyle.XfID] = true
+			}
+		}
+		for _, xf := range s.CellXfs.Xf {
+			if xf.XfID != nil {
+				usedStyleXfs[*xf.XfID] = true
+			}
+		}
+		styleXfMap := compactXfs(&s.CellStyleXfs.Xf, usedStyleXfs)
+		s.CellStyleXfs.Count = len(s.CellStyleXfs.Xf)
+		for i := range s.CellXfs.Xf {
+			if xf := s.CellXfs.Xf[i]; xf.XfID != nil {
+				s.CellXfs.Xf[i].XfID = intPtr(styleXfMap[*xf.XfID])
+			}
+		}
+		if s.CellStyles != nil {
+			for _, cellStyle := range s.CellStyles.CellStyle {
+				cellStyle.XfID = styleXfMap[cellStyle.XfID]
+			}
+		}
+	}
+	compactStyleElements(s)
+	return nil
+}
+
+// compactXfs provides a function to remove the unreferenced formatting records
+// by given formatting records and referenced index, and returns the index
+// map of the formatting records.
+func compactXfs(xfs *[]xlsxXf, used map[int]bool) map[int]int {
+	idxMap, compacted := make(map[int]int), make([]xlsxXf, 0, len(*xfs))
+	for i, xf := range *xfs {
+		if used[i] {
+			idxMap[i] = len(compacted)
+			compacted = append(compacted, xf)
+		}
+	}
+	*xfs = compacted
+	return idxMap
+}
+
+// compactStyleElements provides a function to remove the fonts, fills,
+// borders and custom number formats which are not referenced by the
+// formatting records, and remap the index of them in the formatting records.
+func compactStyleElements(s *xlsxStyleSheet) {
+	xfs := make([]*xlsxXf, 0, len(s.CellXfs.Xf))
+	for i := range s.CellXfs.Xf {
+		xfs = append(xfs, &s.CellXfs.Xf[i])
+	}
+	if s.CellStyleXfs != nil {
+		for i := range s.CellStyleXfs.Xf {
+			xfs = append(xfs, &s.CellStyleXfs.Xf[i])
+		}
+	}
+	usedFonts, usedFills, usedBorders, usedNumFmts := map[int]bool{0: true}, map[int]bool{0: true, 1: true}, map[int]bool{0: true}, map[int]bool{}
+	for _, xf := range xfs {
+		markStyleElement(usedFonts, xf.FontID)
+		markStyleElement(usedFills, xf.FillID)
+		markStyleElement(usedBorders, xf.BorderID)
+		markStyleElement(usedNumFmts, xf.NumFmtID)
+	}
+	var fontMap, fillMap, borderMap map[int]int
+	if s.Fonts != nil {
+		var fonts []*xlsxFont
+		fontMap = make(map[int]int)
+		for i, font := range s.Fonts.Font {
+			if usedFonts[i] {
+				fontMap[i] = len(fonts)
+				fonts = append(fonts, font)
+			}
+		}
+		s.Fonts.Font, s.Fonts.Count = fonts, len(fonts)
+	}
+	if s.Fills != nil {
+		var fills []*xlsxFill
+		fillMap = make(map[int]int)
+		for i, fill := range s.Fills.Fill {
+			if usedFills[i] {
+				fillMap[i] = len(fills)
+				fills = append(fills, fill)
+			}
+		}
+		s.Fills.Fill, s.Fills.Count = fills, len(fills)
+	}
+	if s.Borders != nil {
+		var borders []*xlsxBorder
+		borderMap = make(map[int]int)
+		for i, border := range s.Borders.Border {
+			if usedBorders[i] {
+				borderMap[i] = len(borders)
+				borders = append(borders, border)
+			}
+		}
+		s.Borders.Border, s.Borders.Count = borders, len(borders)
+	}
+	for _, xf := range xfs {
+		xf.FontID = remapStyleElement(fontMap, xf.FontID)
+		xf.FillID = remapStyleElement(fillMap, xf.FillID)
+		xf.BorderID = remapStyleElement(borderMap, xf.BorderID)
+	}
+	if s.NumFmts != nil {
+		var numFmts []*xlsxNumFmt
+		for _, numFmt := range s.NumFmts.NumFmt {
+			if usedNumFmts[numFmt.NumFmtID] {
+				numFmts = append(numFmts, numFmt)
+			}
+		}
+		s.NumFmts.NumFmt, s.NumFmts.Count = numFmts, len(numFmts)
+		if len(numFmts) == 0 {
+			s.NumFmts = nil
+		}
+	}
+}
+
+// markStyleElement provides a function to mark the style element referenced
+// by given index.
+func markStyleElement(used map[int]bool, id *int) {
+	if id != nil {
+		used[*id] = true
+	}
+}
+
+// remapStyleElement provides a function to get the new index of the style
+// element by given index map and the original index.
+func remapStyleElement(idxMap map[int]int, id *int) *int {
+	if id == nil {
+		return id
+	}
+	if idx, ok := idxMap[*id]; ok {
+		return intPtr(idx)
+	}
+	return id
+}
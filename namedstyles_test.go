@@ -0,0 +1,129 @@
+package xlsx
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamedStyle(t *testing.T) {
+	f := NewFile()
+	assert.Equal(t, []string{"Normal"}, f.GetNamedStyles())
+	assert.NoError(t, f.NewNamedStyle("Corporate", &Style{
+		Font:      &Font{Bold: true, Color: "#1F4E78"},
+		Fill:      Fill{Type: "pattern", Color: []string{"#DDEBF7"}, Pattern: 1},
+		Alignment: &Alignment{Horizontal: "center"},
+	}))
+	assert.NoError(t, f.NewNamedStyle("Good", `{"font":{"color":"#006100"},"fill":{"type":"pattern","color":["#C6EFCE"],"pattern":1}}`))
+	assert.Equal(t, []string{"Normal", "Corporate", "Good"}, f.GetNamedStyles())
+	assert.Equal(t, 26, *f.Styles.CellStyles.CellStyle[2].BuiltInID)
+	assert.Nil(t, f.Styles.CellStyles.CellStyle[1].BuiltInID)
+
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Corporate"))
+	assert.NoError(t, f.SetCellNamedStyle("Sheet1", "A1", "B2", "corporate"))
+	styleID, err := f.GetCellStyle("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *f.Styles.CellXfs.Xf[styleID].XfID)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, &Font{Bold: true, Color: "#1F4E78", Size: 11, Family: "Calibri"}, style.Font)
+	assert.Equal(t, &Alignment{Horizontal: "center"}, style.Alignment)
+	// Test apply the same named cell style again.
+	assert.NoError(t, f.SetCellNamedStyle("Sheet1", "C3", "C3", "Corporate"))
+	cellStyleID, err := f.GetCellStyle("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, styleID, cellStyleID)
+
+	// Test redefine the named cell style, the cells inherit the formatting.
+	assert.NoError(t, f.NewNamedStyle("Corporate", &Style{Font: &Font{Italic: true}}))
+	assert.Equal(t, []string{"Normal", "Corporate", "Good"}, f.GetNamedStyles())
+	style, err = f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.True(t, style.Font.Italic)
+	assert.False(t, style.Font.Bold)
+	assert.Equal(t, Fill{}, style.Fill)
+	assert.Nil(t, style.Alignment)
+	style, err = f.GetNamedStyle("Good")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"#C6EFCE"}, style.Fill.Color)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestNamedStyle.xlsx")))
+
+	// Test named cell style with invalid name and style settings.
+	assert.EqualError(t, f.NewNamedStyle("", &Style{}), ErrNamedStyleName.Error())
+	assert.EqualError(t, f.NewNamedStyle(strings.Repeat("c", MaxFieldLength+1), &Style{}), ErrNamedStyleName.Error())
+	assert.EqualError(t, f.NewNamedStyle("Style", "{"), "unexpected end of JSON input")
+	assert.EqualError(t, f.SetCellNamedStyle("Sheet1", "A1", "A1", "Style"), newNamedStyleNotExistError("Style").Error())
+	assert.EqualError(t, f.SetCellNamedStyle("SheetN", "A1", "A1", "Good"), "sheet SheetN is not exist")
+	_, err = f.GetNamedStyle("Style")
+	assert.EqualError(t, err, newNamedStyleNotExistError("Style").Error())
+
+	// Test named cell style on the stylesheet without named cell styles.
+	f.Styles.CellStyles, f.Styles.CellStyleXfs = nil, nil
+	assert.Nil(t, f.GetNamedStyles())
+	assert.NoError(t, f.NewNamedStyle("Normal", &Style{}))
+	assert.Equal(t, []string{"Normal"}, f.GetNamedStyles())
+}
+
+func TestCompactStyles(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	var styles []int
+	for _, color := range []string{"#FF0000", "#00FF00", "#0000FF", "#FFFF00"} {
+		styleID, err := f.NewStyle(&Style{
+			Font:         &Font{Color: color},
+			Fill:         Fill{Type: "pattern", Color: []string{color}, Pattern: 1},
+			Border:       []Border{{Type: "left", Color: color, Style: 1}},
+			CustomNumFmt: stringPtr("0.00" + strings.Repeat("0", len(styles))),
+		})
+		assert.NoError(t, err)
+		styles = append(styles, styleID)
+	}
+	assert.NoError(t, f.NewNamedStyle("Corporate", &Style{Font: &Font{Bold: true}}))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", styles[1]))
+	assert.NoError(t, f.SetRowStyle("Sheet1", 2, 2, styles[3]))
+	assert.NoError(t, f.SetColStyle("Sheet2", "B", styles[3]))
+	assert.NoError(t, f.SetCellNamedStyle("Sheet2", "A1", "A1", "Corporate"))
+	expected := make(map[string]*Style)
+	for sheet, cell := range map[string]string{"Sheet1": "A1", "Sheet2": "A1"} {
+		styleID, err := f.GetCellStyle(sheet, cell)
+		assert.NoError(t, err)
+		expected[sheet+"!"+cell], err = f.GetStyle(styleID)
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCompactStyles.xlsx"), Options{CompactStyles: true}))
+	assert.Len(t, f.Styles.CellXfs.Xf, 4)
+	assert.Len(t, f.Styles.Fonts.Font, 4)
+	assert.Len(t, f.Styles.Fills.Fill, 4)
+	assert.Len(t, f.Styles.Borders.Border, 3)
+	assert.Len(t, f.Styles.NumFmts.NumFmt, 2)
+	assert.Equal(t, 4, f.Styles.CellXfs.Count)
+	assert.Equal(t, []string{"Normal", "Corporate"}, f.GetNamedStyles())
+
+	f, err := OpenFile(filepath.Join("test", "TestCompactStyles.xlsx"))
+	assert.NoError(t, err)
+	for cell, style := range expected {
+		ref := strings.Split(cell, "!")
+		styleID, err := f.GetCellStyle(ref[0], ref[1])
+		assert.NoError(t, err)
+		result, err := f.GetStyle(styleID)
+		assert.NoError(t, err)
+		assert.Equal(t, style, result, cell)
+	}
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ws.SheetData.Row[1].S)
+	ws, err = f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ws.Cols.Col[0].Style)
+	assert.Equal(t, 1, *f.Styles.CellXfs.Xf[3].XfID)
+	assert.NoError(t, f.Close())
+
+	// Test compact the stylesheet of the workbook with stream writer.
+	f = NewFile()
+	_, err = f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.EqualError(t, f.CompactStyles(), ErrCompactStylesStream.Error())
+}
@@ -0,0 +1,1978 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Namespaces and media type of the OpenDocument spreadsheet package.
+const (
+	odsMediaType     = "application/vnd.oasis.opendocument.spreadsheet"
+	odsNameSpaceFO   = "urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"
+	odsNameSpaceMeta = "urn:oasis:names:tc:opendocument:xmlns:meta:1.0"
+	odsNameSpaceNum  = "urn:oasis:names:tc:opendocument:xmlns:datastyle:1.0"
+	odsNameSpaceOf   = "urn:oasis:names:tc:opendocument:xmlns:of:1.2"
+	odsNameSpaceOff  = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odsNameSpaceSVG  = "urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0"
+	odsNameSpaceSty  = "urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+	odsNameSpaceTab  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odsNameSpaceText = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	odsNameSpaceMani = "urn:oasis:names:tc:opendocument:xmlns:manifest:1.0"
+)
+
+// odsNameSpaces defined the namespace declarations of the document parts
+// written by the OpenDocument spreadsheet writer.
+var odsNameSpaces = ` xmlns:office="` + odsNameSpaceOff + `" xmlns:style="` + odsNameSpaceSty +
+	`" xmlns:text="` + odsNameSpaceText + `" xmlns:table="` + odsNameSpaceTab +
+	`" xmlns:fo="` + odsNameSpaceFO + `" xmlns:number="` + odsNameSpaceNum +
+	`" xmlns:svg="` + odsNameSpaceSVG + `" xmlns:of="` + odsNameSpaceOf +
+	`" xmlns:meta="` + odsNameSpaceMeta + `" xmlns:dc="http://purl.org/dc/elements/1.1/" office:version="1.2"`
+
+// odsErrorValues defined the error literals which could be appeared in the
+// formulas.
+var odsErrorValues = []string{"#NULL!", "#DIV/0!", "#VALUE!", "#REF!", "#NAME?", "#NUM!", "#N/A"}
+
+// odsBorderLines defined the OpenDocument border line by the border style
+// name of the spreadsheet.
+var odsBorderLines = map[string]string{
+	"thin":             "0.74pt solid",
+	"medium":           "1.76pt solid",
+	"thick":            "2.49pt solid",
+	"double":           "2.01pt double",
+	"hair":             "0.05pt solid",
+	"dashed":           "0.74pt dashed",
+	"dotted":           "0.74pt dotted",
+	"mediumDashed":     "1.76pt dashed",
+	"dashDot":          "0.74pt dashed",
+	"mediumDashDot":    "1.76pt dashed",
+	"dashDotDot":       "0.74pt dotted",
+	"mediumDashDotDot": "1.76pt dotted",
+	"slantDashDot":     "1.76pt dashed",
+}
+
+var (
+	odsCellRefPattern   = regexp.MustCompile(`^\$?[A-Za-z]{1,3}\$?[0-9]+$`)
+	odsColRefPattern    = regexp.MustCompile(`^\$?[A-Za-z]{1,3}$`)
+	odsRowRefPattern    = regexp.MustCompile(`^\$?[0-9]+$`)
+	odsSheetNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	odsDurationRegexp   = regexp.MustCompile(`^-?P(?:([0-9.]+)D)?T?(?:([0-9.]+)H)?(?:([0-9.]+)M)?(?:([0-9.]+)S)?$`)
+)
+
+// odsStyle directly maps the properties of the style:style element, the
+// attributes of the properties child elements are stored by local name.
+type odsStyle struct {
+	family    string
+	parent    string
+	dataStyle string
+	props     map[string]string
+}
+
+// odsCell directly maps the table:table-cell and table:covered-table-cell
+// elements.
+type odsCell struct {
+	covered   bool
+	style     string
+	valueType string
+	value     string
+	formula   string
+	text      string
+	repeat    int
+	colSpan   int
+	rowSpan   int
+}
+
+// odsReader provides the state of converting the OpenDocument spreadsheet.
+type odsReader struct {
+	f          *File
+	styles     map[string]*odsStyle
+	dataStyles map[string]string
+	cellStyles map[string]int
+	date1904   bool
+	hidden     []string
+	sheet      string
+	ws         *xlsxWorksheet
+	row, col   int
+	colStyles  map[int]string
+	merges     [][2]string
+}
+
+// odsDocumentMeta directly maps the office:meta element in the meta.xml.
+type odsDocumentMeta struct {
+	Title          string `xml:"http://purl.org/dc/elements/1.1/ title"`
+	Subject        string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+	Description    string `xml:"http://purl.org/dc/elements/1.1/ description"`
+	Language       string `xml:"http://purl.org/dc/elements/1.1/ language"`
+	LastModifiedBy string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Modified       string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creator        string `xml:"urn:oasis:names:tc:opendocument:xmlns:meta:1.0 initial-creator"`
+	Created        string `xml:"urn:oasis:names:tc:opendocument:xmlns:meta:1.0 creation-date"`
+	Keywords       string `xml:"urn:oasis:names:tc:opendocument:xmlns:meta:1.0 keyword"`
+	Revision       string `xml:"urn:oasis:names:tc:opendocument:xmlns:meta:1.0 editing-cycles"`
+}
+
+// odsAttr returns the value of the attribute by given namespace and local
+// name of the element.
+func odsAttr(se xml.StartElement, space, local string) string {
+	for _, attr := range se.Attr {
+		if attr.Name.Space == space && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// odsAttrInt returns the positive integer value of the attribute, the
+// default value 1 will be returned if the attribute is absent or invalid.
+func odsAttrInt(se xml.StartElement, space, local string) int {
+	n, err := strconv.Atoi(odsAttr(se, space, local))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// odsLength convert the length with the measure unit to points.
+func odsLength(length string) float64 {
+	length = strings.TrimSpace(length)
+	units := map[string]float64{"pt": 1, "pc": 12, "px": 0.75, "in": 72, "cm": 72 / 2.54, "mm": 72 / 25.4}
+	for unit, ratio := range units {
+		if strings.HasSuffix(length, unit) {
+			val, err := strconv.ParseFloat(strings.TrimSuffix(length, unit), 64)
+			if err != nil {
+				return 0
+			}
+			return val * ratio
+		}
+	}
+	return 0
+}
+
+// openODS convert the package of the OpenDocument spreadsheet into the
+// spreadsheet.
+func openODS(pkg map[string][]byte, opt *Options) (*File, error) {
+	r := &odsReader{
+		styles:     make(map[string]*odsStyle),
+		dataStyles: make(map[string]string),
+		cellStyles: make(map[string]int),
+	}
+	r.f = NewFile()
+	r.f.options = opt
+	if err := r.readStyles(pkg["styles.xml"]); err != nil {
+		return nil, err
+	}
+	if err := r.readContent(pkg["content.xml"]); err != nil {
+		return nil, err
+	}
+	wb := r.f.workbookReader()
+	for _, name := range r.hidden {
+		for idx, sheet := range wb.Sheets.Sheet {
+			if sheet.Name == name {
+				wb.Sheets.Sheet[idx].State = "hidden"
+			}
+		}
+	}
+	if err := r.readMeta(pkg["meta.xml"]); err != nil {
+		return nil, err
+	}
+	return r.f, nil
+}
+
+// readMeta read the document properties in the meta.xml.
+func (r *odsReader) readMeta(content []byte) error {
+	if len(content) == 0 {
+		return nil
+	}
+	var doc struct {
+		Meta odsDocumentMeta `xml:"urn:oasis:names:tc:opendocument:xmlns:office:1.0 meta"`
+	}
+	if err := xml.Unmarshal(content, &doc); err != nil {
+		return err
+	}
+	meta := doc.Meta
+	if meta == (odsDocumentMeta{}) {
+		return nil
+	}
+	return r.f.SetDocProps(&DocProperties{
+		Created:        meta.Created,
+		Creator:        meta.Creator,
+		Description:    meta.Description,
+		Keywords:       meta.Keywords,
+		Language:       meta.Language,
+		LastModifiedBy: meta.LastModifiedBy,
+		Modified:       meta.Modified,
+		Revision:       meta.Revision,
+		Subject:        meta.Subject,
+		Title:          meta.Title,
+	})
+}
+
+// readStyles read the common and automatic styles in the styles.xml, and
+// apply the font of the default cell style as default font of the workbook.
+func (r *odsReader) readStyles(content []byte) error {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if se.Name.Space == odsNameSpaceSty && se.Name.Local == "default-style" &&
+			odsAttr(se, odsNameSpaceSty, "family") == "table-cell" {
+			style, err := r.readStyle(d, se)
+			if err != nil {
+				return err
+			}
+			if family := odsFontFamily(style.props); family != "" {
+				r.f.SetDefaultFont(family)
+			}
+			continue
+		}
+		if err = r.readStyleElement(d, se); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readStyleElement read the style or data style by given start element, the
+// other elements will be ignored.
+func (r *odsReader) readStyleElement(d *xml.Decoder, se xml.StartElement) error {
+	switch {
+	case se.Name.Space == odsNameSpaceSty && se.Name.Local == "style":
+		style, err := r.readStyle(d, se)
+		if err != nil {
+			return err
+		}
+		r.styles[odsAttr(se, odsNameSpaceSty, "name")] = style
+	case se.Name.Space == odsNameSpaceNum && strings.HasSuffix(se.Name.Local, "-style"):
+		code, err := readODSDataStyle(d, se)
+		if err != nil {
+			return err
+		}
+		r.dataStyles[odsAttr(se, odsNameSpaceSty, "name")] = code
+	}
+	return nil
+}
+
+// readStyle read the style:style element, the attributes of the properties
+// child elements will be flatten into the properties map by local name.
+func (r *odsReader) readStyle(d *xml.Decoder, se xml.StartElement) (*odsStyle, error) {
+	style := &odsStyle{
+		family:    odsAttr(se, odsNameSpaceSty, "family"),
+		parent:    odsAttr(se, odsNameSpaceSty, "parent-style-name"),
+		dataStyle: odsAttr(se, odsNameSpaceSty, "data-style-name"),
+		props:     make(map[string]string),
+	}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return style, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if strings.HasSuffix(t.Name.Local, "-properties") {
+				for _, attr := range t.Attr {
+					style.props[attr.Name.Local] = attr.Value
+				}
+			}
+			if err = d.Skip(); err != nil {
+				return style, err
+			}
+		case xml.EndElement:
+			return style, nil
+		}
+	}
+}
+
+// readODSDataStyle read the number, percentage, currency, date, time and
+// boolean data styles and returns the number format code.
+func readODSDataStyle(d *xml.Decoder, se xml.StartElement) (string, error) {
+	var (
+		code  strings.Builder
+		depth int
+		text  *strings.Builder
+	)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return code.String(), err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if t.Name.Space != odsNameSpaceNum {
+				continue
+			}
+			long := odsAttr(t, odsNameSpaceNum, "style") == "long"
+			decimals, _ := strconv.Atoi(odsAttr(t, odsNameSpaceNum, "decimal-places"))
+			switch t.Name.Local {
+			case "number":
+				code.WriteString(odsNumberCode(t, decimals))
+			case "scientific-number":
+				exponent, _ := strconv.Atoi(odsAttr(t, odsNameSpaceNum, "min-exponent-digits"))
+				code.WriteString(odsNumberCode(t, decimals) + "E+" + strings.Repeat("0", int(math.Max(float64(exponent), 2))))
+			case "fraction":
+				digits, _ := strconv.Atoi(odsAttr(t, odsNameSpaceNum, "min-denominator-digits"))
+				code.WriteString("# " + strings.Repeat("?", int(math.Max(float64(digits), 1))) + "/" + strings.Repeat("?", int(math.Max(float64(digits), 1))))
+			case "year":
+				code.WriteString(map[bool]string{true: "yyyy", false: "yy"}[long])
+			case "month":
+				switch {
+				case odsAttr(t, odsNameSpaceNum, "textual") == "true":
+					code.WriteString(map[bool]string{true: "mmmm", false: "mmm"}[long])
+				default:
+					code.WriteString(map[bool]string{true: "mm", false: "m"}[long])
+				}
+			case "day":
+				code.WriteString(map[bool]string{true: "dd", false: "d"}[long])
+			case "day-of-week":
+				code.WriteString(map[bool]string{true: "dddd", false: "ddd"}[long])
+			case "hours":
+				code.WriteString(map[bool]string{true: "hh", false: "h"}[long])
+			case "minutes":
+				code.WriteString(map[bool]string{true: "mm", false: "m"}[long])
+			case "seconds":
+				code.WriteString(map[bool]string{true: "ss", false: "s"}[long])
+				if decimals > 0 {
+					code.WriteString("." + strings.Repeat("0", decimals))
+				}
+			case "am-pm":
+				code.WriteString("AM/PM")
+			case "text-content":
+				code.WriteString("@")
+			case "text", "currency-symbol":
+				text = &strings.Builder{}
+			}
+		case xml.CharData:
+			if text != nil {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if text != nil {
+				code.WriteString(odsLiteralCode(text.String()))
+				text = nil
+			}
+			if depth == 0 {
+				return code.String(), nil
+			}
+			depth--
+		}
+	}
+}
+
+// odsNumberCode returns the integer and decimal part of the number format
+// code by given number:number or number:scientific-number element.
+func odsNumberCode(se xml.StartElement, decimals int) string {
+	digits, err := strconv.Atoi(odsAttr(se, odsNameSpaceNum, "min-integer-digits"))
+	if err != nil {
+		digits = 1
+	}
+	code := strings.Repeat("0", digits)
+	if code == "" {
+		code = "#"
+	}
+	if odsAttr(se, odsNameSpaceNum, "grouping") == "true" {
+		code = "#,##" + strings.Repeat("0", int(math.Max(float64(digits), 1)))
+	}
+	if decimals > 0 {
+		code += "." + strings.Repeat("0", decimals)
+	}
+	return code
+}
+
+// odsLiteralCode returns the number format code of the literal text, the
+// text will be quoted if contains any reserved characters.
+func odsLiteralCode(text string) string {
+	if text == "" || strings.Trim(text, " -/:.,%()$") == "" {
+		return text
+	}
+	return "\"" + strings.ReplaceAll(text, "\"", "") + "\""
+}
+
+// odsFontFamily returns the font family name by given style properties.
+func odsFontFamily(props map[string]string) string {
+	if family := strings.Trim(props["font-family"], "'\""); family != "" {
+		return family
+	}
+	return props["font-name"]
+}
+
+// resolveStyle returns the properties and data style of the style by given
+// name, the properties of the parent styles are inherited except the
+// properties of the default cell style.
+func (r *odsReader) resolveStyle(name string) (map[string]string, string) {
+	props, dataStyle := make(map[string]string), ""
+	var chain []*odsStyle
+	for seen := map[string]bool{}; name != "" && name != "Default" && !seen[name]; {
+		seen[name] = true
+		style, ok := r.styles[name]
+		if !ok {
+			break
+		}
+		chain = append(chain, style)
+		name = style.parent
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		for k, v := range chain[i].props {
+			props[k] = v
+		}
+		if chain[i].dataStyle != "" {
+			dataStyle = chain[i].dataStyle
+		}
+	}
+	return props, dataStyle
+}
+
+// style returns the style index by given cell style name, the number format
+// will be applied if the cell style without data style.
+func (r *odsReader) style(name string, numFmt int) (int, error) {
+	key := name + "\x00" + strconv.Itoa(numFmt)
+	if styleID, ok := r.cellStyles[key]; ok {
+		return styleID, nil
+	}
+	props, dataStyle := r.resolveStyle(name)
+	style := &Style{}
+	if code, ok := r.dataStyles[dataStyle]; ok && code != "" && !strings.EqualFold(code, "general") {
+		style.CustomNumFmt = &code
+		for id, builtIn := range builtInNumFmt {
+			if strings.EqualFold(builtIn, code) {
+				style.NumFmt, style.CustomNumFmt = id, nil
+				break
+			}
+		}
+	} else {
+		style.NumFmt = numFmt
+	}
+	if family, size := odsFontFamily(props), odsLength(props["font-size"]); family != "" || size != 0 ||
+		props["font-weight"] != "" || props["font-style"] != "" || props["color"] != "" ||
+		props["text-underline-style"] != "" || props["text-line-through-style"] != "" {
+		weight, _ := strconv.Atoi(props["font-weight"])
+		style.Font = &Font{
+			Bold:   props["font-weight"] == "bold" || weight >= 600,
+			Italic: props["font-style"] == "italic" || props["font-style"] == "oblique",
+			Strike: props["text-line-through-style"] != "" && props["text-line-through-style"] != "none",
+			Family: family,
+			Size:   size,
+			Color:  props["color"],
+		}
+		if underline := props["text-underline-style"]; underline != "" && underline != "none" {
+			style.Font.Underline = "single"
+			if props["text-underline-type"] == "double" {
+				style.Font.Underline = "double"
+			}
+		}
+	}
+	if color := props["background-color"]; strings.HasPrefix(color, "#") {
+		style.Fill = Fill{Type: "pattern", Pattern: 1, Color: []string{color}}
+	}
+	for _, side := range []struct{ typ, prop string }{
+		{"left", "border-left"}, {"right", "border-right"}, {"top", "border-top"}, {"bottom", "border-bottom"},
+		{"diagonalUp", "diagonal-bl-tr"}, {"diagonalDown", "diagonal-tl-br"},
+	} {
+		line, ok := props[side.prop]
+		if !ok && !strings.HasPrefix(side.typ, "diagonal") {
+			line = props["border"]
+		}
+		if border, ok := odsBorder(side.typ, line); ok {
+			style.Border = append(style.Border, border)
+		}
+	}
+	alignment := &Alignment{
+		Horizontal:  map[string]string{"start": "left", "left": "left", "center": "center", "end": "right", "right": "right", "justify": "justify"}[props["text-align"]],
+		Vertical:    map[string]string{"top": "top", "middle": "center", "bottom": "bottom"}[props["vertical-align"]],
+		WrapText:    props["wrap-option"] == "wrap",
+		ShrinkToFit: props["shrink-to-fit"] == "true",
+	}
+	if angle, err := strconv.Atoi(strings.TrimSuffix(props["rotation-angle"], "deg")); err == nil {
+		if angle = angle % 360; angle > 0 && angle <= 90 {
+			alignment.TextRotation = angle
+		} else if angle >= 270 {
+			alignment.TextRotation = 450 - angle
+		}
+	}
+	if *alignment != (Alignment{}) {
+		style.Alignment = alignment
+	}
+	var styleID int
+	if style.NumFmt != 0 || style.CustomNumFmt != nil || style.Font != nil || style.Fill.Type != "" ||
+		len(style.Border) > 0 || style.Alignment != nil {
+		var err error
+		if styleID, err = r.f.NewStyle(style); err != nil {
+			return 0, err
+		}
+	}
+	r.cellStyles[key] = styleID
+	return styleID, nil
+}
+
+// odsBorder parse the border line properties such as "0.74pt solid #000000"
+// into the border settings.
+func odsBorder(typ, line string) (Border, bool) {
+	border := Border{Type: typ, Color: "#000000"}
+	var width float64
+	var lineStyle string
+	for _, field := range strings.Fields(line) {
+		switch {
+		case strings.HasPrefix(field, "#"):
+			border.Color = field
+		case odsLength(field) > 0:
+			width = odsLength(field)
+		default:
+			lineStyle = field
+		}
+	}
+	switch lineStyle {
+	case "solid":
+		border.Style = 1
+		if width < 0.5 {
+			border.Style = 7
+		} else if width > 2 {
+			border.Style = 5
+		} else if width > 1 {
+			border.Style = 2
+		}
+	case "double":
+		border.Style = 6
+	case "dashed", "dash", "long-dash", "dot-dash", "dot-dot-dash":
+		border.Style = 3
+		if width > 1 {
+			border.Style = 8
+		}
+	case "dotted":
+		border.Style = 4
+	default:
+		return border, false
+	}
+	return border, true
+}
+
+// readContent read the automatic styles and the tables in the content.xml.
+func (r *odsReader) readContent(content []byte) error {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	var sheets int
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == odsNameSpaceTab && t.Name.Local == "null-date":
+				r.setDate1904(odsAttr(t, odsNameSpaceTab, "date-value"))
+			case t.Name.Space == odsNameSpaceTab && t.Name.Local == "table":
+				if err = r.newSheet(t, sheets); err != nil {
+					return err
+				}
+				sheets++
+			case t.Name.Space == odsNameSpaceTab && t.Name.Local == "table-column" && r.ws != nil:
+				if err = r.readColumn(t); err != nil {
+					return err
+				}
+			case t.Name.Space == odsNameSpaceTab && t.Name.Local == "table-row" && r.ws != nil:
+				if err = r.readRow(d, t); err != nil {
+					return err
+				}
+			case r.ws == nil:
+				if err = r.readStyleElement(d, t); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Space == odsNameSpaceTab && t.Name.Local == "table" {
+				if err = r.closeSheet(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// setDate1904 set the 1904 date system of the workbook by given null date.
+func (r *odsReader) setDate1904(nullDate string) {
+	if !strings.HasPrefix(nullDate, "1904-01-01") {
+		return
+	}
+	r.date1904 = true
+	wb := r.f.workbookReader()
+	if wb.WorkbookPr == nil {
+		wb.WorkbookPr = &xlsxWorkbookPr{}
+	}
+	wb.WorkbookPr.Date1904 = true
+}
+
+// newSheet create the worksheet by given table element, the first table will
+// be converted into the default worksheet.
+func (r *odsReader) newSheet(se xml.StartElement, idx int) error {
+	name := odsAttr(se, odsNameSpaceTab, "name")
+	if idx == 0 {
+		r.f.SetSheetName(r.f.GetSheetName(0), name)
+	} else {
+		r.f.NewSheet(name)
+	}
+	r.sheet, r.row, r.col = trimSheetName(name), 0, 0
+	r.colStyles, r.merges = make(map[int]string), nil
+	var err error
+	if r.ws, err = r.f.workSheetReader(r.sheet); err != nil {
+		return err
+	}
+	if style, ok := r.styles[odsAttr(se, odsNameSpaceTab, "style-name")]; ok && style.props["display"] == "false" {
+		r.hidden = append(r.hidden, r.sheet)
+	}
+	return nil
+}
+
+// closeSheet merge the spanned cells of the current worksheet.
+func (r *odsReader) closeSheet() error {
+	for _, merge := range r.merges {
+		if err := r.f.MergeCell(r.sheet, merge[0], merge[1]); err != nil {
+			return err
+		}
+	}
+	r.ws = nil
+	return nil
+}
+
+// readColumn set the width, visibility and default cell style of the columns
+// by given table:table-column element.
+func (r *odsReader) readColumn(se xml.StartElement) error {
+	repeat := odsAttrInt(se, odsNameSpaceTab, "number-columns-repeated")
+	if r.col+repeat > TotalColumns {
+		repeat = TotalColumns - r.col
+	}
+	if repeat <= 0 {
+		return nil
+	}
+	start, _ := ColumnNumberToName(r.col + 1)
+	end, _ := ColumnNumberToName(r.col + repeat)
+	if styleName := odsAttr(se, odsNameSpaceTab, "default-cell-style-name"); styleName != "" {
+		for col := r.col; col < r.col+repeat; col++ {
+			r.colStyles[col] = styleName
+		}
+	}
+	r.col += repeat
+	if style, ok := r.styles[odsAttr(se, odsNameSpaceTab, "style-name")]; ok {
+		if width := odsLength(style.props["column-width"]); width > 0 {
+			width = math.Round((width/0.75-5)/7*100) / 100
+			if err := r.f.SetColWidth(r.sheet, start, end, width); err != nil {
+				return err
+			}
+		}
+	}
+	if odsAttr(se, odsNameSpaceTab, "visibility") == "collapse" {
+		return r.f.SetColVisible(r.sheet, start+":"+end, false)
+	}
+	return nil
+}
+
+// readRow read the cells by given table:table-row element, the repeated rows
+// will be materialized only if it contains any cell value.
+func (r *odsReader) readRow(d *xml.Decoder, se xml.StartElement) error {
+	var cells []odsCell
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		if t, ok := tok.(xml.StartElement); ok {
+			if t.Name.Space == odsNameSpaceTab && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell") {
+				cell, err := r.readCell(d, t)
+				if err != nil {
+					return err
+				}
+				cells = append(cells, cell)
+				continue
+			}
+			if err = d.Skip(); err != nil {
+				return err
+			}
+		}
+		if _, ok := tok.(xml.EndElement); ok {
+			break
+		}
+	}
+	var content bool
+	for _, cell := range cells {
+		content = content || cell.hasContent()
+	}
+	repeat := odsAttrInt(se, odsNameSpaceTab, "number-rows-repeated")
+	if r.row+repeat > TotalRows {
+		repeat = TotalRows - r.row
+	}
+	if !content && repeat > 1 {
+		r.row += repeat
+		return nil
+	}
+	var height float64
+	if style, ok := r.styles[odsAttr(se, odsNameSpaceTab, "style-name")]; ok && style.props["use-optimal-row-height"] != "true" {
+		height = odsLength(style.props["row-height"])
+	}
+	defaultStyle := odsAttr(se, odsNameSpaceTab, "default-cell-style-name")
+	for i := 0; i < repeat; i++ {
+		if err := r.setRow(cells, defaultStyle); err != nil {
+			return err
+		}
+		if height > 0 {
+			if err := r.f.SetRowHeight(r.sheet, r.row+1, height); err != nil {
+				return err
+			}
+		}
+		if odsAttr(se, odsNameSpaceTab, "visibility") == "collapse" {
+			if err := r.f.SetRowVisible(r.sheet, r.row+1, false); err != nil {
+				return err
+			}
+		}
+		r.row++
+	}
+	return nil
+}
+
+// hasContent returns if the cell contains value, formula or direct style.
+func (c odsCell) hasContent() bool {
+	return !c.covered && (c.valueType != "" || c.formula != "" || c.text != "" || (c.style != "" && c.repeat == 1))
+}
+
+// setRow set the cells of the current row.
+func (r *odsReader) setRow(cells []odsCell, defaultStyle string) error {
+	var col int
+	for _, cell := range cells {
+		for i := 0; i < cell.repeat && col < TotalColumns; i++ {
+			if cell.colSpan > 1 || cell.rowSpan > 1 {
+				start, _ := CoordinatesToCellName(col+1, r.row+1)
+				end, _ := CoordinatesToCellName(col+cell.colSpan, r.row+cell.rowSpan)
+				r.merges = append(r.merges, [2]string{start, end})
+			}
+			if cell.hasContent() {
+				styleName := cell.style
+				if styleName == "" {
+					if styleName = defaultStyle; styleName == "" {
+						styleName = r.colStyles[col]
+					}
+				}
+				if err := r.setCell(col, cell, styleName); err != nil {
+					return err
+				}
+			}
+			col++
+		}
+	}
+	return nil
+}
+
+// setCell set the value, formula and style of the cell by given zero-based
+// column number of the current row.
+func (r *odsReader) setCell(col int, cell odsCell, styleName string) error {
+	var t, v string
+	var numFmt int
+	switch cell.valueType {
+	case "float", "percentage", "currency":
+		if _, err := strconv.ParseFloat(cell.value, 64); err != nil {
+			t, v = r.f.setCellString(cell.text)
+			break
+		}
+		v = cell.value
+		if cell.valueType == "percentage" {
+			numFmt = 10
+		}
+	case "date":
+		date, err := time.Parse("2006-01-02T15:04:05.999999999", cell.value)
+		if numFmt = 22; err != nil {
+			date, err = time.Parse("2006-01-02", cell.value)
+			numFmt = 14
+		}
+		if err != nil {
+			t, v = r.f.setCellString(cell.text)
+			break
+		}
+		serial, _ := timeToExcelTime(date)
+		if r.date1904 {
+			serial -= 1462
+		}
+		v = strconv.FormatFloat(serial, 'f', -1, 64)
+	case "time":
+		v, numFmt = strconv.FormatFloat(odsDuration(cell.value), 'f', -1, 64), 21
+	case "boolean":
+		t, v = "b", "0"
+		if cell.value == "true" {
+			v = "1"
+		}
+	default:
+		if cell.formula != "" {
+			t, v = "str", cell.text
+		} else if cell.valueType != "" || cell.text != "" {
+			t, v = r.f.setCellString(cell.text)
+		}
+	}
+	styleID, err := r.style(styleName, numFmt)
+	if err != nil {
+		return err
+	}
+	if t == "" && v == "" && cell.formula == "" && styleID == 0 {
+		return nil
+	}
+	prepareSheetXML(r.ws, col+1, r.row+1)
+	c := &r.ws.SheetData.Row[r.row].C[col]
+	c.S, c.T, c.V = styleID, t, v
+	if cell.formula != "" {
+		c.F = &xlsxF{Content: odsFormulaToExcel(cell.formula)}
+	}
+	return nil
+}
+
+// odsDuration convert the duration such as PT12H30M15S to the fraction of
+// the day.
+func odsDuration(duration string) float64 {
+	matches := odsDurationRegexp.FindStringSubmatch(duration)
+	if matches == nil {
+		return 0
+	}
+	var days float64
+	for i, unit := range []float64{1, 24, 1440, 86400} {
+		val, _ := strconv.ParseFloat(matches[i+1], 64)
+		days += val / unit
+	}
+	if strings.HasPrefix(duration, "-") {
+		return -days
+	}
+	return days
+}
+
+// readCell read the table:table-cell or table:covered-table-cell element,
+// the paragraphs of the cell will be joined by line break.
+func (r *odsReader) readCell(d *xml.Decoder, se xml.StartElement) (odsCell, error) {
+	cell := odsCell{
+		covered:   se.Name.Local == "covered-table-cell",
+		style:     odsAttr(se, odsNameSpaceTab, "style-name"),
+		valueType: odsAttr(se, odsNameSpaceOff, "value-type"),
+		formula:   odsAttr(se, odsNameSpaceTab, "formula"),
+		repeat:    odsAttrInt(se, odsNameSpaceTab, "number-columns-repeated"),
+		colSpan:   odsAttrInt(se, odsNameSpaceTab, "number-columns-spanned"),
+		rowSpan:   odsAttrInt(se, odsNameSpaceTab, "number-rows-spanned"),
+	}
+	switch cell.valueType {
+	case "date":
+		cell.value = odsAttr(se, odsNameSpaceOff, "date-value")
+	case "time":
+		cell.value = odsAttr(se, odsNameSpaceOff, "time-value")
+	case "boolean":
+		cell.value = odsAttr(se, odsNameSpaceOff, "boolean-value")
+	default:
+		cell.value = odsAttr(se, odsNameSpaceOff, "value")
+	}
+	var paragraphs []string
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return cell, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space == odsNameSpaceText && (t.Name.Local == "p" || t.Name.Local == "h") {
+				paragraph, err := readODSParagraph(d)
+				if err != nil {
+					return cell, err
+				}
+				paragraphs = append(paragraphs, paragraph)
+				continue
+			}
+			if err = d.Skip(); err != nil {
+				return cell, err
+			}
+		case xml.EndElement:
+			cell.text = strings.Join(paragraphs, "\n")
+			if stringValue := odsAttr(se, odsNameSpaceOff, "string-value"); stringValue != "" {
+				cell.text = stringValue
+			}
+			return cell, nil
+		}
+	}
+}
+
+// readODSParagraph read the text of the text:p element, the spaces, tabs and
+// line breaks elements will be converted to the characters.
+func readODSParagraph(d *xml.Decoder) (string, error) {
+	var (
+		text  strings.Builder
+		depth int
+	)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return text.String(), err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == odsNameSpaceText && t.Name.Local == "s":
+				text.WriteString(strings.Repeat(" ", odsAttrInt(t, odsNameSpaceText, "c")))
+			case t.Name.Space == odsNameSpaceText && t.Name.Local == "tab":
+				text.WriteString("\t")
+			case t.Name.Space == odsNameSpaceText && t.Name.Local == "line-break":
+				text.WriteString("\n")
+			case t.Name.Space == odsNameSpaceOff && t.Name.Local == "annotation",
+				t.Name.Space == odsNameSpaceText && t.Name.Local == "note":
+			default:
+				depth++
+				continue
+			}
+			if err = d.Skip(); err != nil {
+				return text.String(), err
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				return text.String(), nil
+			}
+			depth--
+		}
+	}
+}
+
+// odsFormulaToExcel translate the OpenFormula expression to the formula of
+// the spreadsheet, for example, "of:=SUM([.A1:.B2];[$'Sheet 2'.C3])" will be
+// translated to "SUM(A1:B2,'Sheet 2'!C3)".
+func odsFormulaToExcel(formula string) string {
+	if idx := strings.Index(formula, ":="); idx != -1 && idx < 6 {
+		formula = formula[idx+1:]
+	}
+	formula = strings.TrimPrefix(formula, "=")
+	var (
+		out    strings.Builder
+		braces int
+		runes  = []rune(formula)
+	)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '"':
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == '"' {
+					if j+1 < len(runes) && runes[j+1] == '"' {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j >= len(runes) {
+				j = len(runes) - 1
+			}
+			out.WriteString(string(runes[i : j+1]))
+			i = j
+		case '[':
+			j, quoted := i+1, false
+			for ; j < len(runes) && (quoted || runes[j] != ']'); j++ {
+				if runes[j] == '\'' {
+					quoted = !quoted
+				}
+			}
+			out.WriteString(odsRefToExcel(string(runes[i+1 : int(math.Min(float64(j), float64(len(runes))))])))
+			i = j
+		case '#':
+			literal := string(c)
+			for _, errorValue := range odsErrorValues {
+				if strings.HasPrefix(string(runes[i:]), errorValue) {
+					literal = errorValue
+					break
+				}
+			}
+			out.WriteString(literal)
+			i += len([]rune(literal)) - 1
+		case '{':
+			braces++
+			out.WriteRune(c)
+		case '}':
+			braces--
+			out.WriteRune(c)
+		case ';', '~':
+			out.WriteRune(',')
+		case '|':
+			if braces > 0 {
+				out.WriteRune(';')
+				continue
+			}
+			out.WriteRune(c)
+		case '!':
+			out.WriteRune(' ')
+		default:
+			out.WriteRune(c)
+		}
+	}
+	return out.String()
+}
+
+// odsRefToExcel translate the OpenFormula reference without brackets such as
+// "$Sheet1.A1:.B2" to the reference "Sheet1!A1:B2".
+func odsRefToExcel(ref string) string {
+	var (
+		parts  []string
+		quoted bool
+		last   int
+	)
+	for i, c := range ref {
+		if c == '\'' {
+			quoted = !quoted
+		}
+		if c == ':' && !quoted {
+			parts, last = append(parts, ref[last:i]), i+1
+		}
+	}
+	parts = append(parts, ref[last:])
+	var sheet string
+	for i, part := range parts {
+		part = strings.TrimPrefix(part, "$")
+		idx, quoted := -1, false
+		for j, c := range part {
+			if c == '\'' {
+				quoted = !quoted
+			}
+			if c == '.' && !quoted {
+				idx = j
+			}
+		}
+		if idx != -1 {
+			if i == 0 {
+				sheet = part[:idx]
+			}
+			part = part[idx+1:]
+		}
+		parts[i] = part
+	}
+	if sheet != "" {
+		return sheet + "!" + strings.Join(parts, ":")
+	}
+	return strings.Join(parts, ":")
+}
+
+// excelFormulaToODS translate the formula of the spreadsheet to the
+// OpenFormula expression, for example, "SUM(A1:B2,'Sheet 2'!C3)" will be
+// translated to "of:=SUM([.A1:.B2];['Sheet 2'.C3])".
+func excelFormulaToODS(formula string) string {
+	ps := ExcelParser()
+	var (
+		out   strings.Builder
+		stack []string
+	)
+	for _, token := range ps.Parse(formula) {
+		switch token.TType {
+		case TokenTypeFunction:
+			if token.TSubType == TokenSubTypeStart {
+				stack = append(stack, token.TValue)
+				switch token.TValue {
+				case "ARRAY":
+					out.WriteString("{")
+				case "ARRAYROW":
+				default:
+					out.WriteString(token.TValue + "(")
+				}
+				continue
+			}
+			var fn string
+			if len(stack) > 0 {
+				fn, stack = stack[len(stack)-1], stack[:len(stack)-1]
+			}
+			switch fn {
+			case "ARRAY":
+				out.WriteString("}")
+			case "ARRAYROW":
+			default:
+				out.WriteString(")")
+			}
+		case TokenTypeArgument:
+			if len(stack) > 0 && stack[len(stack)-1] == "ARRAY" {
+				out.WriteString("|")
+				continue
+			}
+			out.WriteString(";")
+		case TokenTypeSubexpression:
+			if token.TSubType == TokenSubTypeStart {
+				out.WriteString("(")
+				continue
+			}
+			out.WriteString(")")
+		case TokenTypeOperand:
+			switch token.TSubType {
+			case TokenSubTypeText:
+				out.WriteString("\"" + strings.ReplaceAll(token.TValue, "\"", "\"\"") + "\"")
+			case TokenSubTypeRange:
+				out.WriteString(excelRefToODS(token.TValue))
+			default:
+				out.WriteString(token.TValue)
+			}
+		case TokenTypeOperatorInfix:
+			switch token.TSubType {
+			case TokenSubTypeUnion:
+				out.WriteString("~")
+			case TokenSubTypeIntersection:
+				out.WriteString("!")
+			default:
+				out.WriteString(token.TValue)
+			}
+		default:
+			out.WriteString(token.TValue)
+		}
+	}
+	return "of:=" + out.String()
+}
+
+// excelRefToODS translate the reference such as "Sheet1!A1:B2" to the
+// OpenFormula reference "[Sheet1.A1:.B2]", the defined names will be kept.
+func excelRefToODS(ref string) string {
+	var sheet string
+	cells := ref
+	if idx := strings.LastIndex(ref, "!"); idx != -1 {
+		sheet, cells = ref[:idx], ref[idx+1:]
+	}
+	parts := strings.Split(cells, ":")
+	if len(parts) > 2 || (len(parts) == 1 && !odsCellRefPattern.MatchString(parts[0])) {
+		return ref
+	}
+	for _, part := range parts {
+		if !odsCellRefPattern.MatchString(part) && !odsColRefPattern.MatchString(part) && !odsRowRefPattern.MatchString(part) {
+			return ref
+		}
+	}
+	if sheet != "" && !strings.HasPrefix(sheet, "'") && !odsSheetNamePattern.MatchString(sheet) {
+		sheet = "'" + strings.ReplaceAll(sheet, "'", "''") + "'"
+	}
+	return "[" + sheet + "." + strings.Join(parts, ":.") + "]"
+}
+
+// odsWriter provides the state of converting the spreadsheet to the
+// OpenDocument spreadsheet.
+type odsWriter struct {
+	f          *File
+	sst        *xlsxSST
+	date1904   bool
+	body       bytes.Buffer
+	cellStyles map[int]bool
+	colStyles  map[float64]string
+	rowStyles  map[float64]string
+}
+
+// odsEscape returns the escaped text of the element or attribute value.
+func odsEscape(text string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
+
+// writeODSToZip provides a function to write the spreadsheet as the
+// OpenDocument spreadsheet package to the zip.Writer.
+func (f *File) writeODSToZip(zw *zip.Writer) error {
+	w := &odsWriter{
+		f:          f,
+		sst:        f.sharedStringsReader(),
+		cellStyles: make(map[int]bool),
+		colStyles:  make(map[float64]string),
+		rowStyles:  make(map[float64]string),
+	}
+	if wb := f.workbookReader(); wb.WorkbookPr != nil {
+		w.date1904 = wb.WorkbookPr.Date1904
+	}
+	if err := w.writeTables(); err != nil {
+		return err
+	}
+	fi, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err = fi.Write([]byte(odsMediaType)); err != nil {
+		return err
+	}
+	meta, err := w.meta()
+	if err != nil {
+		return err
+	}
+	for _, part := range []struct {
+		name    string
+		content []byte
+	}{
+		{"META-INF/manifest.xml", w.manifest()},
+		{"content.xml", w.content()},
+		{"meta.xml", meta},
+		{"styles.xml", w.styles()},
+	} {
+		if fi, err = zw.Create(part.name); err != nil {
+			return err
+		}
+		if _, err = fi.Write(part.content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manifest returns the content of the META-INF/manifest.xml.
+func (w *odsWriter) manifest() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header + `<manifest:manifest xmlns:manifest="` + odsNameSpaceMani + `" manifest:version="1.2">`)
+	buf.WriteString(`<manifest:file-entry manifest:full-path="/" manifest:version="1.2" manifest:media-type="` + odsMediaType + `"/>`)
+	for _, part := range []string{"content.xml", "meta.xml", "styles.xml"} {
+		buf.WriteString(`<manifest:file-entry manifest:full-path="` + part + `" manifest:media-type="text/xml"/>`)
+	}
+	buf.WriteString(`</manifest:manifest>`)
+	return buf.Bytes()
+}
+
+// meta returns the content of the meta.xml by the document properties.
+func (w *odsWriter) meta() ([]byte, error) {
+	props, err := w.f.GetDocProps()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header + `<office:document-meta` + odsNameSpaces + `><office:meta><meta:generator>xlsx</meta:generator>`)
+	for _, element := range []struct{ name, value string }{
+		{"dc:title", props.Title},
+		{"dc:subject", props.Subject},
+		{"dc:description", props.Description},
+		{"meta:keyword", props.Keywords},
+		{"meta:initial-creator", props.Creator},
+		{"dc:creator", props.LastModifiedBy},
+		{"meta:creation-date", props.Created},
+		{"dc:date", props.Modified},
+		{"dc:language", props.Language},
+		{"meta:editing-cycles", props.Revision},
+	} {
+		if element.value != "" {
+			buf.WriteString("<" + element.name + ">" + odsEscape(element.value) + "</" + element.name + ">")
+		}
+	}
+	buf.WriteString(`</office:meta></office:document-meta>`)
+	return buf.Bytes(), nil
+}
+
+// styles returns the content of the styles.xml, which contains the default
+// cell style and the default master page.
+func (w *odsWriter) styles() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header + `<office:document-styles` + odsNameSpaces + `><office:styles>`)
+	buf.WriteString(`<style:default-style style:family="table-cell"><style:text-properties`)
+	if font := w.f.readDefaultFont(); font != nil {
+		if font.Name != nil && font.Name.Val != nil {
+			buf.WriteString(` fo:font-family="` + odsEscape(*font.Name.Val) + `"`)
+		}
+		if font.Sz != nil && font.Sz.Val != nil {
+			buf.WriteString(` fo:font-size="` + strconv.FormatFloat(*font.Sz.Val, 'f', -1, 64) + `pt"`)
+		}
+	}
+	buf.WriteString(`/></style:default-style><style:style style:name="Default" style:family="table-cell"/></office:styles>`)
+	buf.WriteString(`<office:automatic-styles><style:page-layout style:name="pm1"/></office:automatic-styles>`)
+	buf.WriteString(`<office:master-styles><style:master-page style:name="Default" style:page-layout-name="pm1"/></office:master-styles>`)
+	buf.WriteString(`</office:document-styles>`)
+	return buf.Bytes()
+}
+
+// content returns the content of the content.xml, the automatic styles will
+// be generated by the styles which used in the tables.
+func (w *odsWriter) content() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header + `<office:document-content` + odsNameSpaces + `><office:automatic-styles>`)
+	for _, styles := range []struct {
+		family, prop, attr string
+		names              map[float64]string
+	}{
+		{"table-column", "table-column-properties", "style:column-width", w.colStyles},
+		{"table-row", "table-row-properties", "style:row-height", w.rowStyles},
+	} {
+		var sizes []float64
+		for size := range styles.names {
+			sizes = append(sizes, size)
+		}
+		sort.Float64s(sizes)
+		for _, size := range sizes {
+			fmt.Fprintf(&buf, `<style:style style:name="%s" style:family="%s"><style:%s %s="%spt"/></style:style>`,
+				styles.names[size], styles.family, styles.prop, styles.attr, strconv.FormatFloat(size, 'f', -1, 64))
+		}
+	}
+	buf.WriteString(`<style:style style:name="ta1" style:family="table" style:master-page-name="Default"><style:table-properties table:display="true"/></style:style>`)
+	buf.WriteString(`<style:style style:name="ta2" style:family="table" style:master-page-name="Default"><style:table-properties table:display="false"/></style:style>`)
+	var styleIDs []int
+	for styleID := range w.cellStyles {
+		styleIDs = append(styleIDs, styleID)
+	}
+	sort.Ints(styleIDs)
+	for _, styleID := range styleIDs {
+		w.writeCellStyle(&buf, styleID)
+	}
+	buf.WriteString(`</office:automatic-styles><office:body><office:spreadsheet>`)
+	if w.date1904 {
+		buf.WriteString(`<table:calculation-settings><table:null-date table:date-value="1904-01-01"/></table:calculation-settings>`)
+	}
+	buf.Write(w.body.Bytes())
+	buf.WriteString(`</office:spreadsheet></office:body></office:document-content>`)
+	return buf.Bytes()
+}
+
+// numFmtCode returns the number format code by given cell style index.
+func (w *odsWriter) numFmtCode(styleID int) string {
+	styleSheet := w.f.stylesReader()
+	if styleSheet.CellXfs == nil || styleID <= 0 || styleID >= len(styleSheet.CellXfs.Xf) ||
+		styleSheet.CellXfs.Xf[styleID].NumFmtID == nil {
+		return ""
+	}
+	numFmtID := *styleSheet.CellXfs.Xf[styleID].NumFmtID
+	if code, ok := builtInNumFmt[numFmtID]; ok {
+		return code
+	}
+	if styleSheet.NumFmts != nil {
+		for _, numFmt := range styleSheet.NumFmts.NumFmt {
+			if numFmt.NumFmtID == numFmtID {
+				return numFmt.FormatCode
+			}
+		}
+	}
+	return ""
+}
+
+// writeCellStyle write the automatic cell style and the data style by given
+// cell style index.
+func (w *odsWriter) writeCellStyle(buf *bytes.Buffer, styleID int) {
+	styleSheet := w.f.stylesReader()
+	xf := styleSheet.CellXfs.Xf[styleID]
+	name := "ce" + strconv.Itoa(styleID)
+	var dataStyle string
+	if code := w.numFmtCode(styleID); code != "" {
+		if content := odsDataStyle("N"+strconv.Itoa(styleID), code); content != "" {
+			buf.WriteString(content)
+			dataStyle = ` style:data-style-name="N` + strconv.Itoa(styleID) + `"`
+		}
+	}
+	buf.WriteString(`<style:style style:name="` + name + `" style:family="table-cell" style:parent-style-name="Default"` + dataStyle + `>`)
+	var cellProps, paragraphProps, textProps strings.Builder
+	if xf.FillID != nil && styleSheet.Fills != nil && *xf.FillID < len(styleSheet.Fills.Fill) {
+		if fill := styleSheet.Fills.Fill[*xf.FillID]; fill.PatternFill != nil && fill.PatternFill.PatternType != "" &&
+			fill.PatternFill.PatternType != "none" && fill.PatternFill.FgColor != nil && len(fill.PatternFill.FgColor.RGB) >= 6 {
+			cellProps.WriteString(` fo:background-color="#` + fill.PatternFill.FgColor.RGB[len(fill.PatternFill.FgColor.RGB)-6:] + `"`)
+		}
+	}
+	if xf.BorderID != nil && styleSheet.Borders != nil && *xf.BorderID < len(styleSheet.Borders.Border) {
+		border := styleSheet.Borders.Border[*xf.BorderID]
+		for _, side := range []struct {
+			attr string
+			line xlsxLine
+			ok   bool
+		}{
+			{"fo:border-left", border.Left, true},
+			{"fo:border-right", border.Right, true},
+			{"fo:border-top", border.Top, true},
+			{"fo:border-bottom", border.Bottom, true},
+			{"style:diagonal-bl-tr", border.Diagonal, border.DiagonalUp},
+			{"style:diagonal-tl-br", border.Diagonal, border.DiagonalDown},
+		} {
+			if line, ok := odsBorderLines[side.line.Style]; ok && side.ok {
+				color := "#000000"
+				if side.line.Color != nil && len(side.line.Color.RGB) >= 6 {
+					color = "#" + side.line.Color.RGB[len(side.line.Color.RGB)-6:]
+				}
+				cellProps.WriteString(` ` + side.attr + `="` + line + ` ` + color + `"`)
+			}
+		}
+	}
+	if alignment := xf.Alignment; alignment != nil {
+		if align, ok := map[string]string{"left": "start", "center": "center", "right": "end", "justify": "justify"}[alignment.Horizontal]; ok {
+			cellProps.WriteString(` style:text-align-source="fix"`)
+			paragraphProps.WriteString(` fo:text-align="` + align + `"`)
+		}
+		if align, ok := map[string]string{"top": "top", "center": "middle", "bottom": "bottom"}[alignment.Vertical]; ok {
+			cellProps.WriteString(` style:vertical-align="` + align + `"`)
+		}
+		if alignment.WrapText {
+			cellProps.WriteString(` fo:wrap-option="wrap"`)
+		}
+		if alignment.ShrinkToFit {
+			cellProps.WriteString(` style:shrink-to-fit="true"`)
+		}
+		if rotation := alignment.TextRotation; rotation > 0 && rotation <= 180 {
+			if rotation > 90 {
+				rotation = 450 - rotation
+			}
+			cellProps.WriteString(` style:rotation-angle="` + strconv.Itoa(rotation) + `"`)
+		}
+	}
+	if xf.FontID != nil && *xf.FontID != 0 && styleSheet.Fonts != nil && *xf.FontID < len(styleSheet.Fonts.Font) {
+		font := styleSheet.Fonts.Font[*xf.FontID]
+		if font.Name != nil && font.Name.Val != nil {
+			textProps.WriteString(` fo:font-family="` + odsEscape(*font.Name.Val) + `"`)
+		}
+		if font.Sz != nil && font.Sz.Val != nil {
+			textProps.WriteString(` fo:font-size="` + strconv.FormatFloat(*font.Sz.Val, 'f', -1, 64) + `pt"`)
+		}
+		if font.Color != nil && len(font.Color.RGB) >= 6 {
+			textProps.WriteString(` fo:color="#` + font.Color.RGB[len(font.Color.RGB)-6:] + `"`)
+		}
+		if font.B != nil && (font.B.Val == nil || *font.B.Val) {
+			textProps.WriteString(` fo:font-weight="bold"`)
+		}
+		if font.I != nil && (font.I.Val == nil || *font.I.Val) {
+			textProps.WriteString(` fo:font-style="italic"`)
+		}
+		if font.Strike != nil && (font.Strike.Val == nil || *font.Strike.Val) {
+			textProps.WriteString(` style:text-line-through-style="solid"`)
+		}
+		if font.U != nil {
+			textProps.WriteString(` style:text-underline-style="solid" style:text-underline-width="auto" style:text-underline-color="font-color"`)
+			if font.U.Val != nil && *font.U.Val == "double" {
+				textProps.WriteString(` style:text-underline-type="double"`)
+			}
+		}
+	}
+	for _, props := range []struct {
+		element string
+		attrs   string
+	}{
+		{"table-cell-properties", cellProps.String()},
+		{"paragraph-properties", paragraphProps.String()},
+		{"text-properties", textProps.String()},
+	} {
+		if props.attrs != "" {
+			buf.WriteString(`<style:` + props.element + props.attrs + `/>`)
+		}
+	}
+	buf.WriteString(`</style:style>`)
+}
+
+// odsDataStyle returns the data style by given name and number format code,
+// only the first section of the number format code will be converted. It
+// will return empty string if the number format code doesn't need to convert.
+func odsDataStyle(name, code string) string {
+	code = strings.Split(code, ";")[0]
+	lower := strings.ToLower(code)
+	if lower == "general" || lower == "@" || lower == "" {
+		return ""
+	}
+	if odsIsDateFmt(code) {
+		return odsDateStyle(name, code)
+	}
+	exponent := strings.Index(lower, "e+")
+	mantissa := lower
+	if exponent != -1 {
+		mantissa = lower[:exponent]
+	}
+	start, end := strings.IndexAny(mantissa, "0#?"), strings.LastIndexAny(mantissa, "0#?")
+	if start == -1 {
+		return ""
+	}
+	numeric := strings.Split(mantissa[start:end+1], " ")[0]
+	var decimals, digits int
+	if idx := strings.Index(numeric, "."); idx != -1 {
+		decimals = strings.Count(numeric[idx:], "0") + strings.Count(numeric[idx:], "#")
+		numeric = numeric[:idx]
+	}
+	digits = strings.Count(numeric, "0")
+	number := fmt.Sprintf(` number:decimal-places="%d" number:min-integer-digits="%d"`, decimals, digits)
+	if strings.Contains(numeric, ",") {
+		number += ` number:grouping="true"`
+	}
+	element, family := "number:number", "number:number-style"
+	if idx := strings.Index(mantissa, "/"); idx != -1 {
+		digits := strings.Count(mantissa[idx:], "?") + strings.Count(mantissa[idx:], "0")
+		number = fmt.Sprintf(` number:min-integer-digits="0" number:min-numerator-digits="%d" number:min-denominator-digits="%d"`, digits, digits)
+		element = "number:fraction"
+	} else if exponent != -1 {
+		element = "number:scientific-number"
+		number += fmt.Sprintf(` number:min-exponent-digits="%d"`, strings.Count(lower[exponent:], "0"))
+	} else if strings.Contains(lower, "%") {
+		family = "number:percentage-style"
+	}
+	content := `<` + element + number + `/>`
+	if family == "number:percentage-style" {
+		content += `<number:text>%</number:text>`
+	}
+	return `<` + family + ` style:name="` + name + `">` + content + `</` + family + `>`
+}
+
+// odsStripLiterals returns the lower case number format code without the
+// quoted text, escaped characters and the colors in the brackets.
+func odsStripLiterals(code string) string {
+	var (
+		buf             strings.Builder
+		quoted, bracket bool
+		runes           = []rune(strings.ToLower(strings.Split(code, ";")[0]))
+	)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '[':
+			bracket = i+1 < len(runes) && !strings.ContainsRune("hms", runes[i+1])
+		case c == ']':
+			bracket = false
+		case bracket:
+		case c == '\\' || c == '_' || c == '*':
+			i++
+		default:
+			buf.WriteRune(c)
+		}
+	}
+	return strings.ReplaceAll(buf.String(), "general", "")
+}
+
+// odsIsDateFmt returns if the number format code is a date or time number
+// format.
+func odsIsDateFmt(code string) bool {
+	return code != "" && isTimeNumFmt(odsStripLiterals(code))
+}
+
+// odsDateStyle returns the date or time data style by given name and number
+// format code.
+func odsDateStyle(name, code string) string {
+	var (
+		content strings.Builder
+		text    strings.Builder
+		hasDate bool
+		hours   bool
+		runes   = []rune(code)
+	)
+	flush := func() {
+		if text.Len() > 0 {
+			content.WriteString(`<number:text>` + odsEscape(text.String()) + `</number:text>`)
+			text.Reset()
+		}
+	}
+	element := func(name string, long bool, attrs string) {
+		flush()
+		hours = name == "hours"
+		if long {
+			attrs += ` number:style="long"`
+		}
+		content.WriteString(`<number:` + name + attrs + `/>`)
+	}
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		lower := strings.ToLower(string(runes[i:]))
+		n := 1
+		for i+n < len(runes) && strings.EqualFold(string(runes[i+n]), string(c)) {
+			n++
+		}
+		switch {
+		case c == '"':
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '"'; j++ {
+				text.WriteRune(runes[j])
+			}
+			i = j
+			continue
+		case c == '\\' && i+1 < len(runes):
+			text.WriteRune(runes[i+1])
+			i++
+			continue
+		case c == '[':
+			j := i + 1
+			for ; j < len(runes) && runes[j] != ']'; j++ {
+			}
+			if bracket := strings.ToLower(string(runes[i:int(math.Min(float64(j+1), float64(len(runes))))])); strings.Trim(bracket, "[]hms") == "" {
+				element(map[byte]string{'h': "hours", 'm': "minutes", 's': "seconds"}[bracket[1]], len(bracket) > 3, ` number:truncate-on-overflow="false"`)
+			}
+			i = j
+			continue
+		case strings.HasPrefix(lower, "am/pm"):
+			element("am-pm", false, "")
+			i += 4
+			continue
+		case strings.HasPrefix(lower, "a/p"):
+			element("am-pm", false, "")
+			i += 2
+			continue
+		case c == 'y' || c == 'Y':
+			hasDate = true
+			element("year", n > 2, "")
+		case c == 'd' || c == 'D':
+			hasDate = true
+			switch {
+			case n > 3:
+				element("day-of-week", true, "")
+			case n == 3:
+				element("day-of-week", false, "")
+			default:
+				element("day", n > 1, "")
+			}
+		case c == 'h' || c == 'H':
+			element("hours", n > 1, "")
+		case c == 'm' || c == 'M':
+			next := strings.TrimLeft(strings.ToLower(string(runes[i+n:])), " :")
+			if n <= 2 && (hours || strings.HasPrefix(next, "s")) {
+				element("minutes", n > 1, "")
+				break
+			}
+			hasDate = true
+			switch {
+			case n > 3:
+				element("month", true, ` number:textual="true"`)
+			case n == 3:
+				element("month", false, ` number:textual="true"`)
+			default:
+				element("month", n > 1, "")
+			}
+		case c == 's' || c == 'S':
+			var decimals int
+			for j := i + n; j+1 < len(runes) && runes[j] == '.' && runes[j+1] == '0'; {
+				for j++; j < len(runes) && runes[j] == '0'; j++ {
+					decimals++
+				}
+				n = j - i
+			}
+			attrs := ""
+			if decimals > 0 {
+				attrs = fmt.Sprintf(` number:decimal-places="%d"`, decimals)
+			}
+			element("seconds", strings.HasPrefix(strings.ToLower(string(runes[i:])), "ss"), attrs)
+		default:
+			text.WriteRune(c)
+			continue
+		}
+		i += n - 1
+	}
+	flush()
+	family := "number:time-style"
+	if hasDate {
+		family = "number:date-style"
+	}
+	return `<` + family + ` style:name="` + name + `">` + content.String() + `</` + family + `>`
+}
+
+// writeTables write the worksheets as the tables of the spreadsheet.
+func (w *odsWriter) writeTables() error {
+	wb := w.f.workbookReader()
+	for _, sheet := range wb.Sheets.Sheet {
+		if _, ok := w.f.sheetMap[trimSheetName(sheet.Name)]; !ok {
+			continue
+		}
+		ws, err := w.f.workSheetReader(sheet.Name)
+		if err != nil {
+			continue
+		}
+		tableStyle := "ta1"
+		if sheet.State == "hidden" || sheet.State == "veryHidden" {
+			tableStyle = "ta2"
+		}
+		w.body.WriteString(`<table:table table:name="` + odsEscape(sheet.Name) + `" table:style-name="` + tableStyle + `">`)
+		if err = w.writeTable(sheet.Name, ws); err != nil {
+			return err
+		}
+		w.body.WriteString(`</table:table>`)
+	}
+	return nil
+}
+
+// writeTable write the columns and rows of the worksheet, the merged cells
+// will be written as the spanned and covered cells.
+func (w *odsWriter) writeTable(sheet string, ws *xlsxWorksheet) error {
+	spans, covered := make(map[[2]int][2]int), make(map[[2]int]bool)
+	var maxCol, maxRow int
+	if ws.MergeCells != nil {
+		for _, mergeCell := range ws.MergeCells.Cells {
+			if mergeCell == nil {
+				continue
+			}
+			rect, err := areaRefToCoordinates(mergeCell.Ref)
+			if err != nil {
+				return err
+			}
+			_ = sortCoordinates(rect)
+			spans[[2]int{rect[0], rect[1]}] = [2]int{rect[2] - rect[0] + 1, rect[3] - rect[1] + 1}
+			for col := rect[0]; col <= rect[2]; col++ {
+				for row := rect[1]; row <= rect[3]; row++ {
+					if col != rect[0] || row != rect[1] {
+						covered[[2]int{col, row}] = true
+					}
+				}
+			}
+			maxCol, maxRow = int(math.Max(float64(maxCol), float64(rect[2]))), int(math.Max(float64(maxRow), float64(rect[3])))
+		}
+	}
+	rows := make(map[int]*xlsxRow)
+	for idx := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[idx]
+		rows[row.R] = row
+		maxRow = int(math.Max(float64(maxRow), float64(row.R)))
+		for _, c := range row.C {
+			if col, _, err := CellNameToCoordinates(c.R); err == nil && col > maxCol {
+				maxCol = col
+			}
+		}
+	}
+	w.writeColumns(ws, maxCol)
+	var emptyRows int
+	flushEmptyRows := func() {
+		if emptyRows > 0 {
+			w.body.WriteString(`<table:table-row`)
+			if emptyRows > 1 {
+				w.body.WriteString(` table:number-rows-repeated="` + strconv.Itoa(emptyRows) + `"`)
+			}
+			w.body.WriteString(`><table:table-cell/></table:table-row>`)
+			emptyRows = 0
+		}
+	}
+	for r := 1; r <= maxRow; r++ {
+		row, cells := rows[r], make(map[int]xlsxC)
+		lastCol := 0
+		if row != nil {
+			for _, c := range row.C {
+				if col, _, err := CellNameToCoordinates(c.R); err == nil {
+					cells[col] = c
+					lastCol = int(math.Max(float64(lastCol), float64(col)))
+				}
+			}
+		}
+		for cell := range covered {
+			if cell[1] == r && cell[0] > lastCol {
+				lastCol = cell[0]
+			}
+		}
+		for cell := range spans {
+			if cell[1] == r && cell[0] > lastCol {
+				lastCol = cell[0]
+			}
+		}
+		if row == nil && lastCol == 0 {
+			emptyRows++
+			continue
+		}
+		flushEmptyRows()
+		w.body.WriteString(`<table:table-row`)
+		if row != nil && row.CustomHeight && row.Ht > 0 {
+			w.body.WriteString(` table:style-name="` + w.rowStyle(row.Ht) + `"`)
+		}
+		if row != nil && row.Hidden {
+			w.body.WriteString(` table:visibility="collapse"`)
+		}
+		w.body.WriteString(`>`)
+		if lastCol == 0 {
+			w.body.WriteString(`<table:table-cell/>`)
+		}
+		var emptyCells int
+		flushEmptyCells := func() {
+			if emptyCells > 0 {
+				w.body.WriteString(`<table:table-cell`)
+				if emptyCells > 1 {
+					w.body.WriteString(` table:number-columns-repeated="` + strconv.Itoa(emptyCells) + `"`)
+				}
+				w.body.WriteString(`/>`)
+				emptyCells = 0
+			}
+		}
+		for col := 1; col <= lastCol; col++ {
+			c, ok := cells[col]
+			span, spanned := spans[[2]int{col, r}]
+			if covered[[2]int{col, r}] {
+				flushEmptyCells()
+				w.body.WriteString(`<table:covered-table-cell/>`)
+				continue
+			}
+			if !spanned && (!ok || (c.V == "" && c.F == nil && c.IS == nil && c.S == 0)) {
+				emptyCells++
+				continue
+			}
+			flushEmptyCells()
+			if err := w.writeCell(sheet, c, span); err != nil {
+				return err
+			}
+		}
+		w.body.WriteString(`</table:table-row>`)
+	}
+	flushEmptyRows()
+	if maxRow == 0 {
+		w.body.WriteString(`<table:table-row><table:table-cell/></table:table-row>`)
+	}
+	return nil
+}
+
+// writeColumns write the table:table-column elements of the worksheet by
+// given number of the columns, the adjacent columns with the same width and
+// visibility will be written as the repeated column.
+func (w *odsWriter) writeColumns(ws *xlsxWorksheet, maxCol int) {
+	width := defaultColWidth
+	if ws.SheetFormatPr != nil && ws.SheetFormatPr.DefaultColWidth > 0 {
+		width = ws.SheetFormatPr.DefaultColWidth
+	}
+	type column struct {
+		width  float64
+		hidden bool
+	}
+	var columns []column
+	if ws.Cols != nil {
+		for _, col := range ws.Cols.Col {
+			maxCol = int(math.Max(float64(maxCol), math.Min(float64(col.Max), TotalColumns)))
+		}
+	}
+	if maxCol == 0 {
+		maxCol = 1
+	}
+	for idx := 1; idx <= maxCol; idx++ {
+		col := column{width: width}
+		if ws.Cols != nil {
+			for _, c := range ws.Cols.Col {
+				if c.Min <= idx && idx <= c.Max {
+					if c.Width > 0 {
+						col.width = c.Width
+					}
+					col.hidden = c.Hidden
+				}
+			}
+		}
+		columns = append(columns, col)
+	}
+	for idx := 0; idx < len(columns); {
+		repeat := 1
+		for idx+repeat < len(columns) && columns[idx+repeat] == columns[idx] {
+			repeat++
+		}
+		w.body.WriteString(`<table:table-column table:style-name="` + w.colStyle(columns[idx].width) + `"`)
+		if repeat > 1 {
+			w.body.WriteString(` table:number-columns-repeated="` + strconv.Itoa(repeat) + `"`)
+		}
+		if columns[idx].hidden {
+			w.body.WriteString(` table:visibility="collapse"`)
+		}
+		w.body.WriteString(`/>`)
+		idx += repeat
+	}
+}
+
+// colStyle returns the column style name by given column width in
+// characters.
+func (w *odsWriter) colStyle(width float64) string {
+	width = math.Round((width*7+5)*0.75*100) / 100
+	name, ok := w.colStyles[width]
+	if !ok {
+		name = "co" + strconv.Itoa(len(w.colStyles)+1)
+		w.colStyles[width] = name
+	}
+	return name
+}
+
+// rowStyle returns the row style name by given row height in points.
+func (w *odsWriter) rowStyle(height float64) string {
+	name, ok := w.rowStyles[height]
+	if !ok {
+		name = "ro" + strconv.Itoa(len(w.rowStyles)+1)
+		w.rowStyles[height] = name
+	}
+	return name
+}
+
+// writeCell write the table:table-cell element by given cell and the spans of
+// the merged cell.
+func (w *odsWriter) writeCell(sheet string, c xlsxC, span [2]int) error {
+	w.body.WriteString(`<table:table-cell`)
+	if c.S != 0 {
+		w.cellStyles[c.S] = true
+		w.body.WriteString(` table:style-name="ce` + strconv.Itoa(c.S) + `"`)
+	}
+	if span[0] > 1 || span[1] > 1 {
+		fmt.Fprintf(&w.body, ` table:number-columns-spanned="%d" table:number-rows-spanned="%d"`, span[0], span[1])
+	}
+	if c.F != nil {
+		formula, err := w.f.GetCellFormula(sheet, c.R)
+		if err != nil {
+			return err
+		}
+		if formula != "" {
+			w.body.WriteString(` table:formula="` + odsEscape(excelFormulaToODS(formula)) + `"`)
+		}
+	}
+	raw, err := c.getValueFrom(w.f, w.sst, true)
+	if err != nil {
+		return err
+	}
+	text, err := c.getValueFrom(w.f, w.sst, false)
+	if err != nil {
+		return err
+	}
+	if raw == "" && c.T != "s" && c.T != "inlineStr" && c.T != "str" {
+		w.body.WriteString(`/>`)
+		return nil
+	}
+	switch c.T {
+	case "b":
+		value, display := "false", "FALSE"
+		if raw == "1" {
+			value, display = "true", "TRUE"
+		}
+		w.body.WriteString(` office:value-type="boolean" office:boolean-value="` + value + `"`)
+		text = display
+	case "s", "inlineStr", "str", "e":
+		w.body.WriteString(` office:value-type="string"`)
+		text = raw
+	default:
+		num, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			w.body.WriteString(` office:value-type="string"`)
+			text = raw
+			break
+		}
+		code := strings.ToLower(w.numFmtCode(c.S))
+		switch {
+		case odsIsDateFmt(code):
+			date := timeFromExcelTime(num, w.date1904)
+			if strings.ContainsAny(odsStripLiterals(code), "yd") || num >= 1 {
+				w.body.WriteString(` office:value-type="date" office:date-value="` + date.Format("2006-01-02T15:04:05") + `"`)
+				break
+			}
+			seconds := int(math.Round(num * 86400))
+			fmt.Fprintf(&w.body, ` office:value-type="time" office:time-value="PT%02dH%02dM%02dS"`, seconds/3600, seconds/60%60, seconds%60)
+		case strings.Contains(code, "%"):
+			w.body.WriteString(` office:value-type="percentage" office:value="` + raw + `"`)
+		default:
+			w.body.WriteString(` office:value-type="float" office:value="` + raw + `"`)
+		}
+	}
+	w.body.WriteString(`>`)
+	for _, paragraph := range strings.Split(text, "\n") {
+		w.body.WriteString(`<text:p>` + odsParagraph(paragraph) + `</text:p>`)
+	}
+	w.body.WriteString(`</table:table-cell>`)
+	return nil
+}
+
+// odsParagraph returns the escaped paragraph content, the spaces which
+// should be preserved and tabs will be written as the elements.
+func odsParagraph(text string) string {
+	var buf strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case ' ':
+			n := 1
+			for i+n < len(runes) && runes[i+n] == ' ' {
+				n++
+			}
+			if n == 1 && i > 0 && i+1 < len(runes) {
+				buf.WriteString(" ")
+			} else if n == 1 {
+				buf.WriteString(`<text:s/>`)
+			} else {
+				buf.WriteString(`<text:s text:c="` + strconv.Itoa(n) + `"/>`)
+			}
+			i += n - 1
+		case '\t':
+			buf.WriteString(`<text:tab/>`)
+		default:
+			buf.WriteString(odsEscape(string(runes[i])))
+		}
+	}
+	return buf.String()
+}
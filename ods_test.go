@@ -0,0 +1,226 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveAsODS(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Hello"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", 3.14))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C1", true))
+	assert.NoError(t, f.SetCellStr("Sheet1", "D1", " 中文  text\nline"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "SUM(B1,Sheet2!A2:A3)*2"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "F1", time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)))
+	assert.NoError(t, f.MergeCell("Sheet1", "A2", "C3"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", "Merged"))
+	assert.NoError(t, f.SetColWidth("Sheet1", "B", "C", 20))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 5, 30))
+	style, err := f.NewStyle(&Style{
+		Font:         &Font{Bold: true, Italic: true, Underline: "double", Color: "#FF0000", Family: "Arial", Size: 14},
+		Fill:         Fill{Type: "pattern", Pattern: 1, Color: []string{"#E0EBF5"}},
+		Border:       []Border{{Type: "left", Color: "#0000FF", Style: 2}, {Type: "bottom", Color: "#000000", Style: 1}},
+		Alignment:    &Alignment{Horizontal: "center", Vertical: "top", WrapText: true},
+		CustomNumFmt: stringPtr("0.000"),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", style))
+	percent, err := f.NewStyle(&Style{NumFmt: 10})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "G1", 0.25))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "G1", "G1", percent))
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetSheetRow("Sheet2", "A1", &[]interface{}{"World", 1, 2}))
+	assert.NoError(t, f.SetCellValue("Sheet2", "A2", 10))
+	assert.NoError(t, f.SetCellValue("Sheet2", "A3", 20))
+	assert.NoError(t, f.SetSheetVisible("Sheet2", false))
+	assert.NoError(t, f.SetDocProps(&DocProperties{Title: "Title", Creator: "Creator"}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveAsODS.ods")))
+
+	// Test the parts of the OpenDocument spreadsheet package.
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	assert.Equal(t, "mimetype", zr.File[0].Name)
+	assert.Equal(t, zip.Store, zr.File[0].Method)
+	var parts []string
+	for _, file := range zr.File {
+		parts = append(parts, file.Name)
+	}
+	assert.Equal(t, []string{"mimetype", "META-INF/manifest.xml", "content.xml", "meta.xml", "styles.xml"}, parts)
+
+	// Test open the OpenDocument spreadsheet.
+	f, err = OpenFile(filepath.Join("test", "TestSaveAsODS.ods"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet2"}, f.GetSheetList())
+	assert.False(t, f.GetSheetVisible("Sheet2"))
+	for cell, expected := range map[string]string{"A1": "Hello", "B1": "3.14", "C1": "1", "D1": " 中文  text\nline", "A2": "Merged", "F1": "6/15/21 00:00", "G1": "25.00%"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	cellType, err := f.GetCellType("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, CellTypeBool, cellType)
+	formula, err := f.GetCellFormula("Sheet1", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(B1,Sheet2!A2:A3)*2", formula)
+	rows, err := f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"World", "1", "2"}, {"10"}, {"20"}}, rows)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "A2:C3", mergeCells[0].GetStartAxis()+":"+mergeCells[0].GetEndAxis())
+	width, err := f.GetColWidth("Sheet1", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	height, err := f.GetRowHeight("Sheet1", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	props, err := f.GetDocProps()
+	assert.NoError(t, err)
+	assert.Equal(t, "Title", props.Title)
+	assert.Equal(t, "Creator", props.Creator)
+
+	// Test the cell styles in the OpenDocument spreadsheet.
+	styleID, err := f.GetCellStyle("Sheet1", "B1")
+	assert.NoError(t, err)
+	xf := f.Styles.CellXfs.Xf[styleID]
+	font := f.Styles.Fonts.Font[*xf.FontID]
+	assert.True(t, *font.B.Val)
+	assert.True(t, *font.I.Val)
+	assert.Equal(t, "double", *font.U.Val)
+	assert.Equal(t, "Arial", *font.Name.Val)
+	assert.Equal(t, 14.0, *font.Sz.Val)
+	assert.Equal(t, "FFFF0000", font.Color.RGB)
+	fill := f.Styles.Fills.Fill[*xf.FillID]
+	assert.Equal(t, "FFE0EBF5", fill.PatternFill.FgColor.RGB)
+	border := f.Styles.Borders.Border[*xf.BorderID]
+	assert.Equal(t, "medium", border.Left.Style)
+	assert.Equal(t, "FF0000FF", border.Left.Color.RGB)
+	assert.Equal(t, "thin", border.Bottom.Style)
+	assert.Equal(t, "", border.Top.Style)
+	assert.Equal(t, &xlsxAlignment{Horizontal: "center", Vertical: "top", WrapText: true}, xf.Alignment)
+	assert.Equal(t, "0.000", f.Styles.NumFmts.NumFmt[0].FormatCode)
+
+	// Test save the opened OpenDocument spreadsheet as workbook.
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveAsODS.xlsx")))
+	f, err = OpenFile(filepath.Join("test", "TestSaveAsODS.xlsx"))
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", val)
+	assert.NoError(t, f.Close())
+}
+
+func TestOpenODS(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content` + odsNameSpaces + `>
+<office:automatic-styles>
+<style:style style:name="co1" style:family="table-column"><style:table-column-properties style:column-width="2.258cm"/></style:style>
+<style:style style:name="ro1" style:family="table-row"><style:table-row-properties style:row-height="0.178in" style:use-optimal-row-height="true"/></style:style>
+<number:date-style style:name="N1"><number:year number:style="long"/><number:text>-</number:text><number:month number:style="long"/><number:text>-</number:text><number:day number:style="long"/></number:date-style>
+<style:style style:name="ce1" style:family="table-cell" style:parent-style-name="Default" style:data-style-name="N1"/>
+<style:style style:name="ce2" style:family="table-cell" style:parent-style-name="Default"><style:text-properties fo:font-weight="bold"/></style:style>
+</office:automatic-styles>
+<office:body><office:spreadsheet>
+<table:calculation-settings><table:null-date table:date-value="1904-01-01"/></table:calculation-settings>
+<table:table table:name="Data">
+<table:table-column table:style-name="co1" table:number-columns-repeated="2" table:default-cell-style-name="ce2"/>
+<table:table-column table:style-name="co1" table:number-columns-repeated="1022" table:default-cell-style-name="Default"/>
+<table:table-header-rows><table:table-row table:style-name="ro1">
+<table:table-cell office:value-type="string"><text:p>a<text:s text:c="2"/>b<text:tab/>c<text:span>d</text:span><office:annotation><text:p>note</text:p></office:annotation></text:p></table:table-cell>
+<table:table-cell office:value-type="date" office:date-value="2021-06-15" table:style-name="ce1"><text:p>2021-06-15</text:p></table:table-cell>
+<table:table-cell office:value-type="time" office:time-value="PT12H30M00S"><text:p>12:30:00</text:p></table:table-cell>
+<table:table-cell table:formula="of:=IF([.B1]&gt;1;&quot;a;b&quot;;SUM([$'My Sheet'.A1:.B2]))" office:value-type="string" office:string-value="x"><text:p>x</text:p></table:table-cell>
+</table:table-row></table:table-header-rows>
+<table:table-row table:number-rows-repeated="2"><table:table-cell office:value-type="float" office:value="7"><text:p>7</text:p></table:table-cell><table:table-cell table:number-columns-repeated="1023"/></table:table-row>
+<table:table-row table:number-rows-repeated="1048572"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+</table:table>
+</office:spreadsheet></office:body></office:document-content>`
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, data := range map[string]string{"mimetype": odsMediaType, "content.xml": content} {
+		fi, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = fi.Write([]byte(data))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	f, err := OpenReader(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Data"}, f.GetSheetList())
+	rows, err := f.GetRows("Data", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"a  b\tcd", "42900", "0.5208333333333334", "x"}, {"7"}, {"7"}}, rows)
+	assert.True(t, f.WorkBook.WorkbookPr.Date1904)
+	styleID, err := f.GetCellStyle("Data", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "yyyy-mm-dd", f.Styles.NumFmts.NumFmt[0].FormatCode)
+	assert.Equal(t, f.Styles.NumFmts.NumFmt[0].NumFmtID, *f.Styles.CellXfs.Xf[styleID].NumFmtID)
+	formula, err := f.GetCellFormula("Data", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, `IF(B1>1,"a;b",SUM('My Sheet'!A1:B2))`, formula)
+	styleID, err = f.GetCellStyle("Data", "A2")
+	assert.NoError(t, err)
+	assert.True(t, *f.Styles.Fonts.Font[*f.Styles.CellXfs.Xf[styleID].FontID].B.Val)
+	width, err := f.GetColWidth("Data", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, 11.48, width)
+
+	// Test open the OpenDocument spreadsheet with invalid content.
+	buf.Reset()
+	zw = zip.NewWriter(buf)
+	for name, data := range map[string]string{"mimetype": odsMediaType, "content.xml": strings.Replace(content, "</table:table>", "", 1)} {
+		fi, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = fi.Write([]byte(data))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	_, err = OpenReader(buf)
+	assert.Error(t, err)
+}
+
+func TestODSFormula(t *testing.T) {
+	for excel, ods := range map[string]string{
+		"SUM(A1:B2,'Sheet 2'!C3)":     "of:=SUM([.A1:.B2];['Sheet 2'.C3])",
+		"$A$1+Sheet1!B:B":             "of:=[.$A$1]+[Sheet1.B:.B]",
+		`CONCATENATE("a,b","""")`:     `of:=CONCATENATE("a,b";"""")`,
+		"SUM({1,2;3,4})":              "of:=SUM({1;2|3;4})",
+		"IF(ISERROR(A1),#N/A,MyName)": "of:=IF(ISERROR([.A1]);#N/A;MyName)",
+		"-A1%+(B1-C1)^2":              "of:=-[.A1]%+([.B1]-[.C1])^2",
+		"A1>=B1":                      "of:=[.A1]>=[.B1]",
+	} {
+		assert.Equal(t, ods, excelFormulaToODS(excel), excel)
+		assert.Equal(t, excel, odsFormulaToExcel(ods), ods)
+	}
+	assert.Equal(t, "Sheet1!A1:B2", odsFormulaToExcel("of:=[$Sheet1.A1:$Sheet1.B2]"))
+	assert.Equal(t, "#REF!+1", odsFormulaToExcel("=[.#REF!]+1"))
+}
+
+func TestODSDataStyle(t *testing.T) {
+	for code, expected := range map[string]string{
+		"0.000":      `<number:number-style style:name="N1"><number:number number:decimal-places="3" number:min-integer-digits="1"/></number:number-style>`,
+		"#,##0":      `<number:number-style style:name="N1"><number:number number:decimal-places="0" number:min-integer-digits="1" number:grouping="true"/></number:number-style>`,
+		"0.00%":      `<number:percentage-style style:name="N1"><number:number number:decimal-places="2" number:min-integer-digits="1"/><number:text>%</number:text></number:percentage-style>`,
+		"h:mm:ss":    `<number:time-style style:name="N1"><number:hours/><number:text>:</number:text><number:minutes number:style="long"/><number:text>:</number:text><number:seconds number:style="long"/></number:time-style>`,
+		"mmm d yyyy": `<number:date-style style:name="N1"><number:month number:textual="true"/><number:text> </number:text><number:day/><number:text> </number:text><number:year number:style="long"/></number:date-style>`,
+		"General":    "",
+		"@":          "",
+	} {
+		assert.Equal(t, expected, odsDataStyle("N1", code), code)
+	}
+	assert.False(t, odsIsDateFmt(`#,##0.00 "USD"`))
+	assert.False(t, odsIsDateFmt(`[Red]0.00`))
+	assert.True(t, odsIsDateFmt(`[h]:mm`))
+}
@@ -2,7 +2,6 @@ package xlsx
 
 import (
 	"bytes"
-	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"image"
@@ -17,19 +16,43 @@ import (
 
 // parseFormatPictureSet provides a function to parse the format settings of
 // the picture with default value.
-func parseFormatPictureSet(formatSet string) (*formatPicture, error) {
-	format := formatPicture{
-		FPrintsWithSheet: true,
-		FLocksWithSheet:  false,
-		NoChangeAspect:   false,
-		Autofit:          false,
-		OffsetX:          0,
-		OffsetY:          0,
-		XScale:           1.0,
-		YScale:           1.0,
-	}
-	err := json.Unmarshal(parseFormatSet(formatSet), &format)
-	return &format, err
+func parseFormatPictureSet(formatSet string) (*PictureOptions, error) {
+	var format PictureOptions
+	if err := unmarshalFormatSet(parseFormatSet(formatSet), &format); err != nil {
+		return &format, err
+	}
+	return parsePictureOptions(&format)
+}
+
+// parsePictureOptions provides a function to validate the format settings of
+// the picture and fill the unset settings with default value.
+func parsePictureOptions(opts *PictureOptions) (*PictureOptions, error) {
+	var format PictureOptions
+	if opts != nil {
+		format = *opts
+	}
+	if format.FPrintsWithSheet == nil {
+		format.FPrintsWithSheet = boolPtr(true)
+	}
+	if format.XScale < 0 {
+		return &format, newNegativeOptionError("x_scale", format.XScale)
+	}
+	if format.YScale < 0 {
+		return &format, newNegativeOptionError("y_scale", format.YScale)
+	}
+	if format.XScale == 0 {
+		format.XScale = 1.0
+	}
+	if format.YScale == 0 {
+		format.YScale = 1.0
+	}
+	if inStrSlice([]string{"", "External", "Location"}, format.HyperlinkType) == -1 {
+		return &format, newUnsupportedOptionError("hyperlink_type", format.HyperlinkType)
+	}
+	if inStrSlice([]string{"", "absolute", "oneCell", "twoCell"}, format.Positioning) == -1 {
+		return &format, newUnsupportedOptionError("positioning", format.Positioning)
+	}
+	return &format, nil
 }
 
 // AddPicture provides the method to add picture in a sheet by given picture
@@ -102,6 +125,27 @@ func parseFormatPictureSet(formatSet string) (*formatPicture, error) {
 // the default value of that is 1.0 which presents 100%.
 //
 func (f *File) AddPicture(sheet, cell, picture, format string) error {
+	var formatSet PictureOptions
+	if err := unmarshalFormatSet(parseFormatSet(format), &formatSet); err != nil {
+		return err
+	}
+	return f.AddPictureWithOptions(sheet, cell, picture, &formatSet)
+}
+
+// AddPictureWithOptions provides the method to add picture in a sheet by
+// given worksheet name, cell reference, file path and picture options. The
+// settings are the same as the AddPicture function, the zero value of the
+// options will be filled with default value. For example, insert a picture
+// scaling in the cell with location hyperlink:
+//
+//    err := f.AddPictureWithOptions("Sheet1", "D2", "image.png", &xlsx.PictureOptions{
+//        XScale:        0.5,
+//        YScale:        0.5,
+//        Hyperlink:     "#Sheet2!D8",
+//        HyperlinkType: "Location",
+//    })
+//
+func (f *File) AddPictureWithOptions(sheet, cell, picture string, opts *PictureOptions) error {
 	var err error
 	// Check picture exists first.
 	if _, err = os.Stat(picture); os.IsNotExist(err) {
@@ -113,7 +157,7 @@ func (f *File) AddPicture(sheet, cell, picture, format string) error {
 	}
 	file, _ := ioutil.ReadFile(filepath.Clean(picture))
 	_, name := filepath.Split(picture)
-	return f.AddPictureFromBytes(sheet, cell, format, name, ext, file)
+	return f.AddPictureFromBytesWithOptions(sheet, cell, name, ext, file, opts)
 }
 
 // AddPictureFromBytes provides the method to add picture in a sheet by given
@@ -146,13 +190,36 @@ func (f *File) AddPicture(sheet, cell, picture, format string) error {
 //    }
 //
 func (f *File) AddPictureFromBytes(sheet, cell, format, name, extension string, file []byte) error {
+	if _, ok := supportImageTypes[extension]; !ok {
+		return ErrImgExt
+	}
+	var formatSet PictureOptions
+	if err := unmarshalFormatSet(parseFormatSet(format), &formatSet); err != nil {
+		return err
+	}
+	return f.AddPictureFromBytesWithOptions(sheet, cell, name, extension, file, &formatSet)
+}
+
+// AddPictureFromBytesWithOptions provides the method to add picture in a
+// sheet by given worksheet name, cell reference, file base name, extension
+// name, file bytes and picture options. The settings are the same as the
+// AddPicture function, the zero value of the options will be filled with
+// default value. For example:
+//
+//    err := f.AddPictureFromBytesWithOptions("Sheet1", "A2", "Excel Logo", ".jpg", file, &xlsx.PictureOptions{
+//        XScale:      0.5,
+//        YScale:      0.5,
+//        Positioning: "oneCell",
+//    })
+//
+func (f *File) AddPictureFromBytesWithOptions(sheet, cell, name, extension string, file []byte, opts *PictureOptions) error {
 	var drawingHyperlinkRID int
 	var hyperlinkType string
 	ext, ok := supportImageTypes[extension]
 	if !ok {
 		return ErrImgExt
 	}
-	formatSet, err := parseFormatPictureSet(format)
+	formatSet, err := parsePictureOptions(opts)
 	if err != nil {
 		return err
 	}
@@ -261,7 +328,7 @@ func (f *File) countDrawings() (count int) {
 // addDrawingPicture provides a function to add picture by given sheet,
 // drawingXML, cell, file name, width, height relationship index and format
 // sets.
-func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, height, rID, hyperlinkRID int, formatSet *formatPicture) error {
+func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, height, rID, hyperlinkRID int, formatSet *PictureOptions) error {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
 		return err
@@ -312,7 +379,7 @@ func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, he
 	twoCellAnchor.Pic = &pic
 	twoCellAnchor.ClientData = &xdrClientData{
 		FLocksWithSheet:  formatSet.FLocksWithSheet,
-		FPrintsWithSheet: formatSet.FPrintsWithSheet,
+		FPrintsWithSheet: *formatSet.FPrintsWithSheet,
 	}
 	content.Lock()
 	defer content.Unlock()
@@ -643,7 +710,7 @@ func (f *File) drawingsWriter() {
 }
 
 // drawingResize calculate the height and width after resizing.
-func (f *File) drawingResize(sheet, cell string, width, height float64, formatSet *formatPicture) (w, h, c, r int, err error) {
+func (f *File) drawingResize(sheet, cell string, width, height float64, formatSet *PictureOptions) (w, h, c, r int, err error) {
 	var mergeCells []MergeCell
 	mergeCells, err = f.GetMergeCells(sheet)
 	if err != nil {
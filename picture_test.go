@@ -94,6 +94,34 @@ func TestAddPictureErrors(t *testing.T) {
 	assert.NoError(t, f.Close())
 }
 
+func TestAddPictureWithOptions(t *testing.T) {
+	f := NewFile()
+	opts := &PictureOptions{XScale: 0.5, Positioning: "oneCell"}
+	assert.NoError(t, f.AddPictureWithOptions("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), opts))
+	file, err := ioutil.ReadFile(filepath.Join("test", "images", "excel.jpg"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddPictureFromBytesWithOptions("Sheet1", "F1", "Excel Logo", ".jpg", file, nil))
+	// Test the options of the caller not be changed by the default value.
+	assert.Nil(t, opts.FPrintsWithSheet)
+	assert.Equal(t, 0.0, opts.YScale)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddPictureWithOptions.xlsx")))
+
+	// Test add picture with invalid options.
+	for _, c := range []struct {
+		opts *PictureOptions
+		err  string
+	}{
+		{&PictureOptions{XScale: -0.5}, "invalid x_scale -0.5, negative values are not supported"},
+		{&PictureOptions{YScale: -1}, "invalid y_scale -1, negative values are not supported"},
+		{&PictureOptions{Hyperlink: "#Sheet1!A1", HyperlinkType: "Internal"}, `unsupported hyperlink_type "Internal"`},
+		{&PictureOptions{Positioning: "twoCells"}, `unsupported positioning "twoCells"`},
+	} {
+		assert.EqualError(t, f.AddPictureFromBytesWithOptions("Sheet1", "A20", "Excel Logo", ".jpg", file, c.opts), c.err)
+	}
+	assert.EqualError(t, f.AddPicture("Sheet1", "A20", filepath.Join("test", "images", "excel.png"), `{"xscale":0.5}`), `json: unknown field "xscale"`)
+	assert.EqualError(t, f.AddPictureFromBytes("Sheet1", "A20", `{"x_scale":"0.5"}`, "Excel Logo", ".jpg", file), "json: cannot unmarshal string into Go struct field PictureOptions.x_scale of type float64")
+}
+
 func TestGetPicture(t *testing.T) {
 	f, err := prepareTestBook1()
 	if !assert.NoError(t, err) {
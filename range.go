@@ -0,0 +1,466 @@
+package xlsx
+
+import (
+	"strings"
+
+	"github.com/mohae/deepcopy"
+)
+
+// PasteType is the type of the contents to be pasted when copying a range of
+// cells.
+type PasteType byte
+
+// Paste types enumeration.
+const (
+	PasteAll PasteType = iota
+	PasteValues
+	PasteFormulas
+	PasteFormats
+)
+
+// PasteOptions directly maps the settings of the paste special when copying
+// or moving a range of cells.
+//
+// Type specifies which contents of the cells will be pasted, the values,
+// formulas and formats of the cells will be pasted by default. PasteValues
+// pastes the values and the calculated results of the formulas, PasteFormulas
+// pastes the values and the formulas without formats, PasteFormats pastes the
+// formats of the cells and the merged cells only.
+//
+// Transpose specifies if switch the rows and columns of the pasted cells.
+//
+// SkipBlanks specifies if the blank cells of the source range will not
+// overwrite the cells of the destination range.
+type PasteOptions struct {
+	Type       PasteType
+	Transpose  bool
+	SkipBlanks bool
+}
+
+// rangePaste provides the state of copying or moving a range of cells.
+type rangePaste struct {
+	f                  *File
+	srcSheet, dstSheet string
+	src, dst           *xlsxWorksheet
+	rect               []int
+	col, row           int
+	opts               PasteOptions
+}
+
+// CopyRange provides a function to copy the range of cells to the
+// destination cell as the top-left cell by given source worksheet name,
+// source range reference, destination worksheet name, destination cell and
+// paste options, the source and destination worksheets can be the same or
+// different worksheets. The relative references of the copied formulas will
+// be shifted by the offset between the source and destination cells, and the
+// references that would be out of the worksheet will become #REF!. The merged
+// cells inside the source range will be copied, and the merged cells that
+// overlap the destination range will be unmerged when pasting formats. For
+// example, copy the values of range A1:C3 on Sheet1 to E1 on Sheet2 with
+// switching rows and columns:
+//
+//    err := f.CopyRange("Sheet1", "A1:C3", "Sheet2", "E1", &xlsx.PasteOptions{
+//        Type:      xlsx.PasteValues,
+//        Transpose: true,
+//    })
+//
+func (f *File) CopyRange(srcSheet, srcRange, dstSheet, dstCell string, opts *PasteOptions) error {
+	p, err := f.newRangePaste(srcSheet, srcRange, dstSheet, dstCell, opts)
+	if err != nil {
+		return err
+	}
+	return p.paste(false)
+}
+
+// MoveRange provides a function to move the range of cells to the
+// destination cell as the top-left cell by given source worksheet name,
+// source range reference, destination worksheet name, destination cell and
+// paste options. The values, formulas, formats and merged cells will be moved
+// and the source range will be cleared, the paste type of the options will be
+// ignored. The formulas of the moved cells will not be shifted, and the
+// references in the formulas, defined names, data validations, conditional
+// formats, hyperlinks and charts of the workbook that point into the moved
+// range will be updated to the new location. For example, move range A1:C3
+// on Sheet1 to B5 on the same worksheet:
+//
+//    err := f.MoveRange("Sheet1", "A1:C3", "Sheet1", "B5", nil)
+//
+func (f *File) MoveRange(srcSheet, srcRange, dstSheet, dstCell string, opts *PasteOptions) error {
+	p, err := f.newRangePaste(srcSheet, srcRange, dstSheet, dstCell, opts)
+	if err != nil {
+		return err
+	}
+	p.opts.Type = PasteAll
+	return p.paste(true)
+}
+
+// newRangePaste provides a function to validate the parameters and prepare
+// the state of copying or moving a range of cells.
+func (f *File) newRangePaste(srcSheet, srcRange, dstSheet, dstCell string, opts *PasteOptions) (*rangePaste, error) {
+	p := &rangePaste{f: f}
+	if opts != nil {
+		p.opts = *opts
+	}
+	var err error
+	if p.src, err = f.workSheetReader(srcSheet); err != nil {
+		return p, err
+	}
+	if p.dst, err = f.workSheetReader(dstSheet); err != nil {
+		return p, err
+	}
+	p.srcSheet, p.dstSheet = f.GetSheetName(f.GetSheetIndex(srcSheet)), f.GetSheetName(f.GetSheetIndex(dstSheet))
+	if p.rect, err = rangeRefToCoordinates(srcRange); err != nil {
+		return p, err
+	}
+	if p.col, p.row, err = CellNameToCoordinates(strings.Replace(dstCell, "$", "", -1)); err != nil {
+		return p, err
+	}
+	col, row := p.cell(p.rect[2], p.rect[3])
+	if col > TotalColumns {
+		return p, ErrColumnNumber
+	}
+	if row > TotalRows {
+		return p, ErrMaxRows
+	}
+	return p, err
+}
+
+// rangeRefToCoordinates provides a function to convert the cell reference or
+// range reference to the sorted coordinates, the dollar signs in the
+// reference will be ignored.
+func rangeRefToCoordinates(ref string) ([]int, error) {
+	ref = strings.Replace(ref, "$", "", -1)
+	if !strings.Contains(ref, ":") {
+		ref += ":" + ref
+	}
+	coordinates, err := areaRefToCoordinates(ref)
+	if err != nil {
+		return coordinates, err
+	}
+	return coordinates, sortCoordinates(coordinates)
+}
+
+// cell returns the destination coordinates of the cell by given source
+// coordinates.
+func (p *rangePaste) cell(col, row int) (int, int) {
+	dCol, dRow := col-p.rect[0], row-p.rect[1]
+	if p.opts.Transpose {
+		dCol, dRow = dRow, dCol
+	}
+	return p.col + dCol, p.row + dRow
+}
+
+// paste provides a function to paste the cells of the source range to the
+// destination range, the source range will be cleared and the references to
+// the source range will be updated if moving the cells.
+func (p *rangePaste) paste(move bool) error {
+	dstRect := make([]int, 4)
+	dstRect[0], dstRect[1] = p.cell(p.rect[0], p.rect[1])
+	dstRect[2], dstRect[3] = p.cell(p.rect[2], p.rect[3])
+	unshareFormulas(p.src, p.rect)
+	unshareFormulas(p.dst, dstRect)
+	cells := p.sourceCells()
+	withFormats := move || p.opts.Type == PasteAll || p.opts.Type == PasteFormats
+	var mergeCells [][]int
+	if withFormats {
+		var err error
+		if mergeCells, err = p.sourceMergeCells(move); err != nil {
+			return err
+		}
+	}
+	if move {
+		for idx := range cells {
+			if formula := cells[idx].F; formula != nil {
+				formula.Content = p.moveFormula(formula.Content, p.srcSheet, true)
+			}
+		}
+		p.clearSource()
+		p.f.moveRangeReferences(p)
+	}
+	if withFormats {
+		hCell, _ := CoordinatesToCellName(dstRect[0], dstRect[1])
+		vCell, _ := CoordinatesToCellName(dstRect[2], dstRect[3])
+		if err := p.f.UnmergeCell(p.dstSheet, hCell, vCell); err != nil {
+			return err
+		}
+	}
+	width := p.rect[2] - p.rect[0] + 1
+	for idx := range cells {
+		col, row := p.rect[0]+idx%width, p.rect[1]+idx/width
+		p.pasteCell(&cells[idx], col, row, move)
+	}
+	for _, rect := range mergeCells {
+		hCol, hRow := p.cell(rect[0], rect[1])
+		vCol, vRow := p.cell(rect[2], rect[3])
+		hCell, _ := CoordinatesToCellName(hCol, hRow)
+		vCell, _ := CoordinatesToCellName(vCol, vRow)
+		if err := p.f.MergeCell(p.dstSheet, hCell, vCell); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sourceCells returns the copies of the cells in the source range ordered by
+// rows, the cells that don't exist in the worksheet will be blank cells.
+func (p *rangePaste) sourceCells() []xlsxC {
+	width := p.rect[2] - p.rect[0] + 1
+	cells := make([]xlsxC, width*(p.rect[3]-p.rect[1]+1))
+	for row := p.rect[1]; row <= p.rect[3] && row <= len(p.src.SheetData.Row); row++ {
+		for col := p.rect[0]; col <= p.rect[2] && col <= len(p.src.SheetData.Row[row-1].C); col++ {
+			cells[(row-p.rect[1])*width+col-p.rect[0]] = deepcopy.Copy(p.src.SheetData.Row[row-1].C[col-1]).(xlsxC)
+		}
+	}
+	return cells
+}
+
+// sourceMergeCells returns the coordinates of the merged cells inside the
+// source range, these merged cells will be removed if moving the cells.
+func (p *rangePaste) sourceMergeCells(move bool) ([][]int, error) {
+	var mergeCells [][]int
+	if p.src.MergeCells == nil {
+		return mergeCells, nil
+	}
+	if err := p.f.mergeOverlapCells(p.src); err != nil {
+		return mergeCells, err
+	}
+	i := 0
+	for _, mergeCell := range p.src.MergeCells.Cells {
+		if mergeCell == nil {
+			continue
+		}
+		rect, err := areaRefToCoordinates(mergeCell.Ref)
+		if err != nil {
+			return mergeCells, err
+		}
+		_ = sortCoordinates(rect)
+		if cellInRef(rect[:2], p.rect) && cellInRef(rect[2:], p.rect) {
+			mergeCells = append(mergeCells, rect)
+			if move {
+				continue
+			}
+		}
+		p.src.MergeCells.Cells[i] = mergeCell
+		i++
+	}
+	p.src.MergeCells.Cells = p.src.MergeCells.Cells[:i]
+	p.src.MergeCells.Count = len(p.src.MergeCells.Cells)
+	if p.src.MergeCells.Count == 0 {
+		p.src.MergeCells = nil
+	}
+	return mergeCells, nil
+}
+
+// clearSource provides a function to clear the values, formulas and formats
+// of the cells in the source range.
+func (p *rangePaste) clearSource() {
+	sheetID := p.f.getSheetID(p.srcSheet)
+	for row := p.rect[1]; row <= p.rect[3] && row <= len(p.src.SheetData.Row); row++ {
+		for col := p.rect[0]; col <= p.rect[2] && col <= len(p.src.SheetData.Row[row-1].C); col++ {
+			c := &p.src.SheetData.Row[row-1].C[col-1]
+			if c.F != nil {
+				p.f.deleteCalcChain(sheetID, c.R)
+			}
+			*c = xlsxC{R: c.R}
+		}
+	}
+}
+
+// pasteCell provides a function to paste the copy of the source cell to the
+// destination worksheet by given source coordinates.
+func (p *rangePaste) pasteCell(c *xlsxC, col, row int, move bool) {
+	blank := c.V == "" && c.F == nil && c.IS == nil
+	if blank && p.opts.SkipBlanks {
+		return
+	}
+	dstCol, dstRow := p.cell(col, row)
+	if blank && c.S == 0 && (dstRow > len(p.dst.SheetData.Row) || dstCol > len(p.dst.SheetData.Row[dstRow-1].C)) {
+		return
+	}
+	prepareSheetXML(p.dst, dstCol, dstRow)
+	dst := &p.dst.SheetData.Row[dstRow-1].C[dstCol-1]
+	if p.opts.Type == PasteFormats {
+		dst.S = c.S
+		return
+	}
+	cell := *c
+	cell.R = dst.R
+	switch p.opts.Type {
+	case PasteValues:
+		cell.S, cell.F = dst.S, nil
+		if c.F != nil && c.T == "str" {
+			cell.T, cell.V = p.f.setCellString(c.V)
+		}
+	case PasteFormulas:
+		cell.S = dst.S
+	}
+	if cell.F != nil {
+		dCol, dRow := dstCol-col, dstRow-row
+		if !move {
+			cell.F.Content = shiftFormulaRefs(cell.F.Content, dCol, dRow)
+		}
+		if cell.F.Ref != "" {
+			cell.F.Ref = shiftFormulaRefs(cell.F.Ref, dCol, dRow)
+		}
+	}
+	if dst.F != nil && cell.F == nil {
+		p.f.deleteCalcChain(p.f.getSheetID(p.dstSheet), dst.R)
+	}
+	*dst = cell
+}
+
+// moveFormula provides a function to update the references in the formula
+// that point into the source range to the destination range by given
+// worksheet name of the formula. The references without worksheet name that
+// point outside the source range will be qualified with the source worksheet
+// name if the formula is one of the moved cells and it is moved to the other
+// worksheet.
+func (p *rangePaste) moveFormula(formula, formulaSheet string, moved bool) string {
+	hostSheet := formulaSheet
+	if moved {
+		hostSheet = p.dstSheet
+	}
+	return replaceFormulaRefs(formula, func(sheet, ref string) (string, bool) {
+		name := formulaSheet
+		if sheet != "" {
+			name = unquoteSheetName(sheet)
+		}
+		if name == "" || !strings.EqualFold(name, p.srcSheet) {
+			return ref, false
+		}
+		parts, ok := parseRangeRef(ref)
+		if !ok {
+			return ref, false
+		}
+		first, last := &parts[0], &parts[len(parts)-1]
+		if first.col == 0 || first.row == 0 ||
+			!cellInRef([]int{first.col, first.row}, p.rect) || !cellInRef([]int{last.col, last.row}, p.rect) {
+			if moved && sheet == "" && !strings.EqualFold(p.srcSheet, p.dstSheet) {
+				return quoteSheetName(p.srcSheet) + "!" + ref, true
+			}
+			return ref, false
+		}
+		first.col, first.row = p.cell(first.col, first.row)
+		if len(parts) > 1 {
+			last.col, last.row = p.cell(last.col, last.row)
+		}
+		if sheet != "" || !strings.EqualFold(hostSheet, p.dstSheet) {
+			return quoteSheetName(p.dstSheet) + "!" + formatRangeRef(parts), true
+		}
+		return formatRangeRef(parts), true
+	})
+}
+
+// moveRangeReferences provides a function to update the references that point
+// into the moved range in the formulas of the worksheets, defined names and
+// charts of the workbook.
+func (f *File) moveRangeReferences(p *rangePaste) {
+	for _, name := range f.GetSheetList() {
+		ws, err := f.workSheetReader(name)
+		if err != nil {
+			continue
+		}
+		replaceWorksheetFormulas(ws, func(formula string) string {
+			return p.moveFormula(formula, name, false)
+		})
+	}
+	move := func(formula string) string {
+		return p.moveFormula(formula, "", false)
+	}
+	if wb := f.workbookReader(); wb.DefinedNames != nil {
+		for idx := range wb.DefinedNames.DefinedName {
+			wb.DefinedNames.DefinedName[idx].Data = move(wb.DefinedNames.DefinedName[idx].Data)
+		}
+	}
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if strings.HasPrefix(k.(string), "xl/charts/chart") {
+			f.Pkg.Store(k, []byte(replaceXMLFormulas(string(v.([]byte)), move)))
+		}
+		return true
+	})
+}
+
+// shiftFormulaRefs provides a function to shift the relative references in
+// the formula by given column and row offset, the references that would be
+// out of the worksheet will become #REF!.
+func shiftFormulaRefs(formula string, dCol, dRow int) string {
+	if dCol == 0 && dRow == 0 {
+		return formula
+	}
+	return replaceFormulaRefs(formula, func(sheet, ref string) (string, bool) {
+		parts, ok := parseRangeRef(ref)
+		if !ok {
+			return ref, false
+		}
+		if sheet != "" {
+			sheet += "!"
+		}
+		for idx := range parts {
+			part := &parts[idx]
+			if part.col > 0 && !part.absCol {
+				if part.col += dCol; part.col < 1 || part.col > TotalColumns {
+					return sheet + formulaErrorREF, true
+				}
+			}
+			if part.row > 0 && !part.absRow {
+				if part.row += dRow; part.row < 1 || part.row > TotalRows {
+					return sheet + formulaErrorREF, true
+				}
+			}
+		}
+		return sheet + formatRangeRef(parts), true
+	})
+}
+
+// formatRangeRef provides a function to format the parts of the cell
+// reference or range reference.
+func formatRangeRef(parts []cellRefPart) string {
+	refs := make([]string, 0, len(parts))
+	for _, part := range parts {
+		refs = append(refs, part.String())
+	}
+	return strings.Join(refs, ":")
+}
+
+// unshareFormulas provides a function to convert the shared formulas which
+// overlap the given range into normal formulas, so that the cells of the
+// shared formulas can be copied or overwritten separately.
+func unshareFormulas(ws *xlsxWorksheet, rect []int) {
+	shared := make(map[int]bool)
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			if c.F == nil || c.F.T != STCellFormulaTypeShared || c.F.Si == nil || c.F.Ref == "" {
+				continue
+			}
+			ref := c.F.Ref
+			if !strings.Contains(ref, ":") {
+				ref += ":" + ref
+			}
+			if coordinates, err := areaRefToCoordinates(ref); err == nil {
+				_ = sortCoordinates(coordinates)
+				if isOverlap(coordinates, rect) {
+					shared[*c.F.Si] = true
+				}
+			}
+		}
+	}
+	if len(shared) == 0 {
+		return
+	}
+	formulas := make(map[string]string)
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			if c.F != nil && c.F.T == STCellFormulaTypeShared && c.F.Si != nil && shared[*c.F.Si] {
+				formulas[c.R] = getSharedForumula(ws, *c.F.Si, c.R)
+			}
+		}
+	}
+	for rowIdx := range ws.SheetData.Row {
+		for colIdx := range ws.SheetData.Row[rowIdx].C {
+			c := &ws.SheetData.Row[rowIdx].C[colIdx]
+			if formula, ok := formulas[c.R]; ok && c.F != nil && c.F.T == STCellFormulaTypeShared {
+				c.F = &xlsxF{Content: formula}
+			}
+		}
+	}
+}
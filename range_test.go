@@ -0,0 +1,186 @@
+package xlsx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyRange(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{1, 2}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A2", "A1+B1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B2", "SUM($A$1:B1)"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", "merged"))
+	assert.NoError(t, f.MergeCell("Sheet1", "A3", "B3"))
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+
+	// Test copy range with values, formulas, formats and merged cells.
+	assert.NoError(t, f.CopyRange("Sheet1", "A1:B3", "Sheet1", "D2", nil))
+	for cell, expected := range map[string]string{"D2": "1", "E2": "2", "D4": "merged"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+	for cell, expected := range map[string]string{"D3": "D2+E2", "E3": "SUM($A$1:E2)"} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula)
+	}
+	styleID, err := f.GetCellStyle("Sheet1", "D2")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 2)
+	assert.Equal(t, "D4:E4", mergeCells[1].GetStartAxis()+":"+mergeCells[1].GetEndAxis())
+
+	// Test copy formulas with the references out of the worksheet.
+	assert.NoError(t, f.CopyRange("Sheet1", "A2", "Sheet2", "A1", &PasteOptions{Type: PasteFormulas}))
+	formula, err := f.GetCellFormula("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "#REF!+#REF!", formula)
+
+	// Test copy values of the formulas with transpose.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[1].C[0].V = "3"
+	assert.NoError(t, f.CopyRange("Sheet1", "A1:B2", "Sheet2", "C1", &PasteOptions{Type: PasteValues, Transpose: true}))
+	for cell, expected := range map[string]string{"C1": "1", "C2": "2", "D1": "3"} {
+		val, err := f.GetCellValue("Sheet2", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+	formula, err = f.GetCellFormula("Sheet2", "D1")
+	assert.NoError(t, err)
+	assert.Empty(t, formula)
+	styleID, err = f.GetCellStyle("Sheet2", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, styleID)
+
+	// Test copy formats only.
+	assert.NoError(t, f.CopyRange("Sheet1", "A1:B3", "Sheet2", "F1", &PasteOptions{Type: PasteFormats}))
+	val, err := f.GetCellValue("Sheet2", "F1")
+	assert.NoError(t, err)
+	assert.Empty(t, val)
+	styleID, err = f.GetCellStyle("Sheet2", "F1")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	mergeCells, err = f.GetMergeCells("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+
+	// Test copy range with skip blanks.
+	assert.NoError(t, f.SetCellValue("Sheet2", "H1", "keep"))
+	assert.NoError(t, f.CopyRange("Sheet1", "C1", "Sheet2", "H1", &PasteOptions{SkipBlanks: true}))
+	val, err = f.GetCellValue("Sheet2", "H1")
+	assert.NoError(t, err)
+	assert.Equal(t, "keep", val)
+	assert.NoError(t, f.CopyRange("Sheet1", "C1", "Sheet2", "H1", nil))
+	val, err = f.GetCellValue("Sheet2", "H1")
+	assert.NoError(t, err)
+	assert.Empty(t, val)
+
+	// Test copy a part of the shared formula.
+	for r := 1; r <= 3; r++ {
+		assert.NoError(t, f.SetCellValue("Sheet2", fmt.Sprintf("J%d", r), r))
+	}
+	formulaType, ref := STCellFormulaTypeShared, "K1:K3"
+	assert.NoError(t, f.SetCellFormula("Sheet2", "K1", "J1*2", FormulaOpts{Ref: &ref, Type: &formulaType}))
+	assert.NoError(t, f.CopyRange("Sheet2", "K2", "Sheet2", "L2", nil))
+	for cell, expected := range map[string]string{"K1": "J1*2", "K3": "J3*2", "L2": "K2*2"} {
+		formula, err := f.GetCellFormula("Sheet2", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula)
+	}
+
+	// Test copy formulas with the structured references.
+	assert.NoError(t, f.SetCellFormula("Sheet2", "M1", "SUM(Tbl5[Col])+J1"))
+	assert.NoError(t, f.CopyRange("Sheet2", "M1", "Sheet2", "N3", nil))
+	formula, err = f.GetCellFormula("Sheet2", "N3")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(Tbl5[Col])+K3", formula)
+
+	// Test copy range with invalid parameters.
+	assert.EqualError(t, f.CopyRange("SheetN", "A1", "Sheet1", "A1", nil), "sheet SheetN is not exist")
+	assert.EqualError(t, f.CopyRange("Sheet1", "A1", "SheetN", "A1", nil), "sheet SheetN is not exist")
+	assert.EqualError(t, f.CopyRange("Sheet1", "A", "Sheet1", "A1", nil), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.CopyRange("Sheet1", "A1", "Sheet1", "A", nil), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.CopyRange("Sheet1", "A1:B1", "Sheet1", "XFD1", nil), ErrColumnNumber.Error())
+	assert.EqualError(t, f.CopyRange("Sheet1", "A1:A2", "Sheet1", "A1048576", nil), ErrMaxRows.Error())
+}
+
+func TestMoveRange(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	f.NewSheet("Sheet 3")
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 2))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "SUM(A1:A2)+A1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A3", "A1+H1"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "Sheet1!A1*2+A1"))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Data", RefersTo: "Sheet1!$A$1:$A$2"}))
+	assert.NoError(t, f.MergeCell("Sheet1", "C1", "D1"))
+
+	// Test move range on the same worksheet.
+	assert.NoError(t, f.MoveRange("Sheet1", "A1:A3", "Sheet1", "F5", nil))
+	for cell, expected := range map[string]string{"A1": "", "A2": "", "F5": "1", "F6": "2"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+	for _, c := range []struct{ sheet, cell, expected string }{
+		{"Sheet1", "A3", ""},
+		{"Sheet1", "F7", "F5+H1"},
+		{"Sheet1", "B1", "SUM(F5:F6)+F5"},
+		{"Sheet2", "A1", "Sheet1!F5*2+A1"},
+	} {
+		formula, err := f.GetCellFormula(c.sheet, c.cell)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, formula)
+	}
+	assert.Equal(t, "Sheet1!$F$5:$F$6", f.GetDefinedName()[0].RefersTo)
+
+	// Test move range to the other worksheet.
+	assert.NoError(t, f.MoveRange("Sheet1", "F5:F7", "Sheet 3", "A1", nil))
+	for _, c := range []struct{ sheet, cell, expected string }{
+		{"Sheet 3", "A3", "A1+Sheet1!H1"},
+		{"Sheet1", "B1", "SUM('Sheet 3'!A1:A2)+'Sheet 3'!A1"},
+		{"Sheet2", "A1", "'Sheet 3'!A1*2+A1"},
+	} {
+		formula, err := f.GetCellFormula(c.sheet, c.cell)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, formula)
+	}
+	assert.Equal(t, "'Sheet 3'!$A$1:$A$2", f.GetDefinedName()[0].RefersTo)
+
+	// Test move range with merged cells and transpose.
+	assert.NoError(t, f.MoveRange("Sheet1", "C1:D1", "Sheet1", "C10", &PasteOptions{Transpose: true}))
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "C10", mergeCells[0].GetStartAxis())
+	assert.Equal(t, "C11", mergeCells[0].GetEndAxis())
+
+	// Test move range with invalid parameters.
+	assert.EqualError(t, f.MoveRange("SheetN", "A1", "Sheet1", "A1", nil), "sheet SheetN is not exist")
+}
+
+func TestShiftFormulaRefs(t *testing.T) {
+	for _, c := range []struct {
+		formula, expected string
+	}{
+		{"A1+$B$2+C$3+$D4", "B3+$B$2+D$3+$D6"},
+		{"SUM(A:A,1:1)", "SUM(B:B,3:3)"},
+		{"'Sheet 1'!A1&\"A1\"", "'Sheet 1'!B3&\"A1\""},
+		{"Table1[Col]+Name", "Table1[Col]+Name"},
+		{"SUM(Tbl5[Col])+Tbl5[[#Headers],[Col]]+Sheet1!Tbl5[Col]", "SUM(Tbl5[Col])+Tbl5[[#Headers],[Col]]+Sheet1!Tbl5[Col]"},
+	} {
+		assert.Equal(t, c.expected, shiftFormulaRefs(c.formula, 1, 2))
+	}
+	assert.Equal(t, "Sheet1!#REF!", shiftFormulaRefs("Sheet1!A1", -1, 0))
+}
@@ -10,6 +10,7 @@ import (
 	"math/big"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/mohae/deepcopy"
 )
@@ -59,10 +60,16 @@ type Rows struct {
 	err                         error
 	curRow, totalRows, stashRow int
 	rawCellValue                bool
-	sheet                       string
+	sheet, sheetName            string
 	f                           *File
 	tempFile                    *os.File
 	decoder                     *xml.Decoder
+	rowOpts, stashRowOpts       RowOpts
+	cellStyles                  []int
+	tailRead                    bool
+	mergeCells                  *xlsxMergeCells
+	hyperlinks                  *xlsxHyperlinks
+	dataValidations             *xlsxDataValidations
 }
 
 // CurrentRow returns the row number that represents the current row.
@@ -95,12 +102,27 @@ func (rows *Rows) Close() error {
 	return nil
 }
 
+// GetRowOpts returns the height, visibility, outline level and style of the
+// current row, it should be called after the Columns function.
+func (rows *Rows) GetRowOpts() RowOpts {
+	return rows.rowOpts
+}
+
+// GetCellStyles returns the style index of the cells in the current row, the
+// index of the slice is the column number minus one, it should be called
+// after the Columns function.
+func (rows *Rows) GetCellStyles() []int {
+	return rows.cellStyles
+}
+
 // Columns return the current row's column values.
 func (rows *Rows) Columns(opts ...Options) ([]string, error) {
 	var rowIterator rowXMLIterator
+	rows.rowOpts, rows.cellStyles = RowOpts{}, nil
 	if rows.stashRow >= rows.curRow {
 		return rowIterator.columns, rowIterator.err
 	}
+	rows.rowOpts, rows.stashRowOpts = rows.stashRowOpts, RowOpts{}
 	rows.rawCellValue = parseOptions(opts...).RawCellValue
 	rowIterator.rows = rows
 	rowIterator.d = rows.f.sharedStringsReader()
@@ -119,8 +141,10 @@ func (rows *Rows) Columns(opts ...Options) ([]string, error) {
 				}
 				if rowIterator.row > rowIterator.rows.curRow {
 					rowIterator.rows.stashRow = rowIterator.row - 1
+					rows.stashRowOpts = parseRowOpts(xmlElement.Attr)
 					return rowIterator.columns, rowIterator.err
 				}
+				rows.rowOpts = parseRowOpts(xmlElement.Attr)
 			}
 			rowXMLHandler(&rowIterator, &xmlElement, rows.rawCellValue)
 			if rowIterator.err != nil {
@@ -142,6 +166,129 @@ func (rows *Rows) Columns(opts ...Options) ([]string, error) {
 	return rowIterator.columns, rowIterator.err
 }
 
+// parseRowOpts parse the height, visibility, outline level and style of the
+// row by given attributes of the row XML element.
+func parseRowOpts(attrs []xml.Attr) RowOpts {
+	var opts RowOpts
+	for _, attr := range attrs {
+		switch attr.Name.Local {
+		case "ht":
+			opts.Height, _ = strconv.ParseFloat(attr.Value, 64)
+		case "hidden":
+			opts.Hidden, _ = strconv.ParseBool(attr.Value)
+		case "outlineLevel":
+			level, _ := strconv.ParseUint(attr.Value, 10, 8)
+			opts.OutlineLevel = uint8(level)
+		case "s":
+			opts.StyleID, _ = strconv.Atoi(attr.Value)
+		}
+	}
+	return opts
+}
+
+// readTail provides a function to read the merged cells, hyperlinks and data
+// validations after the sheet data of the worksheet, the rows iteration must
+// be completed.
+func (rows *Rows) readTail() error {
+	if rows.curRow <= rows.totalRows {
+		return ErrRowsIteration
+	}
+	if rows.tailRead || rows.decoder == nil {
+		return nil
+	}
+	rows.tailRead = true
+	for {
+		token, _ := rows.decoder.Token()
+		if token == nil {
+			return nil
+		}
+		xmlElement, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		var err error
+		switch xmlElement.Name.Local {
+		case "mergeCells":
+			rows.mergeCells = new(xlsxMergeCells)
+			err = rows.decoder.DecodeElement(rows.mergeCells, &xmlElement)
+		case "hyperlinks":
+			rows.hyperlinks = new(xlsxHyperlinks)
+			err = rows.decoder.DecodeElement(rows.hyperlinks, &xmlElement)
+		case "dataValidations":
+			rows.dataValidations = new(xlsxDataValidations)
+			err = rows.decoder.DecodeElement(rows.dataValidations, &xmlElement)
+		case "extLst":
+			err = rows.decoder.Skip()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// GetMergeCells returns the merged cells of the worksheet after the rows
+// iteration completes without loading the worksheet, the values of the
+// merged cells are not included. For example:
+//
+//    for rows.Next() {
+//        row, err := rows.Columns()
+//        if err != nil {
+//            fmt.Println(err)
+//        }
+//        fmt.Println(row, rows.GetRowOpts(), rows.GetCellStyles())
+//    }
+//    mergeCells, err := rows.GetMergeCells()
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    for _, mergeCell := range mergeCells {
+//        fmt.Println(mergeCell.GetStartAxis(), mergeCell.GetEndAxis())
+//    }
+//
+func (rows *Rows) GetMergeCells() ([]MergeCell, error) {
+	var mergeCells []MergeCell
+	if err := rows.readTail(); err != nil || rows.mergeCells == nil {
+		return mergeCells, err
+	}
+	for _, cell := range rows.mergeCells.Cells {
+		if cell == nil {
+			continue
+		}
+		ref := cell.Ref
+		if !strings.Contains(ref, ":") {
+			ref += ":" + ref
+		}
+		mergeCells = append(mergeCells, []string{ref, ""})
+	}
+	return mergeCells, nil
+}
+
+// GetHyperLinks returns the hyperlinks of the worksheet after the rows
+// iteration completes without loading the worksheet, the key of the map is
+// the cell reference and the value is the link target.
+func (rows *Rows) GetHyperLinks() (map[string]string, error) {
+	links := make(map[string]string)
+	if err := rows.readTail(); err != nil || rows.hyperlinks == nil {
+		return links, err
+	}
+	for _, link := range rows.hyperlinks.Hyperlink {
+		links[link.Ref] = link.Location
+		if link.RID != "" {
+			links[link.Ref] = rows.f.getSheetRelationshipsTargetByID(rows.sheetName, link.RID)
+		}
+	}
+	return links, nil
+}
+
+// GetDataValidations returns the data validations of the worksheet after the
+// rows iteration completes without loading the worksheet.
+func (rows *Rows) GetDataValidations() ([]*DataValidation, error) {
+	if err := rows.readTail(); err != nil || rows.dataValidations == nil {
+		return nil, err
+	}
+	return rows.dataValidations.DataValidation, nil
+}
+
 // appendSpace append blank characters to slice by given length and source slice.
 func appendSpace(l int, s []string) []string {
 	for i := 1; i < l; i++ {
@@ -181,6 +328,12 @@ func rowXMLHandler(rowIterator *rowXMLIterator, xmlElement *xml.StartElement, ra
 				return
 			}
 		}
+		if colCell.S != 0 {
+			for len(rowIterator.rows.cellStyles) < rowIterator.cellCol {
+				rowIterator.rows.cellStyles = append(rowIterator.rows.cellStyles, 0)
+			}
+			rowIterator.rows.cellStyles[rowIterator.cellCol-1] = colCell.S
+		}
 		blank := rowIterator.cellCol - len(rowIterator.columns)
 		val, _ := colCell.getValueFrom(rowIterator.rows.f, rowIterator.d, raw)
 		if val != "" || colCell.F != nil {
@@ -259,7 +412,7 @@ func (f *File) Rows(sheet string) (*Rows, error) {
 		case xml.EndElement:
 			if xmlElement.Name.Local == "sheetData" {
 				rows.f = f
-				rows.sheet = name
+				rows.sheet, rows.sheetName = name, sheet
 				_, rows.decoder, rows.tempFile, err = f.sheetDecoder(name)
 				return &rows, err
 			}
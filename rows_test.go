@@ -97,6 +97,72 @@ func TestRowsIterator(t *testing.T) {
 	assert.Equal(t, expectedNumRow, rowCount)
 }
 
+func TestRowsMetadata(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Link"}))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "C1", "C1", style))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 1, 30))
+	assert.NoError(t, f.SetRowStyle("Sheet1", 3, 3, style))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A4", "Data"))
+	assert.NoError(t, f.SetRowVisible("Sheet1", 4, false))
+	assert.NoError(t, f.SetRowOutlineLevel("Sheet1", 4, 2))
+	assert.NoError(t, f.MergeCell("Sheet1", "A5", "B6"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "B1", "https://github.com/carmel/xlsx", "External"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "B2", "Sheet1!A1", "Location"))
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "D1:D10"
+	assert.NoError(t, dvRange.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	// Test get the worksheet elements after the sheet data before the rows
+	// iteration completes.
+	_, err = rows.GetMergeCells()
+	assert.EqualError(t, err, ErrRowsIteration.Error())
+	var opts []RowOpts
+	var styles [][]int
+	for rows.Next() {
+		_, err := rows.Columns()
+		assert.NoError(t, err)
+		opts, styles = append(opts, rows.GetRowOpts()), append(styles, rows.GetCellStyles())
+	}
+	assert.Equal(t, []RowOpts{{Height: 30}, {}, {StyleID: style}, {Hidden: true, OutlineLevel: 2}}, opts)
+	assert.Equal(t, []int{0, 0, style}, styles[0])
+	assert.Nil(t, styles[1])
+	mergeCells, err := rows.GetMergeCells()
+	assert.NoError(t, err)
+	assert.Equal(t, []MergeCell{{"A5:B6", ""}}, mergeCells)
+	links, err := rows.GetHyperLinks()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"B1": "https://github.com/carmel/xlsx", "B2": "Sheet1!A1"}, links)
+	dataValidations, err := rows.GetDataValidations()
+	assert.NoError(t, err)
+	assert.Len(t, dataValidations, 1)
+	assert.Equal(t, "D1:D10", dataValidations[0].Sqref)
+	assert.NoError(t, rows.Close())
+
+	// Test get the row options of the sparse rows.
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", "Data"))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 3, 20))
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	opts = opts[:0]
+	for rows.Next() {
+		_, err := rows.Columns()
+		assert.NoError(t, err)
+		opts = append(opts, rows.GetRowOpts())
+	}
+	assert.Equal(t, []RowOpts{{}, {}, {Height: 20}}, opts)
+	dataValidations, err = rows.GetDataValidations()
+	assert.NoError(t, err)
+	assert.Nil(t, dataValidations)
+	assert.NoError(t, rows.Close())
+}
+
 func TestRowsError(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	if !assert.NoError(t, err) {
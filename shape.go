@@ -1,29 +1,43 @@
 package xlsx
 
 import (
-	"encoding/json"
 	"strconv"
 	"strings"
 )
 
-// parseFormatShapeSet provides a function to parse the format settings of the
-// shape with default value.
-func parseFormatShapeSet(formatSet string) (*formatShape, error) {
-	format := formatShape{
-		Width:  160,
-		Height: 160,
-		Format: formatPicture{
-			FPrintsWithSheet: true,
-			FLocksWithSheet:  false,
-			NoChangeAspect:   false,
-			OffsetX:          0,
-			OffsetY:          0,
-			XScale:           1.0,
-			YScale:           1.0,
-		},
-		Line: formatLine{Width: 1},
+// parseShapeOptions provides a function to validate the format settings of
+// the shape and fill the unset settings with default value.
+func parseShapeOptions(opts *ShapeOptions) (*ShapeOptions, error) {
+	if opts == nil {
+		return nil, ErrParameterRequired
+	}
+	format := *opts
+	if format.Type == "" {
+		return &format, newUnsupportedOptionError("shape type", format.Type)
+	}
+	if format.Width < 0 {
+		return &format, newNegativeOptionError("shape width", float64(format.Width))
+	}
+	if format.Height < 0 {
+		return &format, newNegativeOptionError("shape height", float64(format.Height))
+	}
+	if format.Line.Width < 0 {
+		return &format, newNegativeOptionError("shape line width", format.Line.Width)
 	}
-	err := json.Unmarshal([]byte(formatSet), &format)
+	if format.Width == 0 {
+		format.Width = 160
+	}
+	if format.Height == 0 {
+		format.Height = 160
+	}
+	if format.Line.Width == 0 {
+		format.Line.Width = 1
+	}
+	pic, err := parsePictureOptions(&format.Format)
+	if err != nil {
+		return &format, err
+	}
+	format.Format = *pic
 	return &format, err
 }
 
@@ -272,7 +286,31 @@ func parseFormatShapeSet(formatSet string) (*formatShape, error) {
 //    wavyDbl
 //
 func (f *File) AddShape(sheet, cell, format string) error {
-	formatSet, err := parseFormatShapeSet(format)
+	var formatSet ShapeOptions
+	if err := unmarshalFormatSet([]byte(format), &formatSet); err != nil {
+		return err
+	}
+	return f.AddShapeWithOptions(sheet, cell, &formatSet)
+}
+
+// AddShapeWithOptions provides the method to add shape in a sheet by given
+// worksheet name, cell reference and shape options. The settings are the
+// same as the AddShape function, the zero value of the options will be
+// filled with default value. For example, add text box (rect shape) in
+// Sheet1:
+//
+//    err := f.AddShapeWithOptions("Sheet1", "G6", &xlsx.ShapeOptions{
+//        Type:  "rect",
+//        Color: xlsx.ShapeColor{Line: "#4286F4", Fill: "#8EB9FF"},
+//        Paragraph: []xlsx.ShapeParagraph{
+//            {Text: "Rectangle Shape", Font: xlsx.Font{Bold: true, Color: "#777777"}},
+//        },
+//        Width:  180,
+//        Height: 90,
+//    })
+//
+func (f *File) AddShapeWithOptions(sheet, cell string, opts *ShapeOptions) error {
+	formatSet, err := parseShapeOptions(opts)
 	if err != nil {
 		return err
 	}
@@ -308,7 +346,7 @@ func (f *File) AddShape(sheet, cell, format string) error {
 
 // addDrawingShape provides a function to add preset geometry by given sheet,
 // drawingXMLand format sets.
-func (f *File) addDrawingShape(sheet, drawingXML, cell string, formatSet *formatShape) error {
+func (f *File) addDrawingShape(sheet, drawingXML, cell string, formatSet *ShapeOptions) error {
 	fromCol, fromRow, err := CellNameToCoordinates(cell)
 	if err != nil {
 		return err
@@ -400,7 +438,7 @@ func (f *File) addDrawingShape(sheet, drawingXML, cell string, formatSet *format
 		}
 	}
 	if len(formatSet.Paragraph) < 1 {
-		formatSet.Paragraph = []formatShapeParagraph{
+		formatSet.Paragraph = []ShapeParagraph{
 			{
 				Font: Font{
 					Bold:      false,
@@ -454,7 +492,7 @@ func (f *File) addDrawingShape(sheet, drawingXML, cell string, formatSet *format
 	twoCellAnchor.Sp = &shape
 	twoCellAnchor.ClientData = &xdrClientData{
 		FLocksWithSheet:  formatSet.Format.FLocksWithSheet,
-		FPrintsWithSheet: formatSet.Format.FPrintsWithSheet,
+		FPrintsWithSheet: *formatSet.Format.FPrintsWithSheet,
 	}
 	content.TwoCellAnchor = append(content.TwoCellAnchor, &twoCellAnchor)
 	f.Drawings.Store(drawingXML, content)
@@ -88,3 +88,35 @@ func TestAddShape(t *testing.T) {
 	}`))
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddShape2.xlsx")))
 }
+
+func TestAddShapeWithOptions(t *testing.T) {
+	f := NewFile()
+	opts := &ShapeOptions{
+		Type:      "rect",
+		Color:     ShapeColor{Line: "#4286F4", Fill: "#8EB9FF"},
+		Paragraph: []ShapeParagraph{{Text: "Rectangle", Font: Font{Bold: true, Color: "#777777"}}},
+		Line:      ShapeLine{Width: 1.2},
+	}
+	assert.NoError(t, f.AddShapeWithOptions("Sheet1", "A1", opts))
+	assert.NoError(t, f.AddShapeWithOptions("Sheet1", "E1", &ShapeOptions{Type: "ellipse", Width: 90, Height: 60}))
+	// Test the options of the caller not be changed by the default value.
+	assert.Equal(t, 0, opts.Width)
+	assert.Nil(t, opts.Format.FPrintsWithSheet)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddShapeWithOptions.xlsx")))
+
+	// Test add shape with invalid options.
+	for _, c := range []struct {
+		opts *ShapeOptions
+		err  string
+	}{
+		{nil, ErrParameterRequired.Error()},
+		{&ShapeOptions{}, `unsupported shape type ""`},
+		{&ShapeOptions{Type: "rect", Width: -1}, "invalid shape width -1, negative values are not supported"},
+		{&ShapeOptions{Type: "rect", Height: -1}, "invalid shape height -1, negative values are not supported"},
+		{&ShapeOptions{Type: "rect", Line: ShapeLine{Width: -1}}, "invalid shape line width -1, negative values are not supported"},
+		{&ShapeOptions{Type: "rect", Format: PictureOptions{Positioning: "none"}}, `unsupported positioning "none"`},
+	} {
+		assert.EqualError(t, f.AddShapeWithOptions("Sheet1", "A10", c.opts), c.err)
+	}
+	assert.EqualError(t, f.AddShape("Sheet1", "A10", `{"type":"rect","colour":{"fill":"#8EB9FF"}}`), `json: unknown field "colour"`)
+}
@@ -2,7 +2,6 @@ package xlsx
 
 import (
 	"bytes"
-	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -389,10 +388,10 @@ func (f *File) getActiveSheetID() int {
 }
 
 // SetSheetName provides a function to set the worksheet name by given old and
-// new worksheet names. Maximum 31 characters are allowed in sheet title and
-// this function only changes the name of the sheet and will not update the
-// sheet name in the formula or reference associated with the cell. So there
-// may be problem formula error or reference missing.
+// new worksheet names. Maximum 31 characters are allowed in sheet title. This
+// function will also update the worksheet name in the cell formulas, defined
+// names, hyperlinks, data validations, conditional formats, sparklines, chart
+// series and pivot caches which reference the worksheet.
 func (f *File) SetSheetName(oldName, newName string) {
 	oldName = trimSheetName(oldName)
 	newName = trimSheetName(newName)
@@ -405,10 +404,116 @@ func (f *File) SetSheetName(oldName, newName string) {
 			content.Sheets.Sheet[k].Name = newName
 			f.sheetMap[newName] = f.sheetMap[oldName]
 			delete(f.sheetMap, oldName)
+			f.renameSheetReferences(oldName, newName)
 		}
 	}
 }
 
+// renameSheetReferences provides a function to update the worksheet name in
+// all references to the worksheet by given old and new worksheet names.
+func (f *File) renameSheetReferences(oldName, newName string) {
+	rename := func(formula string) string {
+		return renameSheetInFormula(formula, oldName, newName)
+	}
+	for _, name := range f.GetSheetList() {
+		ws, err := f.workSheetReader(name)
+		if err != nil {
+			continue
+		}
+		replaceWorksheetFormulas(ws, rename)
+	}
+	if wb := f.workbookReader(); wb.DefinedNames != nil {
+		for idx := range wb.DefinedNames.DefinedName {
+			wb.DefinedNames.DefinedName[idx].Data = rename(wb.DefinedNames.DefinedName[idx].Data)
+		}
+	}
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(newName))
+	f.Pkg.Range(func(k, v interface{}) bool {
+		path := k.(string)
+		if strings.HasPrefix(path, "xl/charts/chart") {
+			f.Pkg.Store(path, []byte(replaceXMLFormulas(string(v.([]byte)), rename)))
+		}
+		if strings.HasPrefix(path, "xl/pivotCache/pivotCacheDefinition") {
+			f.Pkg.Store(path, pivotSourcePattern.ReplaceAllFunc(v.([]byte), func(tag []byte) []byte {
+				matches := xmlSheetAttrPattern.FindSubmatch(tag)
+				var name string
+				if matches == nil || xml.Unmarshal([]byte("<s>"+string(matches[1])+"</s>"), &name) != nil ||
+					!strings.EqualFold(name, oldName) {
+					return tag
+				}
+				return xmlSheetAttrPattern.ReplaceAll(tag, []byte(` sheet="`+buf.String()+`"`))
+			}))
+		}
+		return true
+	})
+}
+
+// replaceWorksheetFormulas provides a function to update the formulas of the
+// cells, the locations of the hyperlinks, the formulas of the data
+// validations, conditional formats and the extension list in the worksheet by
+// given replace function.
+func replaceWorksheetFormulas(ws *xlsxWorksheet, fn func(formula string) string) {
+	for rowIdx := range ws.SheetData.Row {
+		for colIdx := range ws.SheetData.Row[rowIdx].C {
+			if formula := ws.SheetData.Row[rowIdx].C[colIdx].F; formula != nil && formula.Content != "" {
+				formula.Content = fn(formula.Content)
+			}
+		}
+	}
+	if ws.Hyperlinks != nil {
+		for idx := range ws.Hyperlinks.Hyperlink {
+			if location := ws.Hyperlinks.Hyperlink[idx].Location; location != "" {
+				ws.Hyperlinks.Hyperlink[idx].Location = fn(location)
+			}
+		}
+	}
+	if ws.DataValidations != nil {
+		for _, dv := range ws.DataValidations.DataValidation {
+			dv.Formula1 = replaceXMLFormulas(dv.Formula1, fn)
+			dv.Formula2 = replaceXMLFormulas(dv.Formula2, fn)
+		}
+	}
+	for _, cf := range ws.ConditionalFormatting {
+		for _, rule := range cf.CfRule {
+			for idx := range rule.Formula {
+				rule.Formula[idx] = fn(rule.Formula[idx])
+			}
+		}
+	}
+	if ws.ExtLst != nil {
+		ws.ExtLst.Ext = replaceXMLFormulas(ws.ExtLst.Ext, fn)
+	}
+}
+
+// renameSheetInFormula provides a function to update the worksheet name in
+// the references of the formula by given old and new worksheet names, the
+// new worksheet name will be quoted if it contains spaces or special
+// characters.
+func renameSheetInFormula(formula, oldName, newName string) string {
+	return replaceFormulaRefs(formula, func(sheet, ref string) (string, bool) {
+		if sheet == "" {
+			return ref, false
+		}
+		var renamed, quoted bool
+		names := strings.Split(unquoteSheetName(sheet), ":")
+		for idx := range names {
+			if strings.EqualFold(names[idx], oldName) {
+				names[idx], renamed = newName, true
+			}
+			quoted = quoted || quoteSheetName(names[idx]) != names[idx]
+		}
+		if !renamed {
+			return ref, false
+		}
+		name := strings.Join(names, ":")
+		if quoted {
+			name = quoteSheetName(name)
+		}
+		return name + "!" + ref, true
+	})
+}
+
 // GetSheetName provides a function to get the sheet name of the workbook by
 // the given sheet index. If the given sheet index is invalid, it will return
 // an empty string.
@@ -618,8 +723,11 @@ func (f *File) deleteSheetFromContentTypes(target string) {
 }
 
 // CopySheet provides a function to duplicate a worksheet by gave source and
-// target worksheet index. Note that currently doesn't support duplicate
-// workbooks that contain tables, charts or pictures. For Example:
+// target worksheet index. The drawings with the charts and pictures,
+// comments, tables, sheet-scoped defined names and print settings of the
+// source worksheet will be duplicated, and the duplicated tables will be
+// renamed with a numeric suffix. Note that the pivot tables in the source
+// worksheet will not be duplicated. For Example:
 //
 //    // Sheet1 already exists...
 //    index := f.NewSheet("Sheet2")
@@ -637,29 +745,172 @@ func (f *File) CopySheet(from, to int) error {
 // target worksheet name.
 func (f *File) copySheet(from, to int) error {
 	fromSheet := f.GetSheetName(from)
-	sheet, err := f.workSheetReader(fromSheet)
+	ws, err := f.workSheetReader(fromSheet)
 	if err != nil {
 		return err
 	}
-	worksheet := deepcopy.Copy(sheet).(*xlsxWorksheet)
-	toSheetID := strconv.Itoa(f.getSheetID(f.GetSheetName(to)))
-	path := "xl/worksheets/sheet" + toSheetID + ".xml"
-	if len(worksheet.SheetViews.SheetView) > 0 {
-		worksheet.SheetViews.SheetView[0].TabSelected = false
-	}
-	worksheet.Drawing = nil
-	worksheet.TableParts = nil
-	worksheet.PageSetUp = nil
-	f.Sheet.Store(path, worksheet)
-	toRels := "xl/worksheets/_rels/sheet" + toSheetID + ".xml.rels"
-	fromRels := "xl/worksheets/_rels/sheet" + strconv.Itoa(f.getSheetID(fromSheet)) + ".xml.rels"
-	if rels, ok := f.Pkg.Load(fromRels); ok && rels != nil {
-		f.Pkg.Store(toRels, rels.([]byte))
-	}
-	fromSheetXMLPath := f.sheetMap[trimSheetName(fromSheet)]
-	fromSheetAttr := f.xmlAttr[fromSheetXMLPath]
-	f.xmlAttr[path] = fromSheetAttr
-	return err
+	return newSheetCopier(f, f).copySheet(fromSheet, f.GetSheetName(to), ws)
+}
+
+// MoveSheet provides a function to move the worksheet to the given position
+// by given worksheet name and the new index of the worksheet. The local scope
+// of the defined names, the active and first visible worksheet of the
+// workbook views, the worksheet titles in the application properties and the
+// 3-D references in the formulas will be updated by the new order of the
+// worksheets. The 3-D reference will keep its endpoint worksheets, and the
+// endpoints will be swapped if the first worksheet is moved after the last
+// one. For example, move the worksheet named Sheet3 to the first position:
+//
+//    err := f.MoveSheet("Sheet3", 0)
+//
+func (f *File) MoveSheet(name string, index int) error {
+	from := f.GetSheetIndex(name)
+	if from == -1 {
+		return fmt.Errorf("sheet %s is not exist", name)
+	}
+	wb := f.workbookReader()
+	if index < 0 || index >= len(wb.Sheets.Sheet) {
+		return ErrSheetIdx
+	}
+	if from == index {
+		return nil
+	}
+	sheet := wb.Sheets.Sheet[from]
+	sheets := append(append([]xlsxSheet{}, wb.Sheets.Sheet[:from]...), wb.Sheets.Sheet[from+1:]...)
+	sheets = append(sheets[:index], append([]xlsxSheet{sheet}, sheets[index:]...)...)
+	idxMap, names := make(map[int]int), make([]string, 0, len(sheets))
+	for oldIdx, v := range wb.Sheets.Sheet {
+		for newIdx, s := range sheets {
+			if s.Name == v.Name {
+				idxMap[oldIdx] = newIdx
+			}
+		}
+	}
+	for _, v := range sheets {
+		names = append(names, v.Name)
+	}
+	wb.Sheets.Sheet = sheets
+	if wb.DefinedNames != nil {
+		for idx, dn := range wb.DefinedNames.DefinedName {
+			if dn.LocalSheetID != nil {
+				if localSheetID, ok := idxMap[*dn.LocalSheetID]; ok {
+					wb.DefinedNames.DefinedName[idx].LocalSheetID = intPtr(localSheetID)
+				}
+			}
+		}
+	}
+	if wb.BookViews != nil {
+		for idx, view := range wb.BookViews.WorkBookView {
+			if activeTab, ok := idxMap[view.ActiveTab]; ok {
+				wb.BookViews.WorkBookView[idx].ActiveTab = activeTab
+			}
+			if firstSheet, ok := idxMap[view.FirstSheet]; ok {
+				wb.BookViews.WorkBookView[idx].FirstSheet = firstSheet
+			}
+		}
+	}
+	f.moveAppTitlesOfParts(names)
+	f.moveSheetReferences(names)
+	return nil
+}
+
+var (
+	// appTitlesOfPartsPattern defined the pattern of the titles of parts in
+	// the application properties.
+	appTitlesOfPartsPattern = regexp.MustCompile(`(?s)<TitlesOfParts>.*?</TitlesOfParts>`)
+	// appTitlePattern defined the pattern of the title in the titles of parts.
+	appTitlePattern = regexp.MustCompile(`(<vt:lpstr>)([^<]*)(</vt:lpstr>)`)
+)
+
+// moveAppTitlesOfParts provides a function to reorder the worksheet titles
+// in the titles of parts of the application properties by given worksheet
+// names in the new order.
+func (f *File) moveAppTitlesOfParts(names []string) {
+	content := f.readXML("docProps/app.xml")
+	if len(content) == 0 {
+		return
+	}
+	escaped, sheets := make(map[string]bool), make([]string, 0, len(names))
+	for _, name := range names {
+		var buf bytes.Buffer
+		_ = xml.EscapeText(&buf, []byte(name))
+		escaped[buf.String()] = true
+		sheets = append(sheets, buf.String())
+	}
+	f.Pkg.Store("docProps/app.xml", appTitlesOfPartsPattern.ReplaceAllFunc(content, func(titles []byte) []byte {
+		exists := make(map[string]bool)
+		for _, matches := range appTitlePattern.FindAllSubmatch(titles, -1) {
+			exists[string(matches[2])] = true
+		}
+		var ordered []string
+		for _, name := range sheets {
+			if exists[name] {
+				ordered = append(ordered, name)
+			}
+		}
+		var idx int
+		return appTitlePattern.ReplaceAllFunc(titles, func(title []byte) []byte {
+			matches := appTitlePattern.FindSubmatch(title)
+			if !escaped[string(matches[2])] || idx >= len(ordered) {
+				return title
+			}
+			idx++
+			return []byte(string(matches[1]) + ordered[idx-1] + string(matches[3]))
+		})
+	}))
+}
+
+// moveSheetReferences provides a function to update the 3-D references in
+// the formulas of the worksheets, defined names and charts by given
+// worksheet names in the new order.
+func (f *File) moveSheetReferences(names []string) {
+	order := make(map[string]int)
+	for idx, name := range names {
+		order[strings.ToLower(name)] = idx
+	}
+	reorder := func(formula string) string {
+		return reorderSheetRangeInFormula(formula, order)
+	}
+	for _, name := range names {
+		ws, err := f.workSheetReader(name)
+		if err != nil {
+			continue
+		}
+		replaceWorksheetFormulas(ws, reorder)
+	}
+	if wb := f.workbookReader(); wb.DefinedNames != nil {
+		for idx := range wb.DefinedNames.DefinedName {
+			wb.DefinedNames.DefinedName[idx].Data = reorder(wb.DefinedNames.DefinedName[idx].Data)
+		}
+	}
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if strings.HasPrefix(k.(string), "xl/charts/chart") {
+			f.Pkg.Store(k, []byte(replaceXMLFormulas(string(v.([]byte)), reorder)))
+		}
+		return true
+	})
+}
+
+// reorderSheetRangeInFormula provides a function to swap the endpoint
+// worksheets of the 3-D references in the formula if the first worksheet is
+// after the last one by given order of the worksheets.
+func reorderSheetRangeInFormula(formula string, order map[string]int) string {
+	return replaceFormulaRefs(formula, func(sheet, ref string) (string, bool) {
+		names := strings.Split(unquoteSheetName(sheet), ":")
+		if len(names) != 2 {
+			return ref, false
+		}
+		first, ok1 := order[strings.ToLower(names[0])]
+		last, ok2 := order[strings.ToLower(names[1])]
+		if !ok1 || !ok2 || first <= last {
+			return ref, false
+		}
+		name := names[1] + ":" + names[0]
+		if strings.HasPrefix(sheet, "'") {
+			name = quoteSheetName(name)
+		}
+		return name + "!" + ref, true
+	})
 }
 
 // SetSheetVisible provides a function to set worksheet visible by given worksheet
@@ -708,11 +959,33 @@ func (f *File) SetSheetVisible(name string, visible bool) error {
 	return nil
 }
 
-// parseFormatPanesSet provides a function to parse the panes settings.
-func parseFormatPanesSet(formatSet string) (*formatPanes, error) {
-	format := formatPanes{}
-	err := json.Unmarshal([]byte(formatSet), &format)
-	return &format, err
+// parsePaneOptions provides a function to validate the panes settings.
+func parsePaneOptions(opts *PaneOptions) (*PaneOptions, error) {
+	var format PaneOptions
+	if opts != nil {
+		format = *opts
+	}
+	panes := []string{"", "bottomLeft", "bottomRight", "topLeft", "topRight"}
+	if inStrSlice(panes, format.ActivePane) == -1 {
+		return &format, newUnsupportedOptionError("active pane", format.ActivePane)
+	}
+	if format.XSplit < 0 {
+		return &format, newNegativeOptionError("x_split", float64(format.XSplit))
+	}
+	if format.YSplit < 0 {
+		return &format, newNegativeOptionError("y_split", float64(format.YSplit))
+	}
+	if format.TopLeftCell != "" {
+		if _, _, err := CellNameToCoordinates(format.TopLeftCell); err != nil {
+			return &format, err
+		}
+	}
+	for _, p := range format.Panes {
+		if inStrSlice(panes, p.Pane) == -1 {
+			return &format, newUnsupportedOptionError("pane", p.Pane)
+		}
+	}
+	return &format, nil
 }
 
 // SetPanes provides a function to create and remove freeze panes and split panes
@@ -801,7 +1074,34 @@ func parseFormatPanesSet(formatSet string) (*formatPanes, error) {
 //    f.SetPanes("Sheet1", `{"freeze":false,"split":false}`)
 //
 func (f *File) SetPanes(sheet, panes string) error {
-	fs, _ := parseFormatPanesSet(panes)
+	var fs PaneOptions
+	if err := unmarshalFormatSet(parseFormatSet(panes), &fs); err != nil {
+		return err
+	}
+	return f.SetPanesWithOptions(sheet, &fs)
+}
+
+// SetPanesWithOptions provides a function to create and remove freeze panes
+// and split panes by given worksheet name and panes options. The settings
+// are the same as the SetPanes function, for example freeze the first row in
+// the Sheet1:
+//
+//    err := f.SetPanesWithOptions("Sheet1", &xlsx.PaneOptions{
+//        Freeze:      true,
+//        YSplit:      1,
+//        TopLeftCell: "A2",
+//        ActivePane:  "bottomLeft",
+//    })
+//
+// An example of how to unfreeze and remove all panes on Sheet1:
+//
+//    err := f.SetPanesWithOptions("Sheet1", &xlsx.PaneOptions{})
+//
+func (f *File) SetPanesWithOptions(sheet string, opts *PaneOptions) error {
+	fs, err := parsePaneOptions(opts)
+	if err != nil {
+		return err
+	}
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
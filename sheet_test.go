@@ -105,6 +105,36 @@ func TestSetPane(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetPane.xlsx")))
 }
 
+func TestSetPanesWithOptions(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetPanesWithOptions("Sheet1", &PaneOptions{
+		Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft",
+		Panes: []PaneSelection{{SQRef: "A2", ActiveCell: "A2", Pane: "bottomLeft"}},
+	}))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &xlsxPane{State: "frozen", YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}, ws.SheetViews.SheetView[0].Pane)
+	assert.Equal(t, []*xlsxSelection{{SQRef: "A2", ActiveCell: "A2", Pane: "bottomLeft"}}, ws.SheetViews.SheetView[0].Selection)
+	// Test remove all panes.
+	assert.NoError(t, f.SetPanesWithOptions("Sheet1", nil))
+	assert.Nil(t, ws.SheetViews.SheetView[0].Pane)
+
+	// Test set panes with invalid options.
+	for _, c := range []struct {
+		opts *PaneOptions
+		err  string
+	}{
+		{&PaneOptions{ActivePane: "bottom"}, `unsupported active pane "bottom"`},
+		{&PaneOptions{Split: true, XSplit: -1}, "invalid x_split -1, negative values are not supported"},
+		{&PaneOptions{Split: true, YSplit: -1}, "invalid y_split -1, negative values are not supported"},
+		{&PaneOptions{Freeze: true, TopLeftCell: "A"}, `cannot convert cell "A" to coordinates: invalid cell name "A"`},
+		{&PaneOptions{Panes: []PaneSelection{{SQRef: "A1", Pane: "left"}}}, `unsupported pane "left"`},
+	} {
+		assert.EqualError(t, f.SetPanesWithOptions("Sheet1", c.opts), c.err)
+	}
+	assert.EqualError(t, f.SetPanes("Sheet1", `{"freeze":true,"ysplit":1}`), `json: unknown field "ysplit"`)
+}
+
 func TestPageLayoutOption(t *testing.T) {
 	const sheet = "Sheet1"
 
@@ -377,6 +407,97 @@ func TestSetSheetName(t *testing.T) {
 	// Test set workksheet with the same name.
 	f.SetSheetName("Sheet1", "Sheet1")
 	assert.Equal(t, "Sheet1", f.GetSheetName(0))
+
+	// Test rename worksheet and update the references to the worksheet.
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", `SUM(Sheet1!A1:A3)+'Sheet1'!B1+sheet1!C1+Sheet10!A1+"Sheet1!A1"`))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A4", "SUM(A1:A3)+Sheet1:Sheet2!B1"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet2", "B1", "Sheet1!A1", "Location"))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$1:$A$3"}))
+	dv := NewDataValidation(true)
+	dv.Sqref = "C1"
+	assert.NoError(t, dv.SetSqrefDropList("Sheet1!$A$1:$A$3", true))
+	assert.NoError(t, f.AddDataValidation("Sheet2", dv))
+	assert.NoError(t, f.AddSparkline("Sheet2", &SparklineOption{Location: []string{"D1"}, Range: []string{"Sheet1!A1:A3"}}))
+	assert.NoError(t, f.AddChart("Sheet2", "E1", `{"type":"col","series":[{"name":"Sheet1!$A$1","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`))
+	for row := 1; row <= 3; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(row), &[]interface{}{"Month", "Sales"}))
+	}
+	assert.NoError(t, f.AddPivotTable(&PivotTableOption{
+		DataRange:       "Sheet1!$A$1:$B$3",
+		PivotTableRange: "Sheet2!$G$2:$H$10",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Data:            []PivotTableField{{Data: "Sales", Subtotal: "Sum"}},
+	}))
+
+	f.SetSheetName("Sheet1", "Q1 'Sales'")
+	formula, err := f.GetCellFormula("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, `SUM('Q1 ''Sales'''!A1:A3)+'Q1 ''Sales'''!B1+'Q1 ''Sales'''!C1+Sheet10!A1+"Sheet1!A1"`, formula)
+	formula, err = f.GetCellFormula("Q1 'Sales'", "A4")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(A1:A3)+'Q1 ''Sales'':Sheet2'!B1", formula)
+	_, target, err := f.GetCellHyperLink("Sheet2", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "'Q1 ''Sales'''!A1", target)
+	assert.Equal(t, "'Q1 ''Sales'''!$A$1:$A$3", f.GetDefinedName()[0].RefersTo)
+	ws, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, "<formula1>&#39;Q1 &#39;&#39;Sales&#39;&#39;&#39;!$A$1:$A$3</formula1>", ws.DataValidations.DataValidation[0].Formula1)
+	assert.Contains(t, ws.ExtLst.Ext, "<xm:f>&#39;Q1 &#39;&#39;Sales&#39;&#39;&#39;!A1:A3</xm:f>")
+	content, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), "<f>&#39;Q1 &#39;&#39;Sales&#39;&#39;&#39;!$B$2:$D$2</f>")
+	content, ok = f.Pkg.Load("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `sheet="Q1 &#39;Sales&#39;"`)
+
+	// Test rename worksheet with the name which looks like a cell reference.
+	f.SetSheetName("Sheet2", "AB12")
+	formula, err = f.GetCellFormula("Q1 'Sales'", "A4")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(A1:A3)+'Q1 ''Sales'':AB12'!B1", formula)
+	assert.Equal(t, "'AB12'", quoteSheetName("AB12"))
+	assert.Equal(t, "'R1C1'", quoteSheetName("R1C1"))
+	assert.Equal(t, "'2021'", quoteSheetName("2021"))
+	assert.Equal(t, "Sales_2021", quoteSheetName("Sales_2021"))
+}
+
+func TestMoveSheet(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	f.NewSheet("Sheet 3")
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "SUM(Sheet1:Sheet2!A1)"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A2", "SUM('Sheet1:Sheet 3'!A1,Sheet1!A1)"))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$1", Scope: "Sheet1"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "SUM('Sheet1:Sheet 3'!$A$1)", Scope: "Workbook"}))
+	f.SetActiveSheet(1)
+	f.Pkg.Store("docProps/app.xml", []byte(`<Properties><TitlesOfParts><vt:vector size="4" baseType="lpstr"><vt:lpstr>Sheet1</vt:lpstr><vt:lpstr>Sheet2</vt:lpstr><vt:lpstr>Sheet 3</vt:lpstr><vt:lpstr>Total</vt:lpstr></vt:vector></TitlesOfParts></Properties>`))
+
+	assert.NoError(t, f.MoveSheet("Sheet1", 2))
+	assert.Equal(t, []string{"Sheet2", "Sheet 3", "Sheet1"}, f.GetSheetList())
+	assert.Equal(t, 0, f.GetActiveSheetIndex())
+	formula, err := f.GetCellFormula("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(Sheet2:Sheet1!A1)", formula)
+	formula, err = f.GetCellFormula("Sheet2", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM('Sheet 3:Sheet1'!A1,Sheet1!A1)", formula)
+	assert.Equal(t, []DefinedName{
+		{Name: "Amount", RefersTo: "Sheet1!$A$1", Scope: "Sheet1"},
+		{Name: "Total", RefersTo: "SUM('Sheet 3:Sheet1'!$A$1)", Scope: "Workbook"},
+	}, f.GetDefinedName())
+	app, ok := f.Pkg.Load("docProps/app.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(app.([]byte)), "<vt:lpstr>Sheet2</vt:lpstr><vt:lpstr>Sheet 3</vt:lpstr><vt:lpstr>Sheet1</vt:lpstr><vt:lpstr>Total</vt:lpstr>")
+
+	// Test move worksheet to the same position.
+	assert.NoError(t, f.MoveSheet("Sheet1", 2))
+	assert.Equal(t, []string{"Sheet2", "Sheet 3", "Sheet1"}, f.GetSheetList())
+	// Test move worksheet with invalid parameters.
+	assert.EqualError(t, f.MoveSheet("SheetN", 0), "sheet SheetN is not exist")
+	assert.EqualError(t, f.MoveSheet("Sheet1", 3), ErrSheetIdx.Error())
+	assert.EqualError(t, f.MoveSheet("Sheet1", -1), ErrSheetIdx.Error())
 }
 
 func TestGetWorkbookPath(t *testing.T) {
@@ -0,0 +1,434 @@
+package xlsx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mohae/deepcopy"
+)
+
+// SortKey directly maps the sort condition of a column when sorting a range
+// of cells.
+//
+// Col specifies the column name of the sort key, such as "B", the column
+// must be in the sort range.
+//
+// Descending specifies if sort the values from largest to smallest, the
+// values will be sorted from smallest to largest by default.
+//
+// CaseSensitive specifies if the text values will be compared with case
+// sensitive, the lowercase letters will be sorted before the uppercase
+// letters in ascending order.
+//
+// CustomList specifies the custom order of the text values, the values in
+// the list will be sorted by the order of the list, and will be sorted
+// before the values which not in the list.
+type SortKey struct {
+	Col           string
+	Descending    bool
+	CaseSensitive bool
+	CustomList    []string
+}
+
+// SortOptions directly maps the settings of sorting a range of cells.
+//
+// Header specifies if the first row of the range is the header row, which
+// will not be sorted.
+//
+// SortState specifies if record the sort conditions on the table or auto
+// filter which contains the sort range, the sort conditions will be recorded
+// on the worksheet if there is no such table or auto filter.
+type SortOptions struct {
+	Header    bool
+	SortState bool
+}
+
+// sortValue directly maps the value of the cell for comparing when sorting a
+// range of cells, the kind of the value is in the order of numbers, text,
+// booleans, errors and blanks.
+type sortValue struct {
+	kind int
+	num  float64
+	text string
+}
+
+// Sort value kinds enumeration.
+const (
+	sortValueNumber = iota
+	sortValueText
+	sortValueBool
+	sortValueError
+	sortValueBlank
+)
+
+// SortRange provides a function to sort the rows of the range by given
+// worksheet name, range reference, sort keys and sort options. The cells are
+// reordered in place with the styles, the relative references of the
+// formulas will be adjusted by the offset of the rows, and the merged cells
+// in a single row will be moved with the row. The values are compared in the
+// order of numbers, text, booleans and errors, the blank cells are always
+// sorted at the end. For example, sort range A1:D10 on Sheet1 with the
+// header row by column B in descending order, and then by column C with
+// custom order:
+//
+//    err := f.SortRange("Sheet1", "A1:D10", []xlsx.SortKey{
+//        {Col: "B", Descending: true},
+//        {Col: "C", CustomList: []string{"Low", "Medium", "High"}},
+//    }, &xlsx.SortOptions{Header: true})
+//
+func (f *File) SortRange(sheet, rangeRef string, keys []SortKey, opts *SortOptions) error {
+	var options SortOptions
+	if opts != nil {
+		options = *opts
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	rect, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return ErrParameterRequired
+	}
+	cols := make([]int, len(keys))
+	for idx, key := range keys {
+		if cols[idx], err = ColumnNameToNumber(key.Col); err != nil {
+			return err
+		}
+		if cols[idx] < rect[0] || cols[idx] > rect[2] {
+			return ErrSortKeyColumn
+		}
+	}
+	if options.Header {
+		rect[1]++
+	}
+	if options.SortState && rect[1] <= rect[3] {
+		if err = f.setSortState(sheet, ws, rect, keys, cols); err != nil {
+			return err
+		}
+	}
+	// The rows after the last row of the worksheet are blank rows which will
+	// be kept at the end of the range.
+	if rect[3] > len(ws.SheetData.Row) {
+		rect[3] = len(ws.SheetData.Row)
+	}
+	if rect[1] >= rect[3] {
+		return nil
+	}
+	mergeCells, err := sortRangeMergeCells(ws, rect)
+	if err != nil {
+		return err
+	}
+	unshareFormulas(ws, rect)
+	return f.sortRows(sheet, ws, rect, keys, cols, mergeCells)
+}
+
+// sortRows provides a function to reorder the rows of the range by given
+// sort keys, the columns of the sort keys and the merged cells in each row.
+func (f *File) sortRows(sheet string, ws *xlsxWorksheet, rect []int, keys []SortKey, cols []int, mergeCells map[int][]*xlsxMergeCell) error {
+	sst := f.sharedStringsReader()
+	width, height := rect[2]-rect[0]+1, rect[3]-rect[1]+1
+	cells, values := make([][]xlsxC, height), make([][]sortValue, height)
+	order := make([]int, height)
+	for idx := range cells {
+		row := rect[1] + idx
+		order[idx], cells[idx] = idx, make([]xlsxC, width)
+		for col := rect[0]; col <= rect[2] && col <= len(ws.SheetData.Row[row-1].C); col++ {
+			cells[idx][col-rect[0]] = deepcopy.Copy(ws.SheetData.Row[row-1].C[col-1]).(xlsxC)
+		}
+		values[idx] = make([]sortValue, len(keys))
+		for i, col := range cols {
+			value, err := f.getSortValue(&cells[idx][col-rect[0]], sst)
+			if err != nil {
+				return err
+			}
+			values[idx][i] = value
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		for idx, key := range keys {
+			if result := compareSortValues(values[order[i]][idx], values[order[j]][idx], key); result != 0 {
+				return result < 0
+			}
+		}
+		return false
+	})
+	sheetID, rowMap := f.getSheetID(sheet), make(map[int]int)
+	for idx, srcIdx := range order {
+		if idx == srcIdx {
+			continue
+		}
+		row, dRow := rect[1]+idx, idx-srcIdx
+		rowMap[rect[1]+srcIdx] = row
+		for colIdx := range cells[srcIdx] {
+			cell, col := cells[srcIdx][colIdx], rect[0]+colIdx
+			if col > len(ws.SheetData.Row[row-1].C) {
+				if !cell.hasValue() && cell.IS == nil {
+					continue
+				}
+				prepareSheetXML(ws, col, row)
+			}
+			dst := &ws.SheetData.Row[row-1].C[col-1]
+			if cell.F != nil {
+				cell.F.Content = shiftFormulaRefs(cell.F.Content, 0, dRow)
+				if cell.F.Ref != "" {
+					cell.F.Ref = shiftFormulaRefs(cell.F.Ref, 0, dRow)
+				}
+			} else if dst.F != nil {
+				f.deleteCalcChain(sheetID, dst.R)
+			}
+			cell.R = dst.R
+			*dst = cell
+		}
+		for _, mergeCell := range mergeCells[rect[1]+srcIdx] {
+			mergeCell.rect[1], mergeCell.rect[3] = row, row
+			mergeCell.Ref, _ = f.coordinatesToAreaRef(mergeCell.rect)
+		}
+	}
+	sortHyperlinks(ws, rect, rowMap)
+	f.sortComments(ws, sheet, rect, rowMap)
+	return nil
+}
+
+// sortHyperlinks provides a function to move the hyperlinks of the cells in
+// the sort range with the rows by given map of the source and destination
+// rows, the hyperlinks across multiple rows or partially in the range will
+// be kept.
+func sortHyperlinks(ws *xlsxWorksheet, rect []int, rowMap map[int]int) {
+	if ws.Hyperlinks == nil {
+		return
+	}
+	for i := range ws.Hyperlinks.Hyperlink {
+		link := &ws.Hyperlinks.Hyperlink[i]
+		cells := strings.Split(link.Ref, ":")
+		coordinates, err := areaRangeToCoordinates(cells[0], cells[len(cells)-1])
+		if err != nil {
+			continue
+		}
+		_ = sortCoordinates(coordinates)
+		row, ok := rowMap[coordinates[1]]
+		if !ok || coordinates[1] != coordinates[3] || coordinates[0] < rect[0] || coordinates[2] > rect[2] {
+			continue
+		}
+		for j := range cells {
+			col, _, _ := CellNameToCoordinates(cells[j])
+			cells[j], _ = CoordinatesToCellName(col, row)
+		}
+		link.Ref = strings.Join(cells, ":")
+	}
+}
+
+// sortComments provides a function to move the comments of the cells in the
+// sort range and the anchors of the comment shapes with the rows by given map
+// of the source and destination rows.
+func (f *File) sortComments(ws *xlsxWorksheet, sheet string, rect []int, rowMap map[int]int) {
+	moveTo := func(col, row int) (int, bool) {
+		dst, ok := rowMap[row]
+		return dst, ok && col >= rect[0] && col <= rect[2]
+	}
+	if commentsXML := f.getSheetCommentsXML(f.sheetMap[trimSheetName(sheet)]); commentsXML != "" {
+		if comments := f.commentsReader(commentsXML); comments != nil {
+			for i := range comments.CommentList.Comment {
+				cmt := &comments.CommentList.Comment[i]
+				col, row, err := CellNameToCoordinates(cmt.Ref)
+				if dst, ok := moveTo(col, row); err == nil && ok {
+					cmt.Ref, _ = CoordinatesToCellName(col, dst)
+				}
+			}
+		}
+	}
+	f.replaceVMLShapes(ws, sheet, func(shape string) (string, bool) {
+		col, row := -1, -1
+		for _, matches := range vmlClientDataPattern.FindAllStringSubmatch(shape, -1) {
+			if idx, err := strconv.Atoi(matches[3]); err == nil && matches[2] == "Row" {
+				row = idx
+			} else if err == nil {
+				col = idx
+			}
+		}
+		if dst, ok := moveTo(col+1, row+1); ok {
+			// Shift all rows of the shape, the attached row will not be
+			// deleted since the destination row is in the worksheet.
+			return adjustVMLShape(shape, rows, 1, dst-row-1)
+		}
+		return shape, true
+	})
+}
+
+// sortRangeMergeCells returns the merged cells in the sort range grouped by
+// the rows, the merged cells across multiple rows or partially in the range
+// can't be sorted.
+func sortRangeMergeCells(ws *xlsxWorksheet, rect []int) (map[int][]*xlsxMergeCell, error) {
+	mergeCells := make(map[int][]*xlsxMergeCell)
+	if ws.MergeCells == nil {
+		return mergeCells, nil
+	}
+	for _, mergeCell := range ws.MergeCells.Cells {
+		if mergeCell == nil {
+			continue
+		}
+		coordinates, err := areaRefToCoordinates(mergeCell.Ref)
+		if err != nil {
+			return mergeCells, err
+		}
+		_ = sortCoordinates(coordinates)
+		if !isOverlap(coordinates, rect) {
+			continue
+		}
+		if coordinates[1] != coordinates[3] || coordinates[0] < rect[0] || coordinates[2] > rect[2] {
+			return mergeCells, ErrSortMergedCells
+		}
+		mergeCell.rect = coordinates
+		mergeCells[coordinates[1]] = append(mergeCells[coordinates[1]], mergeCell)
+	}
+	return mergeCells, nil
+}
+
+// getSortValue provides a function to get the value of the cell for
+// comparing when sorting a range of cells.
+func (f *File) getSortValue(c *xlsxC, sst *xlsxSST) (sortValue, error) {
+	if c.V == "" && c.IS == nil {
+		return sortValue{kind: sortValueBlank}, nil
+	}
+	switch c.T {
+	case "b":
+		value := sortValue{kind: sortValueBool}
+		if c.V == "1" {
+			value.num = 1
+		}
+		return value, nil
+	case "e":
+		return sortValue{kind: sortValueError}, nil
+	case "s", "str", "inlineStr":
+		text, err := c.getValueFrom(f, sst, true)
+		return sortValue{kind: sortValueText, text: text}, err
+	}
+	if num, err := strconv.ParseFloat(c.V, 64); err == nil {
+		return sortValue{kind: sortValueNumber, num: num}, nil
+	}
+	return sortValue{kind: sortValueText, text: c.V}, nil
+}
+
+// compareSortValues provides a function to compare two values of the cells
+// by given sort key, it returns a negative number if the first value should
+// be sorted before the second one, a positive number if after, and 0 if the
+// order should be kept.
+func compareSortValues(a, b sortValue, key SortKey) int {
+	if a.kind == sortValueBlank || b.kind == sortValueBlank {
+		return a.kind/sortValueBlank - b.kind/sortValueBlank
+	}
+	result := a.kind - b.kind
+	if result == 0 {
+		switch a.kind {
+		case sortValueNumber, sortValueBool:
+			if a.num < b.num {
+				result = -1
+			} else if a.num > b.num {
+				result = 1
+			}
+		case sortValueText:
+			result = compareSortText(a.text, b.text, key)
+		}
+	}
+	if key.Descending {
+		return -result
+	}
+	return result
+}
+
+// compareSortText provides a function to compare two text values by given
+// sort key, the text in the custom list will be compared by the position in
+// the list.
+func compareSortText(a, b string, key SortKey) int {
+	if len(key.CustomList) > 0 {
+		aIdx, bIdx := -1, -1
+		for idx, item := range key.CustomList {
+			if aIdx == -1 && strings.EqualFold(item, a) {
+				aIdx = idx
+			}
+			if bIdx == -1 && strings.EqualFold(item, b) {
+				bIdx = idx
+			}
+		}
+		switch {
+		case aIdx != -1 && bIdx != -1:
+			return aIdx - bIdx
+		case aIdx != -1:
+			return -1
+		case bIdx != -1:
+			return 1
+		}
+	}
+	if result := strings.Compare(strings.ToLower(a), strings.ToLower(b)); result != 0 || !key.CaseSensitive {
+		return result
+	}
+	// Swap the case of the letters so that the lowercase letters will be
+	// sorted before the uppercase letters.
+	swapCase := func(r rune) rune {
+		if unicode.IsUpper(r) {
+			return unicode.ToLower(r)
+		}
+		return unicode.ToUpper(r)
+	}
+	return strings.Compare(strings.Map(swapCase, a), strings.Map(swapCase, b))
+}
+
+// setSortState provides a function to record the sort conditions on the
+// table or auto filter which contains the sort range, or on the worksheet if
+// there is no such table or auto filter.
+func (f *File) setSortState(sheet string, ws *xlsxWorksheet, rect []int, keys []SortKey, cols []int) error {
+	ref, err := f.coordinatesToAreaRef(rect)
+	if err != nil {
+		return err
+	}
+	sortState := &xlsxSortState{Ref: ref}
+	for idx, key := range keys {
+		condition := &xlsxSortCondition{Descending: key.Descending, CustomList: strings.Join(key.CustomList, ",")}
+		if condition.Ref, err = f.coordinatesToAreaRef([]int{cols[idx], rect[1], cols[idx], rect[3]}); err != nil {
+			return err
+		}
+		sortState.CaseSensitive = sortState.CaseSensitive || key.CaseSensitive
+		sortState.SortCondition = append(sortState.SortCondition, condition)
+	}
+	if ws.TableParts != nil {
+		for _, tablePart := range ws.TableParts.TableParts {
+			tableXML := strings.Replace(f.getSheetRelationshipsTargetByID(sheet, tablePart.RID), "..", "xl", -1)
+			content, ok := f.Pkg.Load(tableXML)
+			if !ok {
+				continue
+			}
+			t := xlsxTable{}
+			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte)))).
+				Decode(&t); err != nil && err != io.EOF {
+				return err
+			}
+			if t.AutoFilter == nil || !sortRangeInRef(rect, t.Ref) {
+				continue
+			}
+			t.AutoFilter.SortState = sortState
+			table, _ := xml.Marshal(t)
+			f.saveFileList(tableXML, table)
+			return nil
+		}
+	}
+	if ws.AutoFilter != nil && sortRangeInRef(rect, ws.AutoFilter.Ref) {
+		ws.AutoFilter.SortState = sortState
+		return nil
+	}
+	ws.SortState = sortState
+	return nil
+}
+
+// sortRangeInRef returns if the sort range is inside the given range
+// reference.
+func sortRangeInRef(rect []int, ref string) bool {
+	coordinates, err := rangeRefToCoordinates(ref)
+	return err == nil && cellInRef(rect[:2], coordinates) && cellInRef(rect[2:], coordinates)
+}
@@ -0,0 +1,128 @@
+package xlsx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortRange(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Name", "Value", "Level"},
+		{"b", 3, "High"},
+		{"A", true, "Low"},
+		{"a", nil, "Medium"},
+		{"C", "text", "Low"},
+		{"B", 1, "High"},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+1), &row))
+	}
+	assert.NoError(t, f.SetCellValue("Sheet1", "B7", 2))
+	assert.NoError(t, f.SetCellStr("Sheet1", "A7", "d"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D2", "B2*2+$B$1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D3", "SUM(Tbl5[Col])+B3"))
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A6", "A6", style))
+	assert.NoError(t, f.MergeCell("Sheet1", "E4", "F4"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A2", "https://github.com/carmel/xlsx", "External"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "H2", "Sheet1!A1", "Location"))
+	assert.NoError(t, f.AddComment("Sheet1", "A3", "Author", "Comment"))
+
+	getColumn := func(col string) string {
+		var values []string
+		for row := 1; row <= 7; row++ {
+			val, err := f.GetCellValue("Sheet1", fmt.Sprintf("%s%d", col, row))
+			assert.NoError(t, err)
+			values = append(values, val)
+		}
+		return strings.Join(values, ",")
+	}
+
+	// Test sort range by numbers, text, booleans and blanks with header row.
+	assert.NoError(t, f.SortRange("Sheet1", "A1:F7", []SortKey{{Col: "B"}}, &SortOptions{Header: true}))
+	assert.Equal(t, "Name,B,d,b,C,A,a", getColumn("A"))
+	assert.Equal(t, "Value,1,2,3,text,1,", getColumn("B"))
+	formula, err := f.GetCellFormula("Sheet1", "D4")
+	assert.NoError(t, err)
+	assert.Equal(t, "B4*2+$B$1", formula)
+	formula, err = f.GetCellFormula("Sheet1", "D6")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(Tbl5[Col])+B6", formula)
+	styleID, err := f.GetCellStyle("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "E7", mergeCells[0].GetStartAxis())
+	assert.Equal(t, "F7", mergeCells[0].GetEndAxis())
+	for cell, expected := range map[string]string{"A2": "", "A4": "https://github.com/carmel/xlsx", "H2": "Sheet1!A1"} {
+		_, target, err := f.GetCellHyperLink("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, target, cell)
+	}
+	assert.Equal(t, "A6", f.GetComments()["Sheet1"][0].Ref)
+	shape := f.VMLDrawing["xl/drawings/vmlDrawing1.vml"].Shape[0].Val
+	assert.Contains(t, shape, "<x:Row>5</x:Row>")
+	assert.Contains(t, shape, "<x:Anchor>1, 23, 6, 0, 3, 13, 8, 5</x:Anchor>")
+
+	// Test sort range in descending order with blanks at the end.
+	assert.NoError(t, f.SortRange("Sheet1", "A2:F7", []SortKey{{Col: "B", Descending: true}}, nil))
+	assert.Equal(t, "Value,1,text,3,2,1,", getColumn("B"))
+
+	// Test sort range by case sensitive text and custom list.
+	assert.NoError(t, f.SortRange("Sheet1", "A2:F7", []SortKey{{Col: "A", CaseSensitive: true}}, nil))
+	assert.Equal(t, "Name,a,A,b,B,C,d", getColumn("A"))
+	assert.NoError(t, f.SortRange("Sheet1", "A2:F7", []SortKey{{Col: "C", CustomList: []string{"Low", "Medium", "High"}}, {Col: "A", Descending: true}}, nil))
+	assert.Equal(t, "Name,C,A,a,b,B,d", getColumn("A"))
+	assert.Equal(t, "Level,Low,Low,Medium,High,High,", getColumn("C"))
+
+	// Test sort range with merged cells across multiple rows.
+	assert.NoError(t, f.MergeCell("Sheet1", "E2", "E3"))
+	assert.EqualError(t, f.SortRange("Sheet1", "A2:F7", []SortKey{{Col: "A"}}, nil), ErrSortMergedCells.Error())
+
+	// Test sort range with invalid parameters.
+	assert.EqualError(t, f.SortRange("SheetN", "A1:B2", []SortKey{{Col: "A"}}, nil), "sheet SheetN is not exist")
+	assert.EqualError(t, f.SortRange("Sheet1", "A", []SortKey{{Col: "A"}}, nil), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SortRange("Sheet1", "A1:B2", nil, nil), ErrParameterRequired.Error())
+	assert.EqualError(t, f.SortRange("Sheet1", "A1:B2", []SortKey{{Col: "-"}}, nil), newInvalidColumnNameError("-").Error())
+	assert.EqualError(t, f.SortRange("Sheet1", "A1:B2", []SortKey{{Col: "C"}}, nil), ErrSortKeyColumn.Error())
+}
+
+func TestSortRangeSortState(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{"Name", "Value"}, {"b", 2}, {"a", 1}} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+1), &row))
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("D%d", idx+1), &row))
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("G%d", idx+1), &row))
+	}
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "B3", `{"table_name":"Table1"}`))
+	assert.NoError(t, f.AutoFilter("Sheet1", "D1", "E3", ""))
+	opts := &SortOptions{Header: true, SortState: true}
+
+	// Test record the sort state on the table.
+	assert.NoError(t, f.SortRange("Sheet1", "A1:B3", []SortKey{{Col: "B", Descending: true}}, opts))
+	table, ok := f.Pkg.Load("xl/tables/table1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(table.([]byte)), `<sortState ref="A2:B3"><sortCondition descending="true" ref="B2:B3"></sortCondition></sortState>`)
+
+	// Test record the sort state on the auto filter.
+	assert.NoError(t, f.SortRange("Sheet1", "D1:E3", []SortKey{{Col: "D", CaseSensitive: true}}, opts))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &xlsxSortState{CaseSensitive: true, Ref: "D2:E3", SortCondition: []*xlsxSortCondition{{Ref: "D2:D3"}}}, ws.AutoFilter.SortState)
+
+	// Test record the sort state on the worksheet.
+	assert.NoError(t, f.SortRange("Sheet1", "G1:H3", []SortKey{{Col: "G", CustomList: []string{"b", "a"}}}, opts))
+	assert.Equal(t, &xlsxSortState{Ref: "G2:H3", SortCondition: []*xlsxSortCondition{{Ref: "G2:G3", CustomList: "b,a"}}}, ws.SortState)
+	for cell, expected := range map[string]string{"A2": "b", "D2": "a", "G2": "b"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+	_, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+}
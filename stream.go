@@ -19,14 +19,40 @@ type StreamWriter struct {
 	Sheet           string
 	SheetID         int
 	sheetWritten    bool
-	cols            string
+	cols            []xlsxCol
 	worksheet       *xlsxWorksheet
 	rawData         bufferedWriter
 	mergeCellsCount int
 	mergeCells      string
 	tableParts      string
+	sharedStrings   map[string]int
+	sharedLimit     int
+	firstRow        int
+	firstRowCells   []xlsxC
 }
 
+// StreamOpts define the options for the stream writer.
+//
+// SharedStrings specifies if store the string values in the shared strings
+// table, the repeated string values will be written only once in the
+// spreadsheet. The string values will be written as inline strings if the
+// number of distinct string values stored by the stream writer exceeds the
+// SharedStringsLimit, the default limit is 65536.
+type StreamOpts struct {
+	SharedStrings      bool
+	SharedStringsLimit int
+}
+
+// ErrorValue can be used directly in StreamWriter.SetRow to write an error
+// typed cell value, the value should be one of "#NULL!", "#DIV/0!",
+// "#VALUE!", "#REF!", "#NAME?", "#NUM!", "#N/A", "#SPILL!", "#CALC!" and
+// "#GETTING_DATA".
+type ErrorValue string
+
+// defaultStreamSharedStringsLimit defined the default number of distinct
+// string values stored in shared strings table by the stream writer.
+const defaultStreamSharedStringsLimit = 65536
+
 // NewStreamWriter return stream writer struct by given worksheet name for
 // generate new worksheet with large amounts of data. Note that after set
 // rows, you must call the 'Flush' method to end the streaming writing
@@ -79,7 +105,28 @@ type StreamWriter struct {
 //        xlsx.Cell{Value: 1}},
 //        xlsx.RowOpts{StyleID: styleID, Height: 20, Hidden: false});
 //
-func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
+// Freeze the header row, group the rows and add the auto filter for a
+// worksheet with stream writer:
+//
+//    err := streamWriter.SetPanes(`{"freeze":true,"y_split":1,"top_left_cell":"A2","active_pane":"bottomLeft"}`)
+//    err = streamWriter.SetRow("A1", []interface{}{"Category", "Amount"})
+//    err = streamWriter.SetRow("A2", []interface{}{"Food", 100}, xlsx.RowOpts{OutlineLevel: 1})
+//    err = streamWriter.AutoFilter("A1", "B2", "")
+//    err = streamWriter.Flush()
+//
+// Set rich text, error value, formula with cached value and array formula
+// with the shared strings table for a worksheet with stream writer:
+//
+//    streamWriter, err := file.NewStreamWriter("Sheet1", xlsx.StreamOpts{SharedStrings: true})
+//    formulaType, ref := xlsx.STCellFormulaTypeArray, "D1:D1"
+//    err = streamWriter.SetRow("A1", []interface{}{
+//        []xlsx.RichTextRun{{Text: "Bold", Font: &xlsx.Font{Bold: true}}, {Text: " text"}},
+//        xlsx.ErrorValue("#N/A"),
+//        xlsx.Cell{Formula: "SUM(E1:F1)", Value: 3},
+//        xlsx.Cell{Formula: "SUM(E1:F1*2)", FormulaOpts: xlsx.FormulaOpts{Type: &formulaType, Ref: &ref}},
+//        1, 2})
+//
+func (f *File) NewStreamWriter(sheet string, opts ...StreamOpts) (*StreamWriter, error) {
 	sheetID := f.getSheetID(sheet)
 	if sheetID == -1 {
 		return nil, fmt.Errorf("sheet %s is not exist", sheet)
@@ -89,6 +136,15 @@ func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 		Sheet:   sheet,
 		SheetID: sheetID,
 	}
+	for _, opt := range opts {
+		sw.sharedStrings, sw.sharedLimit = nil, opt.SharedStringsLimit
+		if opt.SharedStrings {
+			sw.sharedStrings = make(map[string]int)
+		}
+		if sw.sharedLimit <= 0 {
+			sw.sharedLimit = defaultStreamSharedStringsLimit
+		}
+	}
 	var err error
 	sw.worksheet, err = f.workSheetReader(sheet)
 	if err != nil {
@@ -96,13 +152,23 @@ func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 	}
 
 	sheetPath := f.sheetMap[trimSheetName(sheet)]
+	if f.streamOutput != nil {
+		for _, stream := range f.streams {
+			if stream.rawData.w != nil {
+				return nil, ErrStreamNotFlushed
+			}
+		}
+		if sw.rawData.w, err = f.streamOutput.Create(sheetPath); err != nil {
+			return nil, err
+		}
+		sw.rawData.direct = true
+	}
 	if f.streams == nil {
 		f.streams = make(map[string]*StreamWriter)
 	}
 	f.streams[sheetPath] = sw
 
 	_, _ = sw.rawData.WriteString(XMLHeader + `<worksheet` + templateNamespaceIDMap)
-	bulkAppendFields(&sw.rawData, sw.worksheet, 2, 5)
 	return sw, err
 }
 
@@ -189,7 +255,7 @@ func (sw *StreamWriter) AddTable(hcell, vcell, format string) error {
 			Name:              formatSet.TableStyle,
 			ShowFirstColumn:   formatSet.ShowFirstColumn,
 			ShowLastColumn:    formatSet.ShowLastColumn,
-			ShowRowStripes:    formatSet.ShowRowStripes,
+			ShowRowStripes:    *formatSet.ShowRowStripes,
 			ShowColumnStripes: formatSet.ShowColumnStripes,
 		},
 	}
@@ -214,6 +280,12 @@ func (sw *StreamWriter) AddTable(hcell, vcell, format string) error {
 // Extract values from a row in the StreamWriter.
 func (sw *StreamWriter) getRowValues(hrow, hcol, vcol int) (res []string, err error) {
 	res = make([]string, vcol-hcol+1)
+	if sw.rawData.direct {
+		if hrow != sw.firstRow {
+			return nil, ErrStreamTableHeader
+		}
+		return res, sw.getCellValues(sw.firstRowCells, res, hcol, vcol)
+	}
 
 	r, err := sw.rawData.Reader()
 	if err != nil {
@@ -238,18 +310,26 @@ func (sw *StreamWriter) getRowValues(hrow, hcol, vcol int) (res []string, err er
 		if err := dec.DecodeElement(&row, &startElement); err != nil {
 			return nil, err
 		}
-		for _, c := range row.C {
-			col, _, err := CellNameToCoordinates(c.R)
-			if err != nil {
-				return nil, err
-			}
-			if col < hcol || col > vcol {
-				continue
-			}
-			res[col-hcol] = c.V
+		return res, sw.getCellValues(row.C, res, hcol, vcol)
+	}
+}
+
+// getCellValues provides a function to extract the values of the given cells
+// in the columns range into the result.
+func (sw *StreamWriter) getCellValues(cells []xlsxC, res []string, hcol, vcol int) error {
+	for _, c := range cells {
+		col, _, err := CellNameToCoordinates(c.R)
+		if err != nil {
+			return err
+		}
+		if col < hcol || col > vcol {
+			continue
+		}
+		if res[col-hcol], err = c.getValueFrom(sw.File, sw.File.sharedStringsReader(), true); err != nil {
+			return err
 		}
-		return res, nil
 	}
+	return nil
 }
 
 // Check if the token is an XLSX row with the matching row number.
@@ -277,19 +357,26 @@ func getRowElement(token xml.Token, hrow int) (startElement xml.StartElement, ok
 }
 
 // Cell can be used directly in StreamWriter.SetRow to specify a style and
-// a value.
+// a value. The Value will be written as the cached result if the Formula is
+// specified, and the FormulaOpts specifies the array formula with the type
+// STCellFormulaTypeArray and the range reference of the formula, the range
+// reference will be the cell itself if it is empty.
 type Cell struct {
-	StyleID int
-	Formula string
-	Value   interface{}
+	StyleID     int
+	Formula     string
+	FormulaOpts FormulaOpts
+	Value       interface{}
 }
 
 // RowOpts define the options for the set row, it can be used directly in
-// StreamWriter.SetRow to specify the style and properties of the row.
+// StreamWriter.SetRow to specify the style and properties of the row. The
+// OutlineLevel specifies the outline level of the row for grouping rows, the
+// value should be between 0 and 7.
 type RowOpts struct {
-	Height  float64
-	Hidden  bool
-	StyleID int
+	Height       float64
+	Hidden       bool
+	StyleID      int
+	OutlineLevel uint8
 }
 
 // SetRow writes an array to stream rows by giving a worksheet name, starting
@@ -297,23 +384,24 @@ type RowOpts struct {
 // 'Flush' method to end the streaming writing process.
 //
 // As a special case, if Cell is used as a value, then the Cell.StyleID will be
-// applied to that cell.
+// applied to that cell. The []RichTextRun values will be written as the
+// inline rich text, and the ErrorValue values will be written as the error
+// typed cell values.
 func (sw *StreamWriter) SetRow(axis string, values []interface{}, opts ...RowOpts) error {
 	col, row, err := CellNameToCoordinates(axis)
 	if err != nil {
 		return err
 	}
-	if !sw.sheetWritten {
-		if len(sw.cols) > 0 {
-			_, _ = sw.rawData.WriteString("<cols>" + sw.cols + "</cols>")
-		}
-		_, _ = sw.rawData.WriteString(`<sheetData>`)
-		sw.sheetWritten = true
-	}
 	attrs, err := marshalRowAttrs(opts...)
 	if err != nil {
 		return err
 	}
+	sw.writeSheetData()
+	// Keep the first row on the streaming output for creating the table.
+	firstRow := sw.rawData.direct && sw.firstRow == 0
+	if firstRow {
+		sw.firstRow = row
+	}
 	fmt.Fprintf(&sw.rawData, `<row r="%d"%s>`, row, attrs)
 	for i, val := range values {
 		axis, err := CoordinatesToCellName(col+i, row)
@@ -321,19 +409,21 @@ func (sw *StreamWriter) SetRow(axis string, values []interface{}, opts ...RowOpt
 			return err
 		}
 		c := xlsxC{R: axis}
+		if v, ok := val.(*Cell); ok && v != nil {
+			val = *v
+		}
 		if v, ok := val.(Cell); ok {
 			c.S = v.StyleID
 			val = v.Value
-			setCellFormula(&c, v.Formula)
-		} else if v, ok := val.(*Cell); ok && v != nil {
-			c.S = v.StyleID
-			val = v.Value
-			setCellFormula(&c, v.Formula)
+			setCellFormula(&c, v.Formula, v.FormulaOpts)
 		}
-		if err = setCellValFunc(&c, val); err != nil {
+		if err = sw.setCellValFunc(&c, val); err != nil {
 			_, _ = sw.rawData.WriteString(`</row>`)
 			return err
 		}
+		if firstRow {
+			sw.firstRowCells = append(sw.firstRowCells, c)
+		}
 		writeCell(&sw.rawData, c)
 	}
 	_, _ = sw.rawData.WriteString(`</row>`)
@@ -353,6 +443,10 @@ func marshalRowAttrs(opts ...RowOpts) (attrs string, err error) {
 		err = ErrMaxRowHeight
 		return
 	}
+	if opt.OutlineLevel > 7 {
+		err = ErrOutlineLevel
+		return
+	}
 	if opt.StyleID > 0 {
 		attrs += fmt.Sprintf(` s="%d" customFormat="true"`, opt.StyleID)
 	}
@@ -362,9 +456,28 @@ func marshalRowAttrs(opts ...RowOpts) (attrs string, err error) {
 	if opt.Hidden {
 		attrs += ` hidden="true"`
 	}
+	if opt.OutlineLevel > 0 {
+		attrs += fmt.Sprintf(` outlineLevel="%d"`, opt.OutlineLevel)
+	}
 	return
 }
 
+// writeSheetData writes the worksheet elements before the sheet data and the
+// start tag of the sheet data if it has not been written, the sheet
+// properties, sheet views, sheet format properties and columns can't be
+// changed after that.
+func (sw *StreamWriter) writeSheetData() {
+	if sw.sheetWritten {
+		return
+	}
+	bulkAppendFields(&sw.rawData, sw.worksheet, 2, 5)
+	if len(sw.cols) > 0 {
+		_ = xml.NewEncoder(&sw.rawData).Encode(&xlsxCols{Col: sw.cols})
+	}
+	_, _ = sw.rawData.WriteString(`<sheetData>`)
+	sw.sheetWritten = true
+}
+
 // SetColWidth provides a function to set the width of a single column or
 // multiple columns for the StreamWriter. Note that you must call
 // the 'SetColWidth' function before the 'SetRow' function. For example set
@@ -376,22 +489,175 @@ func (sw *StreamWriter) SetColWidth(min, max int, width float64) error {
 	if sw.sheetWritten {
 		return ErrStreamSetColWidth
 	}
+	if width > MaxColumnWidth {
+		return ErrColumnWidth
+	}
+	return sw.setCols(min, max, func(col *xlsxCol) {
+		col.Width, col.CustomWidth = width, true
+	})
+}
+
+// SetColStyle provides a function to set the default style of a single
+// column or multiple columns for the StreamWriter. Note that you must call
+// the 'SetColStyle' function before the 'SetRow' function. For example set
+// the style of column B:C:
+//
+//    err := streamWriter.SetColStyle(2, 3, styleID)
+//
+func (sw *StreamWriter) SetColStyle(min, max, styleID int) error {
+	if sw.sheetWritten {
+		return ErrStreamSetColStyle
+	}
+	if styleID < 0 {
+		return newInvalidStyleID(styleID)
+	}
+	return sw.setCols(min, max, func(col *xlsxCol) {
+		col.Style = styleID
+	})
+}
+
+// SetColOutlineLevel provides a function to set the outline level of a single
+// column or multiple columns for the StreamWriter, the value of level should
+// be between 1 and 7. Note that you must call the 'SetColOutlineLevel'
+// function before the 'SetRow' function. For example group the column B:C:
+//
+//    err := streamWriter.SetColOutlineLevel(2, 3, 1)
+//
+func (sw *StreamWriter) SetColOutlineLevel(min, max int, level uint8) error {
+	if sw.sheetWritten {
+		return ErrStreamSetColOutlineLevel
+	}
+	if level > 7 || level < 1 {
+		return ErrOutlineLevel
+	}
+	return sw.setCols(min, max, func(col *xlsxCol) {
+		col.OutlineLevel = level
+	})
+}
+
+// SetColVisible provides a function to set the visible of a single column or
+// multiple columns for the StreamWriter. Note that you must call the
+// 'SetColVisible' function before the 'SetRow' function. For example hide
+// the column B:C:
+//
+//    err := streamWriter.SetColVisible(2, 3, false)
+//
+func (sw *StreamWriter) SetColVisible(min, max int, visible bool) error {
+	if sw.sheetWritten {
+		return ErrStreamSetColVisible
+	}
+	return sw.setCols(min, max, func(col *xlsxCol) {
+		col.Hidden = !visible
+	})
+}
+
+// setCols provides a function to apply the settings for the columns range by
+// given modifier, the columns of the StreamWriter are kept in ascending order
+// without overlapping.
+func (sw *StreamWriter) setCols(min, max int, modifier func(col *xlsxCol)) error {
 	if min > TotalColumns || max > TotalColumns {
 		return ErrColumnNumber
 	}
 	if min < 1 || max < 1 {
 		return ErrColumnNumber
 	}
-	if width > MaxColumnWidth {
-		return ErrColumnWidth
-	}
 	if min > max {
 		min, max = max, min
 	}
-	sw.cols += fmt.Sprintf(`<col min="%d" max="%d" width="%f" customWidth="1"/>`, min, max, width)
+	var cols []xlsxCol
+	next := min
+	fill := func(to int) {
+		if next <= to {
+			col := xlsxCol{Min: next, Max: to, Width: defaultColWidth}
+			modifier(&col)
+			cols = append(cols, col)
+			next = to + 1
+		}
+	}
+	for _, col := range sw.cols {
+		if col.Max < min || col.Min > max {
+			if col.Min > max {
+				fill(max)
+			}
+			cols = append(cols, col)
+			continue
+		}
+		if col.Min < min {
+			left := col
+			left.Max = min - 1
+			cols = append(cols, left)
+			col.Min = min
+		}
+		fill(col.Min - 1)
+		right := col
+		if col.Max > max {
+			col.Max = max
+		}
+		modifier(&col)
+		cols = append(cols, col)
+		next = col.Max + 1
+		if right.Max > max {
+			right.Min = max + 1
+			cols = append(cols, right)
+		}
+	}
+	fill(max)
+	sw.cols = cols
 	return nil
 }
 
+// SetPanes provides a function to create and remove freeze panes and split
+// panes for the StreamWriter by given panes format set, the format set is the
+// same as File.SetPanes. Note that you must call the 'SetPanes' function
+// before the 'SetRow' function. For example, freeze the first row:
+//
+//    err := streamWriter.SetPanes(`{"freeze":true,"split":false,"x_split":0,"y_split":1,"top_left_cell":"A2","active_pane":"bottomLeft","panes":[{"sqref":"A2","active_cell":"A2","pane":"bottomLeft"}]}`)
+//
+func (sw *StreamWriter) SetPanes(panes string) error {
+	if sw.sheetWritten {
+		return ErrStreamSetPanes
+	}
+	return sw.File.SetPanes(sw.Sheet, panes)
+}
+
+// SetSheetViewOptions provides a function to set the sheet view options of
+// the worksheet for the StreamWriter, the options are the same as
+// File.SetSheetViewOptions. Note that you must call the
+// 'SetSheetViewOptions' function before the 'SetRow' function. For example,
+// hide the grid lines of the worksheet:
+//
+//    err := streamWriter.SetSheetViewOptions(-1, xlsx.ShowGridLines(false))
+//
+func (sw *StreamWriter) SetSheetViewOptions(viewIndex int, opts ...SheetViewOption) error {
+	if sw.sheetWritten {
+		return ErrStreamSetSheetView
+	}
+	return sw.File.SetSheetViewOptions(sw.Sheet, viewIndex, opts...)
+}
+
+// SetCellHyperLink provides a function to set cell hyperlink for the
+// StreamWriter by given cell reference, link and link type, the link type
+// and options are the same as File.SetCellHyperLink. Note that you must call
+// the 'SetCellHyperLink' function before the 'Flush' function. For example:
+//
+//    err := streamWriter.SetCellHyperLink("A1", "https://github.com/carmel/xlsx", "External")
+//
+func (sw *StreamWriter) SetCellHyperLink(axis, link, linkType string, opts ...HyperlinkOpts) error {
+	return sw.File.SetCellHyperLink(sw.Sheet, axis, link, linkType, opts...)
+}
+
+// AutoFilter provides a function to add the auto filter for the StreamWriter
+// by given cell range and format set, the format set is the same as
+// File.AutoFilter. Note that you must call the 'AutoFilter' function before
+// the 'Flush' function, and the rows will not be hidden by the filter
+// criteria. For example, add the auto filter for the range A1:D20:
+//
+//    err := streamWriter.AutoFilter("A1", "D20", "")
+//
+func (sw *StreamWriter) AutoFilter(hcell, vcell, format string) error {
+	return sw.File.AutoFilter(sw.Sheet, hcell, vcell, format)
+}
+
 // MergeCell provides a function to merge cells by a given coordinate area for
 // the StreamWriter. Don't create a merged cell that overlaps with another
 // existing merged cell.
@@ -405,11 +671,94 @@ func (sw *StreamWriter) MergeCell(hcell, vcell string) error {
 	return nil
 }
 
-// setCellFormula provides a function to set formula of a cell.
-func setCellFormula(c *xlsxC, formula string) {
-	if formula != "" {
-		c.F = &xlsxF{Content: formula}
+// setCellFormula provides a function to set formula of a cell, only the
+// array formula type of the formula options is supported.
+func setCellFormula(c *xlsxC, formula string, opts ...FormulaOpts) {
+	if formula == "" {
+		return
+	}
+	c.F = &xlsxF{Content: formula}
+	for _, opt := range opts {
+		if opt.Type != nil && *opt.Type == STCellFormulaTypeArray {
+			c.F.T, c.F.Ref = *opt.Type, c.R
+			if opt.Ref != nil && *opt.Ref != "" {
+				c.F.Ref = *opt.Ref
+			}
+		}
+	}
+}
+
+// setCellValFunc provides a function to set value of a cell for the
+// StreamWriter, which supports the rich text and error values, and the
+// string values will be stored in the shared strings table in the shared
+// strings mode.
+func (sw *StreamWriter) setCellValFunc(c *xlsxC, val interface{}) error {
+	if rv := reflect.ValueOf(val); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			val = nil
+		} else if _, ok := val.(*Cell); !ok {
+			val = rv.Elem().Interface()
+		}
+	}
+	switch val := val.(type) {
+	case []RichTextRun:
+		si, err := newRichTextSI(val)
+		if err != nil {
+			return err
+		}
+		c.T, c.IS = "inlineStr", &si
+		return nil
+	case ErrorValue:
+		if inStrSlice([]string{formulaErrorNULL, formulaErrorDIV, formulaErrorVALUE, formulaErrorREF, formulaErrorNAME,
+			formulaErrorNUM, formulaErrorNA, formulaErrorSPILL, formulaErrorCALC, formulaErrorGETTINGDATA}, string(val)) == -1 {
+			return ErrCellErrorValue
+		}
+		c.T, c.V = "e", string(val)
+		return nil
+	case string:
+		if sw.setSharedString(c, val) {
+			return nil
+		}
+	case []byte:
+		if sw.setSharedString(c, string(val)) {
+			return nil
+		}
+	case nil:
+		if c.F != nil {
+			return nil
+		}
+	}
+	return setCellValFunc(c, val)
+}
+
+// setSharedString provides a function to set the shared string value of a
+// cell in the shared strings mode, returns false if the string value can't be
+// stored in the shared strings table.
+func (sw *StreamWriter) setSharedString(c *xlsxC, val string) bool {
+	if sw.sharedStrings == nil || c.F != nil {
+		return false
+	}
+	if len(val) > TotalCellChars {
+		val = val[:TotalCellChars]
 	}
+	idx, ok := sw.sharedStrings[val]
+	if !ok && len(sw.sharedStrings) >= sw.sharedLimit {
+		return false
+	}
+	sst := sw.File.sharedStringsReader()
+	sw.File.Lock()
+	defer sw.File.Unlock()
+	if !ok {
+		t := xlsxT{Val: val}
+		_, _, t.Space = setCellStr(val)
+		sst.SI = append(sst.SI, xlsxSI{T: &t})
+		sst.UniqueCount++
+		idx = len(sst.SI) - 1
+		sw.sharedStrings[val] = idx
+	}
+	sst.Count++
+	c.T, c.V = "s", strconv.Itoa(idx)
+	return true
 }
 
 // setCellValFunc provides a function to set value of a cell.
@@ -481,7 +830,14 @@ func writeCell(buf *bufferedWriter, c xlsxC) {
 	}
 	_, _ = buf.WriteString(`>`)
 	if c.F != nil {
-		_, _ = buf.WriteString(`<f>`)
+		_, _ = buf.WriteString(`<f`)
+		if c.F.T != "" {
+			fmt.Fprintf(buf, ` t="%s"`, c.F.T)
+		}
+		if c.F.Ref != "" {
+			fmt.Fprintf(buf, ` ref="%s"`, c.F.Ref)
+		}
+		_, _ = buf.WriteString(`>`)
 		_ = xml.EscapeText(buf, []byte(c.F.Content))
 		_, _ = buf.WriteString(`</f>`)
 	}
@@ -490,15 +846,15 @@ func writeCell(buf *bufferedWriter, c xlsxC) {
 		_ = xml.EscapeText(buf, []byte(c.V))
 		_, _ = buf.WriteString(`</v>`)
 	}
+	if c.IS != nil {
+		_ = xml.NewEncoder(buf).EncodeElement(c.IS, xml.StartElement{Name: xml.Name{Local: "is"}})
+	}
 	_, _ = buf.WriteString(`</c>`)
 }
 
 // Flush ending the streaming writing process.
 func (sw *StreamWriter) Flush() error {
-	if !sw.sheetWritten {
-		_, _ = sw.rawData.WriteString(`<sheetData>`)
-		sw.sheetWritten = true
-	}
+	sw.writeSheetData()
 	_, _ = sw.rawData.WriteString(`</sheetData>`)
 	bulkAppendFields(&sw.rawData, sw.worksheet, 8, 15)
 	if sw.mergeCellsCount > 0 {
@@ -512,6 +868,8 @@ func (sw *StreamWriter) Flush() error {
 	if err := sw.rawData.Flush(); err != nil {
 		return err
 	}
+	// End the worksheet part on the streaming output.
+	sw.rawData.w = nil
 
 	sheetPath := sw.File.sheetMap[trimSheetName(sw.Sheet)]
 	sw.File.Sheet.Delete(sheetPath)
@@ -536,10 +894,13 @@ func bulkAppendFields(w io.Writer, ws *xlsxWorksheet, from, to int) {
 // bufferedWriter uses a temp file to store an extended buffer. Writes are
 // always made to an in-memory buffer, which will always succeed. The buffer
 // is written to the temp file with Sync, which may return an error.
-// Therefore, Sync should be periodically called and the error checked.
+// Therefore, Sync should be periodically called and the error checked. The
+// buffer will be written to the w directly with Sync if the direct is true.
 type bufferedWriter struct {
-	tmp *os.File
-	buf bytes.Buffer
+	tmp    *os.File
+	buf    bytes.Buffer
+	direct bool
+	w      io.Writer
 }
 
 // Write to the in-memory buffer. The err is always nil.
@@ -571,6 +932,9 @@ func (bw *bufferedWriter) Reader() (io.Reader, error) {
 // Sync will write the in-memory buffer to a temp file, if the in-memory
 // buffer has grown large enough. Any error will be returned.
 func (bw *bufferedWriter) Sync() (err error) {
+	if bw.w != nil {
+		return bw.Flush()
+	}
 	// Try to use local storage
 	if bw.buf.Len() < StreamChunkSize {
 		return nil
@@ -586,8 +950,12 @@ func (bw *bufferedWriter) Sync() (err error) {
 }
 
 // Flush the entire in-memory buffer to the temp file, if a temp file is being
-// used.
+// used, or the writer on the streaming output.
 func (bw *bufferedWriter) Flush() error {
+	if bw.w != nil {
+		_, err := bw.buf.WriteTo(bw.w)
+		return err
+	}
 	if bw.tmp == nil {
 		return nil
 	}
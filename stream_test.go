@@ -1,6 +1,7 @@
 package xlsx
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
@@ -229,3 +230,217 @@ func TestSetCellValFunc(t *testing.T) {
 	assert.NoError(t, setCellValFunc(c, nil))
 	assert.NoError(t, setCellValFunc(c, complex64(5+10i)))
 }
+
+func TestStreamSetCols(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	styleID, err := file.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetColWidth(2, 4, 20))
+	assert.NoError(t, streamWriter.SetColStyle(1, 3, styleID))
+	assert.NoError(t, streamWriter.SetColOutlineLevel(6, 3, 1))
+	assert.NoError(t, streamWriter.SetColVisible(7, 7, false))
+	assert.Equal(t, []xlsxCol{
+		{Min: 1, Max: 1, Width: defaultColWidth, Style: styleID},
+		{Min: 2, Max: 2, Width: 20, CustomWidth: true, Style: styleID},
+		{Min: 3, Max: 3, Width: 20, CustomWidth: true, Style: styleID, OutlineLevel: 1},
+		{Min: 4, Max: 4, Width: 20, CustomWidth: true, OutlineLevel: 1},
+		{Min: 5, Max: 6, Width: defaultColWidth, OutlineLevel: 1},
+		{Min: 7, Max: 7, Width: defaultColWidth, Hidden: true},
+	}, streamWriter.cols)
+	// Test set columns with invalid settings.
+	assert.EqualError(t, streamWriter.SetColStyle(1, 2, -1), newInvalidStyleID(-1).Error())
+	assert.EqualError(t, streamWriter.SetColStyle(0, 2, styleID), ErrColumnNumber.Error())
+	assert.EqualError(t, streamWriter.SetColOutlineLevel(1, 2, 8), ErrOutlineLevel.Error())
+	assert.EqualError(t, streamWriter.SetColVisible(1, TotalColumns+1, false), ErrColumnNumber.Error())
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"A"}))
+	// Test set columns after set rows.
+	assert.EqualError(t, streamWriter.SetColStyle(1, 2, styleID), ErrStreamSetColStyle.Error())
+	assert.EqualError(t, streamWriter.SetColOutlineLevel(1, 2, 1), ErrStreamSetColOutlineLevel.Error())
+	assert.EqualError(t, streamWriter.SetColVisible(1, 2, false), ErrStreamSetColVisible.Error())
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamSetCols.xlsx")))
+
+	file, err = OpenFile(filepath.Join("test", "TestStreamSetCols.xlsx"))
+	assert.NoError(t, err)
+	width, err := file.GetColWidth("Sheet1", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	level, err := file.GetColOutlineLevel("Sheet1", "E")
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(1), level)
+	visible, err := file.GetColVisible("Sheet1", "G")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	assert.NoError(t, file.Close())
+}
+
+func TestStreamWorksheetFeatures(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetPanes(`{"freeze":true,"y_split":1,"top_left_cell":"A2","active_pane":"bottomLeft","panes":[{"sqref":"A2","active_cell":"A2","pane":"bottomLeft"}]}`))
+	assert.NoError(t, streamWriter.SetSheetViewOptions(-1, ShowGridLines(false)))
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Category", "Amount", "Link"}))
+	for row := 2; row <= 5; row++ {
+		cell, _ := CoordinatesToCellName(1, row)
+		assert.NoError(t, streamWriter.SetRow(cell, []interface{}{"Food", row, "Details"}, RowOpts{OutlineLevel: 1, Hidden: row > 3}))
+	}
+	assert.EqualError(t, streamWriter.SetRow("A6", nil, RowOpts{OutlineLevel: 8}), ErrOutlineLevel.Error())
+	assert.NoError(t, streamWriter.SetCellHyperLink("C2", "https://github.com/carmel/xlsx", "External"))
+	assert.NoError(t, streamWriter.SetCellHyperLink("C3", "Sheet1!A1", "Location"))
+	assert.EqualError(t, streamWriter.SetCellHyperLink("C4", "Sheet1!A1", "None"), `invalid link type "None"`)
+	assert.NoError(t, streamWriter.AutoFilter("A1", "C5", `{"column":"B","expression":"x > 3"}`))
+	// Test the auto filter is not evaluated on the rows of the stream writer.
+	assert.Len(t, streamWriter.worksheet.SheetData.Row, 0)
+	assert.NoError(t, streamWriter.MergeCell("D1", "E1"))
+	// Test set sheet views after set rows.
+	assert.EqualError(t, streamWriter.SetPanes(`{"freeze":false}`), ErrStreamSetPanes.Error())
+	assert.EqualError(t, streamWriter.SetSheetViewOptions(-1, ShowGridLines(true)), ErrStreamSetSheetView.Error())
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamWorksheetFeatures.xlsx")))
+
+	file, err = OpenFile(filepath.Join("test", "TestStreamWorksheetFeatures.xlsx"))
+	assert.NoError(t, err)
+	ws, err := file.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	// Test the elements are written in the schema order.
+	assert.Equal(t, "frozen", ws.SheetViews.SheetView[0].Pane.State)
+	assert.Equal(t, "A2", ws.SheetViews.SheetView[0].Pane.TopLeftCell)
+	var showGridLines ShowGridLines
+	assert.NoError(t, file.GetSheetViewOptions("Sheet1", -1, &showGridLines))
+	assert.False(t, bool(showGridLines))
+	assert.Equal(t, "$A$1:$C$5", ws.AutoFilter.Ref)
+	assert.Len(t, ws.MergeCells.Cells, 1)
+	level, err := file.GetRowOutlineLevel("Sheet1", 4)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(1), level)
+	visible, err := file.GetRowVisible("Sheet1", 4)
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	link, target, err := file.GetCellHyperLink("Sheet1", "C2")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://github.com/carmel/xlsx", target)
+	link, target, err = file.GetCellHyperLink("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "Sheet1!A1", target)
+	assert.NoError(t, file.Close())
+}
+
+func TestStreamCellValues(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1", StreamOpts{SharedStrings: true, SharedStringsLimit: 2})
+	assert.NoError(t, err)
+	arrayType, ref := STCellFormulaTypeArray, "F1:F2"
+	val := "pointer"
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{
+		"Category", "Category", []RichTextRun{{Text: "Bold", Font: &Font{Bold: true}}, {Text: " text"}},
+		ErrorValue("#N/A"), Cell{Formula: "SUM(G1:H1)", Value: 3}, Cell{Formula: "G1:G2*2", FormulaOpts: FormulaOpts{Type: &arrayType, Ref: &ref}},
+		1, 2, Cell{Formula: "1/0", Value: ErrorValue("#DIV/0!")}, &val, (*int)(nil), Cell{Formula: "G1"},
+	}))
+	// Test the distinct string values exceed the limit of the shared strings.
+	assert.NoError(t, streamWriter.SetRow("A2", []interface{}{"Type", "Inline", []byte("Type"), &Cell{Value: "Category"}}))
+	assert.EqualError(t, streamWriter.SetRow("A3", []interface{}{ErrorValue("#ERR")}), ErrCellErrorValue.Error())
+	assert.EqualError(t, streamWriter.SetRow("A4", []interface{}{[]RichTextRun{{Text: strings.Repeat("c", TotalCellChars+1)}}}), ErrCellCharsLength.Error())
+	assert.NoError(t, streamWriter.AddTable("A1", "B2", ""))
+	assert.NoError(t, streamWriter.Flush())
+	sst := file.sharedStringsReader()
+	assert.Equal(t, 2, sst.UniqueCount)
+	assert.Equal(t, 4, sst.Count)
+	var table xlsxTable
+	tableXML, ok := file.Pkg.Load("xl/tables/table1.xml")
+	assert.True(t, ok)
+	assert.NoError(t, xml.Unmarshal(tableXML.([]byte), &table))
+	assert.Equal(t, "Category", table.TableColumns.TableColumn[0].Name)
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamCellValues.xlsx")))
+
+	file, err = OpenFile(filepath.Join("test", "TestStreamCellValues.xlsx"))
+	assert.NoError(t, err)
+	for cell, expected := range map[string]string{
+		"A1": "Category", "B1": "Category", "C1": "Bold text", "D1": "#N/A", "E1": "3", "I1": "#DIV/0!", "J1": "pointer", "K1": "",
+		"A2": "Type", "B2": "Inline", "C2": "Type", "D2": "Category",
+	} {
+		val, err := file.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	cellType, err := file.GetCellType("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, CellTypeError, cellType)
+	runs, err := file.GetCellRichText("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	assert.True(t, runs[0].Font.Bold)
+	ws, err := file.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "s", ws.SheetData.Row[0].C[0].T)
+	assert.Equal(t, "str", ws.SheetData.Row[1].C[0].T)
+	assert.Equal(t, "s", ws.SheetData.Row[1].C[3].T)
+	assert.Equal(t, &xlsxF{Content: "G1:G2*2", T: STCellFormulaTypeArray, Ref: "F1:F2"}, ws.SheetData.Row[0].C[5].F)
+	assert.Equal(t, "", ws.SheetData.Row[0].C[11].T)
+	formula, err := file.GetCellFormula("Sheet1", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(G1:H1)", formula)
+	assert.NoError(t, file.Close())
+}
+
+func TestStreamTo(t *testing.T) {
+	file, buf := NewFile(), new(bytes.Buffer)
+	assert.NoError(t, file.StreamTo(buf))
+	assert.EqualError(t, file.StreamTo(buf), ErrStreamOutput.Error())
+	file.NewSheet("Sheet2")
+	streamWriter, err := file.NewStreamWriter("Sheet1", StreamOpts{SharedStrings: true})
+	assert.NoError(t, err)
+	// Test create another stream writer before the previous one flushed.
+	_, err = file.NewStreamWriter("Sheet2")
+	assert.EqualError(t, err, ErrStreamNotFlushed.Error())
+	assert.EqualError(t, file.EndStream(), ErrStreamNotFlushed.Error())
+	assert.NoError(t, streamWriter.SetColWidth(1, 2, 20))
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Name", "Value"}))
+	for rowID := 2; rowID <= 10000; rowID++ {
+		cell, _ := CoordinatesToCellName(1, rowID)
+		assert.NoError(t, streamWriter.SetRow(cell, []interface{}{"Data", rowID}))
+	}
+	// Test the rows were compressed into the output directly.
+	assert.NotZero(t, buf.Len())
+	assert.EqualError(t, streamWriter.AddTable("A2", "B3", ""), ErrStreamTableHeader.Error())
+	assert.NoError(t, streamWriter.AddTable("A1", "B10000", ""))
+	assert.NoError(t, streamWriter.Flush())
+	// Test write the workbook on the streaming output.
+	_, err = file.WriteToBuffer()
+	assert.EqualError(t, err, ErrStreamOutput.Error())
+	assert.EqualError(t, file.SaveAs(filepath.Join("test", "TestStreamTo.xlsx")), ErrStreamOutput.Error())
+
+	streamWriter, err = file.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("B2", []interface{}{"Sheet2"}))
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SetCellValue("Sheet1", "A1", "ignored"))
+	assert.NoError(t, file.EndStream())
+	assert.NoError(t, file.EndStream())
+
+	file, err = OpenReader(buf)
+	assert.NoError(t, err)
+	for sheet, cells := range map[string]map[string]string{
+		"Sheet1": {"A1": "Name", "B1": "Value", "A10000": "Data", "B10000": "10000"},
+		"Sheet2": {"B2": "Sheet2"},
+	} {
+		for cell, expected := range cells {
+			val, err := file.GetCellValue(sheet, cell)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, val)
+		}
+	}
+	width, err := file.GetColWidth("Sheet1", "B")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	var table xlsxTable
+	tableXML, ok := file.Pkg.Load("xl/tables/table1.xml")
+	assert.True(t, ok)
+	assert.NoError(t, xml.Unmarshal(tableXML.([]byte), &table))
+	assert.Equal(t, "Name", table.TableColumns.TableColumn[0].Name)
+	assert.NoError(t, file.Close())
+}
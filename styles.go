@@ -12,6 +12,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Excel styles can reference number formats that are built-in, all of which
@@ -782,22 +783,23 @@ var builtInNumFmtFunc = map[int]func(v string, format string) string{
 // validType defined the list of valid validation types.
 var validType = map[string]string{
 	"cell":          "cellIs",
-	"date":          "date", // Doesn't support currently
-	"time":          "time", // Doesn't support currently
+	"date":          "cellIs",
+	"time":          "cellIs",
 	"average":       "aboveAverage",
 	"duplicate":     "duplicateValues",
 	"unique":        "uniqueValues",
 	"top":           "top10",
 	"bottom":        "top10",
-	"text":          "text",              // Doesn't support currently
-	"time_period":   "timePeriod",        // Doesn't support currently
-	"blanks":        "containsBlanks",    // Doesn't support currently
-	"no_blanks":     "notContainsBlanks", // Doesn't support currently
-	"errors":        "containsErrors",    // Doesn't support currently
-	"no_errors":     "notContainsErrors", // Doesn't support currently
+	"text":          "text",
+	"time_period":   "timePeriod",
+	"blanks":        "containsBlanks",
+	"no_blanks":     "notContainsBlanks",
+	"errors":        "containsErrors",
+	"no_errors":     "notContainsErrors",
 	"2_color_scale": "2_color_scale",
 	"3_color_scale": "3_color_scale",
 	"data_bar":      "dataBar",
+	"icon_set":      "iconSet",
 	"formula":       "expression",
 }
 
@@ -825,13 +827,83 @@ var criteriaType = map[string]string{
 	"ends with":                "endsWith",
 	"yesterday":                "yesterday",
 	"today":                    "today",
+	"tomorrow":                 "tomorrow",
 	"last 7 days":              "last7Days",
 	"last week":                "lastWeek",
 	"this week":                "thisWeek",
-	"continue week":            "continueWeek",
+	"next week":                "nextWeek",
 	"last month":               "lastMonth",
 	"this month":               "thisMonth",
-	"continue month":           "continueMonth",
+	"next month":               "nextMonth",
+}
+
+// condFmtCriteria defined the criteria names of the conditional formatting
+// rule operators, text types and time periods.
+var condFmtCriteria = map[string]string{
+	"between":            "between",
+	"notBetween":         "not between",
+	"equal":              "==",
+	"notEqual":           "!=",
+	"greaterThan":        ">",
+	"lessThan":           "<",
+	"greaterThanOrEqual": ">=",
+	"lessThanOrEqual":    "<=",
+	"containsText":       "containing",
+	"notContains":        "not containing",
+	"notContainsText":    "not containing",
+	"beginsWith":         "begins with",
+	"endsWith":           "ends with",
+	"yesterday":          "yesterday",
+	"today":              "today",
+	"tomorrow":           "tomorrow",
+	"last7Days":          "last 7 days",
+	"lastWeek":           "last week",
+	"thisWeek":           "this week",
+	"nextWeek":           "next week",
+	"lastMonth":          "last month",
+	"thisMonth":          "this month",
+	"nextMonth":          "next month",
+}
+
+// iconSetTypes defined the list of valid icon set styles of the conditional
+// formatting. The icon sets 3_stars, 3_triangles and 5_boxes are only
+// supported by Excel 2010 and later versions.
+var iconSetTypes = map[string]string{
+	"3_arrows":                "3Arrows",
+	"3_arrows_gray":           "3ArrowsGray",
+	"3_flags":                 "3Flags",
+	"3_traffic_lights":        "3TrafficLights1",
+	"3_traffic_lights_rimmed": "3TrafficLights2",
+	"3_signs":                 "3Signs",
+	"3_symbols":               "3Symbols2",
+	"3_symbols_circled":       "3Symbols",
+	"3_stars":                 "3Stars",
+	"3_triangles":             "3Triangles",
+	"4_arrows":                "4Arrows",
+	"4_arrows_gray":           "4ArrowsGray",
+	"4_red_to_black":          "4RedToBlack",
+	"4_ratings":               "4Rating",
+	"4_traffic_lights":        "4TrafficLights",
+	"5_arrows":                "5Arrows",
+	"5_arrows_gray":           "5ArrowsGray",
+	"5_ratings":               "5Rating",
+	"5_quarters":              "5Quarters",
+	"5_boxes":                 "5Boxes",
+}
+
+// condFmtTimePeriodFormulas defined the formula templates of the conditional
+// formatting rules for the time period criteria.
+var condFmtTimePeriodFormulas = map[string]string{
+	"yesterday": "FLOOR(%[1]s,1)=TODAY()-1",
+	"today":     "FLOOR(%[1]s,1)=TODAY()",
+	"tomorrow":  "FLOOR(%[1]s,1)=TODAY()+1",
+	"last7Days": "AND(TODAY()-FLOOR(%[1]s,1)<=6,FLOOR(%[1]s,1)<=TODAY())",
+	"lastWeek":  "AND(TODAY()-ROUNDDOWN(%[1]s,0)>=(WEEKDAY(TODAY())),TODAY()-ROUNDDOWN(%[1]s,0)<(WEEKDAY(TODAY())+7))",
+	"thisWeek":  "AND(TODAY()-ROUNDDOWN(%[1]s,0)<=WEEKDAY(TODAY())-1,ROUNDDOWN(%[1]s,0)-TODAY()<=7-WEEKDAY(TODAY()))",
+	"nextWeek":  "AND(ROUNDDOWN(%[1]s,0)-TODAY()>(7-WEEKDAY(TODAY())),ROUNDDOWN(%[1]s,0)-TODAY()<(15-WEEKDAY(TODAY())))",
+	"lastMonth": "AND(MONTH(%[1]s)=MONTH(TODAY())-1,OR(YEAR(%[1]s)=YEAR(TODAY()),AND(MONTH(%[1]s)=1,YEAR(%[1]s)=YEAR(TODAY())-1)))",
+	"thisMonth": "AND(MONTH(%[1]s)=MONTH(TODAY()),YEAR(%[1]s)=YEAR(TODAY()))",
+	"nextMonth": "AND(MONTH(%[1]s)=MONTH(TODAY())+1,OR(YEAR(%[1]s)=YEAR(TODAY()),AND(MONTH(%[1]s)=12,YEAR(%[1]s)=YEAR(TODAY())+1)))",
 }
 
 // formatToString provides a function to return original string by given
@@ -1975,7 +2047,7 @@ func parseFormatStyleSet(style interface{}) (*Style, error) {
 func (f *File) NewStyle(style interface{}) (int, error) {
 	var fs *Style
 	var err error
-	var cellXfsID, fontID, borderID, fillID int
+	var cellXfsID int
 	fs, err = parseFormatStyleSet(style)
 	if err != nil {
 		return cellXfsID, err
@@ -1990,8 +2062,17 @@ func (f *File) NewStyle(style interface{}) (int, error) {
 	if cellXfsID = f.getStyleID(s, fs); cellXfsID != -1 {
 		return cellXfsID, err
 	}
+	numFmtID, fontID, borderID, fillID := f.newXfIDs(s, fs)
+	applyAlignment, alignment := fs.Alignment != nil, newAlignment(fs)
+	applyProtection, protection := fs.Protection != nil, newProtection(fs)
+	cellXfsID = setCellXfs(s, fontID, numFmtID, fillID, borderID, applyAlignment, applyProtection, alignment, protection)
+	return cellXfsID, nil
+}
 
-	numFmtID := newNumFmt(s, fs)
+// newXfIDs provides a function to get or create the number format, font,
+// border and fill by given style settings, and returns the index of them.
+func (f *File) newXfIDs(s *xlsxStyleSheet, fs *Style) (numFmtID, fontID, borderID, fillID int) {
+	numFmtID = newNumFmt(s, fs)
 
 	if fs.Font != nil {
 		fontID = f.getFontID(s, fs)
@@ -2022,11 +2103,7 @@ func (f *File) NewStyle(style interface{}) (int, error) {
 			fillID = 0
 		}
 	}
-
-	applyAlignment, alignment := fs.Alignment != nil, newAlignment(fs)
-	applyProtection, protection := fs.Protection != nil, newProtection(fs)
-	cellXfsID = setCellXfs(s, fontID, numFmtID, fillID, borderID, applyAlignment, applyProtection, alignment, protection)
-	return cellXfsID, nil
+	return
 }
 
 var getXfIDFuncs = map[string]func(int, xlsxXf, *Style) bool{
@@ -2101,11 +2178,17 @@ func (f *File) getStyleID(ss *xlsxStyleSheet, style *Style) (styleID int) {
 // NewStyle(). Note that the color field uses RGB color code and only support
 // to set font, fills, alignment and borders currently.
 func (f *File) NewConditionalStyle(style string) (int, error) {
-	s := f.stylesReader()
 	fs, err := parseFormatStyleSet(style)
 	if err != nil {
 		return 0, err
 	}
+	return f.newDxf(fs), nil
+}
+
+// newDxf provides a function to add the differential formatting record by
+// given style settings, and returns the index of the record.
+func (f *File) newDxf(fs *Style) int {
+	s := f.stylesReader()
 	dxf := dxf{
 		Fill: newFills(fs, false),
 	}
@@ -2126,7 +2209,51 @@ func (f *File) NewConditionalStyle(style string) (int, error) {
 	s.Dxfs.Dxfs = append(s.Dxfs.Dxfs, &xlsxDxf{
 		Dxf: string(dxfStr[5 : len(dxfStr)-6]),
 	})
-	return s.Dxfs.Count - 1, nil
+	return s.Dxfs.Count - 1
+}
+
+// GetConditionalStyle provides a function to get the format settings of the
+// conditional formatting by given format index, which was created by the
+// NewConditionalStyle function or read from the workbook.
+func (f *File) GetConditionalStyle(idx int) (*Style, error) {
+	if idx < 0 {
+		return nil, newInvalidStyleID(idx)
+	}
+	s := f.stylesReader()
+	s.Lock()
+	defer s.Unlock()
+	if s.Dxfs == nil || idx >= len(s.Dxfs.Dxfs) {
+		return nil, newStyleNotExistError(idx)
+	}
+	var d dxf
+	if err := f.xmlNewDecoder(strings.NewReader("<dxf>" + s.Dxfs.Dxfs[idx].Dxf + "</dxf>")).
+		Decode(&d); err != nil && err != io.EOF {
+		return nil, err
+	}
+	style := &Style{}
+	if d.Font != nil {
+		style.Font = f.getFont(d.Font)
+	}
+	if d.Fill != nil {
+		style.Fill = f.getFill(d.Fill)
+	}
+	if d.Border != nil {
+		style.Border = f.getBorders(d.Border)
+	}
+	if d.Alignment != nil {
+		style.Alignment = getAlignment(d.Alignment)
+	}
+	if d.Protection != nil {
+		style.Protection = getProtection(d.Protection)
+	}
+	if d.NumFmt != nil {
+		if _, ok := builtInNumFmt[d.NumFmt.NumFmtID]; ok {
+			style.NumFmt = d.NumFmt.NumFmtID
+		} else {
+			style.CustomNumFmt = stringPtr(d.NumFmt.FormatCode)
+		}
+	}
+	return style, nil
 }
 
 // GetDefaultFont provides the default font name currently set in the workbook
@@ -2357,31 +2484,32 @@ func getFillID(styleSheet *xlsxStyleSheet, style *Style) (fillID int) {
 	return
 }
 
+// fillPatterns defined the pattern types of the cell fill by index.
+var fillPatterns = []string{
+	"none",
+	"solid",
+	"mediumGray",
+	"darkGray",
+	"lightGray",
+	"darkHorizontal",
+	"darkVertical",
+	"darkDown",
+	"darkUp",
+	"darkGrid",
+	"darkTrellis",
+	"lightHorizontal",
+	"lightVertical",
+	"lightDown",
+	"lightUp",
+	"lightGrid",
+	"lightTrellis",
+	"gray125",
+	"gray0625",
+}
+
 // newFills provides a function to add fill elements in the styles.xml by
 // given cell format settings.
 func newFills(style *Style, fg bool) *xlsxFill {
-	var patterns = []string{
-		"none",
-		"solid",
-		"mediumGray",
-		"darkGray",
-		"lightGray",
-		"darkHorizontal",
-		"darkVertical",
-		"darkDown",
-		"darkUp",
-		"darkGrid",
-		"darkTrellis",
-		"lightHorizontal",
-		"lightVertical",
-		"lightDown",
-		"lightUp",
-		"lightGrid",
-		"lightTrellis",
-		"gray125",
-		"gray0625",
-	}
-
 	var variants = []float64{
 		90,
 		0,
@@ -2425,7 +2553,7 @@ func newFills(style *Style, fg bool) *xlsxFill {
 			break
 		}
 		var pattern xlsxPatternFill
-		pattern.PatternType = patterns[style.Fill.Pattern]
+		pattern.PatternType = fillPatterns[style.Fill.Pattern]
 		if fg {
 			if pattern.FgColor == nil {
 				pattern.FgColor = new(xlsxColor)
@@ -2491,26 +2619,27 @@ func getBorderID(styleSheet *xlsxStyleSheet, style *Style) (borderID int) {
 	return
 }
 
+// borderStyles defined the line styles of the cell border by index.
+var borderStyles = []string{
+	"none",
+	"thin",
+	"medium",
+	"dashed",
+	"dotted",
+	"thick",
+	"double",
+	"hair",
+	"mediumDashed",
+	"dashDot",
+	"mediumDashDot",
+	"dashDotDot",
+	"mediumDashDotDot",
+	"slantDashDot",
+}
+
 // newBorders provides a function to add border elements in the styles.xml by
 // given borders format settings.
 func newBorders(style *Style) *xlsxBorder {
-	var styles = []string{
-		"none",
-		"thin",
-		"medium",
-		"dashed",
-		"dotted",
-		"thick",
-		"double",
-		"hair",
-		"mediumDashed",
-		"dashDot",
-		"mediumDashDot",
-		"dashDotDot",
-		"mediumDashDotDot",
-		"slantDashDot",
-	}
-
 	var border xlsxBorder
 	for _, v := range style.Border {
 		if 0 <= v.Style && v.Style < 14 {
@@ -2518,23 +2647,23 @@ func newBorders(style *Style) *xlsxBorder {
 			color.RGB = getPaletteColor(v.Color)
 			switch v.Type {
 			case "left":
-				border.Left.Style = styles[v.Style]
+				border.Left.Style = borderStyles[v.Style]
 				border.Left.Color = &color
 			case "right":
-				border.Right.Style = styles[v.Style]
+				border.Right.Style = borderStyles[v.Style]
 				border.Right.Color = &color
 			case "top":
-				border.Top.Style = styles[v.Style]
+				border.Top.Style = borderStyles[v.Style]
 				border.Top.Color = &color
 			case "bottom":
-				border.Bottom.Style = styles[v.Style]
+				border.Bottom.Style = borderStyles[v.Style]
 				border.Bottom.Color = &color
 			case "diagonalUp":
-				border.Diagonal.Style = styles[v.Style]
+				border.Diagonal.Style = borderStyles[v.Style]
 				border.Diagonal.Color = &color
 				border.DiagonalUp = true
 			case "diagonalDown":
-				border.Diagonal.Style = styles[v.Style]
+				border.Diagonal.Style = borderStyles[v.Style]
 				border.Diagonal.Color = &color
 				border.DiagonalDown = true
 			}
@@ -2592,6 +2721,203 @@ func (f *File) GetCellStyle(sheet, axis string) (int, error) {
 	return f.prepareCellStyle(ws, col, cellData.S), err
 }
 
+// GetStyle provides a function to get the style settings by given style index,
+// the font, fill, borders, alignment, protection and number format of the
+// style will be returned, the theme colors and indexed colors will be
+// resolved as the RGB color code. The returned style can be used to create a
+// new style by the NewStyle function. For example, get the style of the cell
+// Sheet1!A1 and create a new style with bold font based on it:
+//
+//    styleID, err := f.GetCellStyle("Sheet1", "A1")
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    style, err := f.GetStyle(styleID)
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    if style.Font == nil {
+//        style.Font = &xlsx.Font{}
+//    }
+//    style.Font.Bold = true
+//    styleID, err = f.NewStyle(style)
+//
+func (f *File) GetStyle(styleID int) (*Style, error) {
+	if styleID < 0 {
+		return nil, newInvalidStyleID(styleID)
+	}
+	s := f.stylesReader()
+	s.Lock()
+	defer s.Unlock()
+	if s.CellXfs == nil || styleID >= len(s.CellXfs.Xf) {
+		return nil, newStyleNotExistError(styleID)
+	}
+	return f.getXfStyle(s, s.CellXfs.Xf[styleID]), nil
+}
+
+// getXfStyle provides a function to get the style settings by given
+// formatting record.
+func (f *File) getXfStyle(s *xlsxStyleSheet, xf xlsxXf) *Style {
+	style := &Style{}
+	if xf.FontID != nil && *xf.FontID > 0 && s.Fonts != nil && *xf.FontID < len(s.Fonts.Font) {
+		style.Font = f.getFont(s.Fonts.Font[*xf.FontID])
+	}
+	if xf.FillID != nil && *xf.FillID > 0 && s.Fills != nil && *xf.FillID < len(s.Fills.Fill) {
+		style.Fill = f.getFill(s.Fills.Fill[*xf.FillID])
+	}
+	if xf.BorderID != nil && *xf.BorderID > 0 && s.Borders != nil && *xf.BorderID < len(s.Borders.Border) {
+		style.Border = f.getBorders(s.Borders.Border[*xf.BorderID])
+	}
+	if xf.Alignment != nil && ((xf.ApplyAlignment != nil && *xf.ApplyAlignment) ||
+		(xf.ApplyAlignment == nil && *xf.Alignment != xlsxAlignment{})) {
+		style.Alignment = getAlignment(xf.Alignment)
+	}
+	if xf.Protection != nil && (xf.ApplyProtection == nil || *xf.ApplyProtection) {
+		style.Protection = getProtection(xf.Protection)
+	}
+	if xf.NumFmtID != nil {
+		if _, ok := builtInNumFmt[*xf.NumFmtID]; ok {
+			style.NumFmt = *xf.NumFmtID
+		} else if s.NumFmts != nil {
+			for _, numFmt := range s.NumFmts.NumFmt {
+				if numFmt.NumFmtID == *xf.NumFmtID {
+					style.CustomNumFmt = stringPtr(numFmt.FormatCode)
+					break
+				}
+			}
+		}
+	}
+	return style
+}
+
+// getAlignment provides a function to get the alignment settings by given
+// alignment.
+func getAlignment(alignment *xlsxAlignment) *Alignment {
+	return &Alignment{
+		Horizontal:      alignment.Horizontal,
+		Indent:          alignment.Indent,
+		JustifyLastLine: alignment.JustifyLastLine,
+		ReadingOrder:    alignment.ReadingOrder,
+		RelativeIndent:  alignment.RelativeIndent,
+		ShrinkToFit:     alignment.ShrinkToFit,
+		TextRotation:    alignment.TextRotation,
+		Vertical:        alignment.Vertical,
+		WrapText:        alignment.WrapText,
+	}
+}
+
+// getProtection provides a function to get the protection settings by given
+// protection, the cells are locked by default.
+func getProtection(protection *xlsxProtection) *Protection {
+	p := &Protection{Locked: true}
+	if protection.Hidden != nil {
+		p.Hidden = *protection.Hidden
+	}
+	if protection.Locked != nil {
+		p.Locked = *protection.Locked
+	}
+	return p
+}
+
+// getFont provides a function to get the font settings by given font.
+func (f *File) getFont(font *xlsxFont) *Font {
+	fnt := &Font{}
+	if font.B != nil {
+		fnt.Bold = font.B.Val == nil || *font.B.Val
+	}
+	if font.I != nil {
+		fnt.Italic = font.I.Val == nil || *font.I.Val
+	}
+	if font.Strike != nil {
+		fnt.Strike = font.Strike.Val == nil || *font.Strike.Val
+	}
+	if font.U != nil {
+		fnt.Underline = "single"
+		if font.U.Val != nil {
+			fnt.Underline = *font.U.Val
+		}
+	}
+	if font.Name != nil && font.Name.Val != nil {
+		fnt.Family = *font.Name.Val
+	}
+	if font.Sz != nil && font.Sz.Val != nil {
+		fnt.Size = *font.Sz.Val
+	}
+	if color := f.getColor(font.Color); color != "" {
+		fnt.Color = "#" + color
+	}
+	return fnt
+}
+
+// getFill provides a function to get the fill settings by given fill.
+func (f *File) getFill(fill *xlsxFill) Fill {
+	var fl Fill
+	if fill.PatternFill != nil {
+		fl.Type = "pattern"
+		if fl.Pattern = inStrSlice(fillPatterns, fill.PatternFill.PatternType); fl.Pattern == -1 {
+			fl.Pattern = 0
+		}
+		color := f.getColor(fill.PatternFill.FgColor)
+		if color == "" {
+			color = f.getColor(fill.PatternFill.BgColor)
+		}
+		if color != "" {
+			fl.Color = []string{"#" + color}
+		}
+	}
+	if fill.GradientFill != nil {
+		fl.Type = "gradient"
+		switch fill.GradientFill.Type {
+		case "path":
+			fl.Shading = 4
+			if fill.GradientFill.Left == 0.5 && fill.GradientFill.Right == 0.5 &&
+				fill.GradientFill.Top == 0.5 && fill.GradientFill.Bottom == 0.5 {
+				fl.Shading = 5
+			}
+		default:
+			for shading, degree := range []float64{90, 0, 45, 135} {
+				if fill.GradientFill.Degree == degree {
+					fl.Shading = shading
+				}
+			}
+		}
+		for _, stop := range fill.GradientFill.Stop {
+			if color := f.getColor(&stop.Color); color != "" {
+				fl.Color = append(fl.Color, "#"+color)
+			}
+		}
+	}
+	return fl
+}
+
+// getBorders provides a function to get the borders settings by given border.
+func (f *File) getBorders(border *xlsxBorder) []Border {
+	var borders []Border
+	for _, side := range []struct {
+		typ  string
+		line xlsxLine
+		ok   bool
+	}{
+		{"left", border.Left, true},
+		{"right", border.Right, true},
+		{"top", border.Top, true},
+		{"bottom", border.Bottom, true},
+		{"diagonalUp", border.Diagonal, border.DiagonalUp},
+		{"diagonalDown", border.Diagonal, border.DiagonalDown},
+	} {
+		idx := inStrSlice(borderStyles, side.line.Style)
+		if !side.ok || idx <= 0 {
+			continue
+		}
+		b := Border{Type: side.typ, Style: idx}
+		if color := f.getColor(side.line.Color); color != "" {
+			b.Color = "#" + color
+		}
+		borders = append(borders, b)
+	}
+	return borders
+}
+
 // SetCellStyle provides a function to add style attribute for cells by given
 // worksheet name, coordinate area and style ID. Note that diagonalDown and
 // diagonalUp type border should be use same color in the same coordinate
@@ -2751,7 +3077,24 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 //                   | max_type
 //                   | min_value
 //                   | max_value
+//                   | min_length
+//                   | max_length
 //                   | bar_color
+//                   | bar_solid
+//                   | bar_no_border
+//                   | bar_border_color
+//                   | bar_negative_color
+//                   | bar_negative_color_same
+//                   | bar_negative_border_color
+//                   | bar_negative_border_color_same
+//                   | bar_direction
+//                   | bar_axis_position
+//                   | bar_axis_color
+//                   | bar_only
+//     icon_set      | icon_style
+//                   | reverse_icons
+//                   | icons_only
+//                   | icons
 //     formula       | criteria
 //
 // The criteria parameter is used to set the criteria by which the cell data
@@ -2849,6 +3192,41 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 //
 //    f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"top","criteria":"=","format":%d,"value":"6","percent":true}]`, format))
 //
+// type: date - The date type is similar the cell type and uses the same
+// criteria and values. The values should be a date in the "YYYY-MM-DD" or
+// RFC 3339 format, and will be converted to the Excel serial date number:
+//
+//    // Hightlight cells rules: A Date Occurring...
+//    f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"date","criteria":"between","format":%d,"minimum":"2022-01-01","maximum":"2022-12-31"}]`, format))
+//
+// type: time - The time type is similar the cell type and uses the same
+// criteria and values. The values should be a time in the "hh:mm" or
+// "hh:mm:ss" format, and will be converted to the fraction of a day.
+//
+// type: text - The text type is used to specify Excel's "Specific Text" style
+// conditional format. It is used to do simple string matching using the
+// criteria and value parameters, the criteria can be one of "containing",
+// "not containing", "begins with" and "ends with":
+//
+//    // Hightlight cells rules: Text that Contains...
+//    f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"text","criteria":"containing","format":%d,"value":"foo"}]`, format))
+//
+// type: time_period - The time_period type is used to specify Excel's "Dates
+// Occurring" style conditional format. The criteria can be one of
+// "yesterday", "today", "tomorrow", "last 7 days", "last week", "this week",
+// "next week", "last month", "this month" and "next month":
+//
+//    // Hightlight cells rules: A Date Occurring: Last Week.
+//    f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"time_period","criteria":"last week","format":%d}]`, format))
+//
+// type: blanks - The blanks type is used to highlight blank cells in a range.
+// The no_blanks type is the opposite of it, the errors and no_errors types
+// are used to highlight the cells with or without formula errors in the same
+// way:
+//
+//    // Hightlight cells rules: Format only cells that contain blanks.
+//    f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"blanks","format":%d}]`, format))
+//
 // type: 2_color_scale - The 2_color_scale type is used to specify Excel's "2
 // Color Scale" style conditional format:
 //
@@ -2909,55 +3287,312 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 //
 // max_color - Same as min_color, see above.
 //
-// bar_color - Used for data_bar. Same as min_color, see above.
+// bar_color - Used for data_bar. Same as min_color, see above. The default
+// bar color is #638EC6.
+//
+// The data bar will be created with the extended options which supported by
+// Excel 2010 and later versions, and stored in the worksheet extension list.
+// The min_type and max_type of the data bar default to min and max, which are
+// the "Automatic" types in Excel. The extended options of the data bar are:
+//
+// min_length - The minimum length of the data bar as a percentage of the
+// cell width, default is 0.
+//
+// max_length - The maximum length of the data bar as a percentage of the
+// cell width, default is 100.
+//
+// bar_solid - Use the solid fill instead of the gradient fill for the data
+// bar.
+//
+// bar_no_border - Hide the border of the data bar.
+//
+// bar_border_color - The border color of the data bar, default is the same as
+// the bar_color.
+//
+// bar_negative_color - The fill color of the negative data bar, default is
+// #FF0000.
+//
+// bar_negative_color_same - Use the bar_color for the negative data bar.
+//
+// bar_negative_border_color - The border color of the negative data bar,
+// default is #FF0000.
+//
+// bar_negative_border_color_same - Use the bar_border_color for the negative
+// data bar.
+//
+// bar_direction - The direction of the data bar, the available values are
+// context (default), left_to_right and right_to_left.
+//
+// bar_axis_position - The position of the axis between the positive and
+// negative data bars, the available values are automatic (default), middle
+// and none.
+//
+// bar_axis_color - The color of the axis, default is #000000.
+//
+// bar_only - Only show the data bar and hide the cell value.
+//
+// For example, create a solid fill data bar with the custom negative color:
+//
+//    // Data Bars: Solid Fill.
+//    f.SetConditionalFormat("Sheet1", "K1:K10", `[{"type":"data_bar","criteria":"=","bar_color":"#638EC6","bar_solid":true,"bar_negative_color":"#FFC000","bar_axis_position":"middle"}]`)
+//
+// type: icon_set - The icon_set type is used to specify Excel's "Icon Sets"
+// style conditional format. The icon_style parameter is used to specify the
+// icon set, default is 3_traffic_lights. The available icon styles are:
+//
+//    3_arrows                | 4_arrows         | 5_arrows
+//    3_arrows_gray           | 4_arrows_gray    | 5_arrows_gray
+//    3_flags                 | 4_red_to_black   | 5_ratings
+//    3_traffic_lights        | 4_ratings        | 5_quarters
+//    3_traffic_lights_rimmed | 4_traffic_lights | 5_boxes
+//    3_signs                 |                  |
+//    3_symbols               |                  |
+//    3_symbols_circled       |                  |
+//    3_stars                 |                  |
+//    3_triangles             |                  |
+//
+// The 3_stars, 3_triangles and 5_boxes icon sets are only supported by Excel
+// 2010 and later versions, they will be stored in the worksheet extension
+// list.
+//
+// reverse_icons - Used for icon_set. Reverse the order of the icons.
+//
+// icons_only - Used for icon_set. Only show the icons and hide the cell value.
+//
+// icons - Used for icon_set. Specify the thresholds of the icons from the
+// first (the highest values) icon, the last icon covers the remaining values,
+// so it doesn't need a threshold. The criteria can be ">=" (default) or ">",
+// the type can be num, percent (default), percentile or formula. The
+// unspecified thresholds use the percent which divided equally by the number
+// of the icons. For example, show the green arrow for the values greater than
+// or equal to 90, and the red arrow for the values less than 50:
+//
+//    // Icon sets: 3 arrows.
+//    f.SetConditionalFormat("Sheet1", "A1:A10", `[{"type":"icon_set","icon_style":"3_arrows","icons":[{"criteria":">=","type":"num","value":"90"},{"criteria":">=","type":"num","value":"50"}]}]`)
+//
+// priority - The priority of the rule, the rules with lower value will be
+// evaluated first. The default value is the position of the rule in the
+// format set, starting from 1.
+//
+// stop_if_true - Used for the rules with format, the lower priority rules will
+// not be evaluated if the conditional of this rule is met.
 //
 func (f *File) SetConditionalFormat(sheet, area, formatSet string) error {
-	var format []*formatConditional
-	err := json.Unmarshal([]byte(formatSet), &format)
-	if err != nil {
+	var format []ConditionalFormatOptions
+	if err := unmarshalFormatSet([]byte(formatSet), &format); err != nil {
 		return err
 	}
-	drawContFmtFunc := map[string]func(p int, ct string, fmtCond *formatConditional) *xlsxCfRule{
-		"cellIs":          drawCondFmtCellIs,
-		"top10":           drawCondFmtTop10,
-		"aboveAverage":    drawCondFmtAboveAverage,
-		"duplicateValues": drawCondFmtDuplicateUniqueValues,
-		"uniqueValues":    drawCondFmtDuplicateUniqueValues,
-		"2_color_scale":   drawCondFmtColorScale,
-		"3_color_scale":   drawCondFmtColorScale,
-		"dataBar":         drawCondFmtDataBar,
-		"expression":      drawConfFmtExp,
-	}
+	return f.SetConditionalFormatWithOptions(sheet, area, format)
+}
 
+// SetConditionalFormatWithOptions provides a function to create conditional
+// formatting rules by given worksheet name, range reference and the rules
+// options. The settings are the same as the SetConditionalFormat function.
+// For example, highlight cells with values greater than 6 in the range
+// A1:A10 of Sheet1:
+//
+//    format, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511"},"fill":{"type":"pattern","color":["#FEC7CE"],"pattern":1}}`)
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    err = f.SetConditionalFormatWithOptions("Sheet1", "A1:A10", []xlsx.ConditionalFormatOptions{
+//        {Type: "cell", Criteria: ">", Format: format, Value: "6"},
+//    })
+//
+func (f *File) SetConditionalFormatWithOptions(sheet, area string, opts []ConditionalFormatOptions) error {
+	drawContFmtFunc := map[string]func(p int, ct, ref string, fmtCond *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule){
+		"cellIs":            drawCondFmtCellIs,
+		"top10":             drawCondFmtTop10,
+		"aboveAverage":      drawCondFmtAboveAverage,
+		"duplicateValues":   drawCondFmtDuplicateUniqueValues,
+		"uniqueValues":      drawCondFmtDuplicateUniqueValues,
+		"text":              drawCondFmtText,
+		"timePeriod":        drawCondFmtTimePeriod,
+		"containsBlanks":    drawCondFmtBlanksErrors,
+		"notContainsBlanks": drawCondFmtBlanksErrors,
+		"containsErrors":    drawCondFmtBlanksErrors,
+		"notContainsErrors": drawCondFmtBlanksErrors,
+		"2_color_scale":     drawCondFmtColorScale,
+		"3_color_scale":     drawCondFmtColorScale,
+		"dataBar":           drawCondFmtDataBar,
+		"iconSet":           drawCondFmtIconSet,
+		"expression":        drawConfFmtExp,
+	}
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
-	cfRule := []*xlsxCfRule{}
-	for p, v := range format {
-		var vt, ct string
-		var ok bool
+	// The formulas of the rules are relative to the top-left cell of the range.
+	var ref string
+	if refs := strings.Fields(area); len(refs) > 0 {
+		ref = strings.Replace(strings.Split(refs[0], ":")[0], "$", "", -1)
+	}
+	cfRule, x14CfRule := []*xlsxCfRule{}, []*xlsxX14CfRule{}
+	for p := range opts {
+		v := opts[p]
 		// "type" is a required parameter, check for valid validation types.
-		vt, ok = validType[v.Type]
-		if ok {
-			// Check for valid criteria types.
-			ct, ok = criteriaType[v.Criteria]
-			if ok || vt == "expression" {
-				drawfunc, ok := drawContFmtFunc[vt]
-				if ok {
-					cfRule = append(cfRule, drawfunc(p, ct, v))
-				}
+		vt, ct, err := validateCondFmtOptions(&v)
+		if err != nil {
+			return err
+		}
+		rule, x14Rule := drawContFmtFunc[vt](p, ct, ref, &v)
+		if rule == nil && x14Rule == nil {
+			return newUnsupportedOptionError("conditional format criteria", v.Criteria)
+		}
+		if rule != nil {
+			if v.Priority > 0 {
+				rule.Priority = v.Priority
 			}
+			rule.StopIfTrue = v.StopIfTrue
+			cfRule = append(cfRule, rule)
+		}
+		if x14Rule != nil {
+			// The data bar rule linked by the rule ID has no priority.
+			if v.Priority > 0 && x14Rule.Priority > 0 {
+				x14Rule.Priority = v.Priority
+			}
+			x14CfRule = append(x14CfRule, x14Rule)
 		}
 	}
 
-	ws.ConditionalFormatting = append(ws.ConditionalFormatting, &xlsxConditionalFormatting{
-		SQRef:  area,
-		CfRule: cfRule,
-	})
+	if len(cfRule) > 0 || len(x14CfRule) == 0 {
+		ws.ConditionalFormatting = append(ws.ConditionalFormatting, &xlsxConditionalFormatting{
+			SQRef:  area,
+			CfRule: cfRule,
+		})
+	}
+	if len(x14CfRule) > 0 {
+		if err = f.appendCondFmtExt(ws, &xlsxX14ConditionalFormatting{
+			XMLNSXM: NameSpaceSpreadSheetExcel2006Main.Value,
+			CfRule:  x14CfRule,
+			SQRef:   area,
+		}); err != nil {
+			return err
+		}
+		f.addSheetNameSpace(sheet, NameSpaceSpreadSheetX14)
+	}
+	return err
+}
+
+// validateCondFmtOptions provides a function to validate the settings of the
+// conditional formatting rule, and returns the rule type and criteria type.
+func validateCondFmtOptions(format *ConditionalFormatOptions) (string, string, error) {
+	vt, ok := validType[format.Type]
+	if !ok {
+		return vt, "", newUnsupportedOptionError("conditional format type", format.Type)
+	}
+	// Only the cell, text and time period rules require the criteria, and
+	// the criteria of the formula rule is the formula expression.
+	ct, ok := criteriaType[format.Criteria]
+	if !ok && vt != "expression" && (format.Criteria != "" || inStrSlice([]string{"cellIs", "text", "timePeriod"}, vt) != -1) {
+		return vt, ct, newUnsupportedOptionError("conditional format criteria", format.Criteria)
+	}
+	valueTypes := []string{"", "min", "max", "num", "percent", "percentile", "formula"}
+	for _, typ := range []string{format.MinType, format.MidType, format.MaxType} {
+		if inStrSlice(valueTypes, typ) == -1 {
+			return vt, ct, newUnsupportedOptionError("conditional format value type", typ)
+		}
+	}
+	if _, ok := iconSetTypes[format.IconStyle]; !ok && format.IconStyle != "" {
+		return vt, ct, newUnsupportedOptionError("icon style", format.IconStyle)
+	}
+	for _, icon := range format.Icons {
+		if icon == nil {
+			continue
+		}
+		if inStrSlice([]string{"", ">=", ">"}, icon.Criteria) == -1 {
+			return vt, ct, newUnsupportedOptionError("icon criteria", icon.Criteria)
+		}
+		if inStrSlice([]string{"", "num", "percent", "percentile", "formula"}, icon.Type) == -1 {
+			return vt, ct, newUnsupportedOptionError("icon value type", icon.Type)
+		}
+	}
+	if inStrSlice([]string{"", "context", "left_to_right", "right_to_left"}, format.BarDirection) == -1 {
+		return vt, ct, newUnsupportedOptionError("bar direction", format.BarDirection)
+	}
+	if inStrSlice([]string{"", "automatic", "middle", "none"}, format.BarAxisPosition) == -1 {
+		return vt, ct, newUnsupportedOptionError("bar axis position", format.BarAxisPosition)
+	}
+	return vt, ct, nil
+}
+
+// getCondFmtExt provides a function to decode the worksheet extension list
+// and the conditional formattings extension in it. The returned index will be
+// -1 if the conditional formattings extension doesn't exist.
+func (f *File) getCondFmtExt(ws *xlsxWorksheet) (*decodeWorksheetExt, *decodeX14ConditionalFormattings, int, error) {
+	var err error
+	decodeExtLst, condFmts := new(decodeWorksheetExt), new(decodeX14ConditionalFormattings)
+	if ws.ExtLst == nil {
+		return decodeExtLst, condFmts, -1, err
+	}
+	if err = f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return decodeExtLst, condFmts, -1, err
+	}
+	for idx, ext := range decodeExtLst.Ext {
+		if ext.URI == ExtURIConditionalFormattings {
+			if err = f.xmlNewDecoder(strings.NewReader(ext.Content)).
+				Decode(condFmts); err != nil && err != io.EOF {
+				return decodeExtLst, condFmts, idx, err
+			}
+			return decodeExtLst, condFmts, idx, nil
+		}
+	}
+	return decodeExtLst, condFmts, -1, nil
+}
+
+// setCondFmtExt provides a function to set the conditional formattings
+// extension of the worksheet by given decoded extension list, index of the
+// conditional formattings extension and its content. The extension will be
+// removed if the content is empty.
+func (f *File) setCondFmtExt(ws *xlsxWorksheet, decodeExtLst *decodeWorksheetExt, idx int, content string) error {
+	if content == "" {
+		if idx != -1 {
+			decodeExtLst.Ext = append(decodeExtLst.Ext[:idx], decodeExtLst.Ext[idx+1:]...)
+		}
+	} else {
+		condFmtsBytes, err := xml.Marshal(&xlsxX14ConditionalFormattings{Content: content})
+		if err != nil {
+			return err
+		}
+		if idx == -1 {
+			// The conditional formattings should be the first extension of
+			// the worksheet.
+			decodeExtLst.Ext = append([]*xlsxWorksheetExt{{
+				URI: ExtURIConditionalFormattings, Content: string(condFmtsBytes),
+			}}, decodeExtLst.Ext...)
+		} else {
+			decodeExtLst.Ext[idx].Content = string(condFmtsBytes)
+		}
+	}
+	extLstBytes, err := xml.Marshal(decodeExtLst)
+	if err != nil {
+		return err
+	}
+	ws.ExtLst = &xlsxExtLst{
+		Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>"),
+	}
+	if ws.ExtLst.Ext == "" {
+		ws.ExtLst = nil
+	}
 	return err
 }
 
+// appendCondFmtExt provides a function to append the conditional formatting
+// which only supported by Excel 2010 and later versions to the worksheet
+// extension list.
+func (f *File) appendCondFmtExt(ws *xlsxWorksheet, cf *xlsxX14ConditionalFormatting) error {
+	decodeExtLst, condFmts, idx, err := f.getCondFmtExt(ws)
+	if err != nil {
+		return err
+	}
+	cfBytes, err := xml.Marshal(cf)
+	if err != nil {
+		return err
+	}
+	return f.setCondFmtExt(ws, decodeExtLst, idx, condFmts.Content+string(cfBytes))
+}
+
 // UnsetConditionalFormat provides a function to unset the conditional format
 // by given worksheet name and range.
 func (f *File) UnsetConditionalFormat(sheet, area string) error {
@@ -2965,19 +3600,254 @@ func (f *File) UnsetConditionalFormat(sheet, area string) error {
 	if err != nil {
 		return err
 	}
-	for i, cf := range ws.ConditionalFormatting {
-		if cf.SQRef == area {
-			ws.ConditionalFormatting = append(ws.ConditionalFormatting[:i], ws.ConditionalFormatting[i+1:]...)
-			return nil
+	condFmts := ws.ConditionalFormatting[:0]
+	for _, cf := range ws.ConditionalFormatting {
+		if cf.SQRef != area {
+			condFmts = append(condFmts, cf)
+		}
+	}
+	ws.ConditionalFormatting = condFmts
+	// Remove the conditional formatting in the worksheet extension list.
+	decodeExtLst, x14CondFmts, idx, err := f.getCondFmtExt(ws)
+	if err != nil || idx == -1 {
+		return err
+	}
+	var content string
+	for _, cf := range x14CondFmts.CondFmt {
+		if cf.SQRef != area {
+			content += fmt.Sprintf(`<x14:conditionalFormatting xmlns:xm="%s">%s</x14:conditionalFormatting>`,
+				NameSpaceSpreadSheetExcel2006Main.Value, cf.Content)
+		}
+	}
+	return f.setCondFmtExt(ws, decodeExtLst, idx, content)
+}
+
+// GetConditionalFormats returns the conditional formatting rules by given
+// worksheet name. The result is a map of the range reference and the rules
+// applied to it, including the rules stored in the worksheet extension list.
+// The returned rules can be serialized to JSON and used with the
+// SetConditionalFormat function, for example, copy the conditional formats
+// from Sheet1 to Sheet2 in the same workbook:
+//
+//    formats, err := f.GetConditionalFormats("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for area, rules := range formats {
+//        formatSet, _ := json.Marshal(rules)
+//        if err := f.SetConditionalFormat("Sheet2", area, string(formatSet)); err != nil {
+//            fmt.Println(err)
+//        }
+//    }
+//
+// The date and time type rules will be returned as the cell type, and the
+// value of them is the Excel serial number.
+func (f *File) GetConditionalFormats(sheet string) (map[string][]ConditionalFormatOptions, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	formats := make(map[string][]ConditionalFormatOptions)
+	_, condFmts, _, err := f.getCondFmtExt(ws)
+	if err != nil {
+		return formats, err
+	}
+	x14DataBars := make(map[string]*decodeX14DataBar)
+	for _, cf := range condFmts.CondFmt {
+		for _, rule := range cf.CfRule {
+			if rule.Type == "dataBar" && rule.DataBar != nil {
+				x14DataBars[rule.ID] = rule.DataBar
+			}
+		}
+	}
+	for _, cf := range ws.ConditionalFormatting {
+		for _, rule := range cf.CfRule {
+			if opts, ok := f.getCondFmtOptions(rule, x14DataBars); ok {
+				formats[cf.SQRef] = append(formats[cf.SQRef], opts)
+			}
+		}
+	}
+	for _, cf := range condFmts.CondFmt {
+		for _, rule := range cf.CfRule {
+			if opts, ok := getX14CondFmtOptions(rule); ok {
+				formats[cf.SQRef] = append(formats[cf.SQRef], opts)
+			}
+		}
+	}
+	return formats, err
+}
+
+// getCondFmtOptions provides a function to get the conditional formatting
+// rule settings by given conditional formatting rule and the data bars in the
+// worksheet extension list. It returns false if the type of the rule is
+// unsupported.
+func (f *File) getCondFmtOptions(rule *xlsxCfRule, x14DataBars map[string]*decodeX14DataBar) (ConditionalFormatOptions, bool) {
+	opts := ConditionalFormatOptions{Criteria: "=", Priority: rule.Priority, StopIfTrue: rule.StopIfTrue}
+	if rule.DxfID != nil {
+		opts.Format = *rule.DxfID
+		opts.Style, _ = f.GetConditionalStyle(*rule.DxfID)
+	}
+	switch rule.Type {
+	case "cellIs":
+		opts.Type, opts.Criteria = "cell", condFmtCriteria[rule.Operator]
+		if rule.Operator == "between" || rule.Operator == "notBetween" {
+			if len(rule.Formula) == 2 {
+				opts.Minimum, opts.Maximum = rule.Formula[0], rule.Formula[1]
+			}
+		} else if len(rule.Formula) > 0 {
+			opts.Value = rule.Formula[0]
+		}
+	case "top10":
+		opts.Type, opts.Value, opts.Percent = "top", strconv.Itoa(rule.Rank), rule.Percent
+		if rule.Bottom {
+			opts.Type = "bottom"
+		}
+	case "aboveAverage":
+		opts.Type, opts.AboveAverage = "average", rule.AboveAverage == nil || *rule.AboveAverage
+	case "duplicateValues":
+		opts.Type = "duplicate"
+	case "uniqueValues":
+		opts.Type = "unique"
+	case "containsText", "notContainsText", "beginsWith", "endsWith":
+		opts.Type, opts.Criteria, opts.Value = "text", condFmtCriteria[rule.Type], rule.Text
+	case "timePeriod":
+		opts.Type, opts.Criteria = "time_period", condFmtCriteria[rule.TimePeriod]
+	case "containsBlanks", "notContainsBlanks", "containsErrors", "notContainsErrors":
+		opts.Criteria = ""
+		opts.Type = map[string]string{
+			"containsBlanks": "blanks", "notContainsBlanks": "no_blanks",
+			"containsErrors": "errors", "notContainsErrors": "no_errors",
+		}[rule.Type]
+	case "colorScale":
+		if rule.ColorScale == nil || len(rule.ColorScale.Cfvo) < 2 || len(rule.ColorScale.Cfvo) > 3 ||
+			len(rule.ColorScale.Color) != len(rule.ColorScale.Cfvo) {
+			return opts, false
+		}
+		cfvo, color := rule.ColorScale.Cfvo, rule.ColorScale.Color
+		opts.Type = fmt.Sprintf("%d_color_scale", len(cfvo))
+		opts.MinType, opts.MinValue, opts.MinColor = cfvo[0].Type, cfvo[0].Val, f.getCondFmtColor(color[0])
+		if len(cfvo) == 3 {
+			opts.MidType, opts.MidValue, opts.MidColor = cfvo[1].Type, cfvo[1].Val, f.getCondFmtColor(color[1])
+		}
+		last := len(cfvo) - 1
+		opts.MaxType, opts.MaxValue, opts.MaxColor = cfvo[last].Type, cfvo[last].Val, f.getCondFmtColor(color[last])
+	case "dataBar":
+		if rule.DataBar == nil || len(rule.DataBar.Cfvo) != 2 {
+			return opts, false
+		}
+		cfvo := rule.DataBar.Cfvo
+		opts.Type = "data_bar"
+		opts.MinType, opts.MinValue = cfvo[0].Type, cfvo[0].Val
+		opts.MaxType, opts.MaxValue = cfvo[1].Type, cfvo[1].Val
+		if len(rule.DataBar.Color) > 0 {
+			opts.BarColor = f.getCondFmtColor(rule.DataBar.Color[0])
+		}
+		opts.BarOnly = rule.DataBar.ShowValue != nil && !*rule.DataBar.ShowValue
+		if rule.ExtLst != nil {
+			decodeExtLst := new(decodeCfRuleExtLst)
+			_ = f.xmlNewDecoder(strings.NewReader("<extLst>" + rule.ExtLst.Ext + "</extLst>")).Decode(decodeExtLst)
+			for _, ext := range decodeExtLst.Ext {
+				if dataBar, ok := x14DataBars[ext.ID]; ok && ext.URI == ExtURIConditionalFormattingRuleID {
+					f.setCondFmtDataBarOptions(&opts, dataBar)
+				}
+			}
+		}
+	case "iconSet":
+		if rule.IconSet == nil {
+			return opts, false
+		}
+		opts.Criteria = ""
+		setCondFmtIconSetOptions(&opts, rule.IconSet.IconSet, rule.IconSet.ShowValue, rule.IconSet.Reverse, rule.IconSet.Cfvo)
+	case "expression":
+		opts.Type, opts.Criteria = "formula", ""
+		if len(rule.Formula) > 0 {
+			opts.Criteria = rule.Formula[0]
+		}
+	default:
+		return opts, false
+	}
+	return opts, true
+}
+
+// setCondFmtDataBarOptions provides a function to set the extended options of
+// the data bar conditional formatting rule by given data bar in the worksheet
+// extension list.
+func (f *File) setCondFmtDataBarOptions(opts *ConditionalFormatOptions, dataBar *decodeX14DataBar) {
+	if len(dataBar.Cfvo) == 2 {
+		opts.MinType, opts.MinValue = dataBar.Cfvo[0].Type, dataBar.Cfvo[0].F
+		opts.MaxType, opts.MaxValue = dataBar.Cfvo[1].Type, dataBar.Cfvo[1].F
+		if opts.MinType == "autoMin" {
+			opts.MinType = "min"
+		}
+		if opts.MaxType == "autoMax" {
+			opts.MaxType = "max"
+		}
+	}
+	opts.MinLength, opts.MaxLength = strconv.Itoa(dataBar.MinLength), strconv.Itoa(dataBar.MaxLength)
+	opts.BarOnly = dataBar.ShowValue != nil && !*dataBar.ShowValue
+	opts.BarSolid = dataBar.Gradient != nil && !*dataBar.Gradient
+	opts.BarNoBorder = !dataBar.Border
+	opts.BarBorderColor = f.getCondFmtColor(dataBar.BorderColor)
+	opts.BarNegativeColorSame = dataBar.NegativeBarColorSameAsPositive
+	opts.BarNegativeColor = f.getCondFmtColor(dataBar.NegativeFillColor)
+	opts.BarNegativeBorderColorSame = dataBar.NegativeBarBorderColorSameAsPositive == nil || *dataBar.NegativeBarBorderColorSameAsPositive
+	opts.BarNegativeBorderColor = f.getCondFmtColor(dataBar.NegativeBorderColor)
+	opts.BarDirection = map[string]string{"leftToRight": "left_to_right", "rightToLeft": "right_to_left"}[dataBar.Direction]
+	opts.BarAxisPosition = map[string]string{"middle": "middle", "none": "none"}[dataBar.AxisPosition]
+	opts.BarAxisColor = f.getCondFmtColor(dataBar.AxisColor)
+}
+
+// getX14CondFmtOptions provides a function to get the conditional formatting
+// rule settings by given conditional formatting rule in the worksheet
+// extension list. It returns false if the type of the rule is unsupported.
+func getX14CondFmtOptions(rule *decodeX14CfRule) (ConditionalFormatOptions, bool) {
+	opts := ConditionalFormatOptions{Priority: rule.Priority}
+	if rule.Type != "iconSet" || rule.IconSet == nil {
+		return opts, false
+	}
+	var cfvo []*xlsxCfvo
+	for _, c := range rule.IconSet.Cfvo {
+		cfvo = append(cfvo, &xlsxCfvo{Type: c.Type, Gte: c.Gte, Val: c.F})
+	}
+	setCondFmtIconSetOptions(&opts, rule.IconSet.IconSet, rule.IconSet.ShowValue, rule.IconSet.Reverse, cfvo)
+	return opts, true
+}
+
+// setCondFmtIconSetOptions provides a function to set the icon set settings
+// of the conditional formatting rule by given icon set name, show value,
+// reverse and the thresholds of the icons.
+func setCondFmtIconSetOptions(opts *ConditionalFormatOptions, iconSet string, showValue *bool, reverse bool, cfvo []*xlsxCfvo) {
+	opts.Type, opts.IconStyle = "icon_set", "3_traffic_lights"
+	for style, name := range iconSetTypes {
+		if name == iconSet {
+			opts.IconStyle = style
 		}
 	}
-	return nil
+	opts.ReverseIcons, opts.IconsOnly = reverse, showValue != nil && !*showValue
+	// The thresholds of the icons are specified from the highest values.
+	for i := len(cfvo) - 1; i > 0; i-- {
+		icon := &ConditionalFormatIconOptions{Criteria: ">=", Type: cfvo[i].Type, Value: cfvo[i].Val}
+		if cfvo[i].Gte != nil && !*cfvo[i].Gte {
+			icon.Criteria = ">"
+		}
+		opts.Icons = append(opts.Icons, icon)
+	}
+}
+
+// getCondFmtColor provides a function to get the color of the conditional
+// formatting rule in the "#RRGGBB" format by given color settings.
+func (f *File) getCondFmtColor(color *xlsxColor) string {
+	if rgb := f.getColor(color); rgb != "" {
+		return "#" + rgb
+	}
+	return ""
 }
 
 // drawCondFmtCellIs provides a function to create conditional formatting rule
 // for cell value (include between, not between, equal, not equal, greater
 // than and less than) by given priority, criteria type and format settings.
-func drawCondFmtCellIs(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtCellIs(p int, ct, ref string, format *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule) {
 	c := &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
@@ -2987,20 +3857,45 @@ func drawCondFmtCellIs(p int, ct string, format *formatConditional) *xlsxCfRule
 	// "between" and "not between" criteria require 2 values.
 	_, ok := map[string]bool{"between": true, "notBetween": true}[ct]
 	if ok {
-		c.Formula = append(c.Formula, format.Minimum)
-		c.Formula = append(c.Formula, format.Maximum)
+		c.Formula = append(c.Formula, condFmtDateTimeValue(format.Type, format.Minimum))
+		c.Formula = append(c.Formula, condFmtDateTimeValue(format.Type, format.Maximum))
 	}
 	_, ok = map[string]bool{"equal": true, "notEqual": true, "greaterThan": true, "lessThan": true, "greaterThanOrEqual": true, "lessThanOrEqual": true, "containsText": true, "notContains": true, "beginsWith": true, "endsWith": true}[ct]
 	if ok {
-		c.Formula = append(c.Formula, format.Value)
+		c.Formula = append(c.Formula, condFmtDateTimeValue(format.Type, format.Value))
 	}
-	return c
+	return c, nil
+}
+
+// condFmtDateTimeValue provides a function to convert the value of the date
+// and time type conditional formatting rule to the Excel serial number. The
+// value which isn't a valid date or time, such as the cell reference, will be
+// returned as it is.
+func condFmtDateTimeValue(typ, value string) string {
+	switch typ {
+	case "date":
+		for _, layout := range []string{"2006-01-02", "2006-01-02T15:04:05", time.RFC3339} {
+			if t, err := time.Parse(layout, value); err == nil {
+				if excelTime, err := timeToExcelTime(t); err == nil {
+					return strconv.FormatFloat(excelTime, 'f', -1, 64)
+				}
+			}
+		}
+	case "time":
+		for _, layout := range []string{"15:04", "15:04:05"} {
+			if t, err := time.Parse(layout, value); err == nil {
+				seconds := t.Hour()*3600 + t.Minute()*60 + t.Second()
+				return strconv.FormatFloat(float64(seconds)/86400, 'f', -1, 64)
+			}
+		}
+	}
+	return value
 }
 
 // drawCondFmtTop10 provides a function to create conditional formatting rule
 // for top N (default is top 10) by given priority, criteria type and format
 // settings.
-func drawCondFmtTop10(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtTop10(p int, ct, ref string, format *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule) {
 	c := &xlsxCfRule{
 		Priority: p + 1,
 		Bottom:   format.Type == "bottom",
@@ -3013,36 +3908,100 @@ func drawCondFmtTop10(p int, ct string, format *formatConditional) *xlsxCfRule {
 	if err == nil {
 		c.Rank = rank
 	}
-	return c
+	return c, nil
 }
 
 // drawCondFmtAboveAverage provides a function to create conditional
 // formatting rule for above average and below average by given priority,
 // criteria type and format settings.
-func drawCondFmtAboveAverage(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtAboveAverage(p int, ct, ref string, format *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule) {
 	return &xlsxCfRule{
 		Priority:     p + 1,
 		Type:         validType[format.Type],
 		AboveAverage: &format.AboveAverage,
 		DxfID:        &format.Format,
-	}
+	}, nil
 }
 
 // drawCondFmtDuplicateUniqueValues provides a function to create conditional
 // formatting rule for duplicate and unique values by given priority, criteria
 // type and format settings.
-func drawCondFmtDuplicateUniqueValues(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtDuplicateUniqueValues(p int, ct, ref string, format *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule) {
 	return &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
 		DxfID:    &format.Format,
+	}, nil
+}
+
+// drawCondFmtText provides a function to create conditional formatting rule
+// for specific text (include containing, not containing, begins with and ends
+// with) by given priority, criteria type, reference cell and format settings.
+func drawCondFmtText(p int, ct, ref string, format *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule) {
+	text := strings.Replace(format.Value, "\"", "\"\"", -1)
+	formulas := map[string]string{
+		"containsText": fmt.Sprintf("NOT(ISERROR(SEARCH(\"%s\",%s)))", text, ref),
+		"notContains":  fmt.Sprintf("ISERROR(SEARCH(\"%s\",%s))", text, ref),
+		"beginsWith":   fmt.Sprintf("LEFT(%s,%d)=\"%s\"", ref, len([]rune(format.Value)), text),
+		"endsWith":     fmt.Sprintf("RIGHT(%s,%d)=\"%s\"", ref, len([]rune(format.Value)), text),
+	}
+	formula, ok := formulas[ct]
+	if !ok {
+		return nil, nil
+	}
+	typ := ct
+	if ct == "notContains" {
+		typ = "notContainsText"
+	}
+	return &xlsxCfRule{
+		Priority: p + 1,
+		Type:     typ,
+		Operator: ct,
+		Text:     format.Value,
+		Formula:  []string{formula},
+		DxfID:    &format.Format,
+	}, nil
+}
+
+// drawCondFmtTimePeriod provides a function to create conditional formatting
+// rule for dates occurring by given priority, criteria type, reference cell
+// and format settings.
+func drawCondFmtTimePeriod(p int, ct, ref string, format *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule) {
+	formula, ok := condFmtTimePeriodFormulas[ct]
+	if !ok {
+		return nil, nil
 	}
+	return &xlsxCfRule{
+		Priority:   p + 1,
+		Type:       validType[format.Type],
+		TimePeriod: ct,
+		Formula:    []string{fmt.Sprintf(formula, ref)},
+		DxfID:      &format.Format,
+	}, nil
+}
+
+// drawCondFmtBlanksErrors provides a function to create conditional
+// formatting rule for blanks, no blanks, errors and no errors by given
+// priority, criteria type, reference cell and format settings.
+func drawCondFmtBlanksErrors(p int, ct, ref string, format *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule) {
+	formula := map[string]string{
+		"containsBlanks":    "LEN(TRIM(%s))=0",
+		"notContainsBlanks": "LEN(TRIM(%s))>0",
+		"containsErrors":    "ISERROR(%s)",
+		"notContainsErrors": "NOT(ISERROR(%s))",
+	}[validType[format.Type]]
+	return &xlsxCfRule{
+		Priority: p + 1,
+		Type:     validType[format.Type],
+		Formula:  []string{fmt.Sprintf(formula, ref)},
+		DxfID:    &format.Format,
+	}, nil
 }
 
 // drawCondFmtColorScale provides a function to create conditional formatting
 // rule for color scale (include 2 color scale and 3 color scale) by given
 // priority, criteria type and format settings.
-func drawCondFmtColorScale(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtColorScale(p int, ct, ref string, format *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule) {
 	minValue := format.MinValue
 	if minValue == "" {
 		minValue = "0"
@@ -3055,50 +4014,204 @@ func drawCondFmtColorScale(p int, ct string, format *formatConditional) *xlsxCfR
 	if midValue == "" {
 		midValue = "50"
 	}
+	// The default types and colors are the same as Excel's "Color Scales"
+	// menu.
+	minType, midType, maxType := format.MinType, format.MidType, format.MaxType
+	if minType == "" {
+		minType = "min"
+	}
+	if midType == "" {
+		midType = "percentile"
+	}
+	if maxType == "" {
+		maxType = "max"
+	}
+	minColor, midColor, maxColor := format.MinColor, format.MidColor, format.MaxColor
+	if minColor == "" {
+		minColor = map[string]string{"2_color_scale": "#FF7128", "3_color_scale": "#F8696B"}[format.Type]
+	}
+	if midColor == "" {
+		midColor = "#FFEB84"
+	}
+	if maxColor == "" {
+		maxColor = map[string]string{"2_color_scale": "#FFEF9C", "3_color_scale": "#63BE7B"}[format.Type]
+	}
 
 	c := &xlsxCfRule{
 		Priority: p + 1,
 		Type:     "colorScale",
 		ColorScale: &xlsxColorScale{
 			Cfvo: []*xlsxCfvo{
-				{Type: format.MinType, Val: minValue},
+				{Type: minType, Val: minValue},
 			},
 			Color: []*xlsxColor{
-				{RGB: getPaletteColor(format.MinColor)},
+				{RGB: getPaletteColor(minColor)},
 			},
 		},
 	}
 	if validType[format.Type] == "3_color_scale" {
-		c.ColorScale.Cfvo = append(c.ColorScale.Cfvo, &xlsxCfvo{Type: format.MidType, Val: midValue})
-		c.ColorScale.Color = append(c.ColorScale.Color, &xlsxColor{RGB: getPaletteColor(format.MidColor)})
+		c.ColorScale.Cfvo = append(c.ColorScale.Cfvo, &xlsxCfvo{Type: midType, Val: midValue})
+		c.ColorScale.Color = append(c.ColorScale.Color, &xlsxColor{RGB: getPaletteColor(midColor)})
 	}
-	c.ColorScale.Cfvo = append(c.ColorScale.Cfvo, &xlsxCfvo{Type: format.MaxType, Val: maxValue})
-	c.ColorScale.Color = append(c.ColorScale.Color, &xlsxColor{RGB: getPaletteColor(format.MaxColor)})
-	return c
+	c.ColorScale.Cfvo = append(c.ColorScale.Cfvo, &xlsxCfvo{Type: maxType, Val: maxValue})
+	c.ColorScale.Color = append(c.ColorScale.Color, &xlsxColor{RGB: getPaletteColor(maxColor)})
+	return c, nil
 }
 
 // drawCondFmtDataBar provides a function to create conditional formatting
-// rule for data bar by given priority, criteria type and format settings.
-func drawCondFmtDataBar(p int, ct string, format *formatConditional) *xlsxCfRule {
-	return &xlsxCfRule{
+// rule for data bar by given priority, criteria type and format settings. The
+// extended options of the data bar will be created as the x14 conditional
+// formatting rule, which linked with the rule by the rule ID.
+func drawCondFmtDataBar(p int, ct, ref string, format *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule) {
+	var (
+		id        = newGUID()
+		showValue *bool
+		cfvo      []*xlsxCfvo
+		x14Cfvo   []*xlsxX14Cfvo
+	)
+	for _, v := range []struct{ typ, val, defaultType, autoType string }{
+		{format.MinType, format.MinValue, "min", "autoMin"},
+		{format.MaxType, format.MaxValue, "max", "autoMax"},
+	} {
+		if v.typ == "" {
+			v.typ = v.defaultType
+		}
+		if v.typ == v.defaultType {
+			cfvo = append(cfvo, &xlsxCfvo{Type: v.typ})
+			x14Cfvo = append(x14Cfvo, &xlsxX14Cfvo{Type: v.autoType})
+			continue
+		}
+		if v.val == "" {
+			v.val = "0"
+		}
+		cfvo = append(cfvo, &xlsxCfvo{Type: v.typ, Val: v.val})
+		x14Cfvo = append(x14Cfvo, &xlsxX14Cfvo{Type: v.typ, F: v.val})
+	}
+	if format.BarOnly {
+		showValue = boolPtr(false)
+	}
+	barColor := format.BarColor
+	if barColor == "" {
+		barColor = "#638EC6"
+	}
+	dataBar := &xlsxX14DataBar{
+		MaxLength:                            100,
+		ShowValue:                            showValue,
+		Border:                               !format.BarNoBorder,
+		Direction:                            map[string]string{"left_to_right": "leftToRight", "right_to_left": "rightToLeft"}[format.BarDirection],
+		NegativeBarColorSameAsPositive:       format.BarNegativeColorSame,
+		NegativeBarBorderColorSameAsPositive: format.BarNegativeBorderColorSame,
+		AxisPosition:                         map[string]string{"middle": "middle", "none": "none"}[format.BarAxisPosition],
+		Cfvo:                                 x14Cfvo,
+	}
+	if minLength, err := strconv.Atoi(format.MinLength); err == nil {
+		dataBar.MinLength = minLength
+	}
+	if maxLength, err := strconv.Atoi(format.MaxLength); err == nil {
+		dataBar.MaxLength = maxLength
+	}
+	if format.BarSolid {
+		dataBar.Gradient = boolPtr(false)
+	}
+	if dataBar.Border {
+		dataBar.BorderColor = &xlsxColor{RGB: getPaletteColor(barColor)}
+		if format.BarBorderColor != "" {
+			dataBar.BorderColor.RGB = getPaletteColor(format.BarBorderColor)
+		}
+	}
+	if !dataBar.NegativeBarColorSameAsPositive {
+		dataBar.NegativeFillColor = &xlsxColor{RGB: getPaletteColor("#FF0000")}
+		if format.BarNegativeColor != "" {
+			dataBar.NegativeFillColor.RGB = getPaletteColor(format.BarNegativeColor)
+		}
+	}
+	if dataBar.Border && !dataBar.NegativeBarBorderColorSameAsPositive {
+		dataBar.NegativeBorderColor = &xlsxColor{RGB: getPaletteColor("#FF0000")}
+		if format.BarNegativeBorderColor != "" {
+			dataBar.NegativeBorderColor.RGB = getPaletteColor(format.BarNegativeBorderColor)
+		}
+	}
+	if dataBar.AxisPosition != "none" {
+		dataBar.AxisColor = &xlsxColor{RGB: getPaletteColor("#000000")}
+		if format.BarAxisColor != "" {
+			dataBar.AxisColor.RGB = getPaletteColor(format.BarAxisColor)
+		}
+	}
+	rule := &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
 		DataBar: &xlsxDataBar{
-			Cfvo:  []*xlsxCfvo{{Type: format.MinType}, {Type: format.MaxType}},
-			Color: []*xlsxColor{{RGB: getPaletteColor(format.BarColor)}},
+			ShowValue: showValue,
+			Cfvo:      cfvo,
+			Color:     []*xlsxColor{{RGB: getPaletteColor(barColor)}},
 		},
+		ExtLst: &xlsxExtLst{Ext: fmt.Sprintf(`<ext uri="%s" xmlns:x14="%s"><x14:id>%s</x14:id></ext>`,
+			ExtURIConditionalFormattingRuleID, NameSpaceSpreadSheetX14.Value, id)},
 	}
+	return rule, &xlsxX14CfRule{Type: validType[format.Type], ID: id, DataBar: dataBar}
+}
+
+// drawCondFmtIconSet provides a function to create conditional formatting
+// rule for icon set by given priority, criteria type and format settings. The
+// icon sets only supported by Excel 2010 and later versions will be created
+// as the x14 conditional formatting rule.
+func drawCondFmtIconSet(p int, ct, ref string, format *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule) {
+	iconStyle := format.IconStyle
+	if iconStyle == "" {
+		iconStyle = "3_traffic_lights"
+	}
+	iconSet, ok := iconSetTypes[iconStyle]
+	if !ok {
+		return nil, nil
+	}
+	// The first threshold is always the minimum value of the range, the
+	// thresholds of the icons are specified from the highest values.
+	count := int(iconSet[0] - '0')
+	cfvo := []*xlsxCfvo{{Type: "percent", Val: "0"}}
+	for i := 1; i < count; i++ {
+		c := &xlsxCfvo{Type: "percent", Val: strconv.Itoa(int(math.Round(float64(i) * 100 / float64(count))))}
+		if idx := count - 1 - i; idx < len(format.Icons) {
+			if icon := format.Icons[idx]; icon != nil {
+				if icon.Type != "" {
+					c.Type = icon.Type
+				}
+				if icon.Value != "" {
+					c.Val = icon.Value
+				}
+				if icon.Criteria == ">" {
+					c.Gte = boolPtr(false)
+				}
+			}
+		}
+		cfvo = append(cfvo, c)
+	}
+	var showValue *bool
+	if format.IconsOnly {
+		showValue = boolPtr(false)
+	}
+	if _, ok = map[string]bool{"3Stars": true, "3Triangles": true, "5Boxes": true}[iconSet]; ok {
+		x14IconSet := &xlsxX14IconSet{IconSet: iconSet, ShowValue: showValue, Reverse: format.ReverseIcons}
+		for _, c := range cfvo {
+			x14IconSet.Cfvo = append(x14IconSet.Cfvo, &xlsxX14Cfvo{Type: c.Type, Gte: c.Gte, F: c.Val})
+		}
+		return nil, &xlsxX14CfRule{Type: "iconSet", Priority: p + 1, ID: newGUID(), IconSet: x14IconSet}
+	}
+	return &xlsxCfRule{
+		Priority: p + 1,
+		Type:     validType[format.Type],
+		IconSet:  &xlsxIconSet{IconSet: iconSet, ShowValue: showValue, Reverse: format.ReverseIcons, Cfvo: cfvo},
+	}, nil
 }
 
 // drawConfFmtExp provides a function to create conditional formatting rule
 // for expression by given priority, criteria type and format settings.
-func drawConfFmtExp(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawConfFmtExp(p int, ct, ref string, format *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule) {
 	return &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
 		Formula:  []string{format.Criteria},
 		DxfID:    &format.Format,
-	}
+	}, nil
 }
 
 // getPaletteColor provides a function to convert the RBG color by given
@@ -3107,6 +4220,49 @@ func getPaletteColor(color string) string {
 	return "FF" + strings.Replace(strings.ToUpper(color), "#", "", -1)
 }
 
+// getColor provides a function to get the RGB color code without alpha channel
+// by given color settings, the theme colors and indexed colors will be
+// resolved, the tint value will be applied. It returns an empty string if the
+// color is automatic or can't be resolved.
+func (f *File) getColor(color *xlsxColor) string {
+	if color == nil {
+		return ""
+	}
+	var rgb string
+	switch {
+	case color.RGB != "" && len(color.RGB) >= 6:
+		rgb = color.RGB[len(color.RGB)-6:]
+	case color.Theme != nil:
+		if f.Theme == nil {
+			return ""
+		}
+		idx := *color.Theme
+		if idx < 4 {
+			idx = []int{1, 0, 3, 2}[idx]
+		}
+		children := f.Theme.ThemeElements.ClrScheme.Children
+		if idx < 0 || idx >= len(children) {
+			return ""
+		}
+		if children[idx].SrgbClr != nil && children[idx].SrgbClr.Val != nil {
+			rgb = *children[idx].SrgbClr.Val
+		} else if children[idx].SysClr != nil {
+			rgb = children[idx].SysClr.LastClr
+		}
+	case color.Indexed > 0 && color.Indexed < len(xlsDefaultPalette):
+		rgb = xlsDefaultPalette[color.Indexed]
+	default:
+		return ""
+	}
+	if len(rgb) != 6 {
+		return ""
+	}
+	if color.Tint != 0 {
+		rgb = ThemeColor(rgb, color.Tint)[2:]
+	}
+	return strings.ToUpper(rgb)
+}
+
 // themeReader provides a function to get the pointer to the xl/theme/theme1.xml
 // structure after deserialization.
 func (f *File) themeReader() *xlsxTheme {
@@ -1,6 +1,7 @@
 package xlsx
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"path/filepath"
@@ -175,6 +176,159 @@ func TestSetConditionalFormat(t *testing.T) {
 	}
 }
 
+func TestSetConditionalFormatRuleTypes(t *testing.T) {
+	f := NewFile()
+	format, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511"}}`)
+	assert.NoError(t, err)
+	for _, rule := range []string{
+		`[{"type":"date","criteria":"between","format":%d,"minimum":"2022-01-01","maximum":"2022-01-31T12:00:00"}]`,
+		`[{"type":"time","criteria":">","format":%d,"value":"18:00"}]`,
+		`[{"type":"text","criteria":"containing","format":%d,"value":"say \"hi\""}]`,
+		`[{"type":"text","criteria":"begins with","format":%d,"value":"abc"}]`,
+		`[{"type":"time_period","criteria":"last week","format":%d}]`,
+		`[{"type":"blanks","format":%d}]`,
+		`[{"type":"no_errors","format":%d}]`,
+	} {
+		assert.NoError(t, f.SetConditionalFormat("Sheet1", "B2:B10 D2:D10", fmt.Sprintf(rule, format)))
+	}
+	// Test set icon sets conditional format with custom thresholds.
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "C1:C10", `[{"type":"icon_set","icon_style":"4_arrows","reverse_icons":true,"icons_only":true,"icons":[{"criteria":">","type":"num","value":"90"},{"type":"percentile","value":"60"}]}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "E1:E10", `[{"type":"icon_set"}]`))
+	// Test set invalid text criteria and icon style.
+	assert.EqualError(t, f.SetConditionalFormat("Sheet1", "F1:F10", fmt.Sprintf(`[{"type":"text","criteria":">","format":%d,"value":"abc"}]`, format)), `unsupported conditional format criteria ">"`)
+	assert.EqualError(t, f.SetConditionalFormat("Sheet1", "F1:F10", `[{"type":"icon_set","icon_style":"unknown"}]`), `unsupported icon style "unknown"`)
+
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.ConditionalFormatting, 9)
+	assert.Equal(t, []string{"44562", "44592.5"}, ws.ConditionalFormatting[0].CfRule[0].Formula)
+	assert.Equal(t, []string{"0.75"}, ws.ConditionalFormatting[1].CfRule[0].Formula)
+	assert.Equal(t, &xlsxCfRule{
+		Type: "containsText", DxfID: &format, Priority: 1, Operator: "containsText", Text: `say "hi"`,
+		Formula: []string{`NOT(ISERROR(SEARCH("say ""hi""",B2)))`},
+	}, ws.ConditionalFormatting[2].CfRule[0])
+	assert.Equal(t, []string{`LEFT(B2,3)="abc"`}, ws.ConditionalFormatting[3].CfRule[0].Formula)
+	assert.Equal(t, "lastWeek", ws.ConditionalFormatting[4].CfRule[0].TimePeriod)
+	assert.Equal(t, []string{"LEN(TRIM(B2))=0"}, ws.ConditionalFormatting[5].CfRule[0].Formula)
+	assert.Equal(t, "notContainsErrors", ws.ConditionalFormatting[6].CfRule[0].Type)
+	assert.Equal(t, &xlsxIconSet{
+		IconSet: "4Arrows", ShowValue: boolPtr(false), Reverse: true,
+		Cfvo: []*xlsxCfvo{
+			{Type: "percent", Val: "0"}, {Type: "percent", Val: "25"},
+			{Type: "percentile", Val: "60"}, {Type: "num", Val: "90", Gte: boolPtr(false)},
+		},
+	}, ws.ConditionalFormatting[7].CfRule[0].IconSet)
+	assert.Equal(t, &xlsxIconSet{
+		IconSet: "3TrafficLights1",
+		Cfvo:    []*xlsxCfvo{{Type: "percent", Val: "0"}, {Type: "percent", Val: "33"}, {Type: "percent", Val: "67"}},
+	}, ws.ConditionalFormatting[8].CfRule[0].IconSet)
+
+	// Test set icon sets which only supported by Excel 2010 and later versions.
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOption{Location: []string{"G1"}, Range: []string{"Sheet1!A1:A10"}}))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "G1:G10", `[{"type":"icon_set","icon_style":"3_stars"}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "H1:H10", `[{"type":"icon_set","icon_style":"5_boxes","icons":[{"criteria":">","value":"90"}]}]`))
+	assert.Len(t, ws.ConditionalFormatting, 9)
+	assert.True(t, strings.HasPrefix(ws.ExtLst.Ext, `<ext uri="`+ExtURIConditionalFormattings+`"><x14:conditionalFormattings>`))
+	assert.Contains(t, ws.ExtLst.Ext, `<x14:iconSet iconSet="3Stars"><x14:cfvo type="percent"><xm:f>0</xm:f></x14:cfvo>`)
+	assert.Contains(t, ws.ExtLst.Ext, `<x14:cfvo type="percent" gte="false"><xm:f>90</xm:f></x14:cfvo></x14:iconSet></x14:cfRule><xm:sqref>H1:H10</xm:sqref>`)
+	assert.Equal(t, 2, strings.Count(ws.ExtLst.Ext, "<x14:conditionalFormatting "))
+	assert.Contains(t, ws.ExtLst.Ext, ExtURISparklineGroups)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetConditionalFormatRuleTypes.xlsx")))
+
+	// Test set x14 conditional format with unsupported charset worksheet extension.
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.ExtLst.Ext = string(MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetConditionalFormat("Sheet1", "I1:I10", `[{"type":"icon_set","icon_style":"3_stars"}]`), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestSetConditionalFormatWithOptions(t *testing.T) {
+	f := NewFile()
+	format, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511"}}`)
+	assert.NoError(t, err)
+	opts := []ConditionalFormatOptions{
+		{Type: "cell", Criteria: ">", Format: format, Value: "6"},
+		{Type: "duplicate", Format: format},
+		{Type: "2_color_scale", MinType: "num", MinValue: "0", MaxType: "percentile", MaxValue: "90"},
+	}
+	assert.NoError(t, f.SetConditionalFormatWithOptions("Sheet1", "A1:A10", opts))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.ConditionalFormatting, 1)
+	assert.Len(t, ws.ConditionalFormatting[0].CfRule, 3)
+	assert.Equal(t, "duplicateValues", ws.ConditionalFormatting[0].CfRule[1].Type)
+
+	// Test set conditional format with invalid options.
+	for _, c := range []struct {
+		opts ConditionalFormatOptions
+		err  string
+	}{
+		{ConditionalFormatOptions{Type: "cells", Criteria: ">"}, `unsupported conditional format type "cells"`},
+		{ConditionalFormatOptions{Type: "cell", Criteria: "greater"}, `unsupported conditional format criteria "greater"`},
+		{ConditionalFormatOptions{Type: "time_period", Criteria: ">"}, `unsupported conditional format criteria ">"`},
+		{ConditionalFormatOptions{Type: "data_bar", MinType: "lowest"}, `unsupported conditional format value type "lowest"`},
+		{ConditionalFormatOptions{Type: "data_bar", BarDirection: "up"}, `unsupported bar direction "up"`},
+		{ConditionalFormatOptions{Type: "data_bar", BarAxisPosition: "left"}, `unsupported bar axis position "left"`},
+		{ConditionalFormatOptions{Type: "icon_set", Icons: []*ConditionalFormatIconOptions{nil, {Criteria: "<"}}}, `unsupported icon criteria "<"`},
+		{ConditionalFormatOptions{Type: "icon_set", Icons: []*ConditionalFormatIconOptions{{Type: "min"}}}, `unsupported icon value type "min"`},
+	} {
+		assert.EqualError(t, f.SetConditionalFormatWithOptions("Sheet1", "B1:B10", []ConditionalFormatOptions{c.opts}), c.err)
+	}
+	assert.Len(t, ws.ConditionalFormatting, 1)
+	assert.EqualError(t, f.SetConditionalFormat("Sheet1", "B1:B10", `[{"type":"cell","criteria":">","valeu":"6"}]`), `json: unknown field "valeu"`)
+}
+
+func TestSetConditionalFormatDataBar(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", `[{"type":"data_bar","criteria":"=","min_type":"num","min_value":"-10","max_type":"percentile","max_value":"90","min_length":"10","max_length":"80","bar_color":"#63C384","bar_solid":true,"bar_border_color":"#00B050","bar_negative_color":"#FFC000","bar_negative_border_color_same":true,"bar_direction":"right_to_left","bar_axis_position":"middle","bar_axis_color":"#7030A0","bar_only":true}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "B1:B10", `[{"type":"data_bar","criteria":"=","bar_no_border":true,"bar_negative_color_same":true,"bar_axis_position":"none"}]`))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.ConditionalFormatting, 2)
+	rule := ws.ConditionalFormatting[0].CfRule[0]
+	assert.Equal(t, &xlsxDataBar{
+		ShowValue: boolPtr(false),
+		Cfvo:      []*xlsxCfvo{{Type: "num", Val: "-10"}, {Type: "percentile", Val: "90"}},
+		Color:     []*xlsxColor{{RGB: "FF63C384"}},
+	}, rule.DataBar)
+	assert.Contains(t, rule.ExtLst.Ext, `<ext uri="`+ExtURIConditionalFormattingRuleID+`" xmlns:x14="`+NameSpaceSpreadSheetX14.Value+`"><x14:id>{`)
+	assert.Equal(t, &xlsxDataBar{
+		Cfvo:  []*xlsxCfvo{{Type: "min"}, {Type: "max"}},
+		Color: []*xlsxColor{{RGB: "FF638EC6"}},
+	}, ws.ConditionalFormatting[1].CfRule[0].DataBar)
+	assert.Contains(t, ws.ExtLst.Ext, `<x14:dataBar maxLength="80" minLength="10" showValue="false" border="true" gradient="false" direction="rightToLeft" negativeBarBorderColorSameAsPositive="true" axisPosition="middle">`+
+		`<x14:cfvo type="num"><xm:f>-10</xm:f></x14:cfvo><x14:cfvo type="percentile"><xm:f>90</xm:f></x14:cfvo>`+
+		`<x14:borderColor rgb="FF00B050"></x14:borderColor><x14:negativeFillColor rgb="FFFFC000"></x14:negativeFillColor><x14:axisColor rgb="FF7030A0"></x14:axisColor></x14:dataBar>`)
+	assert.Contains(t, ws.ExtLst.Ext, `<x14:dataBar maxLength="100" minLength="0" negativeBarColorSameAsPositive="true" negativeBarBorderColorSameAsPositive="false" axisPosition="none">`+
+		`<x14:cfvo type="autoMin"></x14:cfvo><x14:cfvo type="autoMax"></x14:cfvo></x14:dataBar></x14:cfRule><xm:sqref>B1:B10</xm:sqref>`)
+
+	// Test get the data bar with extended options.
+	formats, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []ConditionalFormatOptions{{
+		Type: "data_bar", Criteria: "=", Priority: 1, MinType: "num", MinValue: "-10", MaxType: "percentile", MaxValue: "90",
+		MinLength: "10", MaxLength: "80", BarColor: "#63C384", BarSolid: true, BarBorderColor: "#00B050", BarNegativeColor: "#FFC000",
+		BarNegativeBorderColorSame: true, BarDirection: "right_to_left", BarAxisPosition: "middle", BarAxisColor: "#7030A0", BarOnly: true,
+	}}, formats["A1:A10"])
+	assert.Equal(t, []ConditionalFormatOptions{{
+		Type: "data_bar", Criteria: "=", Priority: 1, MinType: "min", MaxType: "max", MinLength: "0", MaxLength: "100",
+		BarColor: "#638EC6", BarNoBorder: true, BarNegativeColorSame: true, BarAxisPosition: "none",
+	}}, formats["B1:B10"])
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetConditionalFormatDataBar.xlsx")))
+
+	// Test set color scales with default types and colors.
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "C1:C10", `[{"type":"2_color_scale","criteria":"="},{"type":"3_color_scale","criteria":"="}]`))
+	formats, err = f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []ConditionalFormatOptions{{
+		Type: "2_color_scale", Criteria: "=", Priority: 1, MinType: "min", MaxType: "max", MinValue: "0", MaxValue: "0",
+		MinColor: "#FF7128", MaxColor: "#FFEF9C",
+	}, {
+		Type: "3_color_scale", Criteria: "=", Priority: 2, MinType: "min", MidType: "percentile", MaxType: "max",
+		MinValue: "0", MidValue: "50", MaxValue: "0", MinColor: "#F8696B", MidColor: "#FFEB84", MaxColor: "#63BE7B",
+	}}, formats["C1:C10"])
+}
+
 func TestUnsetConditionalFormat(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 7))
@@ -183,10 +337,150 @@ func TestUnsetConditionalFormat(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"cell","criteria":">","format":%d,"value":"6"}]`, format)))
 	assert.NoError(t, f.UnsetConditionalFormat("Sheet1", "A1:A10"))
+	// Test unset conditional format in the worksheet extension list.
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "B1:B10", `[{"type":"icon_set","icon_style":"3_stars"}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "C1:C10", `[{"type":"icon_set","icon_style":"5_boxes"}]`))
+	assert.NoError(t, f.UnsetConditionalFormat("Sheet1", "B1:B10"))
+	formats, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, formats, 1)
+	assert.Len(t, formats["C1:C10"], 1)
+	assert.NoError(t, f.UnsetConditionalFormat("Sheet1", "C1:C10"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.ExtLst)
 	// Test unset conditional format on not exists worksheet.
 	assert.EqualError(t, f.UnsetConditionalFormat("SheetN", "A1:A10"), "sheet SheetN is not exist")
 	// Save spreadsheet by the given path.
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestUnsetConditionalFormat.xlsx")))
+	// Test unset conditional format with unsupported charset worksheet extension.
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.ExtLst = &xlsxExtLst{Ext: string(MacintoshCyrillicCharset)}
+	assert.EqualError(t, f.UnsetConditionalFormat("Sheet1", "A1:A10"), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetConditionalFormats(t *testing.T) {
+	f := NewFile()
+	format, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511","bold":true},"fill":{"type":"pattern","color":["#FEC7CE"],"pattern":1}}`)
+	assert.NoError(t, err)
+	for area, formatSet := range map[string]string{
+		"A1:A10": fmt.Sprintf(`[{"type":"cell","criteria":"between","format":%d,"minimum":"6","maximum":"8"},{"type":"cell","criteria":">","format":%d,"value":"10","stop_if_true":true}]`, format, format),
+		"B1:B10": fmt.Sprintf(`[{"type":"top","criteria":"=","format":%d,"value":"6","percent":true},{"type":"average","criteria":"=","format":%d,"above_average":false}]`, format, format),
+		"C1:C10": fmt.Sprintf(`[{"type":"text","criteria":"not containing","format":%d,"value":"foo"},{"type":"time_period","criteria":"this month","format":%d}]`, format, format),
+		"D1:D10": fmt.Sprintf(`[{"type":"no_blanks","format":%d},{"type":"formula","criteria":"D1<3","format":%d,"priority":5}]`, format, format),
+		"E1:E10": `[{"type":"3_color_scale","criteria":"=","min_type":"min","mid_type":"percentile","max_type":"max","min_color":"#F8696B","mid_color":"#FFEB84","max_color":"#63BE7B"}]`,
+		"F1:F10": `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6"}]`,
+		"G1:G10": `[{"type":"icon_set","icon_style":"4_arrows","reverse_icons":true,"icons_only":true,"icons":[{"criteria":">","type":"num","value":"90"}]}]`,
+		"H1:H10": `[{"type":"icon_set","icon_style":"3_triangles"}]`,
+	} {
+		assert.NoError(t, f.SetConditionalFormat("Sheet1", area, formatSet))
+	}
+	formats, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, formats, 8)
+	style := &Style{
+		Font: &Font{Bold: true, Family: "Calibri", Size: 11, Color: "#9A0511"},
+		Fill: Fill{Type: "pattern", Pattern: 1, Color: []string{"#FEC7CE"}},
+	}
+	assert.Equal(t, []ConditionalFormatOptions{
+		{Type: "cell", Criteria: "between", Format: format, Minimum: "6", Maximum: "8", Priority: 1, Style: style},
+		{Type: "cell", Criteria: ">", Format: format, Value: "10", Priority: 2, StopIfTrue: true, Style: style},
+	}, formats["A1:A10"])
+	assert.Equal(t, []ConditionalFormatOptions{
+		{Type: "top", Criteria: "=", Format: format, Value: "6", Percent: true, Priority: 1, Style: style},
+		{Type: "average", Criteria: "=", Format: format, Priority: 2, Style: style},
+	}, formats["B1:B10"])
+	assert.Equal(t, []ConditionalFormatOptions{
+		{Type: "text", Criteria: "not containing", Format: format, Value: "foo", Priority: 1, Style: style},
+		{Type: "time_period", Criteria: "this month", Format: format, Priority: 2, Style: style},
+	}, formats["C1:C10"])
+	assert.Equal(t, []ConditionalFormatOptions{
+		{Type: "no_blanks", Format: format, Priority: 1, Style: style},
+		{Type: "formula", Criteria: "D1<3", Format: format, Priority: 5, Style: style},
+	}, formats["D1:D10"])
+	assert.Equal(t, []ConditionalFormatOptions{{
+		Type: "3_color_scale", Criteria: "=", Priority: 1,
+		MinType: "min", MidType: "percentile", MaxType: "max", MinValue: "0", MidValue: "50", MaxValue: "0",
+		MinColor: "#F8696B", MidColor: "#FFEB84", MaxColor: "#63BE7B",
+	}}, formats["E1:E10"])
+	assert.Equal(t, []ConditionalFormatOptions{{
+		Type: "data_bar", Criteria: "=", Priority: 1, MinType: "min", MaxType: "max", MinLength: "0", MaxLength: "100",
+		BarColor: "#638EC6", BarBorderColor: "#638EC6", BarNegativeColor: "#FF0000", BarNegativeBorderColor: "#FF0000", BarAxisColor: "#000000",
+	}}, formats["F1:F10"])
+	assert.Equal(t, []ConditionalFormatOptions{{
+		Type: "icon_set", Priority: 1, IconStyle: "4_arrows", ReverseIcons: true, IconsOnly: true,
+		Icons: []*ConditionalFormatIconOptions{
+			{Criteria: ">", Type: "num", Value: "90"},
+			{Criteria: ">=", Type: "percent", Value: "50"},
+			{Criteria: ">=", Type: "percent", Value: "25"},
+		},
+	}}, formats["G1:G10"])
+	assert.Equal(t, []ConditionalFormatOptions{{
+		Type: "icon_set", Priority: 1, IconStyle: "3_triangles",
+		Icons: []*ConditionalFormatIconOptions{
+			{Criteria: ">=", Type: "percent", Value: "67"},
+			{Criteria: ">=", Type: "percent", Value: "33"},
+		},
+	}}, formats["H1:H10"])
+
+	// Test copy the conditional formats to another worksheet.
+	f.NewSheet("Sheet2")
+	for area, rules := range formats {
+		formatSet, err := json.Marshal(rules)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetConditionalFormat("Sheet2", area, string(formatSet)))
+	}
+	copied, err := f.GetConditionalFormats("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, formats, copied)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetConditionalFormats.xlsx")))
+
+	// Test get conditional formats with unsupported rule type and style.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.ConditionalFormatting = []*xlsxConditionalFormatting{{SQRef: "A1", CfRule: []*xlsxCfRule{
+		{Type: "unknown"}, {Type: "colorScale"}, {Type: "dataBar"}, {Type: "iconSet"}, {Type: "uniqueValues", DxfID: intPtr(100)},
+	}}}
+	ws.ExtLst = nil
+	formats, err = f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]ConditionalFormatOptions{"A1": {{Type: "unique", Criteria: "=", Format: 100}}}, formats)
+	// Test get conditional formats on not exists worksheet.
+	_, err = f.GetConditionalFormats("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get conditional formats with unsupported charset worksheet extension.
+	ws.ExtLst = &xlsxExtLst{Ext: string(MacintoshCyrillicCharset)}
+	_, err = f.GetConditionalFormats("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetConditionalStyle(t *testing.T) {
+	f := NewFile()
+	idx, err := f.NewConditionalStyle(`{"font":{"italic":true},"border":[{"type":"left","color":"#0000FF","style":1}],"alignment":{"horizontal":"center"}}`)
+	assert.NoError(t, err)
+	style, err := f.GetConditionalStyle(idx)
+	assert.NoError(t, err)
+	assert.Equal(t, &Style{
+		Font:      &Font{Italic: true, Family: "Calibri", Size: 11, Color: "#000000"},
+		Border:    []Border{{Type: "left", Color: "#0000FF", Style: 1}},
+		Alignment: &Alignment{Horizontal: "center"},
+	}, style)
+	f.Styles.Dxfs.Dxfs = append(f.Styles.Dxfs.Dxfs, &xlsxDxf{Dxf: `<numFmt numFmtId="14" formatCode="mm-dd-yy"/><protection hidden="1"/>`},
+		&xlsxDxf{Dxf: `<numFmt numFmtId="200" formatCode="0.0%"/>`}, &xlsxDxf{Dxf: "<font"})
+	style, err = f.GetConditionalStyle(idx + 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &Style{NumFmt: 14, Protection: &Protection{Hidden: true, Locked: true}}, style)
+	style, err = f.GetConditionalStyle(idx + 2)
+	assert.NoError(t, err)
+	assert.Equal(t, &Style{CustomNumFmt: stringPtr("0.0%")}, style)
+	_, err = f.GetConditionalStyle(idx + 3)
+	assert.EqualError(t, err, "XML syntax error on line 1: expected attribute name in element")
+	// Test get conditional style with invalid format index.
+	_, err = f.GetConditionalStyle(-1)
+	assert.EqualError(t, err, "invalid style ID -1, negative values are not supported")
+	_, err = f.GetConditionalStyle(idx + 4)
+	assert.EqualError(t, err, fmt.Sprintf("style ID %d is not exist", idx+4))
 }
 
 func TestNewStyle(t *testing.T) {
@@ -254,6 +548,59 @@ func TestNewStyle(t *testing.T) {
 	assert.Equal(t, [][]string{{"1.23E+00", "1.23E+00"}}, rows)
 }
 
+func TestGetStyle(t *testing.T) {
+	f := NewFile()
+	for _, style := range []*Style{
+		{
+			Font:         &Font{Bold: true, Italic: true, Underline: "double", Family: "Arial", Size: 14, Strike: true, Color: "#FF0000"},
+			Fill:         Fill{Type: "pattern", Pattern: 1, Color: []string{"#E0EBF5"}},
+			Border:       []Border{{Type: "left", Color: "#0000FF", Style: 2}, {Type: "diagonalUp", Color: "#000000", Style: 1}},
+			Alignment:    &Alignment{Horizontal: "center", Vertical: "top", WrapText: true, Indent: 1, TextRotation: 45},
+			Protection:   &Protection{Hidden: true, Locked: false},
+			CustomNumFmt: stringPtr("0.000"),
+		},
+		{Fill: Fill{Type: "gradient", Color: []string{"#FFFFFF", "#E0EBF5"}, Shading: 5}, NumFmt: 14},
+		{Fill: Fill{Type: "gradient", Color: []string{"#FFFFFF", "#E0EBF5"}, Shading: 2}, Alignment: &Alignment{}},
+	} {
+		styleID, err := f.NewStyle(style)
+		assert.NoError(t, err)
+		result, err := f.GetStyle(styleID)
+		assert.NoError(t, err)
+		assert.Equal(t, style.Font, result.Font)
+		assert.Equal(t, style.Fill, result.Fill)
+		assert.Equal(t, style.Border, result.Border)
+		assert.Equal(t, style.Alignment, result.Alignment)
+		assert.Equal(t, style.Protection, result.Protection)
+		assert.Equal(t, style.NumFmt, result.NumFmt)
+		assert.Equal(t, style.CustomNumFmt, result.CustomNumFmt)
+		// Test create the style with the returned style settings.
+		newStyleID, err := f.NewStyle(result)
+		assert.NoError(t, err)
+		assert.Equal(t, styleID, newStyleID)
+	}
+	// Test get the default style.
+	style, err := f.GetStyle(0)
+	assert.NoError(t, err)
+	assert.Equal(t, &Style{}, style)
+
+	// Test get style with the theme and indexed colors.
+	styleID, err := f.NewStyle(&Style{Font: &Font{Color: "#000000"}, Fill: Fill{Type: "pattern", Pattern: 1, Color: []string{"#000000"}}})
+	assert.NoError(t, err)
+	xf := f.Styles.CellXfs.Xf[styleID]
+	f.Styles.Fonts.Font[*xf.FontID].Color = &xlsxColor{Theme: intPtr(4), Tint: 0.5}
+	f.Styles.Fills.Fill[*xf.FillID].PatternFill.FgColor = &xlsxColor{Indexed: 10}
+	style, err = f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, "#ADCDEA", style.Font.Color)
+	assert.Equal(t, []string{"#FF0000"}, style.Fill.Color)
+
+	// Test get style with invalid style index.
+	_, err = f.GetStyle(-1)
+	assert.EqualError(t, err, newInvalidStyleID(-1).Error())
+	_, err = f.GetStyle(len(f.Styles.CellXfs.Xf))
+	assert.EqualError(t, err, newStyleNotExistError(len(f.Styles.CellXfs.Xf)).Error())
+}
+
 func TestGetDefaultFont(t *testing.T) {
 	f := NewFile()
 	s := f.GetDefaultFont()
@@ -1,23 +1,76 @@
 package xlsx
 
 import (
-	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
 
+// tableStylePattern defined the pattern of the built-in table style names.
+var tableStylePattern = regexp.MustCompile(`^TableStyle(Light([1-9]|1[0-9]|2[01])|Medium([1-9]|1[0-9]|2[0-8])|Dark([1-9]|1[01]))$`)
+
+// tableNamePattern defined the pattern of the valid table names.
+var tableNamePattern = regexp.MustCompile(`^[\p{L}_\\][\p{L}\p{N}_.\\]*$`)
+
+// customFilterOperators defined the operators of the custom filter by the
+// operator types of the filter expression.
+var customFilterOperators = map[int]string{
+	1:  "lessThan",
+	2:  "equal",
+	3:  "lessThanOrEqual",
+	4:  "greaterThan",
+	5:  "notEqual",
+	6:  "greaterThanOrEqual",
+	22: "equal",
+}
+
+// dynamicFilterTypes defined the supported types of the dynamic filter.
+var dynamicFilterTypes = []string{
+	"aboveAverage", "belowAverage",
+	"today", "yesterday", "tomorrow",
+	"thisWeek", "lastWeek", "nextWeek",
+	"thisMonth", "lastMonth", "nextMonth",
+	"thisQuarter", "lastQuarter", "nextQuarter",
+	"thisYear", "lastYear", "nextYear", "yearToDate",
+	"Q1", "Q2", "Q3", "Q4",
+	"M1", "M2", "M3", "M4", "M5", "M6", "M7", "M8", "M9", "M10", "M11", "M12",
+}
+
 // parseFormatTableSet provides a function to parse the format settings of the
 // table with default value.
-func parseFormatTableSet(formatSet string) (*formatTable, error) {
-	format := formatTable{
-		TableStyle:     "",
-		ShowRowStripes: true,
+func parseFormatTableSet(formatSet string) (*TableOptions, error) {
+	var format TableOptions
+	if err := unmarshalFormatSet(parseFormatSet(formatSet), &format); err != nil {
+		return &format, err
 	}
-	err := json.Unmarshal(parseFormatSet(formatSet), &format)
-	return &format, err
+	return parseTableOptions(&format)
+}
+
+// parseTableOptions provides a function to validate the format settings of
+// the table and fill the unset settings with default value.
+func parseTableOptions(opts *TableOptions) (*TableOptions, error) {
+	var format TableOptions
+	if opts != nil {
+		format = *opts
+	}
+	if format.ShowRowStripes == nil {
+		format.ShowRowStripes = boolPtr(true)
+	}
+	if format.TableStyle != "" && !tableStylePattern.MatchString(format.TableStyle) {
+		return &format, newUnsupportedOptionError("table style", format.TableStyle)
+	}
+	if name := format.TableName; name != "" {
+		if len(name) > MaxFieldLength || !tableNamePattern.MatchString(name) {
+			return &format, newUnsupportedOptionError("table name", name)
+		}
+		if _, _, err := CellNameToCoordinates(name); err == nil {
+			return &format, newUnsupportedOptionError("table name", name)
+		}
+	}
+	return &format, nil
 }
 
 // AddTable provides the method to add table in a worksheet by given worksheet
@@ -51,7 +104,28 @@ func parseFormatTableSet(formatSet string) (*formatTable, error) {
 //    TableStyleDark1 - TableStyleDark11
 //
 func (f *File) AddTable(sheet, hcell, vcell, format string) error {
-	formatSet, err := parseFormatTableSet(format)
+	var formatSet TableOptions
+	if err := unmarshalFormatSet(parseFormatSet(format), &formatSet); err != nil {
+		return err
+	}
+	return f.AddTableWithOptions(sheet, hcell, vcell, &formatSet)
+}
+
+// AddTableWithOptions provides the method to add table in a worksheet by
+// given worksheet name, coordinate area and table options. The settings are
+// the same as the AddTable function, the row stripes will be shown if the
+// ShowRowStripes is nil. For example, create a table of F2:H6 on Sheet2:
+//
+//    showRowStripes := false
+//    err := f.AddTableWithOptions("Sheet2", "F2", "H6", &xlsx.TableOptions{
+//        TableName:       "table",
+//        TableStyle:      "TableStyleMedium2",
+//        ShowFirstColumn: true,
+//        ShowRowStripes:  &showRowStripes,
+//    })
+//
+func (f *File) AddTableWithOptions(sheet, hcell, vcell string, opts *TableOptions) error {
+	formatSet, err := parseTableOptions(opts)
 	if err != nil {
 		return err
 	}
@@ -123,7 +197,7 @@ func (f *File) addSheetTable(sheet string, rID int) error {
 
 // addTable provides a function to add table by given worksheet name,
 // coordinate area and format set.
-func (f *File) addTable(sheet, tableXML string, x1, y1, x2, y2, i int, formatSet *formatTable) error {
+func (f *File) addTable(sheet, tableXML string, x1, y1, x2, y2, i int, formatSet *TableOptions) error {
 	// Correct the minimum number of rows, the table at least two lines.
 	if y1 == y2 {
 		y2++
@@ -178,7 +252,7 @@ func (f *File) addTable(sheet, tableXML string, x1, y1, x2, y2, i int, formatSet
 			Name:              formatSet.TableStyle,
 			ShowFirstColumn:   formatSet.ShowFirstColumn,
 			ShowLastColumn:    formatSet.ShowLastColumn,
-			ShowRowStripes:    formatSet.ShowRowStripes,
+			ShowRowStripes:    *formatSet.ShowRowStripes,
 			ShowColumnStripes: formatSet.ShowColumnStripes,
 		},
 	}
@@ -187,14 +261,6 @@ func (f *File) addTable(sheet, tableXML string, x1, y1, x2, y2, i int, formatSet
 	return nil
 }
 
-// parseAutoFilterSet provides a function to parse the settings of the auto
-// filter.
-func parseAutoFilterSet(formatSet string) (*formatAutoFilter, error) {
-	format := formatAutoFilter{}
-	err := json.Unmarshal([]byte(formatSet), &format)
-	return &format, err
-}
-
 // AutoFilter provides the method to add auto filter in a worksheet by given
 // worksheet name, coordinate area and settings. An autofilter in Excel is a
 // way of filtering a 2D range of data based on some simple criteria. For
@@ -209,10 +275,11 @@ func parseAutoFilterSet(formatSet string) (*formatAutoFilter, error) {
 // column defines the filter columns in a autofilter range based on simple
 // criteria
 //
-// It isn't sufficient to just specify the filter condition. You must also
-// hide any rows that don't match the filter condition. Rows are hidden using
-// the SetRowVisible() method. Excelize can't filter rows automatically since
-// this isn't part of the file format.
+// The rows that don't match the filter condition will be hidden, and the
+// other rows in the range will be shown. Call the function with the same
+// range multiple times to filter by multiple columns, the filter conditions
+// of the other columns will be kept. Use the ApplyAutoFilter function to
+// filter the rows again after changing the cell values.
 //
 // Setting a filter criteria for a column:
 //
@@ -265,7 +332,67 @@ func parseAutoFilterSet(formatSet string) (*formatAutoFilter, error) {
 //    col   < 2000
 //    Price < 2000
 //
+// Besides the expression, the filter criteria of a column can be one of the
+// value list, top 10, dynamic and color filters. values defines the list of
+// the values to be shown, the empty string in the list matches the blank
+// cells:
+//
+//    {"column":"B","values":["East","West",""]}
+//
+// top10 defines the top or bottom N items or percent of the numbers:
+//
+//    {"column":"B","top10":{"value":10,"percent":true,"bottom":false}}
+//
+// dynamic defines the dynamic filter type, the following types are available:
+//
+//    aboveAverage
+//    belowAverage
+//    today
+//    yesterday
+//    tomorrow
+//    thisWeek
+//    lastWeek
+//    nextWeek
+//    thisMonth
+//    lastMonth
+//    nextMonth
+//    thisQuarter
+//    lastQuarter
+//    nextQuarter
+//    thisYear
+//    lastYear
+//    nextYear
+//    yearToDate
+//    Q1 - Q4
+//    M1 - M12
+//
+// color defines the fill color or the font color of the cells to be shown:
+//
+//    {"column":"B","color":{"color":"#FFFF00","font_color":false}}
+//
 func (f *File) AutoFilter(sheet, hcell, vcell, format string) error {
+	var formatSet AutoFilterOptions
+	if err := unmarshalFormatSet(parseFormatSet(format), &formatSet); err != nil {
+		return err
+	}
+	return f.AutoFilterWithOptions(sheet, hcell, vcell, &formatSet)
+}
+
+// AutoFilterWithOptions provides the method to add auto filter in a worksheet
+// by given worksheet name, coordinate area and auto filter options. The
+// settings are the same as the AutoFilter function, for example filter data
+// in an autofilter:
+//
+//    err := f.AutoFilterWithOptions("Sheet1", "A1", "D4", &xlsx.AutoFilterOptions{
+//        Column:     "B",
+//        Expression: "x != blanks",
+//    })
+//
+func (f *File) AutoFilterWithOptions(sheet, hcell, vcell string, opts *AutoFilterOptions) error {
+	var formatSet AutoFilterOptions
+	if opts != nil {
+		formatSet = *opts
+	}
 	hcol, hrow, err := CellNameToCoordinates(hcell)
 	if err != nil {
 		return err
@@ -283,7 +410,6 @@ func (f *File) AutoFilter(sheet, hcell, vcell, format string) error {
 		vrow, hrow = hrow, vrow
 	}
 
-	formatSet, _ := parseAutoFilterSet(format)
 	cellStart, _ := CoordinatesToCellName(hcol, hrow, true)
 	cellEnd, _ := CoordinatesToCellName(vcol, vrow, true)
 	ref, filterDB := cellStart+":"+cellEnd, "_xlnm._FilterDatabase"
@@ -314,26 +440,35 @@ func (f *File) AutoFilter(sheet, hcell, vcell, format string) error {
 		}
 	}
 	refRange := vcol - hcol
-	return f.autoFilter(sheet, ref, refRange, hcol, formatSet)
+	return f.autoFilter(sheet, ref, refRange, hcol, &formatSet)
 }
 
 // autoFilter provides a function to extract the tokens from the filter
 // expression. The tokens are mainly non-whitespace groups.
-func (f *File) autoFilter(sheet, ref string, refRange, col int, formatSet *formatAutoFilter) error {
+func (f *File) autoFilter(sheet, ref string, refRange, col int, formatSet *AutoFilterOptions) error {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
-	if ws.SheetPr != nil {
-		ws.SheetPr.FilterMode = true
+	if ws.SheetPr == nil {
+		ws.SheetPr = &xlsxSheetPr{}
 	}
-	ws.SheetPr = &xlsxSheetPr{FilterMode: true}
+	ws.SheetPr.FilterMode = true
 	filter := &xlsxAutoFilter{
 		Ref: ref,
 	}
+	if ws.AutoFilter != nil && ws.AutoFilter.Ref == ref && formatSet.Column != "" {
+		// Keep the filter criteria of the other columns in the same range.
+		filter = ws.AutoFilter
+	}
+	if ws.AutoFilter != nil && ws.AutoFilter.Ref != ref {
+		// Show the rows hidden by the previous auto filter in the other range.
+		showAutoFilterRows(ws, ws.AutoFilter.Ref)
+	}
 	ws.AutoFilter = filter
-	if formatSet.Column == "" || formatSet.Expression == "" {
-		return nil
+	if formatSet.Column == "" || formatSet.Expression == "" && len(formatSet.Values) == 0 &&
+		formatSet.Top10 == nil && formatSet.Dynamic == "" && formatSet.Color == nil {
+		return f.applyAutoFilter(sheet, ws)
 	}
 
 	fsCol, err := ColumnNameToNumber(formatSet.Column)
@@ -345,72 +480,116 @@ func (f *File) autoFilter(sheet, ref string, refRange, col int, formatSet *forma
 		return fmt.Errorf("incorrect index of column '%s'", formatSet.Column)
 	}
 
-	filter.FilterColumn = append(filter.FilterColumn, &xlsxFilterColumn{
+	column := &xlsxFilterColumn{
 		ColID: offset,
-	})
-	re := regexp.MustCompile(`"(?:[^"]|"")*"|\S+`)
-	token := re.FindAllString(formatSet.Expression, -1)
-	if len(token) != 3 && len(token) != 7 {
-		return fmt.Errorf("incorrect number of tokens in criteria '%s'", formatSet.Expression)
 	}
-	expressions, tokens, err := f.parseFilterExpression(formatSet.Expression, token)
-	if err != nil {
+	if err = f.writeFilterColumn(column, formatSet); err != nil {
 		return err
 	}
-	f.writeAutoFilter(filter, expressions, tokens)
-	ws.AutoFilter = filter
+	filterColumns := []*xlsxFilterColumn{}
+	for _, filterColumn := range filter.FilterColumn {
+		if filterColumn.ColID != offset {
+			filterColumns = append(filterColumns, filterColumn)
+		}
+	}
+	filter.FilterColumn = append(filterColumns, column)
+	sort.Slice(filter.FilterColumn, func(i, j int) bool {
+		return filter.FilterColumn[i].ColID < filter.FilterColumn[j].ColID
+	})
+	return f.applyAutoFilter(sheet, ws)
+}
+
+// writeFilterColumn provides a function to write the filter criteria of the
+// column by given auto filter options.
+func (f *File) writeFilterColumn(column *xlsxFilterColumn, formatSet *AutoFilterOptions) error {
+	switch {
+	case formatSet.Expression != "":
+		re := regexp.MustCompile(`"(?:[^"]|"")*"|\S+`)
+		token := re.FindAllString(formatSet.Expression, -1)
+		if len(token) != 3 && len(token) != 7 {
+			return fmt.Errorf("incorrect number of tokens in criteria '%s'", formatSet.Expression)
+		}
+		expressions, tokens, err := f.parseFilterExpression(formatSet.Expression, token)
+		if err != nil {
+			return err
+		}
+		f.writeAutoFilter(column, expressions, tokens)
+	case len(formatSet.Values) > 0:
+		column.Filters = &xlsxFilters{}
+		for _, val := range formatSet.Values {
+			if val == "" {
+				column.Filters.Blank = true
+				continue
+			}
+			column.Filters.Filter = append(column.Filters.Filter, &xlsxFilter{Val: val})
+		}
+	case formatSet.Top10 != nil:
+		if formatSet.Top10.Value < 1 || formatSet.Top10.Percent && formatSet.Top10.Value > 100 ||
+			!formatSet.Top10.Percent && formatSet.Top10.Value > 500 {
+			return ErrAutoFilterTop10Value
+		}
+		column.Top10 = &xlsxTop10{
+			Top:     !formatSet.Top10.Bottom,
+			Percent: formatSet.Top10.Percent,
+			Val:     formatSet.Top10.Value,
+		}
+	case formatSet.Dynamic != "":
+		if inStrSlice(dynamicFilterTypes, formatSet.Dynamic) == -1 {
+			return fmt.Errorf("unsupported dynamic filter type '%s'", formatSet.Dynamic)
+		}
+		column.DynamicFilter = &xlsxDynamicFilter{Type: formatSet.Dynamic}
+	default:
+		style := &Style{Fill: Fill{Type: "pattern", Color: []string{formatSet.Color.Color}, Pattern: 1}}
+		if formatSet.Color.FontColor {
+			style = &Style{Font: &Font{Color: formatSet.Color.Color}}
+		}
+		column.ColorFilter = &xlsxColorFilter{
+			CellColor: !formatSet.Color.FontColor,
+			DxfID:     f.newDxf(style),
+		}
+	}
 	return nil
 }
 
 // writeAutoFilter provides a function to check for single or double custom
 // filters as default filters and handle them accordingly.
-func (f *File) writeAutoFilter(filter *xlsxAutoFilter, exp []int, tokens []string) {
-	if len(exp) == 1 && exp[0] == 2 {
-		// Single equality.
-		var filters []*xlsxFilter
-		filters = append(filters, &xlsxFilter{Val: tokens[0]})
-		filter.FilterColumn[0].Filters = &xlsxFilters{Filter: filters}
-	} else if len(exp) == 3 && exp[0] == 2 && exp[1] == 1 && exp[2] == 2 {
-		// Double equality with "or" operator.
-		filters := []*xlsxFilter{}
+func (f *File) writeAutoFilter(column *xlsxFilterColumn, exp []int, tokens []string) {
+	if len(exp) == 1 && exp[0] == 2 || len(exp) == 3 && exp[0] == 2 && exp[1] == 1 && exp[2] == 2 {
+		// Single equality, or double equality with "or" operator.
+		filters := &xlsxFilters{}
 		for _, v := range tokens {
-			filters = append(filters, &xlsxFilter{Val: v})
+			if v == "blanks" {
+				filters.Blank = true
+				continue
+			}
+			filters.Filter = append(filters.Filter, &xlsxFilter{Val: v})
 		}
-		filter.FilterColumn[0].Filters = &xlsxFilters{Filter: filters}
+		column.Filters = filters
 	} else {
 		// Non default custom filter.
 		expRel := map[int]int{0: 0, 1: 2}
 		andRel := map[int]bool{0: true, 1: false}
 		for k, v := range tokens {
-			f.writeCustomFilter(filter, exp[expRel[k]], v)
+			f.writeCustomFilter(column, exp[expRel[k]], v)
 			if k == 1 {
-				filter.FilterColumn[0].CustomFilters.And = andRel[exp[k]]
+				column.CustomFilters.And = andRel[exp[k]]
 			}
 		}
 	}
 }
 
 // writeCustomFilter provides a function to write the <customFilter> element.
-func (f *File) writeCustomFilter(filter *xlsxAutoFilter, operator int, val string) {
-	operators := map[int]string{
-		1:  "lessThan",
-		2:  "equal",
-		3:  "lessThanOrEqual",
-		4:  "greaterThan",
-		5:  "notEqual",
-		6:  "greaterThanOrEqual",
-		22: "equal",
-	}
+func (f *File) writeCustomFilter(column *xlsxFilterColumn, operator int, val string) {
 	customFilter := xlsxCustomFilter{
-		Operator: operators[operator],
+		Operator: customFilterOperators[operator],
 		Val:      val,
 	}
-	if filter.FilterColumn[0].CustomFilters != nil {
-		filter.FilterColumn[0].CustomFilters.CustomFilter = append(filter.FilterColumn[0].CustomFilters.CustomFilter, &customFilter)
+	if column.CustomFilters != nil {
+		column.CustomFilters.CustomFilter = append(column.CustomFilters.CustomFilter, &customFilter)
 	} else {
 		customFilters := []*xlsxCustomFilter{}
 		customFilters = append(customFilters, &customFilter)
-		filter.FilterColumn[0].CustomFilters = &xlsxCustomFilters{CustomFilter: customFilters}
+		column.CustomFilters = &xlsxCustomFilters{CustomFilter: customFilters}
 	}
 }
 
@@ -479,6 +658,9 @@ func (f *File) parseFilterTokens(expression string, tokens []string) ([]int, str
 		return []int{}, "", fmt.Errorf("unknown operator: %s", tokens[1])
 	}
 	token := tokens[2]
+	if len(token) > 1 && strings.HasPrefix(token, `"`) && strings.HasSuffix(token, `"`) {
+		token = strings.Replace(token[1:len(token)-1], `""`, `"`, -1)
+	}
 	// Special handling for Blanks/NonBlanks.
 	re, _ := regexp.Match("blanks|nonblanks", []byte(strings.ToLower(token)))
 	if re {
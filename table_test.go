@@ -3,6 +3,7 @@ package xlsx
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -45,6 +46,33 @@ func TestAddTable(t *testing.T) {
 	assert.EqualError(t, f.addTable("sheet1", "", 1, 1, 0, 0, 0, nil), "invalid cell coordinates [0, 0]")
 }
 
+func TestAddTableWithOptions(t *testing.T) {
+	f, err := prepareTestBook1()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	opts := &TableOptions{TableName: "Sales_2022", TableStyle: "TableStyleLight21"}
+	assert.NoError(t, f.AddTableWithOptions("Sheet1", "A21", "B26", opts))
+	assert.NoError(t, f.AddTableWithOptions("Sheet2", "A2", "B5", &TableOptions{ShowRowStripes: boolPtr(false)}))
+	assert.Nil(t, opts.ShowRowStripes)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddTableWithOptions.xlsx")))
+
+	// Test add table with invalid options.
+	for _, c := range []struct {
+		opts *TableOptions
+		err  string
+	}{
+		{&TableOptions{TableStyle: "TableStyleMedium29"}, `unsupported table style "TableStyleMedium29"`},
+		{&TableOptions{TableName: "Sales 2022"}, `unsupported table name "Sales 2022"`},
+		{&TableOptions{TableName: "1Table"}, `unsupported table name "1Table"`},
+		{&TableOptions{TableName: "AB1"}, `unsupported table name "AB1"`},
+		{&TableOptions{TableName: strings.Repeat("t", MaxFieldLength+1)}, fmt.Sprintf("unsupported table name %q", strings.Repeat("t", MaxFieldLength+1))},
+	} {
+		assert.EqualError(t, f.AddTableWithOptions("Sheet1", "D1", "E5", c.opts), c.err)
+	}
+	assert.EqualError(t, f.AddTable("Sheet1", "D1", "E5", `{"table_styles":"TableStyleMedium2"}`), `json: unknown field "table_styles"`)
+}
+
 func TestAutoFilter(t *testing.T) {
 	outFile := filepath.Join("test", "TestAutoFilter%d.xlsx")
 
@@ -77,6 +105,21 @@ func TestAutoFilter(t *testing.T) {
 	assert.EqualError(t, f.AutoFilter("Sheet1", "A1", "B", ""), `cannot convert cell "B" to coordinates: invalid cell name "B"`)
 }
 
+func TestAutoFilterWithOptions(t *testing.T) {
+	f, err := prepareTestBook1()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, f.AutoFilterWithOptions("Sheet1", "D4", "B1", &AutoFilterOptions{Column: "B", Expression: "x != blanks"}))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "$B$1:$D$4", ws.AutoFilter.Ref)
+	assert.Len(t, ws.AutoFilter.FilterColumn, 1)
+	assert.NoError(t, f.AutoFilterWithOptions("Sheet1", "A1", "B2", nil))
+	assert.Nil(t, ws.AutoFilter.FilterColumn)
+	assert.EqualError(t, f.AutoFilter("Sheet1", "A1", "B2", `{"columns":"B"}`), `json: unknown field "columns"`)
+}
+
 func TestAutoFilterError(t *testing.T) {
 	outFile := filepath.Join("test", "TestAutoFilterError%d.xlsx")
 
@@ -102,19 +145,19 @@ func TestAutoFilterError(t *testing.T) {
 		})
 	}
 
-	assert.EqualError(t, f.autoFilter("SheetN", "A1", 1, 1, &formatAutoFilter{
+	assert.EqualError(t, f.autoFilter("SheetN", "A1", 1, 1, &AutoFilterOptions{
 		Column:     "A",
 		Expression: "",
 	}), "sheet SheetN is not exist")
-	assert.EqualError(t, f.autoFilter("Sheet1", "A1", 1, 1, &formatAutoFilter{
+	assert.EqualError(t, f.autoFilter("Sheet1", "A1", 1, 1, &AutoFilterOptions{
 		Column:     "-",
 		Expression: "-",
 	}), `invalid column name "-"`)
-	assert.EqualError(t, f.autoFilter("Sheet1", "A1", 1, 100, &formatAutoFilter{
+	assert.EqualError(t, f.autoFilter("Sheet1", "A1", 1, 100, &AutoFilterOptions{
 		Column:     "A",
 		Expression: "-",
 	}), `incorrect index of column 'A'`)
-	assert.EqualError(t, f.autoFilter("Sheet1", "A1", 1, 1, &formatAutoFilter{
+	assert.EqualError(t, f.autoFilter("Sheet1", "A1", 1, 1, &AutoFilterOptions{
 		Column:     "A",
 		Expression: "-",
 	}), `incorrect number of tokens in criteria '-'`)
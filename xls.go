@@ -0,0 +1,886 @@
+package xlsx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// BIFF8 record types used by the legacy workbook reader.
+const (
+	xlsRecordFormula    = 0x0006
+	xlsRecordEOF        = 0x000A
+	xlsRecordDateMode   = 0x0022
+	xlsRecordFilePass   = 0x002F
+	xlsRecordFont       = 0x0031
+	xlsRecordContinue   = 0x003C
+	xlsRecordColInfo    = 0x007D
+	xlsRecordBoundSheet = 0x0085
+	xlsRecordPalette    = 0x0092
+	xlsRecordMulRk      = 0x00BD
+	xlsRecordMulBlank   = 0x00BE
+	xlsRecordRString    = 0x00D6
+	xlsRecordXF         = 0x00E0
+	xlsRecordMergeCells = 0x00E5
+	xlsRecordSST        = 0x00FC
+	xlsRecordLabelSST   = 0x00FD
+	xlsRecordBlank      = 0x0201
+	xlsRecordNumber     = 0x0203
+	xlsRecordLabel      = 0x0204
+	xlsRecordBoolErr    = 0x0205
+	xlsRecordString     = 0x0207
+	xlsRecordRow        = 0x0208
+	xlsRecordRK         = 0x027E
+	xlsRecordFormat     = 0x041E
+	xlsRecordBOF        = 0x0809
+)
+
+// xlsUnencryptedRecords defined the records that never be encrypted in an
+// encrypted BIFF8 workbook stream.
+var xlsUnencryptedRecords = map[uint16]bool{
+	xlsRecordBOF:      true,
+	xlsRecordFilePass: true,
+	0x00E1:            true, // INTERFACEHDR
+	0x0138:            true, // RRDHEAD
+	0x0194:            true, // USREXCL
+	0x0195:            true, // FILELOCK
+	0x0196:            true, // RRDINFO
+}
+
+// xlsErrorCodes defined the error values of the BOOLERR and FORMULA records.
+var xlsErrorCodes = map[byte]string{
+	0x00: "#NULL!",
+	0x07: "#DIV/0!",
+	0x0F: "#VALUE!",
+	0x17: "#REF!",
+	0x1D: "#NAME?",
+	0x24: "#NUM!",
+	0x2A: "#N/A",
+	0x2B: "#GETTING_DATA",
+}
+
+// xlsDefaultPalette defined the default color palette of the BIFF8 workbook,
+// the colors from index 8 to 63 can be overridden by the PALETTE record.
+var xlsDefaultPalette = []string{
+	"000000", "FFFFFF", "FF0000", "00FF00", "0000FF", "FFFF00", "FF00FF", "00FFFF",
+	"000000", "FFFFFF", "FF0000", "00FF00", "0000FF", "FFFF00", "FF00FF", "00FFFF",
+	"800000", "008000", "000080", "808000", "800080", "008080", "C0C0C0", "808080",
+	"9999FF", "993366", "FFFFCC", "CCFFFF", "660066", "FF8080", "0066CC", "CCCCFF",
+	"000080", "FF00FF", "FFFF00", "00FFFF", "800080", "800000", "008080", "0000FF",
+	"00CCFF", "CCFFFF", "CCFFCC", "FFFF99", "99CCFF", "FF99CC", "CC99FF", "FFCC99",
+	"3366FF", "33CCCC", "99CC00", "FFCC00", "FF9900", "FF6600", "666699", "969696",
+	"003366", "339966", "003300", "333300", "993300", "993366", "333399", "333333",
+}
+
+// xlsHorizontalAlignment and xlsVerticalAlignment defined the alignment
+// values of the XF record.
+var (
+	xlsHorizontalAlignment = []string{"", "left", "center", "right", "fill", "justify", "centerContinuous", "distributed"}
+	xlsVerticalAlignment   = []string{"top", "center", "", "justify", "distributed"}
+)
+
+// xlsRecord directly maps a BIFF8 record and the CONTINUE records follow it.
+type xlsRecord struct {
+	typ  uint16
+	pos  int
+	data []byte
+	cont [][]byte
+}
+
+// xlsBoundSheet directly maps the BOUNDSHEET record.
+type xlsBoundSheet struct {
+	pos   int
+	state byte
+	typ   byte
+	name  string
+}
+
+// xlsFont directly maps the FONT record.
+type xlsFont struct {
+	height    int
+	italic    bool
+	strike    bool
+	color     int
+	weight    int
+	underline byte
+	name      string
+}
+
+// xlsXF directly maps the XF record.
+type xlsXF struct {
+	font, numFmt          int
+	locked, hidden, style bool
+	horizontal, vertical  int
+	wrap, shrink          bool
+	rotation, indent      int
+	border                [5]int // left, right, top, bottom, diagonal
+	borderColor           [5]int
+	diagonal              int
+	pattern, fgColor      int
+}
+
+// xlsReader holds the workbook globals of a BIFF8 workbook during mapping
+// the worksheets into the spreadsheet file.
+type xlsReader struct {
+	f        *File
+	records  []xlsRecord
+	sst      []string
+	fonts    []xlsFont
+	formats  map[int]string
+	xfs      []xlsXF
+	styles   map[int]int
+	palette  []string
+	date1904 bool
+	sheets   []xlsBoundSheet
+}
+
+// xlsWorkbookStream returns the BIFF workbook stream of the compound file,
+// it will return nil if the data is not a legacy workbook.
+func xlsWorkbookStream(raw []byte) []byte {
+	if !bytes.HasPrefix(raw, oleIdentifier) {
+		return nil
+	}
+	doc, err := mscfb.New(bytes.NewReader(raw))
+	if err != nil {
+		return nil
+	}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if len(entry.Path) > 0 || (entry.Name != "Workbook" && entry.Name != "Book") {
+			continue
+		}
+		buf := make([]byte, entry.Size)
+		if _, err := io.ReadFull(doc, buf); err != nil {
+			return nil
+		}
+		return buf
+	}
+	return nil
+}
+
+// openXLS provides a function to map the BIFF8 workbook stream into a new
+// spreadsheet file. The cell values, shared strings, cell formats, merged
+// cells, column widths, row heights and sheet visibility will be kept, and
+// the formula cells will be filled by the cached results.
+func openXLS(stream []byte, opt *Options) (*File, error) {
+	r := &xlsReader{
+		formats: make(map[int]string),
+		styles:  make(map[int]int),
+		palette: append([]string{}, xlsDefaultPalette...),
+	}
+	if err := r.decryptStream(stream, opt); err != nil {
+		return nil, err
+	}
+	if err := r.readGlobals(); err != nil {
+		return nil, err
+	}
+	r.f = NewFile()
+	r.f.options = opt
+	r.setDefaultFont()
+	if r.date1904 {
+		wb := r.f.workbookReader()
+		if wb.WorkbookPr == nil {
+			wb.WorkbookPr = &xlsxWorkbookPr{}
+		}
+		wb.WorkbookPr.Date1904 = true
+	}
+	var worksheets int
+	for _, sheet := range r.sheets {
+		if sheet.typ != 0 {
+			continue
+		}
+		if worksheets == 0 {
+			r.f.SetSheetName(r.f.GetSheetName(0), sheet.name)
+		} else {
+			r.f.NewSheet(sheet.name)
+		}
+		worksheets++
+		if err := r.readSheet(sheet); err != nil {
+			return nil, err
+		}
+	}
+	wb := r.f.workbookReader()
+	for _, sheet := range r.sheets {
+		for idx, s := range wb.Sheets.Sheet {
+			if s.Name != trimSheetName(sheet.name) || sheet.typ != 0 {
+				continue
+			}
+			switch sheet.state {
+			case 1:
+				wb.Sheets.Sheet[idx].State = "hidden"
+			case 2:
+				wb.Sheets.Sheet[idx].State = "veryHidden"
+			}
+		}
+	}
+	return r.f, nil
+}
+
+// parseXLSRecords split the workbook stream into records, the CONTINUE
+// records will be attached to the preceding record.
+func parseXLSRecords(stream []byte) []xlsRecord {
+	var records []xlsRecord
+	for pos := 0; pos+4 <= len(stream); {
+		typ := binary.LittleEndian.Uint16(stream[pos:])
+		size := int(binary.LittleEndian.Uint16(stream[pos+2:]))
+		if pos+4+size > len(stream) {
+			break
+		}
+		data := stream[pos+4 : pos+4+size]
+		if typ == xlsRecordContinue && len(records) > 0 {
+			records[len(records)-1].cont = append(records[len(records)-1].cont, data)
+		} else {
+			records = append(records, xlsRecord{typ: typ, pos: pos, data: data})
+		}
+		pos += 4 + size
+	}
+	return records
+}
+
+// decryptStream split the workbook stream into records, and decrypt the
+// records data if the workbook stream was encrypted with the password.
+func (r *xlsReader) decryptStream(stream []byte, opt *Options) error {
+	r.records = parseXLSRecords(stream)
+	var filePass []byte
+	for _, rec := range r.records {
+		if rec.typ == xlsRecordFilePass {
+			filePass = rec.data
+			break
+		}
+		if rec.typ == xlsRecordEOF {
+			break
+		}
+	}
+	if filePass == nil {
+		return nil
+	}
+	if len(filePass) < 2 || binary.LittleEndian.Uint16(filePass) != 1 {
+		return ErrUnsupportEncryptMechanism
+	}
+	password := opt.Password
+	if password == "" {
+		password = "VelvetSweatshop"
+	}
+	rc, err := newBinaryRC4(filePass[2:], password)
+	if err != nil {
+		return err
+	}
+	decrypted := append([]byte{}, stream...)
+	if err = rc.crypt(decrypted); err != nil {
+		return err
+	}
+	plain := append([]byte{}, stream...)
+	for pos := 0; pos+4 <= len(plain); {
+		typ := binary.LittleEndian.Uint16(plain[pos:])
+		size := int(binary.LittleEndian.Uint16(plain[pos+2:]))
+		start, end := pos+4, pos+4+size
+		if end > len(plain) {
+			break
+		}
+		if typ == xlsRecordBoundSheet {
+			start += 4
+		}
+		if !xlsUnencryptedRecords[typ] && start < end {
+			copy(plain[start:end], decrypted[start:end])
+		}
+		pos = end
+	}
+	r.records = parseXLSRecords(plain)
+	return nil
+}
+
+// readGlobals read the workbook globals substream.
+func (r *xlsReader) readGlobals() error {
+	if len(r.records) == 0 || r.records[0].typ != xlsRecordBOF || len(r.records[0].data) < 4 ||
+		binary.LittleEndian.Uint16(r.records[0].data) != 0x0600 {
+		return ErrWorkbookFileFormat
+	}
+	for _, rec := range r.records[1:] {
+		d := rec.data
+		switch rec.typ {
+		case xlsRecordEOF:
+			return nil
+		case xlsRecordDateMode:
+			r.date1904 = len(d) >= 2 && binary.LittleEndian.Uint16(d) == 1
+		case xlsRecordBoundSheet:
+			if len(d) < 8 {
+				continue
+			}
+			sr := &xlsSegmentReader{segs: [][]byte{d[6:]}}
+			r.sheets = append(r.sheets, xlsBoundSheet{
+				pos:   int(binary.LittleEndian.Uint32(d)),
+				state: d[4] & 0x03,
+				typ:   d[5],
+				name:  sr.shortUnicodeString(),
+			})
+		case xlsRecordFont:
+			if len(d) < 15 {
+				continue
+			}
+			grbit := binary.LittleEndian.Uint16(d[2:])
+			sr := &xlsSegmentReader{segs: [][]byte{d[14:]}}
+			r.fonts = append(r.fonts, xlsFont{
+				height:    int(binary.LittleEndian.Uint16(d)),
+				italic:    grbit&0x02 != 0,
+				strike:    grbit&0x08 != 0,
+				color:     int(binary.LittleEndian.Uint16(d[4:])),
+				weight:    int(binary.LittleEndian.Uint16(d[6:])),
+				underline: d[10],
+				name:      sr.shortUnicodeString(),
+			})
+		case xlsRecordFormat:
+			if len(d) < 5 {
+				continue
+			}
+			sr := &xlsSegmentReader{segs: append([][]byte{d[2:]}, rec.cont...)}
+			r.formats[int(binary.LittleEndian.Uint16(d))] = sr.unicodeString()
+		case xlsRecordXF:
+			if len(d) < 20 {
+				continue
+			}
+			r.xfs = append(r.xfs, parseXLSXF(d))
+		case xlsRecordPalette:
+			if len(d) < 2 {
+				continue
+			}
+			for i, n := 0, int(binary.LittleEndian.Uint16(d)); i < n && 8+i < len(r.palette) && 6+i*4 <= len(d); i++ {
+				r.palette[8+i] = fmt.Sprintf("%02X%02X%02X", d[2+i*4], d[3+i*4], d[4+i*4])
+			}
+		case xlsRecordSST:
+			r.readSST(rec)
+		}
+	}
+	return nil
+}
+
+// parseXLSXF parse the XF record data.
+func parseXLSXF(d []byte) xlsXF {
+	flags := binary.LittleEndian.Uint16(d[4:])
+	border := binary.LittleEndian.Uint32(d[10:])
+	fill := binary.LittleEndian.Uint32(d[14:])
+	colors := binary.LittleEndian.Uint16(d[18:])
+	return xlsXF{
+		font:        int(binary.LittleEndian.Uint16(d)),
+		numFmt:      int(binary.LittleEndian.Uint16(d[2:])),
+		locked:      flags&0x01 != 0,
+		hidden:      flags&0x02 != 0,
+		style:       flags&0x04 != 0,
+		horizontal:  int(d[6] & 0x07),
+		wrap:        d[6]&0x08 != 0,
+		vertical:    int(d[6]>>4) & 0x07,
+		rotation:    int(d[7]),
+		indent:      int(d[8] & 0x0F),
+		shrink:      d[8]&0x10 != 0,
+		border:      [5]int{int(border & 0x0F), int(border>>4) & 0x0F, int(border>>8) & 0x0F, int(border>>12) & 0x0F, int(fill>>21) & 0x0F},
+		borderColor: [5]int{int(border>>16) & 0x7F, int(border>>23) & 0x7F, int(fill & 0x7F), int(fill>>7) & 0x7F, int(fill>>14) & 0x7F},
+		diagonal:    int(border >> 30),
+		pattern:     int(fill >> 26),
+		fgColor:     int(colors & 0x7F),
+	}
+}
+
+// readSST read the shared string table from the SST record and the CONTINUE
+// records follow it.
+func (r *xlsReader) readSST(rec xlsRecord) {
+	if len(rec.data) < 8 {
+		return
+	}
+	count := int(binary.LittleEndian.Uint32(rec.data[4:]))
+	sr := &xlsSegmentReader{segs: append([][]byte{rec.data[8:]}, rec.cont...)}
+	r.sst = make([]string, 0, count)
+	for i := 0; i < count && !sr.eof(); i++ {
+		r.sst = append(r.sst, sr.richExtendedString())
+	}
+}
+
+// setDefaultFont set the default font of the workbook by the first font of
+// the legacy workbook.
+func (r *xlsReader) setDefaultFont() {
+	if len(r.fonts) == 0 || r.fonts[0].name == "" {
+		return
+	}
+	r.f.SetDefaultFont(r.fonts[0].name)
+	r.f.Styles.Fonts.Font[0].Sz = &attrValFloat{Val: float64Ptr(float64(r.fonts[0].height) / 20)}
+}
+
+// color returns the RGB color code by given color index, it will return an
+// empty string for the automatic color.
+func (r *xlsReader) color(icv int) string {
+	if icv < 0 || icv >= len(r.palette) {
+		return ""
+	}
+	return "#" + r.palette[icv]
+}
+
+// font returns the font record by given font index, the font index 4 is
+// omitted in the BIFF8 workbook.
+func (r *xlsReader) font(ifnt int) (xlsFont, bool) {
+	if ifnt >= 4 {
+		ifnt--
+	}
+	if ifnt < 0 || ifnt >= len(r.fonts) {
+		return xlsFont{}, false
+	}
+	return r.fonts[ifnt], true
+}
+
+// style returns the cell style ID of the spreadsheet by given XF index of the
+// legacy workbook, the styles will be created on demand.
+func (r *xlsReader) style(ixfe int) (int, error) {
+	if styleID, ok := r.styles[ixfe]; ok {
+		return styleID, nil
+	}
+	if ixfe < 0 || ixfe >= len(r.xfs) {
+		return 0, nil
+	}
+	xf := r.xfs[ixfe]
+	style := &Style{NumFmt: xf.numFmt}
+	if _, ok := builtInNumFmt[xf.numFmt]; !ok {
+		if code, ok := r.formats[xf.numFmt]; ok {
+			style.NumFmt, style.CustomNumFmt = 0, &code
+		}
+	}
+	if fnt, ok := r.font(xf.font); ok && xf.font != 0 {
+		style.Font = &Font{
+			Bold:   fnt.weight >= 700,
+			Italic: fnt.italic,
+			Strike: fnt.strike,
+			Family: fnt.name,
+			Size:   float64(fnt.height) / 20,
+			Color:  r.color(fnt.color),
+		}
+		switch fnt.underline {
+		case 0x01, 0x21:
+			style.Font.Underline = "single"
+		case 0x02, 0x22:
+			style.Font.Underline = "double"
+		}
+	}
+	if xf.pattern > 0 && xf.pattern <= 18 {
+		color := r.color(xf.fgColor)
+		if color == "" {
+			color = "#FFFFFF"
+		}
+		style.Fill = Fill{Type: "pattern", Pattern: xf.pattern, Color: []string{color}}
+	}
+	for i, side := range []string{"left", "right", "top", "bottom"} {
+		if xf.border[i] != 0 {
+			style.Border = append(style.Border, Border{Type: side, Color: r.color(xf.borderColor[i]), Style: xf.border[i]})
+		}
+	}
+	for i, side := range []string{"diagonalDown", "diagonalUp"} {
+		if xf.border[4] != 0 && xf.diagonal&(1<<uint(i)) != 0 {
+			style.Border = append(style.Border, Border{Type: side, Color: r.color(xf.borderColor[4]), Style: xf.border[4]})
+		}
+	}
+	if xf.horizontal != 0 || xf.vertical != 2 || xf.wrap || xf.shrink || xf.rotation != 0 || xf.indent != 0 {
+		style.Alignment = &Alignment{
+			Horizontal:   xlsHorizontalAlignment[xf.horizontal],
+			Indent:       xf.indent,
+			ShrinkToFit:  xf.shrink,
+			TextRotation: xf.rotation,
+			WrapText:     xf.wrap,
+		}
+		if xf.vertical < len(xlsVerticalAlignment) {
+			style.Alignment.Vertical = xlsVerticalAlignment[xf.vertical]
+		}
+	}
+	if !xf.locked || xf.hidden {
+		style.Protection = &Protection{Locked: xf.locked, Hidden: xf.hidden}
+	}
+	var styleID int
+	if style.NumFmt != 0 || style.CustomNumFmt != nil || style.Font != nil || style.Fill.Type != "" ||
+		len(style.Border) > 0 || style.Alignment != nil || style.Protection != nil {
+		var err error
+		if styleID, err = r.f.NewStyle(style); err != nil {
+			return 0, err
+		}
+	}
+	r.styles[ixfe] = styleID
+	return styleID, nil
+}
+
+// setCell set the cell value, data type and style by given zero-based row
+// and column number.
+func (r *xlsReader) setCell(ws *xlsxWorksheet, row, col, ixfe int, t, v string) error {
+	if row >= TotalRows || col >= TotalColumns {
+		return nil
+	}
+	styleID, err := r.style(ixfe)
+	if err != nil {
+		return err
+	}
+	if t == "" && v == "" && styleID == 0 {
+		return nil
+	}
+	prepareSheetXML(ws, col+1, row+1)
+	c := &ws.SheetData.Row[row].C[col]
+	c.S, c.T, c.V = styleID, t, v
+	return nil
+}
+
+// setString set the string cell value by given zero-based row and column
+// number.
+func (r *xlsReader) setString(ws *xlsxWorksheet, row, col, ixfe int, value string) error {
+	t, v := r.f.setCellString(value)
+	return r.setCell(ws, row, col, ixfe, t, v)
+}
+
+// readSheet read the worksheet substream by given BOUNDSHEET record.
+func (r *xlsReader) readSheet(sheet xlsBoundSheet) error {
+	ws, err := r.f.workSheetReader(sheet.name)
+	if err != nil {
+		return err
+	}
+	var idx int
+	for idx < len(r.records) && r.records[idx].pos != sheet.pos {
+		idx++
+	}
+	var (
+		depth                  int
+		mergeCells             [][]int
+		formulaRow, formulaCol = -1, -1
+		formulaXF              int
+	)
+	for ; idx < len(r.records); idx++ {
+		rec := r.records[idx]
+		d := rec.data
+		switch rec.typ {
+		case xlsRecordBOF:
+			depth++
+			continue
+		case xlsRecordEOF:
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+		if depth > 1 {
+			continue
+		}
+		if err = r.readCellRecord(ws, rec); err != nil {
+			return err
+		}
+		switch rec.typ {
+		case xlsRecordFormula:
+			formulaRow, formulaCol = -1, -1
+			if len(d) >= 14 && d[6] == 0 && binary.LittleEndian.Uint16(d[12:]) == 0xFFFF {
+				formulaRow, formulaCol, formulaXF = int(binary.LittleEndian.Uint16(d)), int(binary.LittleEndian.Uint16(d[2:])), int(binary.LittleEndian.Uint16(d[4:]))
+			}
+		case xlsRecordString:
+			if formulaRow != -1 && len(d) >= 3 {
+				sr := &xlsSegmentReader{segs: append([][]byte{d}, rec.cont...)}
+				if err = r.setCell(ws, formulaRow, formulaCol, formulaXF, "str", sr.unicodeString()); err != nil {
+					return err
+				}
+			}
+			formulaRow, formulaCol = -1, -1
+		case xlsRecordMergeCells:
+			if len(d) < 2 {
+				continue
+			}
+			for i, n := 0, int(binary.LittleEndian.Uint16(d)); i < n && 10+i*8 <= len(d); i++ {
+				ref := d[2+i*8:]
+				mergeCells = append(mergeCells, []int{
+					int(binary.LittleEndian.Uint16(ref[4:])) + 1, int(binary.LittleEndian.Uint16(ref)) + 1,
+					int(binary.LittleEndian.Uint16(ref[6:])) + 1, int(binary.LittleEndian.Uint16(ref[2:])) + 1,
+				})
+			}
+		case xlsRecordColInfo:
+			if err = r.readColInfo(sheet.name, d); err != nil {
+				return err
+			}
+		case xlsRecordRow:
+			if err = r.readRow(sheet.name, d); err != nil {
+				return err
+			}
+		}
+	}
+	for _, coordinates := range mergeCells {
+		if coordinates[2] > TotalColumns || coordinates[3] > TotalRows {
+			continue
+		}
+		ref, err := r.f.coordinatesToAreaRef(coordinates)
+		if err != nil {
+			return err
+		}
+		if ws.MergeCells == nil {
+			ws.MergeCells = &xlsxMergeCells{}
+		}
+		ws.MergeCells.Cells = append(ws.MergeCells.Cells, &xlsxMergeCell{Ref: ref})
+		ws.MergeCells.Count = len(ws.MergeCells.Cells)
+	}
+	return nil
+}
+
+// readCellRecord read the cell value records of the worksheet substream.
+func (r *xlsReader) readCellRecord(ws *xlsxWorksheet, rec xlsRecord) error {
+	d := rec.data
+	if len(d) < 6 {
+		return nil
+	}
+	row, col, ixfe := int(binary.LittleEndian.Uint16(d)), int(binary.LittleEndian.Uint16(d[2:])), int(binary.LittleEndian.Uint16(d[4:]))
+	switch rec.typ {
+	case xlsRecordLabelSST:
+		if len(d) < 10 {
+			return nil
+		}
+		if isst := int(binary.LittleEndian.Uint32(d[6:])); isst < len(r.sst) {
+			return r.setString(ws, row, col, ixfe, r.sst[isst])
+		}
+	case xlsRecordLabel, xlsRecordRString:
+		if len(d) < 9 {
+			return nil
+		}
+		sr := &xlsSegmentReader{segs: append([][]byte{d[6:]}, rec.cont...)}
+		return r.setString(ws, row, col, ixfe, sr.unicodeString())
+	case xlsRecordNumber:
+		if len(d) < 14 {
+			return nil
+		}
+		return r.setCell(ws, row, col, ixfe, "", formatXLSNumber(math.Float64frombits(binary.LittleEndian.Uint64(d[6:]))))
+	case xlsRecordRK:
+		if len(d) < 10 {
+			return nil
+		}
+		return r.setCell(ws, row, col, ixfe, "", formatXLSNumber(decodeXLSRK(binary.LittleEndian.Uint32(d[6:]))))
+	case xlsRecordMulRk:
+		for i := 0; 4+i*6+6 <= len(d)-2; i++ {
+			rk := d[4+i*6:]
+			if err := r.setCell(ws, row, col+i, int(binary.LittleEndian.Uint16(rk)), "",
+				formatXLSNumber(decodeXLSRK(binary.LittleEndian.Uint32(rk[2:])))); err != nil {
+				return err
+			}
+		}
+	case xlsRecordBlank:
+		return r.setCell(ws, row, col, ixfe, "", "")
+	case xlsRecordMulBlank:
+		for i := 0; 4+i*2+2 <= len(d)-2; i++ {
+			if err := r.setCell(ws, row, col+i, int(binary.LittleEndian.Uint16(d[4+i*2:])), "", ""); err != nil {
+				return err
+			}
+		}
+	case xlsRecordBoolErr:
+		if len(d) < 8 {
+			return nil
+		}
+		if d[7] == 1 {
+			return r.setCell(ws, row, col, ixfe, "e", xlsErrorCodes[d[6]])
+		}
+		t, v := setCellBool(d[6] != 0)
+		return r.setCell(ws, row, col, ixfe, t, v)
+	case xlsRecordFormula:
+		if len(d) < 14 {
+			return nil
+		}
+		if binary.LittleEndian.Uint16(d[12:]) != 0xFFFF {
+			return r.setCell(ws, row, col, ixfe, "", formatXLSNumber(math.Float64frombits(binary.LittleEndian.Uint64(d[6:]))))
+		}
+		switch d[6] {
+		case 1:
+			t, v := setCellBool(d[8] != 0)
+			return r.setCell(ws, row, col, ixfe, t, v)
+		case 2:
+			return r.setCell(ws, row, col, ixfe, "e", xlsErrorCodes[d[8]])
+		case 3:
+			return r.setCell(ws, row, col, ixfe, "str", "")
+		}
+	}
+	return nil
+}
+
+// readColInfo read the column width, visibility and outline level from the
+// COLINFO record.
+func (r *xlsReader) readColInfo(sheet string, d []byte) error {
+	if len(d) < 10 {
+		return nil
+	}
+	first, last := int(binary.LittleEndian.Uint16(d))+1, int(binary.LittleEndian.Uint16(d[2:]))+1
+	if last > TotalColumns {
+		last = TotalColumns
+	}
+	if first > last {
+		return nil
+	}
+	startCol, _ := ColumnNumberToName(first)
+	endCol, _ := ColumnNumberToName(last)
+	if err := r.f.SetColWidth(sheet, startCol, endCol, float64(binary.LittleEndian.Uint16(d[4:]))/256); err != nil {
+		return err
+	}
+	flags := binary.LittleEndian.Uint16(d[8:])
+	if flags&0x01 != 0 {
+		if err := r.f.SetColVisible(sheet, startCol+":"+endCol, false); err != nil {
+			return err
+		}
+	}
+	if level := uint8(flags>>8) & 0x07; level > 0 {
+		for col := first; col <= last; col++ {
+			name, _ := ColumnNumberToName(col)
+			if err := r.f.SetColOutlineLevel(sheet, name, level); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readRow read the row height, visibility and outline level from the ROW
+// record.
+func (r *xlsReader) readRow(sheet string, d []byte) error {
+	if len(d) < 16 {
+		return nil
+	}
+	row := int(binary.LittleEndian.Uint16(d)) + 1
+	flags := binary.LittleEndian.Uint16(d[12:])
+	if flags&0x40 != 0 {
+		if err := r.f.SetRowHeight(sheet, row, float64(binary.LittleEndian.Uint16(d[6:])&0x7FFF)/20); err != nil {
+			return err
+		}
+	}
+	if flags&0x20 != 0 {
+		if err := r.f.SetRowVisible(sheet, row, false); err != nil {
+			return err
+		}
+	}
+	if level := uint8(flags & 0x07); level > 0 {
+		return r.f.SetRowOutlineLevel(sheet, row, level)
+	}
+	return nil
+}
+
+// decodeXLSRK decode the RK number value.
+func decodeXLSRK(rk uint32) float64 {
+	var num float64
+	if rk&0x02 != 0 {
+		num = float64(int32(rk) >> 2)
+	} else {
+		num = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		num /= 100
+	}
+	return num
+}
+
+// formatXLSNumber format the number value as cell value.
+func formatXLSNumber(num float64) string {
+	return strconv.FormatFloat(num, 'f', -1, 64)
+}
+
+// xlsSegmentReader reads the data split into the record and the CONTINUE
+// records, the unicode string characters resume with a new option flags byte
+// at the beginning of each CONTINUE record.
+type xlsSegmentReader struct {
+	segs     [][]byte
+	idx, pos int
+}
+
+// eof returns if all data has been read.
+func (sr *xlsSegmentReader) eof() bool {
+	for sr.idx < len(sr.segs) && sr.pos >= len(sr.segs[sr.idx]) {
+		sr.idx, sr.pos = sr.idx+1, 0
+	}
+	return sr.idx >= len(sr.segs)
+}
+
+// bytes read n bytes across the segments.
+func (sr *xlsSegmentReader) bytes(n int) []byte {
+	var buf []byte
+	for n > 0 && !sr.eof() {
+		seg := sr.segs[sr.idx]
+		l := len(seg) - sr.pos
+		if l > n {
+			l = n
+		}
+		buf = append(buf, seg[sr.pos:sr.pos+l]...)
+		sr.pos += l
+		n -= l
+	}
+	return buf
+}
+
+// uint16 read a 16-bit unsigned integer.
+func (sr *xlsSegmentReader) uint16() int {
+	if buf := sr.bytes(2); len(buf) == 2 {
+		return int(binary.LittleEndian.Uint16(buf))
+	}
+	return 0
+}
+
+// uint32 read a 32-bit unsigned integer.
+func (sr *xlsSegmentReader) uint32() int {
+	if buf := sr.bytes(4); len(buf) == 4 {
+		return int(binary.LittleEndian.Uint32(buf))
+	}
+	return 0
+}
+
+// chars read cch characters of a unicode string.
+func (sr *xlsSegmentReader) chars(cch int, flags byte) string {
+	var runes []uint16
+	for idx := sr.idx; cch > 0 && !sr.eof(); {
+		if sr.idx != idx {
+			idx, flags = sr.idx, sr.bytes(1)[0]
+			continue
+		}
+		seg := sr.segs[sr.idx]
+		if flags&0x01 == 0 {
+			for ; cch > 0 && sr.pos < len(seg); cch, sr.pos = cch-1, sr.pos+1 {
+				runes = append(runes, uint16(seg[sr.pos]))
+			}
+			continue
+		}
+		for ; cch > 0 && sr.pos+1 < len(seg); cch, sr.pos = cch-1, sr.pos+2 {
+			runes = append(runes, binary.LittleEndian.Uint16(seg[sr.pos:]))
+		}
+		if sr.pos+1 == len(seg) {
+			sr.pos++
+		}
+	}
+	return string(utf16.Decode(runes))
+}
+
+// shortUnicodeString read the ShortXLUnicodeString structure.
+func (sr *xlsSegmentReader) shortUnicodeString() string {
+	cch := sr.bytes(1)
+	flags := sr.bytes(1)
+	if len(cch) == 0 || len(flags) == 0 {
+		return ""
+	}
+	return sr.chars(int(cch[0]), flags[0])
+}
+
+// unicodeString read the XLUnicodeString structure.
+func (sr *xlsSegmentReader) unicodeString() string {
+	cch := sr.uint16()
+	flags := sr.bytes(1)
+	if len(flags) == 0 {
+		return ""
+	}
+	return sr.chars(cch, flags[0])
+}
+
+// richExtendedString read the XLUnicodeRichExtendedString structure, the
+// formatting runs and phonetic data will be skipped.
+func (sr *xlsSegmentReader) richExtendedString() string {
+	cch := sr.uint16()
+	flags := sr.bytes(1)
+	if len(flags) == 0 {
+		return ""
+	}
+	var runs, ext int
+	if flags[0]&0x08 != 0 {
+		runs = sr.uint16()
+	}
+	if flags[0]&0x04 != 0 {
+		ext = sr.uint32()
+	}
+	str := sr.chars(cch, flags[0])
+	sr.bytes(runs*4 + ext)
+	return str
+}
@@ -0,0 +1,329 @@
+package xlsx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestOpenXLS(t *testing.T) {
+	f, err := OpenReader(bytes.NewReader(writeCFB(map[string][]byte{"Workbook": xlsTestWorkbook(nil)})))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Data", "Hidden Sheet"}, f.GetSheetList())
+	assert.False(t, f.GetSheetVisible("Hidden Sheet"))
+	assert.True(t, f.workbookReader().WorkbookPr.Date1904)
+	assert.Equal(t, "Arial", f.GetDefaultFont())
+
+	for cell, expected := range map[string]string{
+		"A1": "Hello", "B1": "3.14", "C1": "100", "D1": "1.23",
+		"A2": "1", "B2": "2", "C2": "3",
+		"A3": "1", "B3": "#N/A",
+		"A4": "42", "B4": "cached", "C4": "0", "D4": "#DIV/0!",
+		"A5": "Label", "A6": "ABCDEFGHIJ", "B6": "中文", "C6": "Foo",
+		"A7": "2020-01-01", "A8": "World", "A9": "Merged",
+	} {
+		val, err := f.GetCellValue("Data", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	val, err := f.GetCellValue("Hidden Sheet", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "World", val)
+
+	// Test get the cell types.
+	cellType, err := f.GetCellType("Data", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, CellTypeError, cellType)
+
+	// Test get the mapped styles.
+	styleID, err := f.GetCellStyle("Data", "A8")
+	assert.NoError(t, err)
+	xf := f.Styles.CellXfs.Xf[styleID]
+	fnt := f.Styles.Fonts.Font[*xf.FontID]
+	assert.Equal(t, "Times New Roman", *fnt.Name.Val)
+	assert.Equal(t, 12.0, *fnt.Sz.Val)
+	assert.True(t, *fnt.B.Val)
+	assert.Equal(t, "FFFF0000", fnt.Color.RGB)
+	fill := f.Styles.Fills.Fill[*xf.FillID]
+	assert.Equal(t, "solid", fill.PatternFill.PatternType)
+	assert.Equal(t, "FFFFFF00", fill.PatternFill.FgColor.RGB)
+	border := f.Styles.Borders.Border[*xf.BorderID]
+	assert.Equal(t, "thin", border.Left.Style)
+	assert.Equal(t, "double", border.Bottom.Style)
+	assert.Equal(t, "center", xf.Alignment.Horizontal)
+	assert.True(t, xf.Alignment.WrapText)
+	styleID, err = f.GetCellStyle("Data", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, styleID)
+
+	// Test get merged cells, column width, row height and visibility.
+	mergeCells, err := f.GetMergeCells("Data")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "A9:C10", mergeCells[0][0])
+	width, err := f.GetColWidth("Data", "B")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	visible, err := f.GetColVisible("Data", "D")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	height, err := f.GetRowHeight("Data", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	visible, err = f.GetRowVisible("Data", 11)
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	level, err := f.GetRowOutlineLevel("Data", 11)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(1), level)
+
+	// Test save the legacy workbook as spreadsheet.
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	val, err = f.GetCellValue("Data", "B6")
+	assert.NoError(t, err)
+	assert.Equal(t, "中文", val)
+
+	// Test open the legacy workbook with unsupported BIFF version.
+	stream := xlsTestWorkbook(nil)
+	binary.LittleEndian.PutUint16(stream[4:], 0x0500)
+	_, err = OpenReader(bytes.NewReader(writeCFB(map[string][]byte{"Book": stream})))
+	assert.EqualError(t, err, ErrWorkbookFileFormat.Error())
+}
+
+func TestOpenEncryptedXLS(t *testing.T) {
+	for _, cryptoAPI := range []bool{true, false} {
+		filePass, rc := xlsTestFilePass(cryptoAPI, "password")
+		raw := writeCFB(map[string][]byte{"Workbook": xlsTestEncrypt(xlsTestWorkbook(filePass), rc)})
+		f, err := OpenReader(bytes.NewReader(raw), Options{Password: "password"})
+		assert.NoError(t, err)
+		for cell, expected := range map[string]string{"A1": "Hello", "B4": "cached", "A6": "ABCDEFGHIJ"} {
+			val, err := f.GetCellValue("Data", cell)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, val)
+		}
+		assert.Equal(t, []string{"Data", "Hidden Sheet"}, f.GetSheetList())
+		// Test open the encrypted legacy workbook with incorrect password.
+		_, err = OpenReader(bytes.NewReader(raw), Options{Password: "passwd"})
+		assert.EqualError(t, err, ErrWorkbookPassword.Error())
+	}
+	// Test open the legacy workbook with XOR obfuscation.
+	raw := writeCFB(map[string][]byte{"Workbook": xlsTestWorkbook([]byte{0, 0, 0, 0, 0, 0})})
+	_, err := OpenReader(bytes.NewReader(raw))
+	assert.EqualError(t, err, ErrUnsupportEncryptMechanism.Error())
+	// Test parse the encryption info with unsupported encryption mechanism.
+	_, err = newBinaryRC4([]byte{3, 0, 3, 0}, "")
+	assert.EqualError(t, err, ErrUnsupportEncryptMechanism.Error())
+	_, err = newBinaryRC4([]byte{}, "")
+	assert.EqualError(t, err, ErrUnknownEncryptMechanism.Error())
+}
+
+func TestXLSSegmentReader(t *testing.T) {
+	sr := &xlsSegmentReader{segs: [][]byte{{3, 0, 0, 'a'}, {1, 'b', 0, 'c', 0}}}
+	assert.Equal(t, "abc", sr.unicodeString())
+	assert.True(t, sr.eof())
+	assert.Equal(t, "", sr.unicodeString())
+	assert.Equal(t, "", sr.shortUnicodeString())
+	assert.Equal(t, "", sr.richExtendedString())
+	assert.Equal(t, 0, sr.uint32())
+	assert.Equal(t, -1.5, decodeXLSRK(uint32(math.Float64bits(-1.5)>>32)))
+	assert.Equal(t, -0.05, decodeXLSRK(0xFFFFFFEC|3))
+}
+
+// xlsTestRecord create a BIFF8 record by given record type and data.
+func xlsTestRecord(typ uint16, data ...[]byte) []byte {
+	body := bytes.Join(data, nil)
+	buf := make([]byte, 4, 4+len(body))
+	binary.LittleEndian.PutUint16(buf, typ)
+	binary.LittleEndian.PutUint16(buf[2:], uint16(len(body)))
+	return append(buf, body...)
+}
+
+// xlsTestUint create the little endian bytes of the given integers with the
+// given size.
+func xlsTestUint(size int, values ...int) []byte {
+	var buf []byte
+	for _, v := range values {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, uint64(v))
+		buf = append(buf, b[:size]...)
+	}
+	return buf
+}
+
+// xlsTestString create the unicode string structure with 8-bit or 16-bit
+// length field.
+func xlsTestString(lenSize int, s string) []byte {
+	for _, r := range s {
+		if r > 0xFF {
+			u := utf16.Encode([]rune(s))
+			buf := append(xlsTestUint(lenSize, len(u)), 1)
+			for _, c := range u {
+				buf = append(buf, xlsTestUint(2, int(c))...)
+			}
+			return buf
+		}
+	}
+	return append(append(xlsTestUint(lenSize, len(s)), 0), []byte(s)...)
+}
+
+// xlsTestCell create the cell record by given record type, zero-based row
+// and column number, XF index and record data.
+func xlsTestCell(typ uint16, row, col, ixfe int, data ...[]byte) []byte {
+	return xlsTestRecord(typ, append([][]byte{xlsTestUint(2, row, col, ixfe)}, data...)...)
+}
+
+// xlsTestWorkbook create a BIFF8 workbook stream with two worksheets and a
+// chart sheet.
+func xlsTestWorkbook(filePass []byte) []byte {
+	number := func(v float64) []byte { return xlsTestUint(8, int(math.Float64bits(v))) }
+	xf := func(font, numFmt, flags, align, border, fill, colors int) []byte {
+		return bytes.Join([][]byte{
+			xlsTestUint(2, font, numFmt, flags), {byte(align), 0, 0, 0},
+			xlsTestUint(4, border, fill), xlsTestUint(2, colors),
+		}, nil)
+	}
+	font := func(height, flags, color, weight, underline int, name string) []byte {
+		return xlsTestRecord(xlsRecordFont, xlsTestUint(2, height, flags, color, weight, 0),
+			[]byte{byte(underline), 0, 0, 0}, xlsTestString(1, name))
+	}
+	sst := xlsTestRecord(xlsRecordSST, xlsTestUint(4, 6, 6),
+		xlsTestString(2, "Hello"), xlsTestString(2, "World"), xlsTestString(2, "Merged"),
+		xlsTestString(2, "中文"), xlsTestUint(2, 3), []byte{0x08}, xlsTestUint(2, 1), []byte("Foo"), xlsTestUint(2, 0, 3),
+		xlsTestUint(2, 10), []byte{0}, []byte("ABCDE"))
+	var globals [][]byte
+	globals = append(globals, xlsTestRecord(xlsRecordBOF, xlsTestUint(2, 0x0600, 0x0005), make([]byte, 12)))
+	if filePass != nil {
+		globals = append(globals, xlsTestRecord(xlsRecordFilePass, filePass))
+	}
+	globals = append(globals,
+		xlsTestRecord(xlsRecordDateMode, xlsTestUint(2, 1)),
+		font(200, 0, 0x7FFF, 400, 0, "Arial"), font(200, 0, 0x7FFF, 400, 0, "Arial"),
+		font(200, 0, 0x7FFF, 400, 0, "Arial"), font(200, 0, 0x7FFF, 400, 0, "Arial"),
+		font(240, 0x02, 10, 700, 1, "Times New Roman"),
+		xlsTestRecord(xlsRecordFormat, xlsTestUint(2, 164), xlsTestString(2, "yyyy-mm-dd")),
+		xlsTestRecord(xlsRecordXF, xf(0, 0, 0xFFF5, 0x20, 0, 0, 0x20C0)),
+		xlsTestRecord(xlsRecordXF, xf(0, 0, 0x0001, 0x20, 0, 0, 0x20C0)),
+		xlsTestRecord(xlsRecordXF, xf(0, 164, 0x0001, 0x20, 0, 0, 0x20C0)),
+		xlsTestRecord(xlsRecordXF, xf(5, 0, 0x0001, 0x2A, 0x6<<12|0x1|8<<16|8<<23, 1<<26|8<<7, 13|65<<7)),
+		xlsTestRecord(xlsRecordPalette, xlsTestUint(2, 1), []byte{0x11, 0x22, 0x33, 0}),
+	)
+	boundSheet := func(state, typ int, name string) []byte {
+		return xlsTestRecord(xlsRecordBoundSheet, xlsTestUint(4, 0), []byte{byte(state), byte(typ)}, xlsTestString(1, name))
+	}
+	boundSheets := [][]byte{boundSheet(0, 0, "Data"), boundSheet(1, 0, "Hidden Sheet"), boundSheet(0, 2, "Chart")}
+	globals = append(globals, boundSheets...)
+	globals = append(globals, sst, xlsTestRecord(xlsRecordContinue, []byte{1}, []byte{'F', 0, 'G', 0, 'H', 0, 'I', 0, 'J', 0}))
+	globals = append(globals, xlsTestRecord(xlsRecordEOF))
+
+	sheets := [][]byte{bytes.Join([][]byte{
+		xlsTestRecord(xlsRecordBOF, xlsTestUint(2, 0x0600, 0x0010), make([]byte, 12)),
+		xlsTestRecord(xlsRecordColInfo, xlsTestUint(2, 1, 1, 20*256, 15, 0, 0)),
+		xlsTestRecord(xlsRecordColInfo, xlsTestUint(2, 3, 3, 10*256, 15, 1, 0)),
+		xlsTestRecord(xlsRecordRow, xlsTestUint(2, 1, 0, 3, 600, 0, 0, 0x40, 15)),
+		xlsTestRecord(xlsRecordRow, xlsTestUint(2, 10, 0, 0, 255, 0, 0, 0x21, 15)),
+		xlsTestCell(xlsRecordLabelSST, 0, 0, 1, xlsTestUint(4, 0)),
+		xlsTestCell(xlsRecordNumber, 0, 1, 1, number(3.14)),
+		xlsTestCell(xlsRecordRK, 0, 2, 1, xlsTestUint(4, 100<<2|2)),
+		xlsTestCell(xlsRecordRK, 0, 3, 1, xlsTestUint(4, 123<<2|3)),
+		xlsTestRecord(xlsRecordMulRk, xlsTestUint(2, 1, 0), xlsTestUint(2, 1), xlsTestUint(4, 1<<2|2),
+			xlsTestUint(2, 1), xlsTestUint(4, 2<<2|2), xlsTestUint(2, 1), xlsTestUint(4, 3<<2|2), xlsTestUint(2, 2)),
+		xlsTestCell(xlsRecordBoolErr, 2, 0, 1, []byte{1, 0}),
+		xlsTestCell(xlsRecordBoolErr, 2, 1, 1, []byte{0x2A, 1}),
+		xlsTestCell(xlsRecordFormula, 3, 0, 1, number(42), make([]byte, 8)),
+		xlsTestCell(xlsRecordFormula, 3, 1, 1, []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}, make([]byte, 8)),
+		xlsTestRecord(0x04BC, make([]byte, 10)),
+		xlsTestRecord(xlsRecordString, xlsTestString(2, "cached")),
+		xlsTestCell(xlsRecordFormula, 3, 2, 1, []byte{1, 0, 0, 0, 0, 0, 0xFF, 0xFF}, make([]byte, 8)),
+		xlsTestCell(xlsRecordFormula, 3, 3, 1, []byte{2, 0, 0x07, 0, 0, 0, 0xFF, 0xFF}, make([]byte, 8)),
+		xlsTestCell(xlsRecordLabel, 4, 0, 1, xlsTestString(2, "Label")),
+		xlsTestCell(xlsRecordLabelSST, 5, 0, 1, xlsTestUint(4, 5)),
+		xlsTestCell(xlsRecordLabelSST, 5, 1, 1, xlsTestUint(4, 3)),
+		xlsTestCell(xlsRecordLabelSST, 5, 2, 1, xlsTestUint(4, 4)),
+		xlsTestCell(xlsRecordNumber, 6, 0, 2, number(43831)),
+		xlsTestCell(xlsRecordLabelSST, 7, 0, 3, xlsTestUint(4, 1)),
+		xlsTestCell(xlsRecordLabelSST, 8, 0, 1, xlsTestUint(4, 2)),
+		xlsTestCell(xlsRecordBlank, 8, 1, 3),
+		xlsTestRecord(xlsRecordMulBlank, xlsTestUint(2, 9, 0, 3, 3, 3, 2)),
+		xlsTestRecord(xlsRecordMergeCells, xlsTestUint(2, 1, 8, 9, 0, 2)),
+		xlsTestRecord(xlsRecordBOF, xlsTestUint(2, 0x0600, 0x0020), make([]byte, 12)),
+		xlsTestCell(xlsRecordLabelSST, 0, 0, 1, xlsTestUint(4, 1)),
+		xlsTestRecord(xlsRecordEOF),
+		xlsTestRecord(xlsRecordEOF),
+	}, nil), bytes.Join([][]byte{
+		xlsTestRecord(xlsRecordBOF, xlsTestUint(2, 0x0600, 0x0010), make([]byte, 12)),
+		xlsTestCell(xlsRecordLabelSST, 0, 0, 1, xlsTestUint(4, 1)),
+		xlsTestRecord(xlsRecordEOF),
+	}, nil), bytes.Join([][]byte{
+		xlsTestRecord(xlsRecordBOF, xlsTestUint(2, 0x0600, 0x0020), make([]byte, 12)),
+		xlsTestRecord(xlsRecordEOF),
+	}, nil)}
+
+	stream := bytes.Join(globals, nil)
+	pos := bytes.Index(stream, boundSheets[0])
+	for i, sheet := range sheets {
+		binary.LittleEndian.PutUint32(stream[pos+4:], uint32(len(stream)))
+		pos += len(boundSheets[i])
+		stream = append(stream, sheet...)
+	}
+	return stream
+}
+
+// xlsTestFilePass create the FILEPASS record data of RC4 encryption or RC4
+// CryptoAPI encryption by given password.
+func xlsTestFilePass(cryptoAPI bool, passwd string) ([]byte, *binaryRC4) {
+	encoder := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder()
+	passwordBuffer, _ := encoder.Bytes([]byte(passwd))
+	salt, verifier := bytes.Repeat([]byte{0x5A}, 16), bytes.Repeat([]byte{0xA5}, 16)
+	rc := &binaryRC4{cryptoAPI: cryptoAPI, keySize: 16}
+	var verifierHash []byte
+	if cryptoAPI {
+		rc.hash, verifierHash = hashing("sha1", salt, passwordBuffer), hashing("sha1", verifier)
+	} else {
+		truncatedHash := hashing("md5", passwordBuffer)[:5]
+		var buf []byte
+		for i := 0; i < 16; i++ {
+			buf = append(append(buf, truncatedHash...), salt...)
+		}
+		rc.hash, verifierHash = hashing("md5", buf)[:5], hashing("md5", verifier)
+	}
+	encrypted := append(append([]byte{}, verifier...), verifierHash...)
+	_ = rc.crypt(encrypted)
+	if !cryptoAPI {
+		return bytes.Join([][]byte{xlsTestUint(2, 1, 1, 1), salt, encrypted}, nil), rc
+	}
+	cspName := []byte{0, 0}
+	header := bytes.Join([][]byte{xlsTestUint(4, 0x04, 0, 0x6801, 0x8004, 128, 1, 0, 0), cspName}, nil)
+	return bytes.Join([][]byte{
+		xlsTestUint(2, 1, 4, 2), xlsTestUint(4, 0x04, len(header)), header,
+		xlsTestUint(4, 16), salt, encrypted[:16], xlsTestUint(4, 20), encrypted[16:],
+	}, nil), rc
+}
+
+// xlsTestEncrypt encrypt the records data of the workbook stream.
+func xlsTestEncrypt(stream []byte, rc *binaryRC4) []byte {
+	encrypted := append([]byte{}, stream...)
+	_ = rc.crypt(encrypted)
+	for _, rec := range parseXLSRecords(stream) {
+		start, end := rec.pos+4, rec.pos+4+len(rec.data)
+		if rec.typ == xlsRecordBoundSheet {
+			start += 4
+		}
+		if !xlsUnencryptedRecords[rec.typ] && start < end {
+			copy(stream[start:end], encrypted[start:end])
+		}
+		for _, cont := range rec.cont {
+			end += 4
+			copy(stream[end:end+len(cont)], encrypted[end:end+len(cont)])
+			end += len(cont)
+		}
+	}
+	return stream
+}
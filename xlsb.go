@@ -0,0 +1,1969 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// BIFF12 record types used by the binary workbook parts.
+const (
+	xlsbRecordRowHdr            = 0x0000
+	xlsbRecordCellBlank         = 0x0001
+	xlsbRecordCellRk            = 0x0002
+	xlsbRecordCellError         = 0x0003
+	xlsbRecordCellBool          = 0x0004
+	xlsbRecordCellReal          = 0x0005
+	xlsbRecordCellSt            = 0x0006
+	xlsbRecordCellIsst          = 0x0007
+	xlsbRecordFmlaString        = 0x0008
+	xlsbRecordFmlaNum           = 0x0009
+	xlsbRecordFmlaBool          = 0x000A
+	xlsbRecordFmlaError         = 0x000B
+	xlsbRecordSSTItem           = 0x0013
+	xlsbRecordFont              = 0x002B
+	xlsbRecordFmt               = 0x002C
+	xlsbRecordFill              = 0x002D
+	xlsbRecordBorder            = 0x002E
+	xlsbRecordXF                = 0x002F
+	xlsbRecordStyle             = 0x0030
+	xlsbRecordColInfo           = 0x003C
+	xlsbRecordCellRString       = 0x003E
+	xlsbRecordFileVersion       = 0x0080
+	xlsbRecordBeginSheet        = 0x0081
+	xlsbRecordEndSheet          = 0x0082
+	xlsbRecordBeginBook         = 0x0083
+	xlsbRecordEndBook           = 0x0084
+	xlsbRecordBeginWsViews      = 0x0085
+	xlsbRecordEndWsViews        = 0x0086
+	xlsbRecordBeginBookViews    = 0x0087
+	xlsbRecordEndBookViews      = 0x0088
+	xlsbRecordBeginWsView       = 0x0089
+	xlsbRecordEndWsView         = 0x008A
+	xlsbRecordBeginBundleShs    = 0x008F
+	xlsbRecordEndBundleShs      = 0x0090
+	xlsbRecordBeginSheetData    = 0x0091
+	xlsbRecordEndSheetData      = 0x0092
+	xlsbRecordWsProp            = 0x0093
+	xlsbRecordWsDim             = 0x0094
+	xlsbRecordWbProp            = 0x0099
+	xlsbRecordBundleSh          = 0x009C
+	xlsbRecordBookView          = 0x009E
+	xlsbRecordBeginSst          = 0x009F
+	xlsbRecordEndSst            = 0x00A0
+	xlsbRecordMergeCell         = 0x00B0
+	xlsbRecordBeginMergeCells   = 0x00B1
+	xlsbRecordEndMergeCells     = 0x00B2
+	xlsbRecordBeginStyleSheet   = 0x0116
+	xlsbRecordEndStyleSheet     = 0x0117
+	xlsbRecordBeginColInfos     = 0x0186
+	xlsbRecordEndColInfos       = 0x0187
+	xlsbRecordWsFmtInfo         = 0x01E5
+	xlsbRecordHLink             = 0x01EE
+	xlsbRecordDrawing           = 0x0226
+	xlsbRecordBeginFills        = 0x025B
+	xlsbRecordEndFills          = 0x025C
+	xlsbRecordBeginFonts        = 0x0263
+	xlsbRecordEndFonts          = 0x0264
+	xlsbRecordBeginBorders      = 0x0265
+	xlsbRecordEndBorders        = 0x0266
+	xlsbRecordBeginFmts         = 0x0267
+	xlsbRecordEndFmts           = 0x0268
+	xlsbRecordBeginCellXFs      = 0x0269
+	xlsbRecordEndCellXFs        = 0x026A
+	xlsbRecordBeginStyles       = 0x026B
+	xlsbRecordEndStyles         = 0x026C
+	xlsbRecordBeginCellStyleXFs = 0x0272
+	xlsbRecordEndCellStyleXFs   = 0x0273
+)
+
+// BIFF12 field values used by the binary workbook parts.
+const (
+	xlsbColorIndexed             = 1
+	xlsbColorRGB                 = 2
+	xlsbColorTheme               = 3
+	xlsbFillGradient             = 0x28
+	xlsbBookViewFlags            = 0x78
+	xlsbSheetViewFlags           = 0x0380
+	xlsbSheetPropFlags           = 0x04C1
+	xlsbStyleParentXF            = 0xFFFF
+	xlsbDefaultBaseColWidth      = 8
+	xlsbDefaultRowHeightInTwips  = 300
+	xlsbDefaultFontHeightInTwips = 220
+	xlsbFormulaAlwaysCalc        = 0x0002
+)
+
+// BIFF12 Ptg types of the parsed expressions in the formulas, the operand Ptg
+// types are defined in the reference class.
+const (
+	xlsbPtgAdd        = 0x03
+	xlsbPtgNe         = 0x0E
+	xlsbPtgUplus      = 0x12
+	xlsbPtgUminus     = 0x13
+	xlsbPtgPercent    = 0x14
+	xlsbPtgParen      = 0x15
+	xlsbPtgMissArg    = 0x16
+	xlsbPtgStr        = 0x17
+	xlsbPtgAttr       = 0x19
+	xlsbPtgErr        = 0x1C
+	xlsbPtgBool       = 0x1D
+	xlsbPtgInt        = 0x1E
+	xlsbPtgNum        = 0x1F
+	xlsbPtgFunc       = 0x21
+	xlsbPtgFuncVar    = 0x22
+	xlsbPtgRef        = 0x24
+	xlsbPtgArea       = 0x25
+	xlsbPtgClassValue = 0x20
+	xlsbAttrChoose    = 0x04
+	xlsbAttrSum       = 0x10
+)
+
+// xlsbPartTypes defined the content types of the XML parts which could be
+// converted to the binary parts, and the content types of the binary parts.
+var xlsbPartTypes = map[string]string{
+	ContentTypeSheetML:                    ContentTypeBinaryWorkbook,
+	ContentTypeMacro:                      ContentTypeBinaryWorkbook,
+	ContentTypeSpreadSheetMLWorksheet:     ContentTypeBinaryWorksheet,
+	ContentTypeSpreadSheetMLSharedStrings: ContentTypeBinarySharedStrings,
+	ContentTypeSpreadSheetMLStyles:        ContentTypeBinaryStyles,
+}
+
+// xlsbUnderlineTypes defined the underline types of the BrtFont record.
+var xlsbUnderlineTypes = map[int]string{
+	0x01: "single",
+	0x02: "double",
+	0x21: "singleAccounting",
+	0x22: "doubleAccounting",
+}
+
+// xlsbOperators defined the precedence and the Ptg type of the infix
+// operators in the formulas.
+var xlsbOperators = map[string][2]int{
+	"=": {1, 0x0B}, "<>": {1, 0x0E}, "<": {1, 0x09}, "<=": {1, 0x0A}, ">": {1, 0x0D}, ">=": {1, 0x0C},
+	"&": {2, 0x08}, "+": {3, 0x03}, "-": {3, 0x04}, "*": {4, 0x05}, "/": {4, 0x06}, "^": {5, 0x07},
+}
+
+// xlsbFunctions defined the built-in functions which could be written in the
+// formulas of the binary workbook, the values are the function index and the
+// number of the arguments, -1 means the function takes variable arguments.
+var xlsbFunctions = map[string][2]int{
+	"COUNT": {0, -1}, "IF": {1, -1}, "ISNA": {2, 1}, "ISERROR": {3, 1}, "SUM": {4, -1}, "AVERAGE": {5, -1},
+	"MIN": {6, -1}, "MAX": {7, -1}, "ROW": {8, -1}, "COLUMN": {9, -1}, "NA": {10, 0}, "SQRT": {20, 1},
+	"ABS": {24, 1}, "INT": {25, 1}, "ROUND": {27, 2}, "INDEX": {29, -1}, "MID": {31, 3}, "LEN": {32, 1},
+	"VALUE": {33, 1}, "AND": {36, -1}, "OR": {37, -1}, "NOT": {38, 1}, "MOD": {39, 2}, "TEXT": {48, 2},
+	"DATE": {65, 3}, "DAY": {67, 1}, "MONTH": {68, 1}, "YEAR": {69, 1}, "NOW": {74, 0}, "HLOOKUP": {101, -1},
+	"VLOOKUP": {102, -1}, "LOWER": {112, 1}, "UPPER": {113, 1}, "LEFT": {115, -1}, "RIGHT": {116, -1},
+	"TRIM": {118, 1}, "COUNTA": {169, -1}, "ROUNDUP": {212, 2}, "ROUNDDOWN": {213, 2}, "TODAY": {221, 0},
+	"SUMPRODUCT": {228, -1}, "CONCATENATE": {336, -1}, "POWER": {337, 2}, "SUMIF": {345, -1}, "COUNTIF": {346, 2},
+}
+
+// xlsbRecord directly maps a BIFF12 record of the binary workbook parts.
+type xlsbRecord struct {
+	typ  int
+	data []byte
+}
+
+// xlsbReader reads the little-endian fields of the BIFF12 record data, all
+// read functions return zero value once the data is exhausted.
+type xlsbReader struct {
+	data []byte
+	pos  int
+}
+
+// xlsbWriter builds the BIFF12 records of the binary workbook parts, the
+// fields are appended to the pending record data until the record is
+// written by the record function.
+type xlsbWriter struct {
+	buf  bytes.Buffer
+	data []byte
+}
+
+// readXLSBRecords split the binary workbook part into the BIFF12 records. The
+// record type and the record size are both variable length integers which
+// stores 7 bits in each byte, and the high bit indicates that the following
+// byte is also a part of the integer.
+func readXLSBRecords(b []byte) ([]xlsbRecord, error) {
+	var records []xlsbRecord
+	for pos := 0; pos < len(b); {
+		typ, n := readXLSBVarint(b[pos:], 2)
+		if n == 0 {
+			return records, ErrWorkbookBinaryPart
+		}
+		pos += n
+		size, n := readXLSBVarint(b[pos:], 4)
+		if n == 0 || pos+n+size > len(b) {
+			return records, ErrWorkbookBinaryPart
+		}
+		pos += n
+		records = append(records, xlsbRecord{typ: typ, data: b[pos : pos+size]})
+		pos += size
+	}
+	return records, nil
+}
+
+// readXLSBVarint decodes the variable length integer with given maximum
+// number of bytes, and returns the value and the number of bytes read, the
+// number of bytes will be zero if the integer was not terminated.
+func readXLSBVarint(b []byte, max int) (int, int) {
+	var v int
+	for n := 0; n < max && n < len(b); n++ {
+		v |= int(b[n]&0x7F) << (7 * n)
+		if b[n]&0x80 == 0 {
+			return v, n + 1
+		}
+	}
+	return 0, 0
+}
+
+// bytes reads the given number of bytes.
+func (r *xlsbReader) bytes(n int) []byte {
+	if n < 0 || r.pos+n > len(r.data) {
+		r.pos = len(r.data)
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+// uint8 reads an unsigned 8-bit integer.
+func (r *xlsbReader) uint8() int {
+	if b := r.bytes(1); b != nil {
+		return int(b[0])
+	}
+	return 0
+}
+
+// uint16 reads an unsigned 16-bit integer.
+func (r *xlsbReader) uint16() int {
+	if b := r.bytes(2); b != nil {
+		return int(binary.LittleEndian.Uint16(b))
+	}
+	return 0
+}
+
+// uint32 reads an unsigned 32-bit integer.
+func (r *xlsbReader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+// float64 reads an IEEE 754 double precision floating point number.
+func (r *xlsbReader) float64() float64 {
+	if b := r.bytes(8); b != nil {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+	return 0
+}
+
+// string reads a XLWideString or a XLNullableWideString, the null string
+// will be read as empty string.
+func (r *xlsbReader) string() string {
+	cch := r.uint32()
+	if cch == math.MaxUint32 || int(cch) > len(r.data) {
+		return ""
+	}
+	b := r.bytes(int(cch) * 2)
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[i*2:])
+	}
+	return string(utf16.Decode(u))
+}
+
+// ref reads a RfX cell range and returns the reference of the range.
+func (r *xlsbReader) ref() string {
+	rwFirst, rwLast, colFirst, colLast := r.uint32(), r.uint32(), r.uint32(), r.uint32()
+	ref, _ := CoordinatesToCellName(int(colFirst)+1, int(rwFirst)+1)
+	if rwFirst != rwLast || colFirst != colLast {
+		last, _ := CoordinatesToCellName(int(colLast)+1, int(rwLast)+1)
+		ref += ":" + last
+	}
+	return ref
+}
+
+// color reads a BrtColor structure, the automatic color will be read as nil.
+func (r *xlsbReader) color() *xlsxColor {
+	b := r.bytes(8)
+	if b == nil {
+		return nil
+	}
+	color := &xlsxColor{}
+	switch b[0] >> 1 {
+	case xlsbColorIndexed:
+		color.Indexed = int(b[1])
+	case xlsbColorRGB:
+		color.RGB = fmt.Sprintf("%02X%02X%02X%02X", b[7], b[4], b[5], b[6])
+	case xlsbColorTheme:
+		color.Theme = intPtr(int(b[1]))
+	default:
+		return nil
+	}
+	if tint := int16(binary.LittleEndian.Uint16(b[2:4])); tint != 0 {
+		color.Tint = float64(tint) / math.MaxInt16
+	}
+	return color
+}
+
+// record writes the record with the given type and the pending record data.
+func (w *xlsbWriter) record(typ int) {
+	w.buf.Write(appendXLSBVarint(nil, typ))
+	w.buf.Write(appendXLSBVarint(nil, len(w.data)))
+	w.buf.Write(w.data)
+	w.data = w.data[:0]
+}
+
+// appendXLSBVarint appends the variable length integer to the given bytes.
+func appendXLSBVarint(b []byte, v int) []byte {
+	for ; v > 0x7F; v >>= 7 {
+		b = append(b, byte(v&0x7F)|0x80)
+	}
+	return append(b, byte(v))
+}
+
+// bytes appends the bytes to the pending record data.
+func (w *xlsbWriter) bytes(b []byte) *xlsbWriter {
+	w.data = append(w.data, b...)
+	return w
+}
+
+// uint8 appends an unsigned 8-bit integer to the pending record data.
+func (w *xlsbWriter) uint8(v int) *xlsbWriter {
+	w.data = append(w.data, byte(v))
+	return w
+}
+
+// uint16 appends an unsigned 16-bit integer to the pending record data.
+func (w *xlsbWriter) uint16(v int) *xlsbWriter {
+	w.data = append(w.data, byte(v), byte(v>>8))
+	return w
+}
+
+// uint32 appends an unsigned 32-bit integer to the pending record data, the
+// negative value will be written in two's complement.
+func (w *xlsbWriter) uint32(v int) *xlsbWriter {
+	w.data = append(w.data, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+	return w
+}
+
+// float64 appends an IEEE 754 double precision floating point number to the
+// pending record data.
+func (w *xlsbWriter) float64(v float64) *xlsbWriter {
+	bits := math.Float64bits(v)
+	for i := 0; i < 8; i++ {
+		w.data = append(w.data, byte(bits>>(8*i)))
+	}
+	return w
+}
+
+// string appends a XLWideString to the pending record data.
+func (w *xlsbWriter) string(s string) *xlsbWriter {
+	u := utf16.Encode([]rune(s))
+	w.uint32(len(u))
+	for _, c := range u {
+		w.uint16(int(c))
+	}
+	return w
+}
+
+// nullableString appends a XLNullableWideString to the pending record data,
+// the empty string will be written as null string.
+func (w *xlsbWriter) nullableString(s string) *xlsbWriter {
+	if s == "" {
+		return w.uint32(-1)
+	}
+	return w.string(s)
+}
+
+// ref appends a RfX cell range by given reference to the pending record data.
+func (w *xlsbWriter) ref(ref string) *xlsbWriter {
+	cells := strings.Split(strings.Replace(ref, "$", "", -1), ":")
+	coordinates, err := areaRangeToCoordinates(cells[0], cells[len(cells)-1])
+	if err != nil {
+		coordinates = []int{1, 1, 1, 1}
+	}
+	_ = sortCoordinates(coordinates)
+	return w.uint32(coordinates[1] - 1).uint32(coordinates[3] - 1).
+		uint32(coordinates[0] - 1).uint32(coordinates[2] - 1)
+}
+
+// color appends a BrtColor structure to the pending record data, the nil
+// color will be written as automatic color.
+func (w *xlsbWriter) color(color *xlsxColor) *xlsbWriter {
+	b := make([]byte, 8)
+	if color != nil && !color.Auto {
+		rgb := strings.TrimPrefix(color.RGB, "#")
+		if len(rgb) == 6 {
+			rgb = "FF" + rgb
+		}
+		argb, err := strconv.ParseUint(rgb, 16, 32)
+		switch {
+		case color.Theme != nil:
+			b[0], b[1] = xlsbColorTheme<<1, byte(*color.Theme)
+		case len(rgb) == 8 && err == nil:
+			b[0] = xlsbColorRGB<<1 | 1
+			b[4], b[5], b[6], b[7] = byte(argb>>16), byte(argb>>8), byte(argb), byte(argb>>24)
+		default:
+			b[0], b[1] = xlsbColorIndexed<<1, byte(color.Indexed)
+		}
+		binary.LittleEndian.PutUint16(b[2:4], uint16(int16(math.Round(color.Tint*math.MaxInt16))))
+	}
+	return w.bytes(b)
+}
+
+// xlsbPartName returns the name of the converted part by given part name.
+func xlsbPartName(name string, binary bool) string {
+	if binary {
+		return strings.TrimSuffix(name, ".xml") + ".bin"
+	}
+	return strings.TrimSuffix(name, ".bin") + ".xml"
+}
+
+// partContentType returns the content type of the part by given content
+// types and part name.
+func partContentType(ct *xlsxTypes, name string) string {
+	for _, override := range ct.Overrides {
+		if strings.TrimPrefix(override.PartName, "/") == name {
+			return override.ContentType
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(name), ".")
+	for _, def := range ct.Defaults {
+		if strings.EqualFold(def.Extension, ext) {
+			return def.ContentType
+		}
+	}
+	return ""
+}
+
+// relsTargetPath returns the part name of the relationship target by given
+// relationships part name and relationship target.
+func relsTargetPath(relsPath, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(path.Clean(target), "/")
+	}
+	return path.Join(path.Dir(path.Dir(relsPath)), target)
+}
+
+// convertPackage converts the workbook, worksheet, shared strings and styles
+// parts of the package between the XML parts and the binary (BIFF12) parts,
+// and updates the content types and the relationships for the renamed parts.
+// The calculation chain and other binary parts which could not be converted
+// are removed from the package.
+func convertPackage(pkg map[string][]byte, binary bool) error {
+	var (
+		ct        xlsxTypes
+		names     []string
+		converted = map[string][]byte{}
+		renamed   = map[string]string{}
+		dropped   = map[string]bool{}
+		partTypes = map[string]string{}
+	)
+	if err := xml.Unmarshal(namespaceStrictToTransitional(pkg["[Content_Types].xml"]), &ct); err != nil {
+		return err
+	}
+	for name := range pkg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	xmlTypes, hasBinaryWorkbook := map[string]string{}, false
+	for _, name := range names {
+		switch partContentType(&ct, name) {
+		case ContentTypeBinaryWorkbook:
+			hasBinaryWorkbook = true
+		case ContentTypeVBA:
+			xmlTypes[ContentTypeBinaryWorkbook] = ContentTypeMacro
+		}
+	}
+	if !binary && !hasBinaryWorkbook {
+		return nil
+	}
+	for xmlType, binaryType := range xlsbPartTypes {
+		if _, ok := xmlTypes[binaryType]; !ok && xmlType != ContentTypeMacro {
+			xmlTypes[binaryType] = xmlType
+		}
+	}
+	fromTypes, ext := xlsbPartTypes, ".xml"
+	if !binary {
+		fromTypes, ext = xmlTypes, ".bin"
+	}
+	for _, name := range names {
+		contentType := partContentType(&ct, name)
+		toType, ok := fromTypes[contentType]
+		if !ok || path.Ext(name) != ext {
+			if contentType == ContentTypeSpreadSheetMLCalcChain ||
+				(!binary && path.Ext(name) == ".bin" && strings.HasPrefix(contentType, "application/vnd.ms-excel.")) {
+				dropped[name] = true
+			}
+			continue
+		}
+		content, err := convertPart(contentType, pkg[name], binary)
+		if err != nil {
+			return err
+		}
+		renamed[name] = xlsbPartName(name, binary)
+		converted[renamed[name]], partTypes[renamed[name]] = content, toType
+	}
+	var overrides []xlsxOverride
+	for _, override := range ct.Overrides {
+		name := strings.TrimPrefix(override.PartName, "/")
+		if _, ok := renamed[name]; !ok && !dropped[name] {
+			overrides = append(overrides, override)
+		}
+	}
+	for _, name := range names {
+		if to, ok := renamed[name]; ok {
+			overrides = append(overrides, xlsxOverride{PartName: "/" + to, ContentType: partTypes[to]})
+		}
+	}
+	ct.Overrides = overrides
+	for i, def := range ct.Defaults {
+		if !binary && strings.EqualFold(def.Extension, "bin") && strings.HasPrefix(def.ContentType, "application/vnd.ms-excel.") {
+			ct.Defaults[i].ContentType = ContentTypePrinterSettings
+		}
+	}
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".rels") {
+			continue
+		}
+		var rels xlsxRelationships
+		if err := xml.Unmarshal(namespaceStrictToTransitional(pkg[name]), &rels); err != nil {
+			return err
+		}
+		var relationships []xlsxRelationship
+		for _, rel := range rels.Relationships {
+			if rel.TargetMode != "External" {
+				target := relsTargetPath(name, rel.Target)
+				if dropped[target] {
+					continue
+				}
+				if _, ok := renamed[target]; ok {
+					rel.Target = xlsbPartName(rel.Target, binary)
+				}
+			}
+			relationships = append(relationships, rel)
+		}
+		rels.Relationships = relationships
+		output, err := xml.Marshal(&rels)
+		if err != nil {
+			return err
+		}
+		relsPath := name
+		if source, ok := renamed[path.Join(path.Dir(path.Dir(name)), strings.TrimSuffix(path.Base(name), ".rels"))]; ok {
+			relsPath = path.Join(path.Dir(name), path.Base(source)+".rels")
+			delete(pkg, name)
+		}
+		pkg[relsPath] = append([]byte(XMLHeader), output...)
+	}
+	for name := range dropped {
+		delete(pkg, name)
+	}
+	for name, to := range renamed {
+		delete(pkg, name)
+		pkg[to] = converted[to]
+	}
+	output, err := xml.Marshal(&ct)
+	if err != nil {
+		return err
+	}
+	pkg["[Content_Types].xml"] = append([]byte(XMLHeader), output...)
+	return nil
+}
+
+// convertPart converts a part with given content type between the XML and the
+// binary format.
+func convertPart(contentType string, content []byte, binary bool) ([]byte, error) {
+	var v interface{}
+	switch contentType {
+	case ContentTypeSheetML, ContentTypeMacro, ContentTypeBinaryWorkbook:
+		v = new(xlsxWorkbook)
+	case ContentTypeSpreadSheetMLWorksheet, ContentTypeBinaryWorksheet:
+		v = new(xlsxWorksheet)
+	case ContentTypeSpreadSheetMLSharedStrings, ContentTypeBinarySharedStrings:
+		v = new(xlsxSST)
+	default:
+		v = new(xlsxStyleSheet)
+	}
+	if binary {
+		if err := xml.Unmarshal(namespaceStrictToTransitional(content), v); err != nil {
+			return nil, err
+		}
+		w := new(xlsbWriter)
+		switch v := v.(type) {
+		case *xlsxWorkbook:
+			w.workbook(v)
+		case *xlsxWorksheet:
+			if err := w.worksheet(v); err != nil {
+				return nil, err
+			}
+		case *xlsxSST:
+			w.sharedStrings(v)
+		case *xlsxStyleSheet:
+			w.styleSheet(v)
+		}
+		return w.buf.Bytes(), nil
+	}
+	records, err := readXLSBRecords(content)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case *xlsxWorkbook:
+		readXLSBWorkbook(records, v)
+	case *xlsxWorksheet:
+		readXLSBWorksheet(records, v)
+	case *xlsxSST:
+		readXLSBSharedStrings(records, v)
+	case *xlsxStyleSheet:
+		readXLSBStyleSheet(records, v)
+	}
+	output, err := xml.Marshal(v)
+	return append([]byte(XMLHeader), output...), err
+}
+
+// readBinaryPackage converts the binary workbook parts of the package into
+// the XML parts, the worksheets which have been extracted to the system
+// temporary directory will be loaded for conversion.
+func (f *File) readBinaryPackage(pkg map[string][]byte) error {
+	var err error
+	f.tempFiles.Range(func(name, tempFile interface{}) bool {
+		if path.Ext(name.(string)) != ".bin" {
+			return true
+		}
+		if pkg[name.(string)], err = ioutil.ReadFile(tempFile.(string)); err != nil {
+			return false
+		}
+		f.tempFiles.Delete(name)
+		err = os.Remove(tempFile.(string))
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return convertPackage(pkg, false)
+}
+
+// writeBinaryToZip provides a function to write the package with the binary
+// workbook parts to zip.Writer.
+func (f *File) writeBinaryToZip(zw *zip.Writer) error {
+	pkg := make(map[string][]byte)
+	f.tempFiles.Range(func(name, _ interface{}) bool {
+		pkg[name.(string)] = f.readBytes(name.(string))
+		return true
+	})
+	f.Pkg.Range(func(name, content interface{}) bool {
+		pkg[name.(string)] = content.([]byte)
+		return true
+	})
+	for name, stream := range f.streams {
+		from, err := stream.rawData.Reader()
+		if err != nil {
+			_ = stream.rawData.Close()
+			return err
+		}
+		if pkg[name], err = ioutil.ReadAll(from); err != nil {
+			return err
+		}
+		_ = stream.rawData.Close()
+	}
+	if err := convertPackage(pkg, true); err != nil {
+		return err
+	}
+	var names []string
+	for name := range pkg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fi, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err = fi.Write(pkg[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readXLSBWorkbook maps the records of the workbook.bin to the workbook.
+func readXLSBWorkbook(records []xlsbRecord, wb *xlsxWorkbook) {
+	for _, rec := range records {
+		r := &xlsbReader{data: rec.data}
+		switch rec.typ {
+		case xlsbRecordFileVersion:
+			r.bytes(16)
+			wb.FileVersion = &xlsxFileVersion{AppName: r.string(), LastEdited: r.string(), LowestEdited: r.string(), RupBuild: r.string()}
+		case xlsbRecordWbProp:
+			flags := r.uint32()
+			wb.WorkbookPr = &xlsxWorkbookPr{Date1904: flags&1 != 0, FilterPrivacy: flags&8 != 0}
+			if theme := r.uint32(); theme != 0 {
+				wb.WorkbookPr.DefaultThemeVersion = strconv.Itoa(int(theme))
+			}
+			wb.WorkbookPr.CodeName = r.string()
+		case xlsbRecordBookView:
+			if wb.BookViews == nil {
+				wb.BookViews = &xlsxBookViews{}
+			}
+			view := xlsxWorkBookView{
+				XWindow:      strconv.Itoa(int(int32(r.uint32()))),
+				YWindow:      strconv.Itoa(int(int32(r.uint32()))),
+				WindowWidth:  int(r.uint32()),
+				WindowHeight: int(r.uint32()),
+				TabRatio:     int(r.uint32()),
+				FirstSheet:   int(r.uint32()),
+				ActiveTab:    int(r.uint32()),
+			}
+			flags := r.uint8()
+			view.Minimized = flags&4 != 0
+			if flags&1 != 0 {
+				view.Visibility = "hidden"
+			}
+			if flags&2 != 0 {
+				view.Visibility = "veryHidden"
+			}
+			wb.BookViews.WorkBookView = append(wb.BookViews.WorkBookView, view)
+		case xlsbRecordBundleSh:
+			state := r.uint32()
+			sheet := xlsxSheet{SheetID: int(r.uint32()), ID: r.string(), Name: r.string()}
+			if state == 1 || state == 2 {
+				sheet.State = []string{"", "hidden", "veryHidden"}[state]
+			}
+			wb.Sheets.Sheet = append(wb.Sheets.Sheet, sheet)
+		}
+	}
+}
+
+// workbook writes the workbook.bin records by given workbook.
+func (w *xlsbWriter) workbook(wb *xlsxWorkbook) {
+	w.record(xlsbRecordBeginBook)
+	fileVersion := &xlsxFileVersion{AppName: "xl", LastEdited: "7", LowestEdited: "7", RupBuild: "24326"}
+	if wb.FileVersion != nil && wb.FileVersion.AppName != "" {
+		fileVersion = wb.FileVersion
+	}
+	w.bytes(make([]byte, 16)).string(fileVersion.AppName).string(fileVersion.LastEdited).
+		string(fileVersion.LowestEdited).string(fileVersion.RupBuild).record(xlsbRecordFileVersion)
+	pr, flags := wb.WorkbookPr, 0
+	if pr == nil {
+		pr = &xlsxWorkbookPr{}
+	}
+	if pr.Date1904 {
+		flags |= 1
+	}
+	if pr.FilterPrivacy {
+		flags |= 8
+	}
+	theme, _ := strconv.Atoi(pr.DefaultThemeVersion)
+	w.uint32(flags).uint32(theme).string(pr.CodeName).record(xlsbRecordWbProp)
+	if wb.BookViews != nil && len(wb.BookViews.WorkBookView) > 0 {
+		w.record(xlsbRecordBeginBookViews)
+		for _, view := range wb.BookViews.WorkBookView {
+			x, _ := strconv.Atoi(view.XWindow)
+			y, _ := strconv.Atoi(view.YWindow)
+			flags, tabRatio := xlsbBookViewFlags, view.TabRatio
+			if tabRatio == 0 {
+				tabRatio = 600
+			}
+			if view.Visibility == "hidden" {
+				flags |= 1
+			}
+			if view.Visibility == "veryHidden" {
+				flags |= 2
+			}
+			if view.Minimized {
+				flags |= 4
+			}
+			w.uint32(x).uint32(y).uint32(view.WindowWidth).uint32(view.WindowHeight).uint32(tabRatio).
+				uint32(view.FirstSheet).uint32(view.ActiveTab).uint8(flags).record(xlsbRecordBookView)
+		}
+		w.record(xlsbRecordEndBookViews)
+	}
+	w.record(xlsbRecordBeginBundleShs)
+	for _, sheet := range wb.Sheets.Sheet {
+		state := map[string]int{"hidden": 1, "veryHidden": 2}[sheet.State]
+		w.uint32(state).uint32(sheet.SheetID).nullableString(sheet.ID).string(sheet.Name).record(xlsbRecordBundleSh)
+	}
+	w.record(xlsbRecordEndBundleShs)
+	w.record(xlsbRecordEndBook)
+}
+
+// readXLSBWorksheet maps the records of the sheetN.bin to the worksheet.
+func readXLSBWorksheet(records []xlsbRecord, ws *xlsxWorksheet) {
+	for _, rec := range records {
+		r := &xlsbReader{data: rec.data}
+		switch rec.typ {
+		case xlsbRecordWsProp:
+			r.bytes(3)
+			tabColor := r.color()
+			r.bytes(8)
+			if codeName := r.string(); tabColor != nil || codeName != "" {
+				ws.SheetPr = &xlsxSheetPr{CodeName: codeName}
+				if tabColor != nil {
+					ws.SheetPr.TabColor = &xlsxTabColor{RGB: tabColor.RGB, Indexed: tabColor.Indexed, Tint: tabColor.Tint}
+					if tabColor.Theme != nil {
+						ws.SheetPr.TabColor.Theme = *tabColor.Theme
+					}
+				}
+			}
+		case xlsbRecordWsDim:
+			ws.Dimension = &xlsxDimension{Ref: r.ref()}
+		case xlsbRecordBeginWsView:
+			readXLSBSheetView(r, ws)
+		case xlsbRecordWsFmtInfo:
+			colWidth, baseColWidth, rowHeight, flags := r.uint32(), r.uint16(), r.uint16(), r.uint16()
+			ws.SheetFormatPr = &xlsxSheetFormatPr{
+				BaseColWidth:     uint8(baseColWidth),
+				DefaultRowHeight: float64(rowHeight) / 20,
+				CustomHeight:     flags&1 != 0,
+				ZeroHeight:       flags&2 != 0,
+				ThickTop:         flags&4 != 0,
+				ThickBottom:      flags&8 != 0,
+				OutlineLevelRow:  uint8(r.uint8()),
+				OutlineLevelCol:  uint8(r.uint8()),
+			}
+			if colWidth != math.MaxUint32 {
+				ws.SheetFormatPr.DefaultColWidth = float64(colWidth) / 256
+			}
+		case xlsbRecordColInfo:
+			if ws.Cols == nil {
+				ws.Cols = &xlsxCols{}
+			}
+			col := xlsxCol{Min: int(r.uint32()) + 1, Max: int(r.uint32()) + 1, Width: float64(r.uint32()) / 256, Style: int(r.uint32())}
+			flags := r.uint16()
+			col.Hidden, col.CustomWidth, col.BestFit, col.Phonetic = flags&1 != 0, flags&2 != 0, flags&4 != 0, flags&8 != 0
+			col.OutlineLevel, col.Collapsed = uint8(flags>>8&7), flags&0x1000 != 0
+			ws.Cols.Col = append(ws.Cols.Col, col)
+		case xlsbRecordRowHdr:
+			row := xlsxRow{R: int(r.uint32()) + 1}
+			style, height := int(r.uint32()), r.uint16()
+			r.uint8()
+			flags := r.uint8()
+			row.OutlineLevel, row.Collapsed, row.Hidden = uint8(flags&7), flags&8 != 0, flags&0x10 != 0
+			row.CustomHeight, row.CustomFormat = flags&0x20 != 0, flags&0x40 != 0
+			if row.CustomHeight {
+				row.Ht = float64(height) / 20
+			}
+			if row.CustomFormat {
+				row.S = style
+			}
+			ws.SheetData.Row = append(ws.SheetData.Row, row)
+		case xlsbRecordCellBlank, xlsbRecordCellRk, xlsbRecordCellError, xlsbRecordCellBool,
+			xlsbRecordCellReal, xlsbRecordCellSt, xlsbRecordCellIsst, xlsbRecordFmlaString,
+			xlsbRecordFmlaNum, xlsbRecordFmlaBool, xlsbRecordFmlaError, xlsbRecordCellRString:
+			if len(ws.SheetData.Row) > 0 {
+				row := &ws.SheetData.Row[len(ws.SheetData.Row)-1]
+				row.C = append(row.C, readXLSBCell(r, rec.typ, row.R))
+			}
+		case xlsbRecordMergeCell:
+			if ws.MergeCells == nil {
+				ws.MergeCells = &xlsxMergeCells{}
+			}
+			ws.MergeCells.Cells = append(ws.MergeCells.Cells, &xlsxMergeCell{Ref: r.ref()})
+			ws.MergeCells.Count = len(ws.MergeCells.Cells)
+		case xlsbRecordHLink:
+			if ws.Hyperlinks == nil {
+				ws.Hyperlinks = &xlsxHyperlinks{}
+			}
+			ws.Hyperlinks.Hyperlink = append(ws.Hyperlinks.Hyperlink, xlsxHyperlink{
+				Ref: r.ref(), RID: r.string(), Location: r.string(), Tooltip: r.string(), Display: r.string(),
+			})
+		case xlsbRecordDrawing:
+			ws.Drawing = &xlsxDrawing{RID: r.string()}
+		}
+	}
+}
+
+// readXLSBSheetView maps the BrtBeginWsView record to the sheet view.
+func readXLSBSheetView(r *xlsbReader, ws *xlsxWorksheet) {
+	flags, view := r.uint16(), r.uint32()
+	rwTop, colLeft := r.uint32(), r.uint32()
+	r.bytes(4)
+	sheetView := xlsxSheetView{
+		WindowProtection: flags&1 != 0,
+		ShowFormulas:     flags&2 != 0,
+		RightToLeft:      flags&0x20 != 0,
+		TabSelected:      flags&0x40 != 0,
+		ZoomScale:        float64(r.uint16()),
+		ZoomScaleNormal:  float64(r.uint16()),
+	}
+	sheetView.ZoomScaleSheetLayoutView, sheetView.ZoomScalePageLayoutView = float64(r.uint16()), float64(r.uint16())
+	sheetView.WorkbookViewID = int(r.uint32())
+	if sheetView.ZoomScale == 100 {
+		sheetView.ZoomScale = 0
+	}
+	for bit, show := range map[int]**bool{4: &sheetView.ShowGridLines, 8: &sheetView.ShowRowColHeaders, 0x10: &sheetView.ShowZeros} {
+		if flags&bit == 0 {
+			*show = boolPtr(false)
+		}
+	}
+	if view == 1 || view == 2 {
+		sheetView.View = []string{"", "pageBreakPreview", "pageLayout"}[view]
+	}
+	if rwTop != 0 || colLeft != 0 {
+		sheetView.TopLeftCell, _ = CoordinatesToCellName(int(colLeft)+1, int(rwTop)+1)
+	}
+	if ws.SheetViews == nil {
+		ws.SheetViews = &xlsxSheetViews{}
+	}
+	ws.SheetViews.SheetView = append(ws.SheetViews.SheetView, sheetView)
+}
+
+// readXLSBCell reads the cell record with given record type and row number,
+// the formulas which contain the unsupported Ptg records will be read as the
+// cached values.
+func readXLSBCell(r *xlsbReader, typ, row int) xlsxC {
+	var c xlsxC
+	c.R, _ = CoordinatesToCellName(int(r.uint32())+1, row)
+	c.S = int(r.uint32() & 0xFFFFFF)
+	switch typ {
+	case xlsbRecordCellRk:
+		c.V = formatXLSNumber(decodeXLSRK(r.uint32()))
+	case xlsbRecordCellReal, xlsbRecordFmlaNum:
+		c.V = formatXLSNumber(r.float64())
+	case xlsbRecordCellError, xlsbRecordFmlaError:
+		c.T, c.V = "e", xlsErrorCodes[byte(r.uint8())]
+	case xlsbRecordCellBool, xlsbRecordFmlaBool:
+		c.T, c.V = "b", strconv.Itoa(r.uint8())
+	case xlsbRecordCellIsst:
+		c.T, c.V = "s", strconv.Itoa(int(r.uint32()))
+	case xlsbRecordCellRString:
+		r.uint8()
+		c.T, c.V, c.XMLSpace = setCellStr(r.string())
+	case xlsbRecordCellSt, xlsbRecordFmlaString:
+		c.T, c.V, c.XMLSpace = setCellStr(r.string())
+	}
+	if typ >= xlsbRecordFmlaString && typ <= xlsbRecordFmlaError {
+		r.uint16()
+		if formula, ok := decodeXLSBFormula(r.bytes(int(r.uint32()))); ok {
+			c.F = &xlsxF{Content: formula}
+		}
+	}
+	return c
+}
+
+// worksheet writes the sheetN.bin records by given worksheet.
+func (w *xlsbWriter) worksheet(ws *xlsxWorksheet) error {
+	w.record(xlsbRecordBeginSheet)
+	var tabColor *xlsxColor
+	var codeName string
+	if ws.SheetPr != nil {
+		codeName = ws.SheetPr.CodeName
+		if tc := ws.SheetPr.TabColor; tc != nil {
+			tabColor = &xlsxColor{Auto: tc.Auto, RGB: tc.RGB, Indexed: tc.Indexed, Tint: tc.Tint}
+			if tc.RGB == "" && tc.Indexed == 0 {
+				tabColor.Theme = intPtr(tc.Theme)
+			}
+		}
+	}
+	w.uint16(xlsbSheetPropFlags).uint8(0).color(tabColor).uint32(-1).uint32(-1).string(codeName).record(xlsbRecordWsProp)
+	dimension := "A1"
+	if ws.Dimension != nil && ws.Dimension.Ref != "" {
+		dimension = ws.Dimension.Ref
+	}
+	w.ref(dimension).record(xlsbRecordWsDim)
+	w.sheetViews(ws)
+	w.sheetFormat(ws.SheetFormatPr)
+	if ws.Cols != nil && len(ws.Cols.Col) > 0 {
+		w.record(xlsbRecordBeginColInfos)
+		for _, col := range ws.Cols.Col {
+			flags := int(col.OutlineLevel&7) << 8
+			for bit, set := range map[int]bool{1: col.Hidden, 2: col.CustomWidth, 4: col.BestFit, 8: col.Phonetic, 0x1000: col.Collapsed} {
+				if set {
+					flags |= bit
+				}
+			}
+			w.uint32(col.Min - 1).uint32(col.Max - 1).uint32(int(math.Round(col.Width * 256))).uint32(col.Style).
+				uint16(flags).record(xlsbRecordColInfo)
+		}
+		w.record(xlsbRecordEndColInfos)
+	}
+	w.record(xlsbRecordBeginSheetData)
+	for _, row := range ws.SheetData.Row {
+		if err := w.row(ws, row); err != nil {
+			return err
+		}
+	}
+	w.record(xlsbRecordEndSheetData)
+	if ws.MergeCells != nil && len(ws.MergeCells.Cells) > 0 {
+		w.uint32(len(ws.MergeCells.Cells)).record(xlsbRecordBeginMergeCells)
+		for _, mergeCell := range ws.MergeCells.Cells {
+			w.ref(mergeCell.Ref).record(xlsbRecordMergeCell)
+		}
+		w.record(xlsbRecordEndMergeCells)
+	}
+	if ws.Hyperlinks != nil {
+		for _, link := range ws.Hyperlinks.Hyperlink {
+			w.ref(link.Ref).nullableString(link.RID).string(link.Location).string(link.Tooltip).
+				string(link.Display).record(xlsbRecordHLink)
+		}
+	}
+	if ws.Drawing != nil {
+		w.string(ws.Drawing.RID).record(xlsbRecordDrawing)
+	}
+	w.record(xlsbRecordEndSheet)
+	return nil
+}
+
+// sheetViews writes the BrtBeginWsViews records by given worksheet.
+func (w *xlsbWriter) sheetViews(ws *xlsxWorksheet) {
+	views := []xlsxSheetView{{}}
+	if ws.SheetViews != nil && len(ws.SheetViews.SheetView) > 0 {
+		views = ws.SheetViews.SheetView
+	}
+	w.record(xlsbRecordBeginWsViews)
+	for _, view := range views {
+		flags := xlsbSheetViewFlags
+		for bit, set := range map[int]bool{
+			1: view.WindowProtection, 2: view.ShowFormulas, 0x20: view.RightToLeft, 0x40: view.TabSelected,
+			4: view.ShowGridLines == nil || *view.ShowGridLines, 8: view.ShowRowColHeaders == nil || *view.ShowRowColHeaders,
+			0x10: view.ShowZeros == nil || *view.ShowZeros,
+		} {
+			if set {
+				flags |= bit
+			}
+		}
+		var rwTop, colLeft int
+		if col, row, err := CellNameToCoordinates(view.TopLeftCell); err == nil {
+			rwTop, colLeft = row-1, col-1
+		}
+		zoomScale := int(view.ZoomScale)
+		if zoomScale == 0 {
+			zoomScale = 100
+		}
+		w.uint16(flags).uint32(map[string]int{"pageBreakPreview": 1, "pageLayout": 2}[view.View]).
+			uint32(rwTop).uint32(colLeft).uint8(64).uint8(0).uint16(0).uint16(zoomScale).
+			uint16(int(view.ZoomScaleNormal)).uint16(int(view.ZoomScaleSheetLayoutView)).
+			uint16(int(view.ZoomScalePageLayoutView)).uint32(view.WorkbookViewID).record(xlsbRecordBeginWsView)
+		w.record(xlsbRecordEndWsView)
+	}
+	w.record(xlsbRecordEndWsViews)
+}
+
+// sheetFormat writes the BrtWsFmtInfo record by given sheet format
+// properties.
+func (w *xlsbWriter) sheetFormat(pr *xlsxSheetFormatPr) {
+	colWidth, baseColWidth, rowHeight, flags := -1, xlsbDefaultBaseColWidth, xlsbDefaultRowHeightInTwips, 0
+	if pr == nil {
+		pr = &xlsxSheetFormatPr{}
+	}
+	if pr.DefaultColWidth != 0 {
+		colWidth = int(math.Round(pr.DefaultColWidth * 256))
+	}
+	if pr.BaseColWidth != 0 {
+		baseColWidth = int(pr.BaseColWidth)
+	}
+	if pr.DefaultRowHeight != 0 {
+		rowHeight = int(math.Round(pr.DefaultRowHeight * 20))
+	}
+	for bit, set := range map[int]bool{1: pr.CustomHeight, 2: pr.ZeroHeight, 4: pr.ThickTop, 8: pr.ThickBottom} {
+		if set {
+			flags |= bit
+		}
+	}
+	w.uint32(colWidth).uint16(baseColWidth).uint16(rowHeight).uint16(flags).
+		uint8(int(pr.OutlineLevelRow)).uint8(int(pr.OutlineLevelCol)).record(xlsbRecordWsFmtInfo)
+}
+
+// row writes the BrtRowHdr record and the cell records by given worksheet and
+// row, the column spans are grouped by each 1024 columns.
+func (w *xlsbWriter) row(ws *xlsxWorksheet, row xlsxRow) error {
+	cols, spans := make([]int, len(row.C)), [][2]int{}
+	for i, c := range row.C {
+		col, _, err := CellNameToCoordinates(c.R)
+		if err != nil {
+			col = 1
+			if i > 0 {
+				col = cols[i-1] + 2
+			}
+		}
+		if cols[i] = col - 1; len(spans) > 0 && spans[len(spans)-1][0]>>10 == cols[i]>>10 {
+			spans[len(spans)-1][1] = cols[i]
+			continue
+		}
+		spans = append(spans, [2]int{cols[i], cols[i]})
+	}
+	flags := int(row.OutlineLevel & 7)
+	for bit, set := range map[int]bool{8: row.Collapsed, 0x10: row.Hidden, 0x20: row.CustomHeight, 0x40: row.CustomFormat} {
+		if set {
+			flags |= bit
+		}
+	}
+	height := xlsbDefaultRowHeightInTwips
+	if row.Ht != 0 {
+		height = int(math.Round(row.Ht * 20))
+	}
+	w.uint32(row.R - 1).uint32(row.S).uint16(height).uint8(0).uint8(flags).uint8(0).uint32(len(spans))
+	for _, span := range spans {
+		w.uint32(span[0]).uint32(span[1])
+	}
+	w.record(xlsbRecordRowHdr)
+	for i, c := range row.C {
+		if err := w.cell(ws, cols[i], c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cell writes the cell record by given worksheet, column index and cell. The
+// formula which could not be encoded will be written as the cached value, and
+// an error will be returned if the formula has no cached value.
+func (w *xlsbWriter) cell(ws *xlsxWorksheet, col int, c xlsxC) error {
+	w.uint32(col).uint32(c.S)
+	if c.F != nil {
+		formula := c.F.Content
+		if c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+			formula = getSharedForumula(ws, *c.F.Si, c.R)
+		}
+		if rgce, ok := encodeXLSBFormula(formula); ok && c.F.T != STCellFormulaTypeDataTable {
+			w.formula(c, rgce)
+			return nil
+		}
+		if c.V == "" && c.T != "str" && c.IS == nil {
+			return newXLSBFormulaError(c.R, formula)
+		}
+	}
+	switch c.T {
+	case "s":
+		if isst, err := strconv.Atoi(c.V); err == nil {
+			w.uint32(isst).record(xlsbRecordCellIsst)
+			return nil
+		}
+	case "b":
+		value := 0
+		if c.V == "1" || c.V == "true" {
+			value = 1
+		}
+		w.uint8(value).record(xlsbRecordCellBool)
+		return nil
+	case "e":
+		if code, ok := xlsbErrorCode(c.V); ok {
+			w.uint8(code).record(xlsbRecordCellError)
+			return nil
+		}
+	case "inlineStr":
+		if c.IS != nil {
+			w.string(c.IS.String()).record(xlsbRecordCellSt)
+			return nil
+		}
+	case "", "n":
+		if c.V == "" {
+			w.record(xlsbRecordCellBlank)
+			return nil
+		}
+		if num, err := strconv.ParseFloat(c.V, 64); err == nil {
+			w.float64(num).record(xlsbRecordCellReal)
+			return nil
+		}
+	}
+	w.string(bstrUnmarshal(c.V)).record(xlsbRecordCellSt)
+	return nil
+}
+
+// formula writes the formula cell record by given cell and the parsed
+// expression of the formula, the formula without cached value will be
+// calculated when the workbook is opened.
+func (w *xlsbWriter) formula(c xlsxC, rgce []byte) {
+	typ, flags := xlsbRecordFmlaNum, 0
+	if c.V == "" {
+		flags = xlsbFormulaAlwaysCalc
+	}
+	code, isErr := xlsbErrorCode(c.V)
+	switch {
+	case c.T == "str":
+		typ = xlsbRecordFmlaString
+		w.string(bstrUnmarshal(c.V))
+	case c.T == "b":
+		typ = xlsbRecordFmlaBool
+		value := 0
+		if c.V == "1" || c.V == "true" {
+			value = 1
+		}
+		w.uint8(value)
+	case c.T == "e" && isErr:
+		typ = xlsbRecordFmlaError
+		w.uint8(code)
+	default:
+		num, _ := strconv.ParseFloat(c.V, 64)
+		w.float64(num)
+	}
+	w.uint16(flags).uint32(len(rgce)).bytes(rgce).uint32(0).record(typ)
+}
+
+// xlsbErrorCode returns the error code of the BrtCellError record by given
+// error value.
+func xlsbErrorCode(value string) (int, bool) {
+	for code, v := range xlsErrorCodes {
+		if v == value {
+			return int(code), true
+		}
+	}
+	return 0, false
+}
+
+// xlsbFormulaEncoder encodes the tokens of the formula into the Rgce
+// structure, the Ptg records are written in reverse Polish notation.
+type xlsbFormulaEncoder struct {
+	tokens []Token
+	pos    int
+	w      xlsbWriter
+}
+
+// encodeXLSBFormula encodes the formula into the Rgce structure, returns false
+// if the formula contains the functions, references or operators which are
+// not supported, such as the references to other worksheets and the defined
+// names.
+func encodeXLSBFormula(formula string) ([]byte, bool) {
+	ps := ExcelParser()
+	e := &xlsbFormulaEncoder{tokens: ps.Parse(strings.TrimPrefix(formula, "="))}
+	if len(e.tokens) == 0 || !e.expr(0) || e.pos != len(e.tokens) {
+		return nil, false
+	}
+	return e.w.data, true
+}
+
+// peek returns the current token, the empty token will be returned once all
+// tokens have been consumed.
+func (e *xlsbFormulaEncoder) peek() Token {
+	if e.pos < len(e.tokens) {
+		return e.tokens[e.pos]
+	}
+	return Token{}
+}
+
+// expr encodes the expression whose infix operators have the precedence not
+// lower than the given precedence.
+func (e *xlsbFormulaEncoder) expr(prec int) bool {
+	if !e.unary() {
+		return false
+	}
+	for {
+		token := e.peek()
+		op, ok := xlsbOperators[token.TValue]
+		if token.TType != TokenTypeOperatorInfix || !ok || op[0] < prec {
+			return true
+		}
+		e.pos++
+		if !e.expr(op[0] + 1) {
+			return false
+		}
+		e.w.uint8(op[1])
+	}
+}
+
+// unary encodes the operand with the prefix and postfix operators.
+func (e *xlsbFormulaEncoder) unary() bool {
+	if token := e.peek(); token.TType == TokenTypeOperatorPrefix {
+		e.pos++
+		if !e.unary() {
+			return false
+		}
+		ptg := xlsbPtgUminus
+		if token.TValue == "+" {
+			ptg = xlsbPtgUplus
+		}
+		e.w.uint8(ptg)
+		return true
+	}
+	if !e.operand() {
+		return false
+	}
+	for ; e.peek().TType == TokenTypeOperatorPostfix; e.pos++ {
+		e.w.uint8(xlsbPtgPercent)
+	}
+	return true
+}
+
+// operand encodes the operand, the parenthesized expression or the function
+// call.
+func (e *xlsbFormulaEncoder) operand() bool {
+	token := e.peek()
+	e.pos++
+	switch token.TType {
+	case TokenTypeOperand:
+		return e.value(token)
+	case TokenTypeSubexpression:
+		if token.TSubType != TokenSubTypeStart || !e.expr(0) {
+			return false
+		}
+		if token = e.peek(); token.TType != TokenTypeSubexpression || token.TSubType != TokenSubTypeStop {
+			return false
+		}
+		e.pos++
+		e.w.uint8(xlsbPtgParen)
+		return true
+	case TokenTypeFunction:
+		return token.TSubType == TokenSubTypeStart && e.function(strings.ToUpper(token.TValue))
+	}
+	return false
+}
+
+// value encodes the constant or the cell reference operand.
+func (e *xlsbFormulaEncoder) value(token Token) bool {
+	switch token.TSubType {
+	case TokenSubTypeNumber:
+		num, err := strconv.ParseFloat(token.TValue, 64)
+		if err != nil {
+			return false
+		}
+		if num >= 0 && num <= math.MaxUint16 && num == math.Trunc(num) {
+			e.w.uint8(xlsbPtgInt).uint16(int(num))
+			return true
+		}
+		e.w.uint8(xlsbPtgNum).float64(num)
+	case TokenSubTypeText:
+		u := utf16.Encode([]rune(token.TValue))
+		if len(u) > math.MaxUint8 {
+			return false
+		}
+		e.w.uint8(xlsbPtgStr).uint16(len(u))
+		for _, c := range u {
+			e.w.uint16(int(c))
+		}
+	case TokenSubTypeLogical:
+		value := 0
+		if strings.EqualFold(token.TValue, "TRUE") {
+			value = 1
+		}
+		e.w.uint8(xlsbPtgBool).uint8(value)
+	case TokenSubTypeError:
+		code, ok := xlsbErrorCode(strings.ToUpper(token.TValue))
+		if !ok {
+			return false
+		}
+		e.w.uint8(xlsbPtgErr).uint8(code)
+	case TokenSubTypeRange:
+		return e.ref(token.TValue)
+	default:
+		return false
+	}
+	return true
+}
+
+// ref encodes the cell reference or the range reference without worksheet
+// name, the whole columns and rows are written as the absolute ranges.
+func (e *xlsbFormulaEncoder) ref(ref string) bool {
+	parts, ok := parseRangeRef(ref)
+	if !ok {
+		return false
+	}
+	if len(parts) == 1 {
+		e.w.uint8(xlsbPtgRef).uint32(parts[0].row - 1).uint16(xlsbRefCol(parts[0]))
+		return true
+	}
+	first, last := parts[0], parts[1]
+	if first.row == 0 {
+		first.row, last.row, first.absRow, last.absRow = 1, TotalRows, true, true
+	}
+	if first.col == 0 {
+		first.col, last.col, first.absCol, last.absCol = 1, TotalColumns, true, true
+	}
+	e.w.uint8(xlsbPtgArea).uint32(first.row - 1).uint32(last.row - 1).
+		uint16(xlsbRefCol(first)).uint16(xlsbRefCol(last))
+	return true
+}
+
+// xlsbRefCol returns the ColRelShort structure by given part of the cell
+// reference.
+func xlsbRefCol(part cellRefPart) int {
+	col := part.col - 1
+	if !part.absCol {
+		col |= 0x4000
+	}
+	if !part.absRow {
+		col |= 0x8000
+	}
+	return col
+}
+
+// function encodes the arguments and the function call by given function
+// name, the missing arguments are written as PtgMissArg.
+func (e *xlsbFormulaEncoder) function(name string) bool {
+	fn, ok := xlsbFunctions[name]
+	if !ok {
+		return false
+	}
+	var args int
+	if token := e.peek(); token.TType == TokenTypeFunction && token.TSubType == TokenSubTypeStop {
+		e.pos++
+	} else {
+		for {
+			if token := e.peek(); token.TType == TokenTypeArgument || token.TType == TokenTypeFunction && token.TSubType == TokenSubTypeStop {
+				e.w.uint8(xlsbPtgMissArg)
+			} else if !e.expr(0) {
+				return false
+			}
+			args++
+			token := e.peek()
+			e.pos++
+			if token.TType == TokenTypeFunction && token.TSubType == TokenSubTypeStop {
+				break
+			}
+			if token.TType != TokenTypeArgument {
+				return false
+			}
+		}
+	}
+	if fn[1] < 0 {
+		e.w.uint8(xlsbPtgFuncVar + xlsbPtgClassValue).uint8(args).uint16(fn[0])
+		return true
+	}
+	if args != fn[1] {
+		return false
+	}
+	e.w.uint8(xlsbPtgFunc + xlsbPtgClassValue).uint16(fn[0])
+	return true
+}
+
+// decodeXLSBFormula decodes the formula from the Rgce structure, returns false
+// if the parsed expression contains the Ptg records which are not supported.
+func decodeXLSBFormula(rgce []byte) (string, bool) {
+	r, stack := &xlsbReader{data: rgce}, []string{}
+	pop := func(n int) []string {
+		args := append([]string{}, stack[len(stack)-n:]...)
+		stack = stack[:len(stack)-n]
+		return args
+	}
+	for r.pos < len(r.data) {
+		ptg := r.uint8()
+		if ptg >= 0x20 {
+			ptg = ptg&0x1F | 0x20
+		}
+		switch {
+		case ptg >= xlsbPtgAdd && ptg <= xlsbPtgNe:
+			if len(stack) < 2 {
+				return "", false
+			}
+			args := pop(2)
+			stack = append(stack, args[0]+xlsbOperatorName(ptg)+args[1])
+		case ptg >= xlsbPtgUplus && ptg <= xlsbPtgParen:
+			if len(stack) < 1 {
+				return "", false
+			}
+			top := &stack[len(stack)-1]
+			switch ptg {
+			case xlsbPtgUplus:
+				*top = "+" + *top
+			case xlsbPtgUminus:
+				*top = "-" + *top
+			case xlsbPtgPercent:
+				*top += "%"
+			default:
+				*top = "(" + *top + ")"
+			}
+		case ptg == xlsbPtgMissArg:
+			stack = append(stack, "")
+		case ptg == xlsbPtgStr:
+			u := make([]uint16, r.uint16())
+			for i := range u {
+				u[i] = uint16(r.uint16())
+			}
+			stack = append(stack, `"`+strings.Replace(string(utf16.Decode(u)), `"`, `""`, -1)+`"`)
+		case ptg == xlsbPtgAttr:
+			flags, data := r.uint8(), r.uint16()
+			if flags&xlsbAttrChoose != 0 {
+				r.bytes((data + 1) * 2)
+			}
+			if flags&xlsbAttrSum != 0 {
+				if len(stack) < 1 {
+					return "", false
+				}
+				stack[len(stack)-1] = "SUM(" + stack[len(stack)-1] + ")"
+			}
+		case ptg == xlsbPtgErr:
+			stack = append(stack, xlsErrorCodes[byte(r.uint8())])
+		case ptg == xlsbPtgBool:
+			stack = append(stack, strings.ToUpper(strconv.FormatBool(r.uint8() != 0)))
+		case ptg == xlsbPtgInt:
+			stack = append(stack, strconv.Itoa(r.uint16()))
+		case ptg == xlsbPtgNum:
+			stack = append(stack, formatXLSNumber(r.float64()))
+		case ptg == xlsbPtgFunc, ptg == xlsbPtgFuncVar:
+			args := -1
+			if ptg == xlsbPtgFuncVar {
+				args = r.uint8() & 0x7F
+			}
+			name := xlsbFunctionName(r.uint16() & 0x7FFF)
+			if args < 0 {
+				args = xlsbFunctions[name][1]
+			}
+			if name == "" || args < 0 || len(stack) < args {
+				return "", false
+			}
+			stack = append(stack, name+"("+strings.Join(pop(args), ",")+")")
+		case ptg == xlsbPtgRef:
+			row, col := r.uint32(), r.uint16()
+			stack = append(stack, xlsbRefPart(row, col).String())
+		case ptg == xlsbPtgArea:
+			row1, row2, col1, col2 := r.uint32(), r.uint32(), r.uint16(), r.uint16()
+			first, last := xlsbRefPart(row1, col1), xlsbRefPart(row2, col2)
+			if first.row == 1 && last.row == TotalRows && first.absRow && last.absRow {
+				first.row, last.row = 0, 0
+			}
+			if first.col == 1 && last.col == TotalColumns && first.absCol && last.absCol {
+				first.col, last.col = 0, 0
+			}
+			stack = append(stack, first.String()+":"+last.String())
+		default:
+			return "", false
+		}
+	}
+	if len(stack) != 1 {
+		return "", false
+	}
+	return stack[0], true
+}
+
+// xlsbOperatorName returns the infix operator by given Ptg type.
+func xlsbOperatorName(ptg int) string {
+	for op, v := range xlsbOperators {
+		if v[1] == ptg {
+			return op
+		}
+	}
+	return ""
+}
+
+// xlsbFunctionName returns the function name by given function index.
+func xlsbFunctionName(iftab int) string {
+	for name, fn := range xlsbFunctions {
+		if fn[0] == iftab {
+			return name
+		}
+	}
+	return ""
+}
+
+// xlsbRefPart returns the part of the cell reference by given row index and
+// ColRelShort structure.
+func xlsbRefPart(row uint32, col int) cellRefPart {
+	return cellRefPart{col: col&0x3FFF + 1, row: int(row) + 1, absCol: col&0x4000 == 0, absRow: col&0x8000 == 0}
+}
+
+// readXLSBSharedStrings maps the records of the sharedStrings.bin to the
+// shared string table, the rich text runs will be read as plain text.
+func readXLSBSharedStrings(records []xlsbRecord, sst *xlsxSST) {
+	for _, rec := range records {
+		r := &xlsbReader{data: rec.data}
+		switch rec.typ {
+		case xlsbRecordBeginSst:
+			sst.Count = int(r.uint32())
+		case xlsbRecordSSTItem:
+			r.uint8()
+			t := xlsxT{Val: r.string()}
+			_, _, t.Space = setCellStr(t.Val)
+			sst.SI = append(sst.SI, xlsxSI{T: &t})
+		}
+	}
+	sst.UniqueCount = len(sst.SI)
+}
+
+// sharedStrings writes the sharedStrings.bin records by given shared string
+// table.
+func (w *xlsbWriter) sharedStrings(sst *xlsxSST) {
+	w.uint32(sst.Count).uint32(len(sst.SI)).record(xlsbRecordBeginSst)
+	for _, si := range sst.SI {
+		w.uint8(0).string(si.String()).record(xlsbRecordSSTItem)
+	}
+	w.record(xlsbRecordEndSst)
+}
+
+// readXLSBStyleSheet maps the records of the styles.bin to the style sheet.
+func readXLSBStyleSheet(records []xlsbRecord, ss *xlsxStyleSheet) {
+	ss.Fonts, ss.Fills, ss.Borders = &xlsxFonts{}, &xlsxFills{}, &xlsxBorders{}
+	ss.CellStyleXfs, ss.CellXfs = &xlsxCellStyleXfs{}, &xlsxCellXfs{}
+	var cellXfs bool
+	for _, rec := range records {
+		r := &xlsbReader{data: rec.data}
+		switch rec.typ {
+		case xlsbRecordFmt:
+			if ss.NumFmts == nil {
+				ss.NumFmts = &xlsxNumFmts{}
+			}
+			ss.NumFmts.NumFmt = append(ss.NumFmts.NumFmt, &xlsxNumFmt{NumFmtID: r.uint16(), FormatCode: r.string()})
+			ss.NumFmts.Count = len(ss.NumFmts.NumFmt)
+		case xlsbRecordFont:
+			ss.Fonts.Font = append(ss.Fonts.Font, readXLSBFont(r))
+		case xlsbRecordFill:
+			ss.Fills.Fill = append(ss.Fills.Fill, readXLSBFill(r))
+		case xlsbRecordBorder:
+			flags, border := r.uint8(), &xlsxBorder{}
+			border.DiagonalDown, border.DiagonalUp = flags&1 != 0, flags&2 != 0
+			for _, line := range []*xlsxLine{&border.Top, &border.Bottom, &border.Left, &border.Right, &border.Diagonal} {
+				if style := r.uint8(); style > 0 && style < len(borderStyles) {
+					line.Style = borderStyles[style]
+				}
+				r.uint8()
+				line.Color = r.color()
+			}
+			ss.Borders.Border = append(ss.Borders.Border, border)
+		case xlsbRecordBeginCellXFs, xlsbRecordEndCellXFs:
+			cellXfs = rec.typ == xlsbRecordBeginCellXFs
+		case xlsbRecordXF:
+			if xf := readXLSBXf(r, cellXfs); cellXfs {
+				ss.CellXfs.Xf = append(ss.CellXfs.Xf, xf)
+			} else {
+				ss.CellStyleXfs.Xf = append(ss.CellStyleXfs.Xf, xf)
+			}
+		case xlsbRecordStyle:
+			if ss.CellStyles == nil {
+				ss.CellStyles = &xlsxCellStyles{}
+			}
+			cellStyle := &xlsxCellStyle{XfID: int(r.uint32())}
+			flags, builtIn, level := r.uint16(), r.uint8(), r.uint8()
+			if cellStyle.Name = r.string(); flags&1 != 0 {
+				cellStyle.BuiltInID = intPtr(builtIn)
+				if builtIn == 1 || builtIn == 2 {
+					cellStyle.ILevel = intPtr(level)
+				}
+			}
+			if flags&2 != 0 {
+				cellStyle.Hidden = boolPtr(true)
+			}
+			if flags&4 != 0 {
+				cellStyle.CustomBuiltIn = boolPtr(true)
+			}
+			ss.CellStyles.CellStyle = append(ss.CellStyles.CellStyle, cellStyle)
+			ss.CellStyles.Count = len(ss.CellStyles.CellStyle)
+		}
+	}
+	ss.Fonts.Count, ss.Fills.Count, ss.Borders.Count = len(ss.Fonts.Font), len(ss.Fills.Fill), len(ss.Borders.Border)
+	ss.CellStyleXfs.Count, ss.CellXfs.Count = len(ss.CellStyleXfs.Xf), len(ss.CellXfs.Xf)
+}
+
+// readXLSBFont reads the BrtFont record.
+func readXLSBFont(r *xlsbReader) *xlsxFont {
+	size, flags, weight := float64(r.uint16())/20, r.uint16(), r.uint16()
+	r.uint16()
+	underline, family, charset := r.uint8(), r.uint8(), r.uint8()
+	r.uint8()
+	font := &xlsxFont{Sz: &attrValFloat{Val: &size}, Color: r.color()}
+	scheme, name := r.uint8(), r.string()
+	font.Name = &attrValString{Val: &name}
+	for bit, val := range map[int]**attrValBool{2: &font.I, 8: &font.Strike, 0x10: &font.Outline, 0x20: &font.Shadow, 0x40: &font.Condense, 0x80: &font.Extend} {
+		if flags&bit != 0 {
+			*val = &attrValBool{Val: boolPtr(true)}
+		}
+	}
+	if weight >= 700 {
+		font.B = &attrValBool{Val: boolPtr(true)}
+	}
+	if u, ok := xlsbUnderlineTypes[underline]; ok {
+		font.U = &attrValString{Val: stringPtr(u)}
+	}
+	if family != 0 {
+		font.Family = &attrValInt{Val: intPtr(family)}
+	}
+	if charset != 0 {
+		font.Charset = &attrValInt{Val: intPtr(charset)}
+	}
+	if scheme == 1 || scheme == 2 {
+		font.Scheme = &attrValString{Val: stringPtr([]string{"", "major", "minor"}[scheme])}
+	}
+	return font
+}
+
+// readXLSBFill reads the BrtFill record.
+func readXLSBFill(r *xlsbReader) *xlsxFill {
+	pattern, fgColor, bgColor := int(r.uint32()), r.color(), r.color()
+	if pattern != xlsbFillGradient {
+		fill := &xlsxFill{PatternFill: &xlsxPatternFill{FgColor: fgColor, BgColor: bgColor}}
+		if pattern < len(fillPatterns) {
+			fill.PatternFill.PatternType = fillPatterns[pattern]
+		}
+		return fill
+	}
+	gradient := &xlsxGradientFill{}
+	if r.uint32() == 1 {
+		gradient.Type = "path"
+	}
+	gradient.Degree, gradient.Left, gradient.Right = r.float64(), r.float64(), r.float64()
+	gradient.Top, gradient.Bottom = r.float64(), r.float64()
+	for stops := r.uint32(); stops > 0; stops-- {
+		color := r.color()
+		if color == nil {
+			color = &xlsxColor{}
+		}
+		gradient.Stop = append(gradient.Stop, &xlsxGradientFillStop{Color: *color, Position: r.float64()})
+	}
+	return &xlsxFill{GradientFill: gradient}
+}
+
+// readXLSBXf reads the BrtXF record, the parent cell style format only be
+// used for the cell formats.
+func readXLSBXf(r *xlsbReader, cellXfs bool) xlsxXf {
+	parent := r.uint16()
+	xf := xlsxXf{NumFmtID: intPtr(r.uint16()), FontID: intPtr(r.uint16()), FillID: intPtr(r.uint16()), BorderID: intPtr(r.uint16())}
+	rotation, indent, flags, apply := r.uint8(), r.uint8(), r.uint16(), r.uint8()
+	if cellXfs {
+		xf.XfID = intPtr(parent)
+		for bit, val := range []**bool{&xf.ApplyNumberFormat, &xf.ApplyFont, &xf.ApplyAlignment, &xf.ApplyBorder, &xf.ApplyFill, &xf.ApplyProtection} {
+			if apply&(1<<bit) != 0 {
+				*val = boolPtr(true)
+			}
+		}
+	}
+	horizontal, vertical := flags&7, flags>>3&7
+	if horizontal != 0 || vertical != 2 || rotation != 0 || indent != 0 || flags&0x0DC0 != 0 {
+		xf.Alignment = &xlsxAlignment{
+			Indent:          indent,
+			JustifyLastLine: flags&0x80 != 0,
+			ReadingOrder:    uint64(flags >> 10 & 3),
+			ShrinkToFit:     flags&0x100 != 0,
+			TextRotation:    rotation,
+			WrapText:        flags&0x40 != 0,
+		}
+		xf.Alignment.Horizontal = xlsHorizontalAlignment[horizontal]
+		if vertical < len(xlsVerticalAlignment) {
+			xf.Alignment.Vertical = xlsVerticalAlignment[vertical]
+		}
+	}
+	if locked, hidden := flags&0x1000 != 0, flags&0x2000 != 0; !locked || hidden {
+		xf.Protection = &xlsxProtection{Locked: boolPtr(locked), Hidden: boolPtr(hidden)}
+	}
+	if flags&0x4000 != 0 {
+		xf.PivotButton = boolPtr(true)
+	}
+	if flags&0x8000 != 0 {
+		xf.QuotePrefix = boolPtr(true)
+	}
+	return xf
+}
+
+// styleSheet writes the styles.bin records by given style sheet.
+func (w *xlsbWriter) styleSheet(ss *xlsxStyleSheet) {
+	w.record(xlsbRecordBeginStyleSheet)
+	if ss.NumFmts != nil && len(ss.NumFmts.NumFmt) > 0 {
+		w.uint32(len(ss.NumFmts.NumFmt)).record(xlsbRecordBeginFmts)
+		for _, numFmt := range ss.NumFmts.NumFmt {
+			w.uint16(numFmt.NumFmtID).string(numFmt.FormatCode).record(xlsbRecordFmt)
+		}
+		w.record(xlsbRecordEndFmts)
+	}
+	var fonts []*xlsxFont
+	if ss.Fonts != nil {
+		fonts = ss.Fonts.Font
+	}
+	w.uint32(len(fonts)).record(xlsbRecordBeginFonts)
+	for _, font := range fonts {
+		w.font(font)
+	}
+	w.record(xlsbRecordEndFonts)
+	var fills []*xlsxFill
+	if ss.Fills != nil {
+		fills = ss.Fills.Fill
+	}
+	w.uint32(len(fills)).record(xlsbRecordBeginFills)
+	for _, fill := range fills {
+		w.fill(fill)
+	}
+	w.record(xlsbRecordEndFills)
+	var borders []*xlsxBorder
+	if ss.Borders != nil {
+		borders = ss.Borders.Border
+	}
+	w.uint32(len(borders)).record(xlsbRecordBeginBorders)
+	for _, border := range borders {
+		flags := 0
+		if border.DiagonalDown {
+			flags |= 1
+		}
+		if border.DiagonalUp {
+			flags |= 2
+		}
+		w.uint8(flags)
+		for _, line := range []xlsxLine{border.Top, border.Bottom, border.Left, border.Right, border.Diagonal} {
+			style := 0
+			for i, name := range borderStyles {
+				if name == line.Style {
+					style = i
+				}
+			}
+			w.uint8(style).uint8(0).color(line.Color)
+		}
+		w.record(xlsbRecordBorder)
+	}
+	w.record(xlsbRecordEndBorders)
+	var styleXfs, cellXfs []xlsxXf
+	if ss.CellStyleXfs != nil {
+		styleXfs = ss.CellStyleXfs.Xf
+	}
+	if ss.CellXfs != nil {
+		cellXfs = ss.CellXfs.Xf
+	}
+	w.uint32(len(styleXfs)).record(xlsbRecordBeginCellStyleXFs)
+	for _, xf := range styleXfs {
+		w.xf(xf, false)
+	}
+	w.record(xlsbRecordEndCellStyleXFs)
+	w.uint32(len(cellXfs)).record(xlsbRecordBeginCellXFs)
+	for _, xf := range cellXfs {
+		w.xf(xf, true)
+	}
+	w.record(xlsbRecordEndCellXFs)
+	if ss.CellStyles != nil && len(ss.CellStyles.CellStyle) > 0 {
+		w.uint32(len(ss.CellStyles.CellStyle)).record(xlsbRecordBeginStyles)
+		for _, cellStyle := range ss.CellStyles.CellStyle {
+			flags, builtIn, level := 0, 0, 0
+			if cellStyle.BuiltInID != nil {
+				flags, builtIn = 1, *cellStyle.BuiltInID
+			}
+			if cellStyle.ILevel != nil {
+				level = *cellStyle.ILevel
+			}
+			if cellStyle.Hidden != nil && *cellStyle.Hidden {
+				flags |= 2
+			}
+			if cellStyle.CustomBuiltIn != nil && *cellStyle.CustomBuiltIn {
+				flags |= 4
+			}
+			w.uint32(cellStyle.XfID).uint16(flags).uint8(builtIn).uint8(level).string(cellStyle.Name).record(xlsbRecordStyle)
+		}
+		w.record(xlsbRecordEndStyles)
+	}
+	w.record(xlsbRecordEndStyleSheet)
+}
+
+// font writes the BrtFont record by given font.
+func (w *xlsbWriter) font(font *xlsxFont) {
+	isSet := func(val *attrValBool) bool { return val != nil && (val.Val == nil || *val.Val) }
+	height, flags, weight, underline, family, charset, scheme, name := xlsbDefaultFontHeightInTwips, 0, 400, 0, 0, 0, 0, "Calibri"
+	if font.Sz != nil && font.Sz.Val != nil {
+		height = int(math.Round(*font.Sz.Val * 20))
+	}
+	for bit, val := range map[int]*attrValBool{2: font.I, 8: font.Strike, 0x10: font.Outline, 0x20: font.Shadow, 0x40: font.Condense, 0x80: font.Extend} {
+		if isSet(val) {
+			flags |= bit
+		}
+	}
+	if isSet(font.B) {
+		weight = 700
+	}
+	if font.U != nil {
+		underline = 1
+		for typ, u := range xlsbUnderlineTypes {
+			if font.U.Val != nil && *font.U.Val == u {
+				underline = typ
+			}
+		}
+		if font.U.Val != nil && *font.U.Val == "none" {
+			underline = 0
+		}
+	}
+	if font.Family != nil && font.Family.Val != nil {
+		family = *font.Family.Val
+	}
+	if font.Charset != nil && font.Charset.Val != nil {
+		charset = *font.Charset.Val
+	}
+	if font.Scheme != nil && font.Scheme.Val != nil {
+		scheme = map[string]int{"major": 1, "minor": 2}[*font.Scheme.Val]
+	}
+	if font.Name != nil && font.Name.Val != nil {
+		name = *font.Name.Val
+	}
+	w.uint16(height).uint16(flags).uint16(weight).uint16(0).uint8(underline).uint8(family).uint8(charset).uint8(0).
+		color(font.Color).uint8(scheme).string(name).record(xlsbRecordFont)
+}
+
+// fill writes the BrtFill record by given fill.
+func (w *xlsbWriter) fill(fill *xlsxFill) {
+	if gradient := fill.GradientFill; gradient != nil {
+		var fgColor, bgColor *xlsxColor
+		if len(gradient.Stop) > 0 {
+			fgColor, bgColor = &gradient.Stop[0].Color, &gradient.Stop[len(gradient.Stop)-1].Color
+		}
+		gradientType := 0
+		if gradient.Type == "path" {
+			gradientType = 1
+		}
+		w.uint32(xlsbFillGradient).color(fgColor).color(bgColor).uint32(gradientType).
+			float64(gradient.Degree).float64(gradient.Left).float64(gradient.Right).float64(gradient.Top).
+			float64(gradient.Bottom).uint32(len(gradient.Stop))
+		for _, stop := range gradient.Stop {
+			w.color(&stop.Color).float64(stop.Position)
+		}
+		w.record(xlsbRecordFill)
+		return
+	}
+	pattern, fgColor, bgColor := 0, (*xlsxColor)(nil), (*xlsxColor)(nil)
+	if fill.PatternFill != nil {
+		for i, name := range fillPatterns {
+			if name == fill.PatternFill.PatternType {
+				pattern = i
+			}
+		}
+		fgColor, bgColor = fill.PatternFill.FgColor, fill.PatternFill.BgColor
+	}
+	w.uint32(pattern).color(fgColor).color(bgColor).uint32(0).float64(0).float64(0).float64(0).
+		float64(0).float64(0).uint32(0).record(xlsbRecordFill)
+}
+
+// xf writes the BrtXF record by given cell format, the parent cell style
+// format only be written for the cell formats.
+func (w *xlsbWriter) xf(xf xlsxXf, cellXfs bool) {
+	value := func(v *int) int {
+		if v == nil {
+			return 0
+		}
+		return *v
+	}
+	parent, rotation, indent, flags, apply := xlsbStyleParentXF, 0, 0, 2<<3|0x1000, 0
+	if cellXfs {
+		parent = value(xf.XfID)
+		for bit, val := range []*bool{xf.ApplyNumberFormat, xf.ApplyFont, xf.ApplyAlignment, xf.ApplyBorder, xf.ApplyFill, xf.ApplyProtection} {
+			if val != nil && *val {
+				apply |= 1 << bit
+			}
+		}
+	}
+	if a := xf.Alignment; a != nil {
+		flags &^= 7 << 3
+		for i, name := range xlsHorizontalAlignment {
+			if name == a.Horizontal && name != "" {
+				flags |= i
+			}
+		}
+		vertical := 2
+		for i, name := range xlsVerticalAlignment {
+			if name == a.Vertical && name != "" {
+				vertical = i
+			}
+		}
+		flags |= vertical<<3 | int(a.ReadingOrder&3)<<10
+		for bit, set := range map[int]bool{0x40: a.WrapText, 0x80: a.JustifyLastLine, 0x100: a.ShrinkToFit} {
+			if set {
+				flags |= bit
+			}
+		}
+		rotation, indent = a.TextRotation, a.Indent
+	}
+	if p := xf.Protection; p != nil {
+		if p.Locked != nil && !*p.Locked {
+			flags &^= 0x1000
+		}
+		if p.Hidden != nil && *p.Hidden {
+			flags |= 0x2000
+		}
+	}
+	if xf.PivotButton != nil && *xf.PivotButton {
+		flags |= 0x4000
+	}
+	if xf.QuotePrefix != nil && *xf.QuotePrefix {
+		flags |= 0x8000
+	}
+	w.uint16(parent).uint16(value(xf.NumFmtID)).uint16(value(xf.FontID)).uint16(value(xf.FillID)).
+		uint16(value(xf.BorderID)).uint8(rotation).uint8(indent).uint16(flags).uint8(apply).uint8(0).record(xlsbRecordXF)
+}
@@ -0,0 +1,242 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveAsXLSB(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Hello"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", 3.14))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C1", true))
+	assert.NoError(t, f.SetCellStr("Sheet1", "D1", " 中文 "))
+	assert.NoError(t, f.SetCellDefault("Sheet1", "E1", "inline"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "B1*2"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "G1", "SUM(B1,D1)"))
+	assert.NoError(t, f.MergeCell("Sheet1", "A2", "C3"))
+	assert.NoError(t, f.SetColWidth("Sheet1", "B", "C", 20))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 5, 30))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A6", "https://github.com/carmel/xlsx", "External"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A7", "Sheet2!A1", "Location"))
+	style, err := f.NewStyle(&Style{
+		Font:         &Font{Bold: true, Italic: true, Underline: "double", Color: "#FF0000", Family: "Arial", Size: 14},
+		Fill:         Fill{Type: "pattern", Pattern: 1, Color: []string{"#E0EBF5"}},
+		Border:       []Border{{Type: "left", Color: "#0000FF", Style: 2}, {Type: "diagonalUp", Color: "#000000", Style: 1}},
+		Alignment:    &Alignment{Horizontal: "center", Vertical: "top", WrapText: true, Indent: 1},
+		Protection:   &Protection{Hidden: true},
+		CustomNumFmt: stringPtr("0.000"),
+	})
+	assert.NoError(t, err)
+	gradient, err := f.NewStyle(&Style{Fill: Fill{Type: "gradient", Color: []string{"#FFFFFF", "#E0EBF5"}, Shading: 0}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", style))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A8", "A8", gradient))
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellValue("Sheet2", "A1", "World"))
+	assert.NoError(t, f.SetSheetVisible("Sheet2", false))
+	assert.NoError(t, f.SetSheetPrOptions("Sheet2", TabColor("#00FF00"), CodeName("Data")))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveAsXLSB.xlsb")))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test the binary parts in the package.
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	var parts []string
+	for _, file := range zr.File {
+		parts = append(parts, file.Name)
+	}
+	for _, part := range []string{"xl/workbook.bin", "xl/_rels/workbook.bin.rels", "xl/worksheets/sheet1.bin", "xl/worksheets/_rels/sheet1.bin.rels", "xl/sharedStrings.bin", "xl/styles.bin"} {
+		assert.Contains(t, parts, part)
+	}
+	assert.NotContains(t, parts, "xl/workbook.xml")
+	assert.NotContains(t, parts, "xl/calcChain.xml")
+
+	// Test open the binary workbook.
+	f, err = OpenFile(filepath.Join("test", "TestSaveAsXLSB.xlsb"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet2"}, f.GetSheetList())
+	assert.False(t, f.GetSheetVisible("Sheet2"))
+	for cell, expected := range map[string]string{"A1": "Hello", "B1": "3.14", "C1": "1", "D1": " 中文 ", "E1": "inline", "F1": "0", "G1": "0", "A8": ""} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	for cell, expected := range map[string]string{"F1": "B1*2", "G1": "SUM(B1,D1)"} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	val, err := f.GetCellValue("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "World", val)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "A2:C3", mergeCells[0].GetStartAxis()+":"+mergeCells[0].GetEndAxis())
+	width, err := f.GetColWidth("Sheet1", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	height, err := f.GetRowHeight("Sheet1", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	link, target, err := f.GetCellHyperLink("Sheet1", "A6")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://github.com/carmel/xlsx", target)
+	link, target, err = f.GetCellHyperLink("Sheet1", "A7")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "Sheet2!A1", target)
+	var tabColor TabColor
+	var codeName CodeName
+	assert.NoError(t, f.GetSheetPrOptions("Sheet2", &tabColor, &codeName))
+	assert.Equal(t, TabColor("00FF00"), tabColor)
+	assert.Equal(t, CodeName("Data"), codeName)
+
+	// Test the cell styles in the binary workbook.
+	styleID, err := f.GetCellStyle("Sheet1", "B1")
+	assert.NoError(t, err)
+	xf := f.Styles.CellXfs.Xf[styleID]
+	font := f.Styles.Fonts.Font[*xf.FontID]
+	assert.True(t, *font.B.Val)
+	assert.True(t, *font.I.Val)
+	assert.Equal(t, "double", *font.U.Val)
+	assert.Equal(t, "Arial", *font.Name.Val)
+	assert.Equal(t, 14.0, *font.Sz.Val)
+	assert.Equal(t, "FFFF0000", font.Color.RGB)
+	fill := f.Styles.Fills.Fill[*xf.FillID]
+	assert.Equal(t, "solid", fill.PatternFill.PatternType)
+	assert.Equal(t, "FFE0EBF5", fill.PatternFill.FgColor.RGB)
+	border := f.Styles.Borders.Border[*xf.BorderID]
+	assert.Equal(t, "medium", border.Left.Style)
+	assert.Equal(t, "FF0000FF", border.Left.Color.RGB)
+	assert.True(t, border.DiagonalUp)
+	assert.Equal(t, "thin", border.Diagonal.Style)
+	assert.Equal(t, &xlsxAlignment{Horizontal: "center", Vertical: "top", WrapText: true, Indent: 1}, xf.Alignment)
+	assert.Equal(t, &xlsxProtection{Hidden: boolPtr(true), Locked: boolPtr(false)}, xf.Protection)
+	assert.Equal(t, "0.000", f.Styles.NumFmts.NumFmt[0].FormatCode)
+	styleID, err = f.GetCellStyle("Sheet1", "A8")
+	assert.NoError(t, err)
+	gradientFill := f.Styles.Fills.Fill[*f.Styles.CellXfs.Xf[styleID].FillID].GradientFill
+	assert.Equal(t, 90.0, gradientFill.Degree)
+	assert.Len(t, gradientFill.Stop, 2)
+	assert.Equal(t, "FFE0EBF5", gradientFill.Stop[1].Color.RGB)
+
+	// Test save the opened binary workbook as XML workbook.
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveAsXLSB.xlsx")))
+	f, err = OpenFile(filepath.Join("test", "TestSaveAsXLSB.xlsx"))
+	assert.NoError(t, err)
+	val, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", val)
+	assert.Equal(t, "xl/workbook.xml", f.getWorkbookPath())
+	assert.NoError(t, f.Close())
+}
+
+func TestXLSBFormula(t *testing.T) {
+	for _, formula := range []string{
+		"SUM(B1,D1)", "-A1^2%+(1+2)*3", `IF($A$1>=2,"a""b",TRUE)`, "NOW()", "SUM(A:A,$2:3,$B$1:C2)",
+		"IF(A1,,1)", "#DIV/0!&1.5", `VLOOKUP(A1,B:C,2,FALSE)<>"中文"`,
+	} {
+		rgce, ok := encodeXLSBFormula(formula)
+		assert.True(t, ok, formula)
+		decoded, ok := decodeXLSBFormula(rgce)
+		assert.True(t, ok, formula)
+		assert.Equal(t, formula, decoded)
+	}
+	for _, formula := range []string{"", "Sheet1!A1", "Name", "UNKNOWN(1)", "ROUND(1)", "A1 B1", "SUM(1"} {
+		_, ok := encodeXLSBFormula(formula)
+		assert.False(t, ok, formula)
+	}
+	// Test decode the parsed expression with the PtgAttr and the unsupported
+	// Ptg records.
+	formula, ok := decodeXLSBFormula([]byte{0x25, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0xC0, 0, 0xC0, 0x19, 0x10, 0, 0})
+	assert.True(t, ok)
+	assert.Equal(t, "SUM(A1:A2)", formula)
+	for _, rgce := range [][]byte{{0x03}, {0x1E, 1, 0, 0x1E, 2, 0}, {0x10}, {0x41, 0xFF, 0x7F}} {
+		_, ok = decodeXLSBFormula(rgce)
+		assert.False(t, ok)
+	}
+
+	// Test save the formula which could not be encoded in the binary workbook.
+	f := NewFile()
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "Sheet2!A1"))
+	assert.EqualError(t, f.SaveAs(filepath.Join("test", "TestXLSBFormula.xlsb")),
+		newXLSBFormulaError("A1", "Sheet2!A1").Error())
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "Sheet2!A1"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestXLSBFormula.xlsb")))
+	f, err := OpenFile(filepath.Join("test", "TestXLSBFormula.xlsb"))
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", val)
+	formula, err = f.GetCellFormula("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Empty(t, formula)
+	assert.NoError(t, f.Close())
+}
+
+func TestOpenXLSBCorruptedPart(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestOpenXLSBCorruptedPart.xlsb")))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	corrupted := new(bytes.Buffer)
+	zw := zip.NewWriter(corrupted)
+	for _, file := range zr.File {
+		fi, err := zw.Create(file.Name)
+		assert.NoError(t, err)
+		content, err := readFile(file)
+		assert.NoError(t, err)
+		if file.Name == "xl/worksheets/sheet1.bin" {
+			content = []byte{0x81, 0x81, 0x81}
+		}
+		_, err = fi.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	_, err = OpenReader(corrupted)
+	assert.EqualError(t, err, ErrWorkbookBinaryPart.Error())
+}
+
+func TestXLSBRecords(t *testing.T) {
+	w := new(xlsbWriter)
+	w.uint32(0x12345678).string("中文").nullableString("").ref("B2:C3").float64(1.5).color(&xlsxColor{RGB: "FF112233", Tint: -0.5})
+	w.record(xlsbRecordBeginStyleSheet)
+	w.record(xlsbRecordRowHdr)
+	records, err := readXLSBRecords(w.buf.Bytes())
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, xlsbRecordBeginStyleSheet, records[0].typ)
+	assert.Equal(t, xlsbRecordRowHdr, records[1].typ)
+	assert.Empty(t, records[1].data)
+	r := &xlsbReader{data: records[0].data}
+	assert.Equal(t, uint32(0x12345678), r.uint32())
+	assert.Equal(t, "中文", r.string())
+	assert.Equal(t, "", r.string())
+	assert.Equal(t, "B2:C3", r.ref())
+	assert.Equal(t, 1.5, r.float64())
+	color := r.color()
+	assert.Equal(t, "FF112233", color.RGB)
+	assert.InDelta(t, -0.5, color.Tint, 0.0001)
+	// Test read the exhausted data.
+	assert.Equal(t, 0, r.uint8())
+	assert.Equal(t, 0, r.uint16())
+	assert.Equal(t, "", r.string())
+	assert.Nil(t, r.color())
+
+	// Test read the corrupted records.
+	_, err = readXLSBRecords([]byte{0x81, 0x81, 0x81})
+	assert.EqualError(t, err, ErrWorkbookBinaryPart.Error())
+	_, err = readXLSBRecords([]byte{0x01, 0x05, 0x00})
+	assert.EqualError(t, err, ErrWorkbookBinaryPart.Error())
+}
@@ -25,6 +25,7 @@ type File struct {
 	checked          map[string]bool
 	sheetMap         map[string]string
 	streams          map[string]*StreamWriter
+	streamOutput     *zip.Writer
 	tempFiles        sync.Map
 	CalcChain        *xlsxCalcChain
 	Comments         map[string]*xlsxComments
@@ -62,11 +63,41 @@ type charsetTranscoderFn func(charset string, input io.Reader) (rdr io.Reader, e
 // bytes, worksheet XML will be extracted to system temporary directory when
 // the file size is over this value, this value should be less than or equal
 // to UnzipSizeLimit, the default value is 16MB.
+//
+// EncryptionMechanism specifies the encryption mechanism on saving the
+// spreadsheet with password, the value should be "agile" or "standard", the
+// default value is "agile". The ECMA-376 standard encryption is compatible
+// with the legacy readers that not support the agile encryption.
+//
+// CipherAlgorithm specifies the cipher algorithm on saving the spreadsheet
+// with password, the value should be "AES-128", "AES-192" or "AES-256", the
+// default value is "AES-256" for the agile encryption and "AES-128" for the
+// standard encryption.
+//
+// HashAlgorithm specifies the hash algorithm of the agile encryption, the
+// value should be "SHA1", "SHA256", "SHA384" or "SHA512", the default value
+// is "SHA512". The standard encryption always uses the SHA1 hash algorithm.
+//
+// SpinCount specifies the number of times to iterate the password hash of
+// the agile encryption, the default value is 100000.
+//
+// The encryption settings of the spreadsheet will be filled in the options
+// on opening the spreadsheet with password if the EncryptionMechanism is
+// empty, and the spreadsheet will be encrypted with the same settings on save.
+//
+// CompactStyles specifies if remove the unreferenced formatting records,
+// fonts, fills, borders and custom number formats from the stylesheet on
+// save, see the CompactStyles function for details.
 type Options struct {
 	Password               string
 	RawCellValue           bool
 	UnzipSizeLimit         int64
 	WorksheetUnzipMemLimit int64
+	EncryptionMechanism    string
+	CipherAlgorithm        string
+	HashAlgorithm          string
+	SpinCount              int
+	CompactStyles          bool
 }
 
 // OpenFile take the name of an spreadsheet file and returns a populated
@@ -78,10 +109,24 @@ type Options struct {
 //        return
 //    }
 //
-// Note that the xlsx just support decrypt and not support encrypt
-// currently, the spreadsheet saved by Save and SaveAs will be without
-// password unprotected. Close the file by Close after opening the
-// spreadsheet.
+// The spreadsheet opened with password will be encrypted with the same
+// password and encryption settings on Save, the spreadsheet saved by SaveAs
+// without password will be unprotected. Close the file by Close after
+// opening the spreadsheet.
+//
+// The legacy Excel 97-2003 (BIFF8) workbook with .xls extension will be
+// converted into the spreadsheet on open, the RC4 and RC4 CryptoAPI
+// encrypted legacy workbooks are supported. The formula cells of the legacy
+// workbook keep the cached results only.
+//
+// The binary (BIFF12) workbook with .xlsb extension will be converted into
+// the spreadsheet on open, the formula cells keep the cached results only
+// and the rich text runs of the strings are read as plain text.
+//
+// The OpenDocument spreadsheet with .ods extension will be converted into
+// the spreadsheet on open, including the cell values and types, formulas,
+// merged cells, column widths, row heights, basic cell styles and sheet
+// visibility. The formulas will be translated from the OpenFormula syntax.
 func OpenFile(filename string, opt ...Options) (*File, error) {
 	file, err := os.Open(filepath.Clean(filename))
 	if err != nil {
@@ -139,9 +184,15 @@ func OpenReader(r io.Reader, opt ...Options) (*File, error) {
 	if f.options.WorksheetUnzipMemLimit > f.options.UnzipSizeLimit {
 		return nil, ErrOptionsUnzipSizeLimit
 	}
+	if stream := xlsWorkbookStream(b); stream != nil {
+		return openXLS(stream, f.options)
+	}
 	if bytes.Contains(b, oleIdentifier) {
 		b, err = Decrypt(b, f.options)
 		if err != nil {
+			if err == ErrWorkbookPassword || err == ErrWorkbookDataIntegrity {
+				return nil, err
+			}
 			return nil, fmt.Errorf("decrypted file failed")
 		}
 	}
@@ -153,6 +204,12 @@ func OpenReader(r io.Reader, opt ...Options) (*File, error) {
 	if err != nil {
 		return nil, err
 	}
+	if string(file["mimetype"]) == odsMediaType {
+		return openODS(file, f.options)
+	}
+	if err = f.readBinaryPackage(file); err != nil {
+		return nil, err
+	}
 	f.SheetCount = sheetCount
 	for k, v := range file {
 		f.Pkg.Store(k, v)
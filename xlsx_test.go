@@ -939,6 +939,57 @@ func TestCopySheet(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCopySheet.xlsx")))
 }
 
+func TestCopySheetWithParts(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{"Name", "Q1", "Q2"}, {"Apple", 2, 3}, {"Orange", 5, 7}} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+1), &row))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D2", "SUM(Table1[Q1])"))
+	assert.NoError(t, f.AddComment("Sheet1", "A1", "Author", "Comment"))
+	assert.NoError(t, f.AddPicture("Sheet1", "F1", filepath.Join("test", "images", "excel.png"), ""))
+	assert.NoError(t, f.AddChart("Sheet1", "F20", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$C$1","values":"Sheet1!$B$2:$C$2"}]}`))
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "C3", `{"table_name":"Table1"}`))
+	assert.NoError(t, f.SetPageLayout("Sheet1", PageLayoutOrientation(OrientationLandscape)))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Print_Area", RefersTo: "Sheet1!$A$1:$C$3", Scope: "Sheet1"}))
+	idx := f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Print_Area", RefersTo: "Sheet2!$A$1:$A$2", Scope: "Sheet2"}))
+	assert.NoError(t, f.CopySheet(0, idx))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+
+	formula, err := f.GetCellFormula("Sheet2", "D2")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(Table1_1[Q1])", formula)
+	comments := f.GetComments()
+	assert.Len(t, comments["Sheet1"], 1)
+	assert.Len(t, comments["Sheet2"], 1)
+	for _, sheet := range []string{"Sheet1", "Sheet2"} {
+		name, raw, err := f.GetPicture(sheet, "F1")
+		assert.NoError(t, err)
+		assert.Equal(t, "image1.png", name)
+		assert.NotEmpty(t, raw)
+	}
+	assert.Equal(t, "../drawings/drawing2.xml", f.getSheetRelationshipsTargetByID("Sheet2", "rId3"))
+	chart, ok := f.Pkg.Load("xl/charts/chart2.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(chart.([]byte)), "Sheet2!$B$2:$C$2")
+	table, ok := f.Pkg.Load("xl/tables/table2.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(table.([]byte)), `name="Table1_1"`)
+	var orientation PageLayoutOrientation
+	assert.NoError(t, f.GetPageLayout("Sheet2", &orientation))
+	assert.Equal(t, PageLayoutOrientation(OrientationLandscape), orientation)
+	var printAreas []DefinedName
+	for _, dn := range f.GetDefinedName() {
+		if dn.Scope == "Sheet2" {
+			printAreas = append(printAreas, dn)
+		}
+	}
+	assert.Equal(t, []DefinedName{{Name: "_xlnm.Print_Area", RefersTo: "Sheet2!$A$1:$C$3", Scope: "Sheet2"}}, printAreas)
+}
+
 func TestCopySheetError(t *testing.T) {
 	f, err := prepareTestBook1()
 	if !assert.NoError(t, err) {
@@ -1052,9 +1103,11 @@ func TestConditionalFormat(t *testing.T) {
 	}
 
 	// Set conditional format with illegal valid type.
-	assert.NoError(t, f.SetConditionalFormat(sheet1, "K1:K10", `[{"type":"", "criteria":"=", "min_type":"min","max_type":"max","bar_color":"#638EC6"}]`))
-	// Set conditional format with illegal criteria type.
+	assert.EqualError(t, f.SetConditionalFormat(sheet1, "K1:K10", `[{"type":"", "criteria":"=", "min_type":"min","max_type":"max","bar_color":"#638EC6"}]`), `unsupported conditional format type ""`)
+	// Set conditional format without criteria for the rule which not require it.
 	assert.NoError(t, f.SetConditionalFormat(sheet1, "K1:K10", `[{"type":"data_bar", "criteria":"", "min_type":"min","max_type":"max","bar_color":"#638EC6"}]`))
+	// Set conditional format with illegal criteria type.
+	assert.EqualError(t, f.SetConditionalFormat(sheet1, "K1:K10", `[{"type":"cell", "criteria":"", "value":"0"}]`), `unsupported conditional format criteria ""`)
 
 	// Set conditional format with file without dxfs element shold not return error.
 	f, err = OpenFile(filepath.Join("test", "Book1.xlsx"))
@@ -507,137 +507,170 @@ type cPageMargins struct {
 	T      float64 `xml:"t,attr"`
 }
 
-// formatChartAxis directly maps the format settings of the chart axis.
-type formatChartAxis struct {
-	None                bool    `json:"none"`
-	Crossing            string  `json:"crossing"`
-	MajorGridlines      bool    `json:"major_grid_lines"`
-	MinorGridlines      bool    `json:"minor_grid_lines"`
-	MajorTickMark       string  `json:"major_tick_mark"`
-	MinorTickMark       string  `json:"minor_tick_mark"`
-	MinorUnitType       string  `json:"minor_unit_type"`
-	MajorUnit           float64 `json:"major_unit"`
-	MajorUnitType       string  `json:"major_unit_type"`
-	TickLabelSkip       int     `json:"tick_label_skip"`
-	DisplayUnits        string  `json:"display_units"`
-	DisplayUnitsVisible bool    `json:"display_units_visible"`
-	DateAxis            bool    `json:"date_axis"`
-	ReverseOrder        bool    `json:"reverse_order"`
-	Maximum             float64 `json:"maximum"`
-	Minimum             float64 `json:"minimum"`
-	NumFormat           string  `json:"num_format"`
-	NumFont             struct {
-		Color     string `json:"color"`
-		Bold      bool   `json:"bold"`
-		Italic    bool   `json:"italic"`
-		Underline bool   `json:"underline"`
-	} `json:"num_font"`
-	LogBase    float64      `json:"logbase"`
-	NameLayout formatLayout `json:"name_layout"`
-}
-
-type formatChartDimension struct {
+// ChartAxis directly maps the format settings of the chart axis.
+type ChartAxis struct {
+	None                bool         `json:"none"`
+	Crossing            string       `json:"crossing"`
+	MajorGridlines      bool         `json:"major_grid_lines"`
+	MinorGridlines      bool         `json:"minor_grid_lines"`
+	MajorTickMark       string       `json:"major_tick_mark"`
+	MinorTickMark       string       `json:"minor_tick_mark"`
+	MinorUnitType       string       `json:"minor_unit_type"`
+	MajorUnit           float64      `json:"major_unit"`
+	MajorUnitType       string       `json:"major_unit_type"`
+	TickLabelSkip       int          `json:"tick_label_skip"`
+	DisplayUnits        string       `json:"display_units"`
+	DisplayUnitsVisible bool         `json:"display_units_visible"`
+	DateAxis            bool         `json:"date_axis"`
+	ReverseOrder        bool         `json:"reverse_order"`
+	Maximum             float64      `json:"maximum"`
+	Minimum             float64      `json:"minimum"`
+	NumFormat           string       `json:"num_format"`
+	NumFont             ChartNumFont `json:"num_font"`
+	LogBase             float64      `json:"logbase"`
+	NameLayout          ChartLayout  `json:"name_layout"`
+}
+
+// ChartNumFont directly maps the font settings of the chart axis number
+// labels.
+type ChartNumFont struct {
+	Color     string `json:"color"`
+	Bold      bool   `json:"bold"`
+	Italic    bool   `json:"italic"`
+	Underline bool   `json:"underline"`
+}
+
+// ChartDimension directly maps the dimension of the chart.
+type ChartDimension struct {
 	Width  int `json:"width"`
 	Height int `json:"height"`
 }
 
-// formatChart directly maps the format settings of the chart.
-type formatChart struct {
-	Type       string               `json:"type"`
-	Series     []formatChartSeries  `json:"series"`
-	Format     formatPicture        `json:"format"`
-	Dimension  formatChartDimension `json:"dimension"`
-	Legend     formatChartLegend    `json:"legend"`
-	Title      formatChartTitle     `json:"title"`
-	VaryColors bool                 `json:"vary_colors"`
-	XAxis      formatChartAxis      `json:"x_axis"`
-	YAxis      formatChartAxis      `json:"y_axis"`
-	Chartarea  struct {
-		Border struct {
-			None bool `json:"none"`
-		} `json:"border"`
-		Fill struct {
-			Color string `json:"color"`
-		} `json:"fill"`
-		Pattern struct {
-			Pattern string `json:"pattern"`
-			FgColor string `json:"fg_color"`
-			BgColor string `json:"bg_color"`
-		} `json:"pattern"`
-	} `json:"chartarea"`
-	Plotarea struct {
-		ShowBubbleSize  bool `json:"show_bubble_size"`
-		ShowCatName     bool `json:"show_cat_name"`
-		ShowLeaderLines bool `json:"show_leader_lines"`
-		ShowPercent     bool `json:"show_percent"`
-		ShowSerName     bool `json:"show_series_name"`
-		ShowVal         bool `json:"show_val"`
-		Gradient        struct {
-			Colors []string `json:"colors"`
-		} `json:"gradient"`
-		Border struct {
-			Color    string `json:"color"`
-			Width    int    `json:"width"`
-			DashType string `json:"dash_type"`
-		} `json:"border"`
-		Fill struct {
-			Color string `json:"color"`
-		} `json:"fill"`
-		Layout formatLayout `json:"layout"`
-	} `json:"plotarea"`
-	ShowBlanksAs   string `json:"show_blanks_as"`
-	ShowHiddenData bool   `json:"show_hidden_data"`
-	SetRotation    int    `json:"set_rotation"`
-	SetHoleSize    int    `json:"set_hole_size"`
+// ChartOptions directly maps the format settings of the chart, the JSON tags
+// correspond to the parameters of the AddChart function.
+type ChartOptions struct {
+	Type           string         `json:"type"`
+	Series         []ChartSeries  `json:"series"`
+	Format         PictureOptions `json:"format"`
+	Dimension      ChartDimension `json:"dimension"`
+	Legend         ChartLegend    `json:"legend"`
+	Title          ChartTitle     `json:"title"`
+	VaryColors     *bool          `json:"vary_colors"`
+	XAxis          ChartAxis      `json:"x_axis"`
+	YAxis          ChartAxis      `json:"y_axis"`
+	Chartarea      ChartArea      `json:"chartarea"`
+	Plotarea       ChartPlotArea  `json:"plotarea"`
+	ShowBlanksAs   string         `json:"show_blanks_as"`
+	ShowHiddenData bool           `json:"show_hidden_data"`
+	SetRotation    int            `json:"set_rotation"`
+	SetHoleSize    int            `json:"set_hole_size"`
 	order          int
 }
 
-// formatChartLegend directly maps the format settings of the chart legend.
-type formatChartLegend struct {
-	None            bool         `json:"none"`
-	DeleteSeries    []int        `json:"delete_series"`
-	Font            Font         `json:"font"`
-	Layout          formatLayout `json:"layout"`
-	Position        string       `json:"position"`
-	ShowLegendEntry bool         `json:"show_legend_entry"`
-	ShowLegendKey   bool         `json:"show_legend_key"`
-}
-
-// formatChartSeries directly maps the format settings of the chart series.
-type formatChartSeries struct {
-	Name       string `json:"name"`
-	Categories string `json:"categories"`
-	Values     string `json:"values"`
-	Line       struct {
-		None  bool    `json:"none"`
-		Color string  `json:"color"`
-		Width float64 `json:"width"`
-	} `json:"line"`
-	Marker struct {
-		Symbol string  `json:"symbol"`
-		Size   int     `json:"size"`
-		Width  float64 `json:"width"`
-		Border struct {
-			Color string `json:"color"`
-			None  bool   `json:"none"`
-		} `json:"border"`
-		Fill struct {
-			Color string `json:"color"`
-			None  bool   `json:"none"`
-		} `json:"fill"`
-	} `json:"marker"`
-}
-
-// formatChartTitle directly maps the format settings of the chart title.
-type formatChartTitle struct {
-	None    bool         `json:"none"`
-	Name    string       `json:"name"`
-	Overlay bool         `json:"overlay"`
-	Layout  formatLayout `json:"layout"`
-}
-
-// formatLayout directly maps the format settings of the element layout.
-type formatLayout struct {
+// ChartArea directly maps the format settings of the chart area.
+type ChartArea struct {
+	Border  ChartAreaBorder `json:"border"`
+	Fill    ChartFill       `json:"fill"`
+	Pattern ChartPattern    `json:"pattern"`
+}
+
+// ChartAreaBorder directly maps the border settings of the chart area.
+type ChartAreaBorder struct {
+	None bool `json:"none"`
+}
+
+// ChartFill directly maps the solid fill settings of the chart area and plot
+// area.
+type ChartFill struct {
+	Color string `json:"color"`
+}
+
+// ChartPattern directly maps the pattern fill settings of the chart area.
+type ChartPattern struct {
+	Pattern string `json:"pattern"`
+	FgColor string `json:"fg_color"`
+	BgColor string `json:"bg_color"`
+}
+
+// ChartPlotArea directly maps the format settings of the plot area.
+type ChartPlotArea struct {
+	ShowBubbleSize  bool            `json:"show_bubble_size"`
+	ShowCatName     bool            `json:"show_cat_name"`
+	ShowLeaderLines bool            `json:"show_leader_lines"`
+	ShowPercent     bool            `json:"show_percent"`
+	ShowSerName     bool            `json:"show_series_name"`
+	ShowVal         bool            `json:"show_val"`
+	Gradient        ChartGradient   `json:"gradient"`
+	Border          ChartPlotBorder `json:"border"`
+	Fill            ChartFill       `json:"fill"`
+	Layout          ChartLayout     `json:"layout"`
+}
+
+// ChartGradient directly maps the gradient fill settings of the plot area.
+type ChartGradient struct {
+	Colors []string `json:"colors"`
+}
+
+// ChartPlotBorder directly maps the border settings of the plot area.
+type ChartPlotBorder struct {
+	Color    string `json:"color"`
+	Width    int    `json:"width"`
+	DashType string `json:"dash_type"`
+}
+
+// ChartLegend directly maps the format settings of the chart legend.
+type ChartLegend struct {
+	None            bool        `json:"none"`
+	DeleteSeries    []int       `json:"delete_series"`
+	Font            Font        `json:"font"`
+	Layout          ChartLayout `json:"layout"`
+	Position        string      `json:"position"`
+	ShowLegendEntry bool        `json:"show_legend_entry"`
+	ShowLegendKey   bool        `json:"show_legend_key"`
+}
+
+// ChartSeries directly maps the format settings of the chart series.
+type ChartSeries struct {
+	Name       string      `json:"name"`
+	Categories string      `json:"categories"`
+	Values     string      `json:"values"`
+	Line       ChartLine   `json:"line"`
+	Marker     ChartMarker `json:"marker"`
+}
+
+// ChartLine directly maps the line settings of the chart series.
+type ChartLine struct {
+	None  bool    `json:"none"`
+	Color string  `json:"color"`
+	Width float64 `json:"width"`
+}
+
+// ChartMarker directly maps the marker settings of the chart series.
+type ChartMarker struct {
+	Symbol string           `json:"symbol"`
+	Size   int              `json:"size"`
+	Width  float64          `json:"width"`
+	Border ChartMarkerColor `json:"border"`
+	Fill   ChartMarkerColor `json:"fill"`
+}
+
+// ChartMarkerColor directly maps the border and fill color settings of the
+// chart series marker.
+type ChartMarkerColor struct {
+	Color string `json:"color"`
+	None  bool   `json:"none"`
+}
+
+// ChartTitle directly maps the format settings of the chart title.
+type ChartTitle struct {
+	None    bool        `json:"none"`
+	Name    string      `json:"name"`
+	Overlay bool        `json:"overlay"`
+	Layout  ChartLayout `json:"layout"`
+}
+
+// ChartLayout directly maps the format settings of the element layout.
+type ChartLayout struct {
 	X      float64 `json:"x"`
 	Y      float64 `json:"y"`
 	Width  float64 `json:"width"`
@@ -51,14 +51,22 @@ const (
 	NameSpaceDublinCore                          = "http://purl.org/dc/elements/1.1/"
 	NameSpaceDublinCoreTerms                     = "http://purl.org/dc/terms/"
 	NameSpaceDublinCoreMetadataIntiative         = "http://purl.org/dc/dcmitype/"
+	ContentTypeBinarySharedStrings               = "application/vnd.ms-excel.sharedStrings"
+	ContentTypeBinaryStyles                      = "application/vnd.ms-excel.styles"
+	ContentTypeBinaryWorkbook                    = "application/vnd.ms-excel.sheet.binary.macroEnabled.main"
+	ContentTypeBinaryWorksheet                   = "application/vnd.ms-excel.worksheet"
 	ContentTypeDrawing                           = "application/vnd.openxmlformats-officedocument.drawing+xml"
 	ContentTypeDrawingML                         = "application/vnd.openxmlformats-officedocument.drawingml.chart+xml"
 	ContentTypeMacro                             = "application/vnd.ms-excel.sheet.macroEnabled.main+xml"
+	ContentTypePrinterSettings                   = "application/vnd.openxmlformats-officedocument.spreadsheetml.printerSettings"
+	ContentTypeSheetML                           = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"
+	ContentTypeSpreadSheetMLCalcChain            = "application/vnd.openxmlformats-officedocument.spreadsheetml.calcChain+xml"
 	ContentTypeSpreadSheetMLChartsheet           = "application/vnd.openxmlformats-officedocument.spreadsheetml.chartsheet+xml"
 	ContentTypeSpreadSheetMLComments             = "application/vnd.openxmlformats-officedocument.spreadsheetml.comments+xml"
 	ContentTypeSpreadSheetMLPivotCacheDefinition = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotCacheDefinition+xml"
 	ContentTypeSpreadSheetMLPivotTable           = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotTable+xml"
 	ContentTypeSpreadSheetMLSharedStrings        = "application/vnd.openxmlformats-officedocument.spreadsheetml.sharedStrings+xml"
+	ContentTypeSpreadSheetMLStyles               = "application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"
 	ContentTypeSpreadSheetMLTable                = "application/vnd.openxmlformats-officedocument.spreadsheetml.table+xml"
 	ContentTypeSpreadSheetMLWorksheet            = "application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"
 	ContentTypeVBA                               = "application/vnd.ms-office.vbaProject"
@@ -79,6 +87,11 @@ const (
 	ExtURITimelineRefs           = "{7E03D99C-DC04-49d9-9315-930204A7B6E9}"
 	ExtURIDrawingBlip            = "{28A0092B-C50C-407E-A947-70E740481C1C}"
 	ExtURIMacExcelMX             = "{64002731-A6B0-56B0-2670-7721B7C09600}"
+
+	// ExtURIConditionalFormattingRuleID is the extLst child element of the
+	// cfRule element, which links the rule to the conditional formatting rule
+	// in the worksheet extension list by the rule ID.
+	ExtURIConditionalFormattingRuleID = "{B025F937-C7B1-47D3-B67F-A62EFF666E3E}"
 )
 
 // Excel specifications and limits
@@ -95,6 +108,7 @@ const (
 	TotalColumns         = 16384
 	TotalSheetHyperlinks = 65529
 	TotalCellChars       = 32767
+	MaxSpinCount         = 10000000
 	// pivotTableVersion should be greater than 3. One or more of the
 	// PivotTables chosen are created in a version of Excel earlier than
 	// Excel 2007 or in compatibility mode. Slicer can only be used with
@@ -445,9 +459,10 @@ type xdrTxBody struct {
 	P      []*aP    `xml:"a:p"`
 }
 
-// formatPicture directly maps the format settings of the picture.
-type formatPicture struct {
-	FPrintsWithSheet bool    `json:"print_obj"`
+// PictureOptions directly maps the format settings of the picture, the JSON
+// tags correspond to the parameters of the AddPicture function.
+type PictureOptions struct {
+	FPrintsWithSheet *bool   `json:"print_obj"`
 	FLocksWithSheet  bool    `json:"locked"`
 	NoChangeAspect   bool    `json:"lock_aspect_ratio"`
 	Autofit          bool    `json:"autofit"`
@@ -460,32 +475,33 @@ type formatPicture struct {
 	Positioning      string  `json:"positioning"`
 }
 
-// formatShape directly maps the format settings of the shape.
-type formatShape struct {
-	Type      string                 `json:"type"`
-	Width     int                    `json:"width"`
-	Height    int                    `json:"height"`
-	Format    formatPicture          `json:"format"`
-	Color     formatShapeColor       `json:"color"`
-	Line      formatLine             `json:"line"`
-	Paragraph []formatShapeParagraph `json:"paragraph"`
+// ShapeOptions directly maps the format settings of the shape, the JSON tags
+// correspond to the parameters of the AddShape function.
+type ShapeOptions struct {
+	Type      string           `json:"type"`
+	Width     int              `json:"width"`
+	Height    int              `json:"height"`
+	Format    PictureOptions   `json:"format"`
+	Color     ShapeColor       `json:"color"`
+	Line      ShapeLine        `json:"line"`
+	Paragraph []ShapeParagraph `json:"paragraph"`
 }
 
-// formatShapeParagraph directly maps the format settings of the paragraph in
+// ShapeParagraph directly maps the format settings of the paragraph in
 // the shape.
-type formatShapeParagraph struct {
+type ShapeParagraph struct {
 	Font Font   `json:"font"`
 	Text string `json:"text"`
 }
 
-// formatShapeColor directly maps the color settings of the shape.
-type formatShapeColor struct {
+// ShapeColor directly maps the color settings of the shape.
+type ShapeColor struct {
 	Line   string `json:"line"`
 	Fill   string `json:"fill"`
 	Effect string `json:"effect"`
 }
 
-// formatLine directly maps the line settings of the shape.
-type formatLine struct {
+// ShapeLine directly maps the line settings of the shape.
+type ShapeLine struct {
 	Width float64 `json:"width"`
 }
@@ -15,7 +15,7 @@ type xlsxTable struct {
 	DisplayName          string              `xml:"displayName,attr,omitempty"`
 	HeaderRowBorderDxfID int                 `xml:"headerRowBorderDxfId,attr,omitempty"`
 	HeaderRowCellStyle   string              `xml:"headerRowCellStyle,attr,omitempty"`
-	HeaderRowCount       int                 `xml:"headerRowCount,attr,omitempty"`
+	HeaderRowCount       *int                `xml:"headerRowCount,attr"`
 	HeaderRowDxfID       int                 `xml:"headerRowDxfId,attr,omitempty"`
 	ID                   int                 `xml:"id,attr"`
 	InsertRow            bool                `xml:"insertRow,attr,omitempty"`
@@ -38,6 +38,7 @@ type xlsxAutoFilter struct {
 	XMLName      xml.Name            `xml:"autoFilter"`
 	Ref          string              `xml:"ref,attr"`
 	FilterColumn []*xlsxFilterColumn `xml:"filterColumn"`
+	SortState    *xlsxSortState      `xml:"sortState"`
 }
 
 // xlsxFilterColumn directly maps the filterColumn element. The filterColumn
@@ -109,6 +110,7 @@ type xlsxColorFilter struct {
 // cells whose values do not meet the specified criteria, the corresponding rows
 // shall be hidden from view when the filter is applied.
 type xlsxDynamicFilter struct {
+	MaxVal    float64 `xml:"maxVal,attr,omitempty"`
 	MaxValISO string  `xml:"maxValIso,attr,omitempty"`
 	Type      string  `xml:"type,attr,omitempty"`
 	Val       float64 `xml:"val,attr,omitempty"`
@@ -185,22 +187,54 @@ type xlsxTableStyleInfo struct {
 	ShowColumnStripes bool   `xml:"showColumnStripes,attr"`
 }
 
-// formatTable directly maps the format settings of the table.
-type formatTable struct {
+// TableOptions directly maps the format settings of the table, the JSON tags
+// correspond to the parameters of the AddTable function.
+type TableOptions struct {
 	TableName         string `json:"table_name"`
 	TableStyle        string `json:"table_style"`
 	ShowFirstColumn   bool   `json:"show_first_column"`
 	ShowLastColumn    bool   `json:"show_last_column"`
-	ShowRowStripes    bool   `json:"show_row_stripes"`
+	ShowRowStripes    *bool  `json:"show_row_stripes"`
 	ShowColumnStripes bool   `json:"show_column_stripes"`
 }
 
-// formatAutoFilter directly maps the auto filter settings.
-type formatAutoFilter struct {
-	Column     string `json:"column"`
-	Expression string `json:"expression"`
-	FilterList []struct {
-		Column string `json:"column"`
-		Value  []int  `json:"value"`
-	} `json:"filter_list"`
+// AutoFilterOptions directly maps the auto filter settings, the JSON tags
+// correspond to the parameters of the AutoFilter function.
+type AutoFilterOptions struct {
+	Column     string                  `json:"column"`
+	Expression string                  `json:"expression"`
+	Values     []string                `json:"values"`
+	Top10      *AutoFilterTop10Options `json:"top10"`
+	Dynamic    string                  `json:"dynamic"`
+	Color      *AutoFilterColorOptions `json:"color"`
+	FilterList []AutoFilterListOptions `json:"filter_list"`
+}
+
+// AutoFilterTop10Options directly maps the top 10 filter settings of the
+// auto filter.
+type AutoFilterTop10Options struct {
+	Bottom  bool    `json:"bottom"`
+	Percent bool    `json:"percent"`
+	Value   float64 `json:"value"`
+}
+
+// AutoFilterColorOptions directly maps the color filter settings of the auto
+// filter, the fill color of the cells will be filtered by default.
+type AutoFilterColorOptions struct {
+	Color     string `json:"color"`
+	FontColor bool   `json:"font_color"`
+}
+
+// AutoFilterState directly maps the range and the filter settings of the
+// columns of the auto filter in the worksheet.
+type AutoFilterState struct {
+	Ref     string
+	Columns []AutoFilterOptions
+}
+
+// AutoFilterListOptions directly maps the filter list settings of the auto
+// filter.
+type AutoFilterListOptions struct {
+	Column string `json:"column"`
+	Value  []int  `json:"value"`
 }
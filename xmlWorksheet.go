@@ -321,11 +321,24 @@ type xlsxRow struct {
 // xlsxSortState directly maps the sortState element. This collection
 // preserves the AutoFilter sort state.
 type xlsxSortState struct {
-	ColumnSort    bool   `xml:"columnSort,attr,omitempty"`
-	CaseSensitive bool   `xml:"caseSensitive,attr,omitempty"`
-	SortMethod    string `xml:"sortMethod,attr,omitempty"`
-	Ref           string `xml:"ref,attr"`
-	Content       string `xml:",innerxml"`
+	ColumnSort    bool                 `xml:"columnSort,attr,omitempty"`
+	CaseSensitive bool                 `xml:"caseSensitive,attr,omitempty"`
+	SortMethod    string               `xml:"sortMethod,attr,omitempty"`
+	Ref           string               `xml:"ref,attr"`
+	SortCondition []*xlsxSortCondition `xml:"sortCondition"`
+}
+
+// xlsxSortCondition directly maps the sortCondition element. This collection
+// specifies a sort condition to apply to the range, the conditions are
+// applied in the order of the elements.
+type xlsxSortCondition struct {
+	Descending bool   `xml:"descending,attr,omitempty"`
+	SortBy     string `xml:"sortBy,attr,omitempty"`
+	Ref        string `xml:"ref,attr"`
+	CustomList string `xml:"customList,attr,omitempty"`
+	DxfID      *int   `xml:"dxfId,attr"`
+	IconSet    string `xml:"iconSet,attr,omitempty"`
+	IconID     *int   `xml:"iconId,attr"`
 }
 
 // xlsxCustomSheetViews directly maps the customSheetViews element. This is a
@@ -562,7 +575,7 @@ type xlsxColorScale struct {
 type xlsxDataBar struct {
 	MaxLength int          `xml:"maxLength,attr,omitempty"`
 	MinLength int          `xml:"minLength,attr,omitempty"`
-	ShowValue bool         `xml:"showValue,attr,omitempty"`
+	ShowValue *bool        `xml:"showValue,attr"`
 	Cfvo      []*xlsxCfvo  `xml:"cfvo"`
 	Color     []*xlsxColor `xml:"color"`
 }
@@ -571,7 +584,7 @@ type xlsxDataBar struct {
 type xlsxIconSet struct {
 	Cfvo      []*xlsxCfvo `xml:"cfvo"`
 	IconSet   string      `xml:"iconSet,attr,omitempty"`
-	ShowValue bool        `xml:"showValue,attr,omitempty"`
+	ShowValue *bool       `xml:"showValue,attr"`
 	Percent   bool        `xml:"percent,attr,omitempty"`
 	Reverse   bool        `xml:"reverse,attr,omitempty"`
 }
@@ -579,7 +592,7 @@ type xlsxIconSet struct {
 // cfvo (Conditional Format Value Object) describes the values of the
 // interpolation points in a gradient scale.
 type xlsxCfvo struct {
-	Gte    bool        `xml:"gte,attr,omitempty"`
+	Gte    *bool       `xml:"gte,attr"`
 	Type   string      `xml:"type,attr,omitempty"`
 	Val    string      `xml:"val,attr,omitempty"`
 	ExtLst *xlsxExtLst `xml:"extLst"`
@@ -712,6 +725,142 @@ type xlsxX14SparklineGroups struct {
 	Content         string                   `xml:",innerxml"`
 }
 
+// decodeX14ConditionalFormattings directly maps the conditionalFormattings
+// element.
+type decodeX14ConditionalFormattings struct {
+	XMLName xml.Name                          `xml:"conditionalFormattings"`
+	Content string                            `xml:",innerxml"`
+	CondFmt []*decodeX14ConditionalFormatting `xml:"conditionalFormatting"`
+}
+
+// decodeX14ConditionalFormatting directly maps the conditionalFormatting
+// element.
+type decodeX14ConditionalFormatting struct {
+	Content string             `xml:",innerxml"`
+	CfRule  []*decodeX14CfRule `xml:"cfRule"`
+	SQRef   string             `xml:"sqref"`
+}
+
+// decodeX14CfRule directly maps the cfRule element.
+type decodeX14CfRule struct {
+	Type     string            `xml:"type,attr"`
+	Priority int               `xml:"priority,attr"`
+	ID       string            `xml:"id,attr"`
+	DataBar  *decodeX14DataBar `xml:"dataBar"`
+	IconSet  *decodeX14IconSet `xml:"iconSet"`
+}
+
+// decodeX14DataBar directly maps the dataBar element.
+type decodeX14DataBar struct {
+	MaxLength                            int              `xml:"maxLength,attr"`
+	MinLength                            int              `xml:"minLength,attr"`
+	ShowValue                            *bool            `xml:"showValue,attr"`
+	Border                               bool             `xml:"border,attr"`
+	Gradient                             *bool            `xml:"gradient,attr"`
+	Direction                            string           `xml:"direction,attr"`
+	NegativeBarColorSameAsPositive       bool             `xml:"negativeBarColorSameAsPositive,attr"`
+	NegativeBarBorderColorSameAsPositive *bool            `xml:"negativeBarBorderColorSameAsPositive,attr"`
+	AxisPosition                         string           `xml:"axisPosition,attr"`
+	Cfvo                                 []*decodeX14Cfvo `xml:"cfvo"`
+	FillColor                            *xlsxColor       `xml:"fillColor"`
+	BorderColor                          *xlsxColor       `xml:"borderColor"`
+	NegativeFillColor                    *xlsxColor       `xml:"negativeFillColor"`
+	NegativeBorderColor                  *xlsxColor       `xml:"negativeBorderColor"`
+	AxisColor                            *xlsxColor       `xml:"axisColor"`
+}
+
+// decodeCfRuleExtLst directly maps the extLst element of the conditional
+// formatting rule.
+type decodeCfRuleExtLst struct {
+	XMLName xml.Name           `xml:"extLst"`
+	Ext     []*decodeCfRuleExt `xml:"ext"`
+}
+
+// decodeCfRuleExt directly maps the ext element of the conditional formatting
+// rule, which contains the ID of the rule in the worksheet extension list.
+type decodeCfRuleExt struct {
+	URI string `xml:"uri,attr"`
+	ID  string `xml:"id"`
+}
+
+// decodeX14IconSet directly maps the iconSet element.
+type decodeX14IconSet struct {
+	IconSet   string           `xml:"iconSet,attr"`
+	ShowValue *bool            `xml:"showValue,attr"`
+	Percent   bool             `xml:"percent,attr"`
+	Reverse   bool             `xml:"reverse,attr"`
+	Cfvo      []*decodeX14Cfvo `xml:"cfvo"`
+}
+
+// decodeX14Cfvo directly maps the cfvo element.
+type decodeX14Cfvo struct {
+	Type string `xml:"type,attr"`
+	Gte  *bool  `xml:"gte,attr"`
+	F    string `xml:"f"`
+}
+
+// xlsxX14ConditionalFormattings directly maps the conditionalFormattings
+// element.
+type xlsxX14ConditionalFormattings struct {
+	XMLName xml.Name `xml:"x14:conditionalFormattings"`
+	Content string   `xml:",innerxml"`
+}
+
+// xlsxX14ConditionalFormatting directly maps the conditionalFormatting
+// element, which describes the conditional formatting rules only supported by
+// the Excel 2010 and later versions.
+type xlsxX14ConditionalFormatting struct {
+	XMLName xml.Name         `xml:"x14:conditionalFormatting"`
+	XMLNSXM string           `xml:"xmlns:xm,attr"`
+	CfRule  []*xlsxX14CfRule `xml:"x14:cfRule"`
+	SQRef   string           `xml:"xm:sqref"`
+}
+
+// xlsxX14CfRule directly maps the cfRule element.
+type xlsxX14CfRule struct {
+	Type     string          `xml:"type,attr,omitempty"`
+	Priority int             `xml:"priority,attr,omitempty"`
+	ID       string          `xml:"id,attr,omitempty"`
+	DataBar  *xlsxX14DataBar `xml:"x14:dataBar"`
+	IconSet  *xlsxX14IconSet `xml:"x14:iconSet"`
+}
+
+// xlsxX14DataBar directly maps the dataBar element, which describes the data
+// bar conditional formatting rule with the extended options.
+type xlsxX14DataBar struct {
+	MaxLength                            int            `xml:"maxLength,attr"`
+	MinLength                            int            `xml:"minLength,attr"`
+	ShowValue                            *bool          `xml:"showValue,attr"`
+	Border                               bool           `xml:"border,attr,omitempty"`
+	Gradient                             *bool          `xml:"gradient,attr"`
+	Direction                            string         `xml:"direction,attr,omitempty"`
+	NegativeBarColorSameAsPositive       bool           `xml:"negativeBarColorSameAsPositive,attr,omitempty"`
+	NegativeBarBorderColorSameAsPositive bool           `xml:"negativeBarBorderColorSameAsPositive,attr"`
+	AxisPosition                         string         `xml:"axisPosition,attr,omitempty"`
+	Cfvo                                 []*xlsxX14Cfvo `xml:"x14:cfvo"`
+	FillColor                            *xlsxColor     `xml:"x14:fillColor"`
+	BorderColor                          *xlsxColor     `xml:"x14:borderColor"`
+	NegativeFillColor                    *xlsxColor     `xml:"x14:negativeFillColor"`
+	NegativeBorderColor                  *xlsxColor     `xml:"x14:negativeBorderColor"`
+	AxisColor                            *xlsxColor     `xml:"x14:axisColor"`
+}
+
+// xlsxX14IconSet directly maps the iconSet element.
+type xlsxX14IconSet struct {
+	IconSet   string         `xml:"iconSet,attr,omitempty"`
+	ShowValue *bool          `xml:"showValue,attr"`
+	Percent   bool           `xml:"percent,attr,omitempty"`
+	Reverse   bool           `xml:"reverse,attr,omitempty"`
+	Cfvo      []*xlsxX14Cfvo `xml:"x14:cfvo"`
+}
+
+// xlsxX14Cfvo directly maps the cfvo element.
+type xlsxX14Cfvo struct {
+	Type string `xml:"type,attr,omitempty"`
+	Gte  *bool  `xml:"gte,attr"`
+	F    string `xml:"xm:f,omitempty"`
+}
+
 // xlsxX14SparklineGroup directly maps the sparklineGroup element.
 type xlsxX14SparklineGroup struct {
 	XMLName             xml.Name          `xml:"x14:sparklineGroup"`
@@ -785,44 +934,77 @@ type SparklineOption struct {
 	EmptyCells    string
 }
 
-// formatPanes directly maps the settings of the panes.
-type formatPanes struct {
-	Freeze      bool   `json:"freeze"`
-	Split       bool   `json:"split"`
-	XSplit      int    `json:"x_split"`
-	YSplit      int    `json:"y_split"`
-	TopLeftCell string `json:"top_left_cell"`
-	ActivePane  string `json:"active_pane"`
-	Panes       []struct {
-		SQRef      string `json:"sqref"`
-		ActiveCell string `json:"active_cell"`
-		Pane       string `json:"pane"`
-	} `json:"panes"`
-}
-
-// formatConditional directly maps the conditional format settings of the cells.
-type formatConditional struct {
-	Type         string `json:"type"`
-	AboveAverage bool   `json:"above_average"`
-	Percent      bool   `json:"percent"`
-	Format       int    `json:"format"`
-	Criteria     string `json:"criteria"`
-	Value        string `json:"value,omitempty"`
-	Minimum      string `json:"minimum,omitempty"`
-	Maximum      string `json:"maximum,omitempty"`
-	MinType      string `json:"min_type,omitempty"`
-	MidType      string `json:"mid_type,omitempty"`
-	MaxType      string `json:"max_type,omitempty"`
-	MinValue     string `json:"min_value,omitempty"`
-	MidValue     string `json:"mid_value,omitempty"`
-	MaxValue     string `json:"max_value,omitempty"`
-	MinColor     string `json:"min_color,omitempty"`
-	MidColor     string `json:"mid_color,omitempty"`
-	MaxColor     string `json:"max_color,omitempty"`
-	MinLength    string `json:"min_length,omitempty"`
-	MaxLength    string `json:"max_length,omitempty"`
-	MultiRange   string `json:"multi_range,omitempty"`
-	BarColor     string `json:"bar_color,omitempty"`
+// PaneOptions directly maps the settings of the panes, the JSON tags
+// correspond to the parameters of the SetPanes function.
+type PaneOptions struct {
+	Freeze      bool            `json:"freeze"`
+	Split       bool            `json:"split"`
+	XSplit      int             `json:"x_split"`
+	YSplit      int             `json:"y_split"`
+	TopLeftCell string          `json:"top_left_cell"`
+	ActivePane  string          `json:"active_pane"`
+	Panes       []PaneSelection `json:"panes"`
+}
+
+// PaneSelection directly maps the selection settings of the pane.
+type PaneSelection struct {
+	SQRef      string `json:"sqref"`
+	ActiveCell string `json:"active_cell"`
+	Pane       string `json:"pane"`
+}
+
+// ConditionalFormatOptions directly maps the settings of the conditional
+// formatting rule, the JSON tags correspond to the parameters of the
+// SetConditionalFormat function. The Style field is only used for the
+// GetConditionalFormats function to return the decoded format of the rule.
+type ConditionalFormatOptions struct {
+	Type                       string                          `json:"type"`
+	AboveAverage               bool                            `json:"above_average"`
+	Percent                    bool                            `json:"percent"`
+	Format                     int                             `json:"format"`
+	Criteria                   string                          `json:"criteria"`
+	Value                      string                          `json:"value,omitempty"`
+	Minimum                    string                          `json:"minimum,omitempty"`
+	Maximum                    string                          `json:"maximum,omitempty"`
+	MinType                    string                          `json:"min_type,omitempty"`
+	MidType                    string                          `json:"mid_type,omitempty"`
+	MaxType                    string                          `json:"max_type,omitempty"`
+	MinValue                   string                          `json:"min_value,omitempty"`
+	MidValue                   string                          `json:"mid_value,omitempty"`
+	MaxValue                   string                          `json:"max_value,omitempty"`
+	MinColor                   string                          `json:"min_color,omitempty"`
+	MidColor                   string                          `json:"mid_color,omitempty"`
+	MaxColor                   string                          `json:"max_color,omitempty"`
+	MinLength                  string                          `json:"min_length,omitempty"`
+	MaxLength                  string                          `json:"max_length,omitempty"`
+	MultiRange                 string                          `json:"multi_range,omitempty"`
+	BarColor                   string                          `json:"bar_color,omitempty"`
+	IconStyle                  string                          `json:"icon_style,omitempty"`
+	ReverseIcons               bool                            `json:"reverse_icons,omitempty"`
+	IconsOnly                  bool                            `json:"icons_only,omitempty"`
+	BarSolid                   bool                            `json:"bar_solid,omitempty"`
+	BarNoBorder                bool                            `json:"bar_no_border,omitempty"`
+	BarBorderColor             string                          `json:"bar_border_color,omitempty"`
+	BarNegativeColor           string                          `json:"bar_negative_color,omitempty"`
+	BarNegativeColorSame       bool                            `json:"bar_negative_color_same,omitempty"`
+	BarNegativeBorderColor     string                          `json:"bar_negative_border_color,omitempty"`
+	BarNegativeBorderColorSame bool                            `json:"bar_negative_border_color_same,omitempty"`
+	BarDirection               string                          `json:"bar_direction,omitempty"`
+	BarAxisPosition            string                          `json:"bar_axis_position,omitempty"`
+	BarAxisColor               string                          `json:"bar_axis_color,omitempty"`
+	BarOnly                    bool                            `json:"bar_only,omitempty"`
+	Icons                      []*ConditionalFormatIconOptions `json:"icons,omitempty"`
+	Priority                   int                             `json:"priority,omitempty"`
+	StopIfTrue                 bool                            `json:"stop_if_true,omitempty"`
+	Style                      *Style                          `json:"-"`
+}
+
+// ConditionalFormatIconOptions directly maps the threshold settings of each
+// icon in the icon set conditional formatting rule.
+type ConditionalFormatIconOptions struct {
+	Criteria string `json:"criteria"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
 }
 
 // FormatSheetProtection directly maps the settings of worksheet protection.